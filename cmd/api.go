@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/contextpilot-dev/memorypilot/internal/api"
+	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+	"github.com/contextpilot-dev/memorypilot/internal/metrics"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/internal/summary"
+	"github.com/contextpilot-dev/memorypilot/internal/tracing"
+	"github.com/spf13/cobra"
+)
+
+var apiCmd = &cobra.Command{
+	Use:   "api",
+	Short: "Start the REST API server",
+	Long: `Serve memories over a JSON REST API for tools that don't speak MCP.
+
+Endpoints:
+  GET    /memories          List memories (?type=&topic=&project=&since=&limit=)
+  POST   /memories          Create a memory
+  GET    /memories/{id}     Get a memory
+  PUT    /memories/{id}     Replace a memory's content
+  DELETE /memories/{id}     Delete a memory
+  POST   /recall            Search, with the same filters as memorypilot_recall
+
+Requests must carry "Authorization: Bearer <token>" when a token is set with
+--token or MEMORYPILOT_API_TOKEN. Without a token the server only listens on
+loopback addresses. Request bodies must be sent as application/json, and the
+Host header must name the listen address or localhost.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath := getPaths().Database()
+		
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized. Run 'memorypilot init' first.")
+			return nil
+		}
+		
+		listen, _ := cmd.Flags().GetString("listen")
+		token, _ := cmd.Flags().GetString("token")
+		if token == "" {
+			token = os.Getenv("MEMORYPILOT_API_TOKEN")
+		}
+		if token == "" && !loopbackAddr(listen) {
+			return fmt.Errorf("refusing to listen on %s without a token; set --token or MEMORYPILOT_API_TOKEN", listen)
+		}
+		
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		cacheSize, _ := cmd.Flags().GetInt("recall-cache-size")
+		cacheTTL, _ := cmd.Flags().GetDuration("recall-cache-ttl")
+		s.SetRecallCache(cacheSize, cacheTTL)
+		
+		server := api.NewServer(s, embedding.NewAutoEmbedder(embedding.DefaultProviders()))
+		server.SetToken(token)
+		server.SetListenAddr(listen)
+		
+		embedTimeout, _ := cmd.Flags().GetDuration("embed-timeout")
+		server.SetEmbedTimeout(embedTimeout)
+		
+		lifetimes, err := loadLifetimes()
+		if err != nil {
+			return err
+		}
+		server.SetLifetimes(lifetimes)
+		
+		sum, err := newSummarizer(cmd)
+		if err != nil {
+			return err
+		}
+		server.SetSummarizer(sum)
+		
+		if metricsAddr, _ := cmd.Flags().GetString("metrics"); metricsAddr != "" {
+			s.RegisterMetrics()
+			metrics.Serve(metricsAddr)
+		}
+		
+		shutdownTracing := tracing.Init("memorypilot")
+		defer shutdownTracing()
+		
+		fmt.Printf("🌐 MemoryPilot API listening on %s\n", listen)
+		if token == "" {
+			fmt.Println("   ⚠️  No token set; any local process can read and write memories")
+		}
+		return http.ListenAndServe(listen, server.Handler())
+	},
+}
+
+func init() {
+	apiCmd.Flags().String("listen", "127.0.0.1:9090", "Address to listen on")
+	apiCmd.Flags().String("token", "", "Bearer token required on every request (default $MEMORYPILOT_API_TOKEN)")
+	apiCmd.Flags().String("summarizer", "truncate", "Summary generation backend (truncate|ollama)")
+	apiCmd.Flags().Int("summary-length", summary.DefaultMaxLen, "Maximum summary length in characters of types without their own in summaries.json")
+	apiCmd.Flags().Duration("embed-timeout", embedding.DefaultQueryTimeout, "How long recall waits for the query embedding before falling back to keyword search")
+	apiCmd.Flags().Int("recall-cache-size", store.DefaultRecallCacheSize, "Recall results kept for repeated identical queries (0 disables the cache)")
+	apiCmd.Flags().Duration("recall-cache-ttl", store.DefaultRecallCacheTTL, "How long cached recall results are reused")
+	apiCmd.Flags().String("metrics", "", "Serve Prometheus metrics at /metrics on this address (e.g. :9100)")
+}
+
+// loopbackAddr reports whether a listen address only accepts local
+// connections. An empty host listens on every interface.
+func loopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit <id>",
+	Short: "Show when a memory was recalled and by which queries",
+	Long: `Show the access log for a memory: every recall that returned it, with
+the query and relevance score, most recent first.
+
+Recalls made with --no-access-log are not recorded, and only the most
+recent entries across all memories are kept.
+
+Examples:
+  memorypilot audit 01HQ3K5Z8X`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		// Open store
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		m, err := s.GetMemory(id)
+		if err != nil {
+			return fmt.Errorf("failed to load memory: %w", err)
+		}
+		if m == nil {
+			return fmt.Errorf("memory %s not found", id)
+		}
+		
+		entries, err := s.GetAccessLog(id)
+		if err != nil {
+			return fmt.Errorf("failed to read access log: %w", err)
+		}
+		
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		if jsonOutput {
+			data, _ := json.MarshalIndent(entries, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		
+		fmt.Printf("🔎 %s\n", m.Summary)
+		fmt.Printf("   Accessed %d times in total\n\n", m.AccessCount)
+		
+		if len(entries) == 0 {
+			fmt.Println("No recalls recorded in the access log")
+			return nil
+		}
+		
+		for _, e := range entries {
+			fmt.Printf("  %s  %.2f  %q\n", e.AccessedAt.Format("2006-01-02 15:04:05"), e.Score, e.Query)
+		}
+		
+		return nil
+	},
+}
+
+func init() {
+	auditCmd.Flags().Bool("json", false, "Output as JSON")
+}
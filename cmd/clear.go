@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var clearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete all memories",
+	Long: `Delete every memory, with its history, access log entries and embedding.
+Captured events and projects are kept.
+
+Nothing is deleted unless --yes is given. With --trash, memories are moved
+to a trash table in the database instead of being deleted outright.
+
+Examples:
+  memorypilot clear --yes
+  memorypilot clear --yes --trash`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		// Open store
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		yes, _ := cmd.Flags().GetBool("yes")
+		trash, _ := cmd.Flags().GetBool("trash")
+		
+		if !yes {
+			stats, err := s.GetStats()
+			if err != nil {
+				return fmt.Errorf("failed to get stats: %w", err)
+			}
+			fmt.Printf("⚠️  This would delete all %d memories\n", stats.TotalMemories)
+			fmt.Println("   Re-run with --yes to confirm")
+			return nil
+		}
+		
+		n, err := s.ClearMemories(trash)
+		if err != nil {
+			return fmt.Errorf("failed to clear memories: %w", err)
+		}
+		
+		if trash {
+			fmt.Printf("🗑️  Moved %d memories to the trash\n", n)
+		} else {
+			fmt.Printf("🗑️  Deleted %d memories\n", n)
+		}
+		
+		return nil
+	},
+}
+
+func init() {
+	clearCmd.Flags().Bool("yes", false, "Confirm deleting all memories")
+	clearCmd.Flags().Bool("trash", false, "Move memories to the trash instead of deleting them")
+}
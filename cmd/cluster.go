@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var clusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Group memories into themes by similarity",
+	Long: `Group memories into clusters by embedding similarity and show a label
+(from shared topics) and the most representative memories of each.
+
+Memories without embeddings are excluded; run 'memorypilot reindex' first.
+
+Examples:
+  memorypilot cluster
+  memorypilot cluster --k 10`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		k, _ := cmd.Flags().GetInt("k")
+		perCluster, _ := cmd.Flags().GetInt("show")
+		
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		// Open store
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		result, err := s.ClusterMemories(k, perCluster)
+		if err != nil {
+			return fmt.Errorf("clustering failed: %w", err)
+		}
+		
+		// Check if JSON output requested
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		if jsonOutput {
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		
+		if len(result.Clusters) == 0 {
+			fmt.Println("🔍 No memories with embeddings to cluster")
+		} else {
+			fmt.Printf("🧩 %d clusters\n\n", len(result.Clusters))
+		}
+		
+		for i, c := range result.Clusters {
+			fmt.Printf("%d. %s (%d memories)\n", i+1, c.Label, c.Size)
+			for _, m := range c.Representatives {
+				fmt.Printf("   %s [%s] %s\n", getTypeEmoji(m.Type), m.Type, m.Summary)
+			}
+			fmt.Println()
+		}
+		
+		if result.Excluded > 0 {
+			fmt.Printf("⚠️  %d memories without embeddings were excluded (run 'memorypilot reindex')\n", result.Excluded)
+		}
+		
+		return nil
+	},
+}
+
+func init() {
+	clusterCmd.Flags().IntP("k", "k", 5, "Number of clusters")
+	clusterCmd.Flags().Int("show", 3, "Representative memories to show per cluster")
+	clusterCmd.Flags().Bool("json", false, "Output as JSON")
+}
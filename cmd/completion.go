@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate a shell completion script",
+	Long: `Print a completion script for your shell. Besides commands and flags it
+completes memory types, content types, sources and the topics in your
+database.
+
+Bash:
+  source <(memorypilot completion bash)
+  # Permanently (Linux):
+  memorypilot completion bash > /etc/bash_completion.d/memorypilot
+
+Zsh:
+  memorypilot completion zsh > "${fpath[1]}/_memorypilot"
+  # Completion must be enabled: autoload -U compinit; compinit
+
+Fish:
+  memorypilot completion fish > ~/.config/fish/completions/memorypilot.fish
+
+PowerShell:
+  memorypilot completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		switch args[0] {
+		case "bash":
+			return cmd.Root().GenBashCompletionV2(out, true)
+		case "zsh":
+			return cmd.Root().GenZshCompletion(out)
+		case "fish":
+			return cmd.Root().GenFishCompletion(out, true)
+		default:
+			return cmd.Root().GenPowerShellCompletionWithDesc(out)
+		}
+	},
+}
+
+// registerCompletions adds dynamic completion for flag values. It runs
+// after every command's init has defined its flags.
+func registerCompletions() {
+	var memoryTypes []string
+	for _, t := range models.MemoryTypes {
+		memoryTypes = append(memoryTypes, string(t))
+	}
+	contentTypes := []string{
+		string(models.ContentTypeProse), string(models.ContentTypeCode),
+		string(models.ContentTypeCommand), string(models.ContentTypeConfig),
+	}
+	sources := []string{
+		string(models.SourceTypeGit), string(models.SourceTypeFile), string(models.SourceTypeTerminal),
+		string(models.SourceTypeChat), string(models.SourceTypeManual), string(models.SourceTypeImport),
+	}
+	scopes := []string{
+		string(models.MemoryScopePersonal), string(models.MemoryScopeProject), string(models.MemoryScopeTeam),
+	}
+	summarizers := []string{"truncate", "ollama"}
+
+	flags := []struct {
+		cmd  *cobra.Command
+		flag string
+		fn   cobra.CompletionFunc
+	}{
+		{recallCmd, "type", fixedCompletion(memoryTypes)},
+		{recallCmd, "scope", fixedCompletion(scopes)},
+		{recallCmd, "source", fixedCompletion(sources)},
+		{recallCmd, "content-type", fixedCompletion(contentTypes)},
+		{recallCmd, "topic", completeTopics},
+		{recallCmd, "exclude-topic", completeTopics},
+		{rememberCmd, "type", fixedCompletion(memoryTypes)},
+		{rememberCmd, "topics", completeTopics},
+		{rememberCmd, "content-type", fixedCompletion(contentTypes)},
+		{rememberCmd, "summarizer", fixedCompletion(summarizers)},
+		{exportCmd, "type", fixedCompletion(memoryTypes)},
+		{exportCmd, "topic", completeTopics},
+		{watchCmd, "type", fixedCompletion(memoryTypes)},
+		{importCmd, "format", fixedCompletion([]string{"json", "markdown", "shell-history"})},
+		{importCmd, "type", fixedCompletion(memoryTypes)},
+		{importCmd, "on-conflict", fixedCompletion([]string{
+			string(store.ConflictSkip), string(store.ConflictOverwrite),
+			string(store.ConflictNewestWins), string(store.ConflictMerge),
+		})},
+		{statusCmd, "trend", fixedCompletion([]string{"day", "week", "month"})},
+		{mcpCmd, "summarizer", fixedCompletion(summarizers)},
+		{mcpCmd, "recall-format", fixedCompletion([]string{"compact", "detailed", "markdown"})},
+		{apiCmd, "summarizer", fixedCompletion(summarizers)},
+		{tuiCmd, "summarizer", fixedCompletion(summarizers)},
+		{configRepoAddCmd, "scope", fixedCompletion(append(scopes, string(models.MemoryScopeOrg)))},
+	}
+	for _, f := range flags {
+		if err := f.cmd.RegisterFlagCompletionFunc(f.flag, f.fn); err != nil {
+			panic(err) // Flags are defined at compile time
+		}
+	}
+
+	// Commands without positional arguments shouldn't offer file names
+	var noArgs func(cmd *cobra.Command)
+	noArgs = func(cmd *cobra.Command) {
+		if cmd.Args == nil && cmd.ValidArgsFunction == nil && len(cmd.ValidArgs) == 0 && cmd.Runnable() {
+			cmd.ValidArgsFunction = cobra.NoFileCompletions
+		}
+		for _, sub := range cmd.Commands() {
+			noArgs(sub)
+		}
+	}
+	for _, sub := range rootCmd.Commands() {
+		noArgs(sub)
+	}
+}
+
+// fixedCompletion completes a flag from a fixed list of values. Values of
+// comma-separated list flags are completed after the last comma.
+func fixedCompletion(values []string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeList(values, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeTopics completes topics in use in the database, most used first
+func completeTopics(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	dbPath := getPaths().Database()
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	s, err := store.New(dbPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer s.Close()
+
+	topics, err := s.ListTopics()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeList(topics, toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeList returns the values starting with the part of toComplete after
+// its last comma, prefixed with the part before it
+func completeList(values []string, toComplete string) []string {
+	prefix, partial := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, partial = toComplete[:i+1], toComplete[i+1:]
+	}
+
+	var matches []string
+	for _, v := range values {
+		if strings.HasPrefix(v, partial) {
+			matches = append(matches, prefix+v)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,358 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/contextpilot-dev/memorypilot/internal/repos"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage MemoryPilot configuration",
+}
+
+var configRepoCmd = &cobra.Command{
+	Use:   "repo",
+	Short: "Choose which repositories the daemon captures from",
+	Long: `The daemon captures from every repository it finds unless told otherwise.
+Once a repository is added, only added repositories are captured; ignored
+repositories are never captured. Paths may be directories or glob patterns
+("~/scratch/*") and cover everything under them.
+
+The list is kept in repos.json in the config directory and read when the
+daemon starts.`,
+}
+
+var configRepoAddCmd = &cobra.Command{
+	Use:   "add <path>",
+	Short: "Capture from a repository, optionally overriding importance and scope",
+	Long: `Capture from the repositories matching path. Memories from them can be
+given a fixed importance (0-1) and scope instead of the usual ones.
+
+Examples:
+  memorypilot config repo add ~/Projects/api
+  memorypilot config repo add ~/Projects/api --importance 0.8 --scope project
+  memorypilot config repo add "~/work/*"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := repos.Normalize(args[0])
+		if err != nil {
+			return err
+		}
+		rule := repos.Rule{Path: path}
+		if cmd.Flags().Changed("importance") {
+			importance, _ := cmd.Flags().GetFloat64("importance")
+			rule.Importance = &importance
+		}
+		scope, _ := cmd.Flags().GetString("scope")
+		rule.Scope = models.MemoryScope(scope)
+		
+		return updateRepoConfig(func(cfg *repos.Config) error {
+			if err := cfg.Add(rule); err != nil {
+				return err
+			}
+			fmt.Printf("✅ Capturing from %s\n", path)
+			return nil
+		})
+	},
+}
+
+var configRepoIgnoreCmd = &cobra.Command{
+	Use:   "ignore <path>",
+	Short: "Never capture from a repository",
+	Long: `Never capture from the repositories matching path, even if they are also
+covered by an added path.
+
+Examples:
+  memorypilot config repo ignore ~/Projects/scratch
+  memorypilot config repo ignore "~/Projects/experiment-*"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := repos.Normalize(args[0])
+		if err != nil {
+			return err
+		}
+		return updateRepoConfig(func(cfg *repos.Config) error {
+			cfg.IgnorePath(path)
+			fmt.Printf("✅ Ignoring %s\n", path)
+			return nil
+		})
+	},
+}
+
+var configRepoRemoveCmd = &cobra.Command{
+	Use:   "remove <path>",
+	Short: "Forget an added or ignored repository",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := repos.Normalize(args[0])
+		if err != nil {
+			return err
+		}
+		return updateRepoConfig(func(cfg *repos.Config) error {
+			if !cfg.Remove(path) {
+				return fmt.Errorf("%s is neither added nor ignored", path)
+			}
+			fmt.Printf("✅ Removed %s\n", path)
+			return nil
+		})
+	},
+}
+
+var configRepoListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List added and ignored repositories",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadRepoConfig()
+		if err != nil {
+			return err
+		}
+		printRepoConfig(cfg)
+		return nil
+	},
+}
+
+var configRankingCmd = &cobra.Command{
+	Use:   "ranking",
+	Short: "Show or change how recall ranks memories",
+	Long: `Show or change how recall ranks memories.
+
+--confidence-weight sets how much a memory's confidence counts, from 0
+(ignored) to 1. Recall subtracts (1 - confidence) × weight from the score,
+so auto-captured memories (0.7 confidence) rank below equally relevant
+manually confirmed ones (1.0). The weight is kept in the database, so every
+client ranks the same way; running daemons and MCP servers pick it up when
+restarted.
+
+--importance-floor sets the importance memories captured by the daemon
+(from git, files and the terminal) need to show up in recall, from 0 (all
+do) to 1. Memories below it stay in the store: recall --include-low, or
+filtering by --type or --topic, still finds them. Memories you remember
+yourself, over MCP or by import, and curated memories, always pass.
+
+Examples:
+  memorypilot config ranking
+  memorypilot config ranking --confidence-weight 0.3
+  memorypilot config ranking --importance-floor 0.5`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		// Open store
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		weightChanged := cmd.Flags().Changed("confidence-weight")
+		floorChanged := cmd.Flags().Changed("importance-floor")
+		if weightChanged {
+			weight, _ := cmd.Flags().GetFloat64("confidence-weight")
+			if err := s.SetConfidenceWeight(weight); err != nil {
+				return err
+			}
+			fmt.Printf("✅ Confidence weight set to %g\n", weight)
+		}
+		if floorChanged {
+			floor, _ := cmd.Flags().GetFloat64("importance-floor")
+			if err := s.SetImportanceFloor(floor); err != nil {
+				return err
+			}
+			fmt.Printf("✅ Importance floor set to %g\n", floor)
+		}
+		if weightChanged || floorChanged {
+			fmt.Println("   Restart the daemon and MCP servers to apply")
+			return nil
+		}
+		
+		fmt.Printf("Confidence weight: %g", s.ConfidenceWeight())
+		if s.ConfidenceWeight() == store.DefaultConfidenceWeight {
+			fmt.Print(" (default)")
+		}
+		fmt.Println()
+		fmt.Printf("Importance floor:  %g", s.ImportanceFloor())
+		if s.ImportanceFloor() == store.DefaultImportanceFloor {
+			fmt.Print(" (default)")
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+var configRetentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "Show or change how many memories are kept",
+	Long: `Show or change how many memories are kept, for using MemoryPilot as a
+rolling cache rather than a curated store.
+
+--max-memories caps the number of memories (0, the default, keeps them
+all). The daemon deletes memories over the cap every hour, choosing them
+by the --eviction policy:
+
+  importance  the least important memories, oldest first among equals
+  lru         the least recently recalled memories
+
+Curated memories are never evicted. Deleted memories leave tombstones, so
+incremental exports record them. --evict applies the cap right away.
+
+Examples:
+  memorypilot config retention
+  memorypilot config retention --max-memories 5000 --eviction lru
+  memorypilot config retention --evict`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		// Open store
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		retention, err := s.Retention()
+		if err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("max-memories") || cmd.Flags().Changed("eviction") {
+			if cmd.Flags().Changed("max-memories") {
+				retention.MaxMemories, _ = cmd.Flags().GetInt("max-memories")
+			}
+			if cmd.Flags().Changed("eviction") {
+				retention.Eviction, _ = cmd.Flags().GetString("eviction")
+			}
+			if err := s.SetRetention(retention); err != nil {
+				return err
+			}
+			fmt.Println("✅ Retention updated")
+		}
+		
+		if evict, _ := cmd.Flags().GetBool("evict"); evict {
+			n, err := s.EvictMemories()
+			if err != nil {
+				return fmt.Errorf("failed to evict memories: %w", err)
+			}
+			fmt.Printf("🗑️  Evicted %d memories\n", n)
+		}
+		
+		stats, err := s.GetStats()
+		if err != nil {
+			return fmt.Errorf("failed to get stats: %w", err)
+		}
+		if retention.MaxMemories == 0 {
+			fmt.Printf("Max memories: unlimited (%d stored)\n", stats.TotalMemories)
+		} else {
+			fmt.Printf("Max memories: %d (%d stored)\n", retention.MaxMemories, stats.TotalMemories)
+		}
+		fmt.Printf("Eviction:     %s\n", retention.Eviction)
+		return nil
+	},
+}
+
+// reposFile lists the repositories to capture from or ignore, in the
+// config directory
+const reposFile = "repos.json"
+
+// loadRepoConfig reads the repository configuration, returning an empty one
+// if none exists
+func loadRepoConfig() (repos.Config, error) {
+	cfg, err := repos.Load(filepath.Join(getPaths().Config, reposFile))
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	return cfg, err
+}
+
+// updateRepoConfig applies change to the repository configuration and saves
+// it
+func updateRepoConfig(change func(cfg *repos.Config) error) error {
+	cfg, err := loadRepoConfig()
+	if err != nil {
+		return err
+	}
+	if err := change(&cfg); err != nil {
+		return err
+	}
+	if err := cfg.Save(filepath.Join(getPaths().Config, reposFile)); err != nil {
+		return fmt.Errorf("failed to save repository config: %w", err)
+	}
+	fmt.Println("   Restart the daemon to apply")
+	return nil
+}
+
+// printRepoConfig shows which repositories are captured and ignored
+func printRepoConfig(cfg repos.Config) {
+	if len(cfg.Include) == 0 {
+		fmt.Println("Capturing from: all repositories")
+	} else {
+		fmt.Println("Capturing from:")
+		for _, r := range cfg.Include {
+			fmt.Printf("  • %s%s\n", r.Path, formatRepoOverrides(r))
+		}
+	}
+	if len(cfg.Ignore) > 0 {
+		fmt.Println("Ignoring:")
+		for _, p := range cfg.Ignore {
+			fmt.Printf("  • %s\n", p)
+		}
+	}
+}
+
+// formatRepoOverrides describes a rule's overrides, e.g. " (importance 0.80,
+// scope project)"
+func formatRepoOverrides(r repos.Rule) string {
+	var overrides string
+	if r.Importance != nil {
+		overrides = fmt.Sprintf("importance %.2f", *r.Importance)
+	}
+	if r.Scope != "" {
+		if overrides != "" {
+			overrides += ", "
+		}
+		overrides += "scope " + string(r.Scope)
+	}
+	if overrides == "" {
+		return ""
+	}
+	return " (" + overrides + ")"
+}
+
+func init() {
+	configRepoAddCmd.Flags().Float64("importance", 0, "Fixed importance (0-1) for memories from this repository")
+	configRepoAddCmd.Flags().String("scope", "", "Scope for memories from this repository (personal|project|team|org)")
+	
+	configRepoCmd.AddCommand(configRepoAddCmd)
+	configRepoCmd.AddCommand(configRepoIgnoreCmd)
+	configRepoCmd.AddCommand(configRepoRemoveCmd)
+	configRepoCmd.AddCommand(configRepoListCmd)
+	configCmd.AddCommand(configRepoCmd)
+	
+	configRankingCmd.Flags().Float64("confidence-weight", store.DefaultConfidenceWeight, "How much confidence counts in recall scores (0-1)")
+	configRankingCmd.Flags().Float64("importance-floor", store.DefaultImportanceFloor, "Importance captured memories need to appear in recall (0-1)")
+	configCmd.AddCommand(configRankingCmd)
+	
+	configRetentionCmd.Flags().Int("max-memories", 0, "Most memories to keep (0 for no limit)")
+	configRetentionCmd.Flags().String("eviction", store.EvictionPolicies[0].Name, "Which memories over the cap to delete first (importance|lru)")
+	configRetentionCmd.Flags().Bool("evict", false, "Delete memories over the cap now instead of at the daemon's next sweep")
+	configCmd.AddCommand(configRetentionCmd)
+}
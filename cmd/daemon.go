@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -8,17 +9,30 @@ import (
 	"path/filepath"
 	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/contextpilot-dev/memorypilot/internal/agent"
+	"github.com/contextpilot-dev/memorypilot/internal/ipc"
+	"github.com/contextpilot-dev/memorypilot/internal/repos"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/internal/watcher"
 	"github.com/spf13/cobra"
 )
 
 func getPidFilePath() string {
-	return filepath.Join(getConfigDir(), "memorypilot.pid")
+	return getPaths().PidFile()
+}
+
+func getSocketPath() string {
+	return getPaths().Socket()
 }
 
 func writePidFile(pid int) error {
-	return os.WriteFile(getPidFilePath(), []byte(strconv.Itoa(pid)), 0644)
+	path := getPidFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strconv.Itoa(pid)), 0644)
 }
 
 func readPidFile() (int, error) {
@@ -33,16 +47,6 @@ func removePidFile() {
 	os.Remove(getPidFilePath())
 }
 
-func isProcessRunning(pid int) bool {
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false
-	}
-	// On Unix, FindProcess always succeeds. Send signal 0 to check if process exists.
-	err = process.Signal(syscall.Signal(0))
-	return err == nil
-}
-
 var daemonCmd = &cobra.Command{
 	Use:   "daemon",
 	Short: "Manage the MemoryPilot background daemon",
@@ -54,6 +58,12 @@ var daemonStartCmd = &cobra.Command{
 	Short: "Start the MemoryPilot daemon",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		background, _ := cmd.Flags().GetBool("background")
+		metricsAddr, _ := cmd.Flags().GetString("metrics")
+		linkThreshold, _ := cmd.Flags().GetInt("link-threshold")
+		maxInferredLinks, _ := cmd.Flags().GetInt("max-inferred-links")
+		minDiffLines, _ := cmd.Flags().GetInt("min-diff-lines")
+		embedWorkers, _ := cmd.Flags().GetInt("embed-workers")
+		captureCommands, _ := cmd.Flags().GetBool("capture-commands")
 		
 		// Check if already running
 		if pid, err := readPidFile(); err == nil {
@@ -72,7 +82,19 @@ var daemonStartCmd = &cobra.Command{
 				return fmt.Errorf("failed to get executable path: %w", err)
 			}
 			
-			bgCmd := exec.Command(exe, "daemon", "start")
+			bgArgs := []string{"daemon", "start"}
+			if metricsAddr != "" {
+				bgArgs = append(bgArgs, "--metrics", metricsAddr)
+			}
+			bgArgs = append(bgArgs,
+				"--link-threshold", strconv.Itoa(linkThreshold),
+				"--max-inferred-links", strconv.Itoa(maxInferredLinks),
+				"--min-diff-lines", strconv.Itoa(minDiffLines),
+				"--embed-workers", strconv.Itoa(embedWorkers))
+			if captureCommands {
+				bgArgs = append(bgArgs, "--capture-commands")
+			}
+			bgCmd := exec.Command(exe, bgArgs...)
 			bgCmd.Stdout = nil
 			bgCmd.Stderr = nil
 			bgCmd.Stdin = nil
@@ -98,16 +120,15 @@ var daemonStartCmd = &cobra.Command{
 		
 		// Create and start the agent
 		cfg := agent.DefaultConfig()
-		cfg.DataDir = getDataDir()
-		
-		a, err := agent.New(cfg)
+		cfg.MetricsAddr = metricsAddr
+		cfg.LinkThreshold = linkThreshold
+		cfg.MaxInferredLinks = maxInferredLinks
+		cfg.MinDiffLines = minDiffLines
+		cfg.EmbedWorkers = embedWorkers
+		cfg.CaptureCommands = captureCommands
+		a, err := startAgent(cfg)
 		if err != nil {
-			return fmt.Errorf("failed to create agent: %w", err)
-		}
-		
-		// Start the agent
-		if err := a.Start(); err != nil {
-			return fmt.Errorf("failed to start agent: %w", err)
+			return err
 		}
 		
 		fmt.Println("✅ MemoryPilot daemon started")
@@ -127,6 +148,32 @@ var daemonStartCmd = &cobra.Command{
 	},
 }
 
+// startAgent creates and starts the background agent with cfg, filling in
+// its file locations. It is shared by the foreground daemon and the Windows
+// service entry point.
+func startAgent(cfg *agent.Config) (*agent.Agent, error) {
+	dirs := getPaths()
+	cfg.DataDir = dirs.Data
+	cfg.SocketPath = dirs.Socket()
+	cfg.ImportanceRules = filepath.Join(dirs.Config, "importance.json")
+	cfg.Lifetimes = filepath.Join(dirs.Config, lifetimesFile)
+	cfg.Repos = filepath.Join(dirs.Config, reposFile)
+	cfg.Summaries = filepath.Join(dirs.Config, summariesFile)
+	cfg.Hooks = filepath.Join(dirs.Config, hooksFile)
+	cfg.Redaction = filepath.Join(dirs.Config, redactionFile)
+
+	a, err := agent.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create agent: %w", err)
+	}
+
+	if err := a.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start agent: %w", err)
+	}
+
+	return a, nil
+}
+
 var daemonStopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stop the MemoryPilot daemon",
@@ -150,8 +197,8 @@ var daemonStopCmd = &cobra.Command{
 			return fmt.Errorf("failed to find process: %w", err)
 		}
 		
-		// Send SIGTERM for graceful shutdown
-		if err := process.Signal(syscall.SIGTERM); err != nil {
+		// Send SIGTERM for graceful shutdown (killed outright on Windows)
+		if err := stopProcess(process); err != nil {
 			return fmt.Errorf("failed to stop daemon: %w", err)
 		}
 		
@@ -164,6 +211,14 @@ var daemonStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Check daemon status",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		status := getDaemonStatus()
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		if jsonOutput {
+			data, _ := json.MarshalIndent(status, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		
 		pid, err := readPidFile()
 		if err != nil {
 			fmt.Println("🔴 MemoryPilot daemon is not running")
@@ -179,15 +234,117 @@ var daemonStatusCmd = &cobra.Command{
 		fmt.Printf("🟢 MemoryPilot daemon is running (PID %d)\n", pid)
 		fmt.Println()
 		fmt.Println("Watched directories:")
-		fmt.Println("  • ~/Documents/source-code/")
-		fmt.Println("  • ~/Projects/")
+		for _, dir := range watcher.CodeDirs {
+			fmt.Printf("  • ~/%s/\n", dir)
+		}
+		fmt.Println()
+		printWatcherHealth(status.Watchers)
+		fmt.Println()
+		printRepoConfig(status.Repos)
 		fmt.Println()
-		fmt.Println("Watching:")
+		fmt.Printf("Embedding backlog: %d memories\n", status.EmbeddingBacklog)
+		return nil
+	},
+}
+
+// hooksFile lists commands run for captured memories, in the config
+// directory
+const hooksFile = "hooks.json"
+
+// redactionFile configures the secrets redacted from captured memories, in
+// the config directory
+const redactionFile = "redaction.json"
+
+// daemonStatus is the machine-readable form of 'daemon status'
+type daemonStatus struct {
+	Running       bool         `json:"running"`
+	PID           int          `json:"pid,omitempty"`
+	UptimeSeconds int64        `json:"uptimeSeconds,omitempty"`
+	StartedAt     *time.Time   `json:"startedAt,omitempty"`
+	WatchedDirs   []string     `json:"watchedDirs"`
+	Stats         *store.Stats `json:"stats,omitempty"`
+	// Whether each watcher is capturing, asked from the daemon
+	Watchers []watcher.Health `json:"watchers,omitempty"`
+	// Memories waiting for the background embedding worker
+	EmbeddingBacklog int `json:"embeddingBacklog"`
+	// Repositories captured from and ignored
+	Repos repos.Config `json:"repos"`
+}
+
+// getDaemonStatus collects daemon and store status without printing anything
+func getDaemonStatus() daemonStatus {
+	status := daemonStatus{}
+
+	status.WatchedDirs = watcher.CodeRoots()
+
+	if pid, err := readPidFile(); err == nil && isProcessRunning(pid) {
+		status.Running = true
+		status.PID = pid
+		// The PID file is written when the daemon starts
+		if info, err := os.Stat(getPidFilePath()); err == nil {
+			startedAt := info.ModTime()
+			status.StartedAt = &startedAt
+			status.UptimeSeconds = int64(time.Since(startedAt).Seconds())
+		}
+		status.Watchers, _ = ipc.WatcherHealth(getSocketPath())
+	}
+
+	status.Repos, _ = loadRepoConfig()
+
+	dbPath := getPaths().Database()
+	if _, err := os.Stat(dbPath); err == nil {
+		if s, err := store.New(dbPath); err == nil {
+			if stats, err := s.GetStats(); err == nil {
+				stats.DaemonRunning = status.Running
+				status.Stats = stats
+			}
+			if n, err := s.PendingEmbeddings(); err == nil {
+				status.EmbeddingBacklog = n
+			}
+			s.Close()
+		}
+	}
+
+	return status
+}
+
+// watcherLabels describe what each watcher captures
+var watcherLabels = map[string]string{
+	"git":      "Git commits",
+	"file":     "File changes",
+	"terminal": "Terminal commands",
+}
+
+// printWatcherHealth lists the watchers and whether they are capturing, or
+// just what is watched if the daemon couldn't be asked
+func printWatcherHealth(health []watcher.Health) {
+	fmt.Println("Watching:")
+	if len(health) == 0 {
 		fmt.Println("  • Git commits")
 		fmt.Println("  • File changes")
 		fmt.Println("  • Terminal commands")
-		return nil
-	},
+		return
+	}
+	for _, h := range health {
+		label := watcherLabels[h.Name]
+		if label == "" {
+			label = h.Name
+		}
+		if h.Watches > 0 {
+			label += fmt.Sprintf(" (%d directories)", h.Watches)
+		}
+		switch {
+		case !h.Alive:
+			fmt.Printf("  🔴 %s: %s\n", label, h.Error)
+		case h.Warning != "":
+			fmt.Printf("  🟡 %s: %s\n", label, h.Warning)
+		default:
+			fmt.Printf("  🟢 %s\n", label)
+		}
+		if h.LastRestart != nil {
+			fmt.Printf("     restarts: %d, last %s\n", h.Restarts, h.LastRestart.Format("2006-01-02 15:04"))
+		}
+	}
 }
 
 func init() {
@@ -196,4 +353,11 @@ func init() {
 	daemonCmd.AddCommand(daemonStatusCmd)
 	
 	daemonStartCmd.Flags().BoolP("background", "b", false, "Run daemon in background")
+	daemonStartCmd.Flags().String("metrics", "", "Serve Prometheus metrics at /metrics on this address (e.g. :9100)")
+	daemonStartCmd.Flags().Int("link-threshold", store.DefaultLinkThreshold, "Recalls two memories must appear in together before they are linked")
+	daemonStartCmd.Flags().Int("max-inferred-links", store.DefaultMaxInferredLinks, "Most inferred links kept per memory")
+	daemonStartCmd.Flags().Int("min-diff-lines", watcher.DefaultMinDiffLines, "Fewest added plus removed lines for a file edit to be captured")
+	daemonStartCmd.Flags().Int("embed-workers", agent.DefaultEmbedWorkers, "Memories embedded concurrently in the background")
+	daemonStartCmd.Flags().Bool("capture-commands", false, "Take terminal commands and exit codes from shell hooks (see 'memorypilot shell-hook')")
+	daemonStatusCmd.Flags().Bool("json", false, "Output as JSON")
 }
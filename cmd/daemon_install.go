@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	systemdUnitName = "memorypilot.service"
+	launchdLabel    = "dev.contextpilot.memorypilot"
+)
+
+const systemdUnit = `[Unit]
+Description=MemoryPilot daemon
+After=network.target
+
+[Service]
+ExecStart=%s daemon start
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy=default.target
+`
+
+const launchdPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>%s</string>
+		<string>daemon</string>
+		<string>start</string>
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>StandardOutPath</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+</dict>
+</plist>
+`
+
+var daemonInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the daemon as a login service",
+	Long: `Install the daemon so it starts on login and restarts on crash.
+
+Linux:  writes a systemd user unit to ~/.config/systemd/user/ and enables it
+macOS:  writes a launchd agent to ~/Library/LaunchAgents/ and loads it`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to get executable path: %w", err)
+		}
+		if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+			exe = resolved
+		}
+		
+		switch runtime.GOOS {
+		case "linux":
+			return installSystemdUnit(exe)
+		case "darwin":
+			return installLaunchdAgent(exe)
+		case "windows":
+			return fmt.Errorf("use 'memorypilot service install' on Windows")
+		default:
+			return fmt.Errorf("daemon install is not supported on %s", runtime.GOOS)
+		}
+	},
+}
+
+var daemonUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the daemon login service",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch runtime.GOOS {
+		case "linux":
+			return uninstallSystemdUnit()
+		case "darwin":
+			return uninstallLaunchdAgent()
+		case "windows":
+			return fmt.Errorf("use 'memorypilot service uninstall' on Windows")
+		default:
+			return fmt.Errorf("daemon uninstall is not supported on %s", runtime.GOOS)
+		}
+	},
+}
+
+func systemdUnitPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "systemd", "user", systemdUnitName)
+}
+
+func launchdPlistPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "Library", "LaunchAgents", launchdLabel+".plist")
+}
+
+func installSystemdUnit(exe string) error {
+	path := systemdUnitPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create unit directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(fmt.Sprintf(systemdUnit, exe)), 0644); err != nil {
+		return fmt.Errorf("failed to write unit: %w", err)
+	}
+	fmt.Printf("   ✓ Wrote %s\n", path)
+
+	if err := runQuiet("systemctl", "--user", "daemon-reload"); err != nil {
+		return fmt.Errorf("systemctl daemon-reload failed: %w", err)
+	}
+	if err := runQuiet("systemctl", "--user", "enable", "--now", systemdUnitName); err != nil {
+		return fmt.Errorf("systemctl enable failed: %w", err)
+	}
+
+	fmt.Println("✅ MemoryPilot daemon installed and started")
+	fmt.Println("   Check status: systemctl --user status memorypilot")
+	fmt.Println("   View logs:    journalctl --user -u memorypilot")
+	return nil
+}
+
+func uninstallSystemdUnit() error {
+	path := systemdUnitPath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Println("❌ MemoryPilot daemon is not installed")
+		return nil
+	}
+
+	// Best effort: the unit may already be stopped or disabled
+	runQuiet("systemctl", "--user", "disable", "--now", systemdUnitName)
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove unit: %w", err)
+	}
+	runQuiet("systemctl", "--user", "daemon-reload")
+
+	fmt.Println("✅ MemoryPilot daemon uninstalled")
+	return nil
+}
+
+func installLaunchdAgent(exe string) error {
+	path := launchdPlistPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create LaunchAgents directory: %w", err)
+	}
+
+	logsDir := getPaths().Logs
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create logs directory: %w", err)
+	}
+	logPath := filepath.Join(logsDir, "daemon.log")
+
+	plist := fmt.Sprintf(launchdPlist, launchdLabel, exe, logPath, logPath)
+	if err := os.WriteFile(path, []byte(plist), 0644); err != nil {
+		return fmt.Errorf("failed to write plist: %w", err)
+	}
+	fmt.Printf("   ✓ Wrote %s\n", path)
+
+	// Unload first so reinstalling picks up a changed plist
+	runQuiet("launchctl", "unload", path)
+	if err := runQuiet("launchctl", "load", "-w", path); err != nil {
+		return fmt.Errorf("launchctl load failed: %w", err)
+	}
+
+	fmt.Println("✅ MemoryPilot daemon installed and started")
+	fmt.Printf("   Check status: launchctl list %s\n", launchdLabel)
+	fmt.Printf("   View logs:    tail -f %s\n", logPath)
+	return nil
+}
+
+func uninstallLaunchdAgent() error {
+	path := launchdPlistPath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Println("❌ MemoryPilot daemon is not installed")
+		return nil
+	}
+
+	runQuiet("launchctl", "unload", "-w", path)
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove plist: %w", err)
+	}
+
+	fmt.Println("✅ MemoryPilot daemon uninstalled")
+	return nil
+}
+
+// runQuiet runs a command, including its combined output in any error
+func runQuiet(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, string(out))
+	}
+	return nil
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonInstallCmd)
+	daemonCmd.AddCommand(daemonUninstallCmd)
+}
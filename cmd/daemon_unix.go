@@ -2,10 +2,28 @@
 
 package cmd
 
-import "syscall"
+import (
+	"os"
+	"syscall"
+)
 
 func getSysProcAttr() *syscall.SysProcAttr {
 	return &syscall.SysProcAttr{
 		Setsid: true, // Create new session (detach from terminal)
 	}
 }
+
+func isProcessRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// On Unix, FindProcess always succeeds. Send signal 0 to check if process exists.
+	err = process.Signal(syscall.Signal(0))
+	return err == nil
+}
+
+// stopProcess asks the daemon to shut down gracefully
+func stopProcess(process *os.Process) error {
+	return process.Signal(syscall.SIGTERM)
+}
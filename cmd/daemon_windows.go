@@ -2,11 +2,44 @@
 
 package cmd
 
-import "syscall"
+import (
+	"os"
+	"syscall"
+)
+
+const (
+	detachedProcess       = 0x00000008 // DETACHED_PROCESS
+	createNewProcessGroup = 0x00000200 // CREATE_NEW_PROCESS_GROUP
+	stillActive           = 259        // STILL_ACTIVE exit code
+)
 
 func getSysProcAttr() *syscall.SysProcAttr {
 	return &syscall.SysProcAttr{
-		// Windows doesn't support Setsid, use CREATE_NEW_PROCESS_GROUP instead
-		CreationFlags: 0x00000200, // CREATE_NEW_PROCESS_GROUP
+		// Windows doesn't support Setsid. Detach from the parent console and
+		// start a new process group so the daemon survives the parent exiting
+		// and doesn't receive its Ctrl+C.
+		CreationFlags: detachedProcess | createNewProcessGroup,
+		HideWindow:    true,
+	}
+}
+
+func isProcessRunning(pid int) bool {
+	// Signal(0) is not supported on Windows, so query the exit code instead
+	handle, err := syscall.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
 	}
+	defer syscall.CloseHandle(handle)
+
+	var code uint32
+	if err := syscall.GetExitCodeProcess(handle, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
+
+// stopProcess terminates the daemon. Windows has no SIGTERM equivalent for
+// detached processes, so the process is killed outright.
+func stopProcess(process *os.Process) error {
+	return process.Kill()
 }
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/internal/summary"
+	"github.com/spf13/cobra"
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Edit a memory in $EDITOR",
+	Long: `Open a memory in your editor and save the changes.
+
+The content follows a frontmatter block holding the topics:
+
+  ---
+  topics: auth, jwt
+  ---
+  Always validate JWT tokens server-side
+
+The editor is $VISUAL, then $EDITOR, then vi. Editors that return before
+the file is closed need their wait flag, e.g. EDITOR="code --wait".
+Quitting without saving, or with an error (:cq in vim), changes nothing.
+When the content changes its summary and embedding are regenerated, and the
+previous version is kept in the memory's history.
+
+Examples:
+  memorypilot edit 01HQ3K5Z8X
+  EDITOR="code --wait" memorypilot edit 01HQ3K5Z8X`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		// Open store
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		m, err := s.GetMemory(id)
+		if err != nil {
+			return err
+		}
+		if m == nil {
+			return fmt.Errorf("memory %s not found", id)
+		}
+		
+		sum, err := newSummarizer(cmd)
+		if err != nil {
+			return err
+		}
+		
+		f, err := os.CreateTemp("", "memorypilot-*.md")
+		if err != nil {
+			return err
+		}
+		path := f.Name()
+		original := formatEditFile(m.Content, m.Topics)
+		_, err = f.WriteString(original)
+		f.Close()
+		if err != nil {
+			os.Remove(path)
+			return err
+		}
+		
+		editor := strings.Fields(editorCommand())
+		run := exec.Command(editor[0], append(editor[1:], path)...)
+		run.Stdin, run.Stdout, run.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := run.Run(); err != nil {
+			os.Remove(path)
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				fmt.Println("Edit aborted, nothing changed")
+				return nil
+			}
+			return fmt.Errorf("failed to run editor %q: %w", editor[0], err)
+		}
+		
+		data, err := os.ReadFile(path)
+		if err != nil {
+			os.Remove(path)
+			return err
+		}
+		if string(data) == original {
+			os.Remove(path)
+			fmt.Println("No changes")
+			return nil
+		}
+		
+		content, topics, err := parseEditFile(string(data))
+		if err != nil {
+			// Keep the file so the edit isn't lost
+			return fmt.Errorf("%w (your edit is saved in %s)", err, path)
+		}
+		os.Remove(path)
+		if content == "" {
+			fmt.Println("Content is empty, nothing changed")
+			return nil
+		}
+		
+		contentChanged := content != strings.TrimSpace(m.Content)
+		topicsChanged := !slices.Equal(topics, m.Topics)
+		if !contentChanged && !topicsChanged {
+			fmt.Println("No changes")
+			return nil
+		}
+		
+		if contentChanged {
+			newSummary, err := summary.ForType(sum, content, m.Type)
+			if err != nil {
+				newSummary = summary.Truncate(content, summary.DefaultMaxLen)
+			}
+			if err := s.UpdateMemoryContent(id, content, newSummary, "cli"); err != nil {
+				return fmt.Errorf("failed to update memory: %w", err)
+			}
+			
+			// Regenerate embedding for the new content (best effort)
+			embedder := embedding.NewOllamaEmbedder("", "nomic-embed-text")
+			if emb, err := embedder.Embed(content); err == nil && embedding.Valid(emb) {
+				if err := s.UpdateMemoryEmbedding(id, emb, embedding.ModelName(embedder)); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: Failed to store embedding: %v\n", err)
+				}
+			}
+		}
+		if topicsChanged {
+			if err := s.UpdateMemoryTopics(id, topics); err != nil {
+				return fmt.Errorf("failed to update topics: %w", err)
+			}
+			// Show the topics as stored, aliases resolved
+			if updated, err := s.GetMemory(id); err == nil && updated != nil {
+				topics = updated.Topics
+			}
+		}
+		
+		fmt.Printf("✅ Updated %s\n", id)
+		if contentChanged {
+			fmt.Printf("   %s\n", content)
+		}
+		if topicsChanged && len(topics) > 0 {
+			fmt.Printf("   Topics: %s\n", strings.Join(topics, ", "))
+		} else if topicsChanged {
+			fmt.Println("   Topics: (none)")
+		}
+		
+		return nil
+	},
+}
+
+func init() {
+	editCmd.Flags().String("summarizer", "truncate", "Summary generation backend for the edited content (truncate|ollama)")
+}
+
+// editorCommand returns the command that edits memories: $VISUAL, then
+// $EDITOR, then vi. It may carry arguments, e.g. "code --wait".
+func editorCommand() string {
+	if editor := strings.TrimSpace(os.Getenv("VISUAL")); editor != "" {
+		return editor
+	}
+	if editor := strings.TrimSpace(os.Getenv("EDITOR")); editor != "" {
+		return editor
+	}
+	return "vi"
+}
+
+// formatEditFile lays out a memory for editing: a frontmatter block with
+// its topics, then its content
+func formatEditFile(content string, topics []string) string {
+	return "---\ntopics: " + strings.Join(topics, ", ") + "\n---\n" + content + "\n"
+}
+
+// parseEditFile reads back a file written by formatEditFile. The
+// frontmatter may hold blank lines, # comments and a topics line, listed
+// comma separated or as [a, b].
+func parseEditFile(text string) (content string, topics []string, err error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	rest, ok := strings.CutPrefix(text, "---\n")
+	if !ok {
+		return "", nil, fmt.Errorf("the file must start with a --- frontmatter line")
+	}
+	front, body, ok := strings.Cut(rest, "\n---\n")
+	if !ok {
+		// The closing line may end the file
+		if front, ok = strings.CutSuffix(strings.TrimRight(rest, "\n"), "\n---"); !ok {
+			return "", nil, fmt.Errorf("the frontmatter isn't closed by a --- line")
+		}
+	}
+
+	topics = []string{}
+	for i, line := range strings.Split(front, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return "", nil, fmt.Errorf("frontmatter line %d: expected key: value, got %q", i+1, line)
+		}
+		switch key = strings.TrimSpace(key); key {
+		case "topics":
+			value = strings.TrimSpace(value)
+			if strings.HasPrefix(value, "[") != strings.HasSuffix(value, "]") {
+				return "", nil, fmt.Errorf("frontmatter line %d: unbalanced brackets in topics", i+1)
+			}
+			value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+			for _, topic := range strings.Split(value, ",") {
+				topic = strings.Trim(strings.TrimSpace(topic), `"'`)
+				if topic != "" && !slices.Contains(topics, topic) {
+					topics = append(topics, topic)
+				}
+			}
+		default:
+			return "", nil, fmt.Errorf("frontmatter line %d: unknown field %q (only topics can be edited)", i+1, key)
+		}
+	}
+
+	return strings.TrimSpace(body), topics, nil
+}
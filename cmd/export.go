@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/internal/export"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export memories to a JSON file",
+	Long: `Export memories as JSON, optionally only a subset. The file records the
+filters used, so a shared slice of memory says where it came from.
+
+--since exports only memories created or updated since then, plus the IDs
+of memories deleted since, for cheap incremental sync with 'memorypilot
+import'. It accepts a date (2006-01-02), an RFC 3339 time or a duration
+back from now such as 72h or 30d. --project accepts a project ID, name or path.
+With --limit, the most recent matching memories are exported.
+
+Examples:
+  memorypilot export -o memories.json
+  memorypilot export --type decision --topic auth --since 30d -o auth.json
+  memorypilot export --project webapp --limit 100 --no-embeddings`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		// Open store
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		types, _ := cmd.Flags().GetStringSlice("type")
+		topics, _ := cmd.Flags().GetStringSlice("topic")
+		projectRef, _ := cmd.Flags().GetString("project")
+		since, _ := cmd.Flags().GetString("since")
+		limit, _ := cmd.Flags().GetInt("limit")
+		noEmbeddings, _ := cmd.Flags().GetBool("no-embeddings")
+		output, _ := cmd.Flags().GetString("output")
+		
+		opts := store.ListOptions{
+			Topics:            topics,
+			Limit:             limit,
+			IncludeEmbeddings: !noEmbeddings,
+		}
+		filter := export.Filter{
+			Types:      types,
+			Topics:     topics,
+			Limit:      limit,
+			Embeddings: !noEmbeddings,
+		}
+		for _, t := range types {
+			opts.Types = append(opts.Types, models.MemoryType(t))
+		}
+		
+		if since != "" {
+			t, err := parseSince(since, time.Now())
+			if err != nil {
+				return err
+			}
+			opts.Since = t
+			filter.Since = &t
+		}
+		
+		if projectRef != "" {
+			ref := projectRef
+			if abs, err := filepath.Abs(projectRef); err == nil {
+				if _, err := os.Stat(abs); err == nil {
+					ref = abs
+				}
+			}
+			project, err := s.FindProject(ref)
+			if err != nil {
+				return fmt.Errorf("failed to look up project: %w", err)
+			}
+			if project == nil {
+				return fmt.Errorf("project %q not found", projectRef)
+			}
+			opts.ProjectID = project.ID
+			filter.Project = project.Name
+		}
+		
+		memories, err := s.ListMemories(opts)
+		if err != nil {
+			return fmt.Errorf("failed to list memories: %w", err)
+		}
+		
+		env := export.New(memories, filter)
+		if !opts.Since.IsZero() {
+			env.Deleted, err = s.ListTombstones(opts.Since)
+			if err != nil {
+				return fmt.Errorf("failed to list deletions: %w", err)
+			}
+		}
+		
+		out := os.Stdout
+		if output != "" && output != "-" {
+			f, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", output, err)
+			}
+			defer f.Close()
+			out = f
+		}
+		
+		if err := export.Write(out, env); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+		
+		if out != os.Stdout {
+			fmt.Printf("✅ Exported %d memories to %s", len(memories), output)
+			if len(env.Deleted) > 0 {
+				fmt.Printf(" (%d deletions)", len(env.Deleted))
+			}
+			fmt.Println()
+		}
+		
+		return nil
+	},
+}
+
+// parseSince parses a date, an RFC 3339 time, or a duration back from now
+// (Go syntax such as 72h, or whole days such as 30d)
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q (expected a date like 2006-01-02, an RFC 3339 time, or a duration like 72h or 30d)", value)
+}
+
+func init() {
+	exportCmd.Flags().StringSliceP("type", "t", []string{}, "Only export these memory types (decision|pattern|fact|preference|mistake|learning)")
+	exportCmd.Flags().StringSlice("topic", []string{}, "Only export memories tagged with any of these topics")
+	exportCmd.Flags().String("project", "", "Only export memories of this project (ID, name or path)")
+	exportCmd.Flags().String("since", "", "Only export memories created or updated (and deletions made) since this date or duration ago")
+	exportCmd.Flags().IntP("limit", "l", 0, "Export at most this many of the most recent matches (0 for all)")
+	exportCmd.Flags().Bool("no-embeddings", false, "Leave embeddings out to shrink the file")
+	exportCmd.Flags().StringP("output", "o", "", "Write to this file instead of stdout")
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+	"github.com/contextpilot-dev/memorypilot/internal/highlight"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// federationFile lists the databases 'recall --federated' searches besides
+// the default one, in the config directory
+const federationFile = "federation.json"
+
+// federationConfig is the content of federation.json
+type federationConfig struct {
+	Stores []string `json:"stores"` // Database paths; a leading ~ is the home directory
+}
+
+// loadFederation returns the database paths configured for federated
+// recall, or none if federation.json doesn't exist
+func loadFederation() ([]string, error) {
+	path := filepath.Join(getPaths().Config, federationFile)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cfg federationConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+
+	home, _ := os.UserHomeDir()
+	stores := make([]string, len(cfg.Stores))
+	for i, s := range cfg.Stores {
+		if s == "~" || strings.HasPrefix(s, "~/") {
+			s = filepath.Join(home, s[1:])
+		}
+		stores[i] = s
+	}
+	return stores, nil
+}
+
+// federatedRecall searches the open default store s together with other
+// databases: those in federation.json if useConfig is set, and extra.
+// Results are merged best first and tagged with the database they came
+// from; databases that can't be opened or searched are reported and
+// skipped.
+func federatedRecall(cmd *cobra.Command, s *store.Store, dbPath string, req models.RecallRequest, embedText string, semantic, useConfig bool, extra []string) error {
+	var paths []string
+	if useConfig {
+		configured, err := loadFederation()
+		if err != nil {
+			return err
+		}
+		paths = configured
+	}
+	paths = append(paths, extra...)
+
+	f := store.NewFederation()
+	defer f.Close()
+	f.Add(dbPath, s)
+	seen := map[string]bool{filepath.Clean(dbPath): true}
+	for _, path := range paths {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		f.Open(path)
+	}
+
+	var queryEmb []float32
+	if semantic {
+		embedder := embedding.NewOllamaEmbedder("", "nomic-embed-text")
+		emb, err := embedder.Embed(embedText)
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "Warning: Semantic search unavailable (%v), falling back to keyword search\n", err)
+		case !embedding.Valid(emb):
+			fmt.Fprintf(os.Stderr, "Warning: Embedding backend returned an empty or zero vector, falling back to keyword search\n")
+		default:
+			queryEmb = emb
+		}
+	}
+
+	results := f.Search(context.Background(), req, queryEmb)
+	for _, u := range f.Unavailable {
+		fmt.Fprintf(os.Stderr, "Warning: Skipped %s: %s\n", u.Store, u.Reason)
+	}
+
+	if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+		data, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("🔍 No memories found in %d databases for: %q\n", f.Len(), req.Query)
+		return nil
+	}
+
+	fmt.Printf("🧠 Found %d memories in %d databases for: %q\n\n", len(results), f.Len(), req.Query)
+
+	var h *highlight.Highlighter
+	if doHighlight, _ := cmd.Flags().GetBool("highlight"); doHighlight && isTerminal(os.Stdout) {
+		h = highlight.New(req.Query, false, highlight.ANSI)
+	}
+
+	home, _ := os.UserHomeDir()
+	for i, r := range results {
+		printRecallResult(r.Memory, h)
+		source := r.Store
+		if home != "" && strings.HasPrefix(source, home+string(filepath.Separator)) {
+			source = "~" + source[len(home):]
+		}
+		fmt.Printf("   🗄️  %s (score %.2f, %.3f in its database)\n", source, r.Score, r.StoreScore)
+		if i < len(results)-1 {
+			fmt.Println()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var healthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Check store and embedder readiness",
+	Long: `Check that the memory store opens and the embedder responds.
+
+Prints a one-line status and exits 0 when healthy, 1 otherwise.
+Intended for container liveness/readiness probes.
+
+Examples:
+  memorypilot health
+  memorypilot health --timeout 2s
+  memorypilot health --skip-embedder`,
+	Run: func(cmd *cobra.Command, args []string) {
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		skipEmbedder, _ := cmd.Flags().GetBool("skip-embedder")
+		
+		dbPath := getPaths().Database()
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			unhealthy("store not initialized")
+		}
+		
+		s, err := store.New(dbPath)
+		if err != nil {
+			unhealthy(fmt.Sprintf("store: %v", err))
+		}
+		defer s.Close()
+		
+		if _, err := s.GetStats(); err != nil {
+			unhealthy(fmt.Sprintf("store: %v", err))
+		}
+		
+		if skipEmbedder {
+			fmt.Println("ok: store ready (embedder skipped)")
+			return
+		}
+		
+		done := make(chan error, 1)
+		go func() {
+			embedder := embedding.NewOllamaEmbedder("", "nomic-embed-text")
+			_, err := embedder.Embed("health")
+			done <- err
+		}()
+		
+		select {
+		case err := <-done:
+			if err != nil {
+				unhealthy(fmt.Sprintf("embedder: %v", err))
+			}
+		case <-time.After(timeout):
+			unhealthy(fmt.Sprintf("embedder: no response within %s", timeout))
+		}
+		
+		fmt.Println("ok: store and embedder ready")
+	},
+}
+
+// unhealthy prints a one-line failure status and exits non-zero
+func unhealthy(reason string) {
+	fmt.Printf("unhealthy: %s\n", reason)
+	os.Exit(1)
+}
+
+func init() {
+	healthCmd.Flags().Duration("timeout", 5*time.Second, "Maximum time to wait for the embedder")
+	healthCmd.Flags().Bool("skip-embedder", false, "Only check the store")
+}
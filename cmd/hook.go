@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/contextpilot-dev/memorypilot/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var shellHookCmd = &cobra.Command{
+	Use:   "shell-hook [bash|zsh|fish]",
+	Short: "Print a shell hook that reports commands and exit codes to the daemon",
+	Long: `Print a hook for your shell that reports each finished command and its
+exit code to the daemon, so failed commands can be remembered as mistakes
+worth learning from.
+
+Capture is opt-in: start the daemon with --capture-commands, then add the
+hook to your shell. While it is on, commands are taken from the hook
+rather than from shell history. Trivial commands (ls, cd, ...), commands
+that may carry secrets, typos (exit code 127) and commands interrupted
+with Ctrl+C are skipped.
+
+Bash (~/.bashrc):
+  eval "$(memorypilot shell-hook bash)"
+
+Zsh (~/.zshrc):
+  eval "$(memorypilot shell-hook zsh)"
+
+Fish (~/.config/fish/config.fish):
+  memorypilot shell-hook fish | source`,
+	ValidArgs:             []string{"bash", "zsh", "fish"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to get executable path: %w", err)
+		}
+
+		var hook string
+		switch args[0] {
+		case "bash":
+			hook = strings.ReplaceAll(bashHook, "MEMORYPILOT", shellQuote(exe))
+		case "zsh":
+			hook = strings.ReplaceAll(zshHook, "MEMORYPILOT", shellQuote(exe))
+		default:
+			hook = strings.ReplaceAll(fishHook, "MEMORYPILOT", fishQuote(exe))
+		}
+		fmt.Fprint(cmd.OutOrStdout(), hook)
+		return nil
+	},
+}
+
+// maxReportedCommand bounds the command read from stdin
+const maxReportedCommand = 64 << 10
+
+// reportCommandCmd is run by the shell hooks after every command. It stays
+// silent and succeeds even when the daemon can't be reached, so it never
+// disturbs the prompt. The hooks pass the command on stdin ("-"), since
+// arguments are visible to every local user in ps.
+var reportCommandCmd = &cobra.Command{
+	Use:    "report-command [flags] -- <command>|-",
+	Short:  "Report a finished terminal command to the daemon",
+	Hidden: true,
+	Args:   cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		exitCode, _ := cmd.Flags().GetInt("exit-code")
+		dir, _ := cmd.Flags().GetString("dir")
+		if dir == "" {
+			dir, _ = os.Getwd()
+		}
+		command := strings.Join(args, " ")
+		if command == "-" {
+			data, err := io.ReadAll(io.LimitReader(cmd.InOrStdin(), maxReportedCommand))
+			if err != nil {
+				return nil
+			}
+			command = string(data)
+		}
+
+		err := ipc.ReportCommand(getSocketPath(), ipc.CommandReport{
+			Command:  command,
+			ExitCode: exitCode,
+			Dir:      dir,
+		})
+		if verbose, _ := cmd.Flags().GetBool("verbose"); verbose && err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		return nil
+	},
+}
+
+// The hooks run report-command in the background so the prompt doesn't
+// wait for it, piping the command through the printf builtin so it never
+// appears in a process's arguments. MEMORYPILOT is replaced by the quoted
+// executable path.
+const (
+	bashHook = `# MemoryPilot: report finished commands and their exit codes
+_memorypilot_report() {
+  local code=$? num cmd
+  read -r num cmd <<< "$(HISTTIMEFORMAT= history 1)"
+  if [[ -n $cmd && $num != "$_memorypilot_last" ]]; then
+    _memorypilot_last=$num
+    (printf '%s' "$cmd" | MEMORYPILOT report-command --exit-code "$code" --dir "$PWD" - >/dev/null 2>&1 &)
+  fi
+  return $code
+}
+if [[ $PROMPT_COMMAND != *_memorypilot_report* ]]; then
+  PROMPT_COMMAND="_memorypilot_report${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+fi
+`
+
+	zshHook = `# MemoryPilot: report finished commands and their exit codes
+_memorypilot_preexec() { _memorypilot_cmd=$1 }
+_memorypilot_precmd() {
+  local code=$?
+  [[ -n $_memorypilot_cmd ]] || return
+  (printf '%s' "$_memorypilot_cmd" | MEMORYPILOT report-command --exit-code "$code" --dir "$PWD" - >/dev/null 2>&1 &)
+  unset _memorypilot_cmd
+}
+autoload -Uz add-zsh-hook
+add-zsh-hook preexec _memorypilot_preexec
+add-zsh-hook precmd _memorypilot_precmd
+`
+
+	fishHook = `# MemoryPilot: report finished commands and their exit codes
+function _memorypilot_report --on-event fish_postexec
+    set -l code $status
+    test -n "$argv[1]"; or return
+    printf '%s' "$argv[1]" | MEMORYPILOT report-command --exit-code $code --dir "$PWD" - >/dev/null 2>&1 &
+    disown 2>/dev/null
+end
+`
+)
+
+// shellQuote quotes s for bash and zsh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote quotes s for fish
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+func init() {
+	reportCommandCmd.Flags().Int("exit-code", 0, "Exit code of the command")
+	reportCommandCmd.Flags().String("dir", "", "Directory the command ran in (default: the current directory)")
+	reportCommandCmd.Flags().Bool("verbose", false, "Print errors, e.g. when the daemon isn't running")
+}
@@ -0,0 +1,425 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/internal/export"
+	"github.com/contextpilot-dev/memorypilot/internal/ids"
+	"github.com/contextpilot-dev/memorypilot/internal/markdown"
+	"github.com/contextpilot-dev/memorypilot/internal/shellhistory"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/internal/summary"
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file|dir>",
+	Short: "Import memories from an export file, Markdown notes or shell history",
+	Long: `Import memories written by 'memorypilot export'. Use - to read from stdin.
+
+Memories are upserted by ID. --on-conflict decides what happens to a
+memory that already exists:
+  newest-wins  keep whichever copy was updated more recently (default)
+  skip         keep the local copy
+  overwrite    replace the local copy
+  merge        take the newer content, union topics, metadata and links,
+               and keep the higher importance
+
+Embeddings from a different model than this store's (for example
+nomic-embed-text vs. OpenAI) can't be compared and are reported with a
+warning. Pass --drop-incompatible-embeddings to import those memories
+without their embedding; 'memorypilot reindex' then regenerates them.
+
+Deletions recorded in an incremental export (--since) are applied unless
+the local memory was updated after the deletion, so periodic export/import
+converges.
+
+With --format markdown, the argument is a Markdown file or a directory
+searched recursively for .md files. Each file is split at its headings into
+one memory per section: the heading becomes the summary, frontmatter tags
+(or topics) become topics, and the source is the file path. Importing the
+same notes again updates the memory of each file and heading instead of
+adding a duplicate.
+
+With --format shell-history, commands from a zsh, bash or fish history file
+(--file or the argument; $HISTFILE, ~/.zsh_history, ~/.bash_history or the
+fish history by default) become pattern memories with content type command.
+Trivial commands (ls, cd, bare 'git status', ...) and commands that may
+contain credentials are skipped, repeated commands are stored once, and
+commands already in the store are not added again. Narrow the import with
+--prefix (repeatable) or --match <regex>.
+
+Examples:
+  memorypilot import memories.json
+  memorypilot import --on-conflict merge laptop.json
+  memorypilot export --since 24h | ssh laptop memorypilot import -
+  memorypilot import --format markdown ~/notes
+  memorypilot import --format shell-history --prefix kubectl --prefix docker
+  memorypilot import --format shell-history --file ~/.bash_history --match 'terraform (plan|apply)'`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		format, _ := cmd.Flags().GetString("format")
+		memoryType, _ := cmd.Flags().GetString("type")
+		switch format {
+		case "json":
+			if len(args) == 0 {
+				return fmt.Errorf("missing file to import (use - for stdin)")
+			}
+		case "markdown", "md":
+			if len(args) == 0 {
+				return fmt.Errorf("missing Markdown file or directory to import")
+			}
+			if memoryType == "" {
+				memoryType = string(models.MemoryTypeFact)
+			}
+			s, err := store.New(dbPath)
+			if err != nil {
+				return fmt.Errorf("failed to open store: %w", err)
+			}
+			defer s.Close()
+			return importMarkdown(s, args[0], models.MemoryType(memoryType))
+		case "shell-history":
+			file, _ := cmd.Flags().GetString("file")
+			if len(args) > 0 {
+				file = args[0]
+			}
+			prefixes, _ := cmd.Flags().GetStringSlice("prefix")
+			match, _ := cmd.Flags().GetString("match")
+			var pattern *regexp.Regexp
+			if match != "" {
+				var err error
+				if pattern, err = regexp.Compile(match); err != nil {
+					return fmt.Errorf("invalid --match pattern: %w", err)
+				}
+			}
+			if memoryType == "" {
+				memoryType = string(models.MemoryTypePattern)
+			}
+			s, err := store.New(dbPath)
+			if err != nil {
+				return fmt.Errorf("failed to open store: %w", err)
+			}
+			defer s.Close()
+			return importShellHistory(s, file, models.MemoryType(memoryType), prefixes, pattern)
+		default:
+			return fmt.Errorf("unknown format %q (expected json, markdown or shell-history)", format)
+		}
+		
+		var in io.Reader = os.Stdin
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", args[0], err)
+			}
+			defer f.Close()
+			in = f
+		}
+		
+		onConflict, _ := cmd.Flags().GetString("on-conflict")
+		strategy, err := store.ParseConflictStrategy(onConflict)
+		if err != nil {
+			return err
+		}
+		
+		env, err := export.Read(in)
+		if err != nil {
+			return err
+		}
+		
+		// Open store
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		dropIncompatible, _ := cmd.Flags().GetBool("drop-incompatible-embeddings")
+		model, err := s.EmbeddingModel()
+		if err != nil {
+			return fmt.Errorf("failed to read embedding model: %w", err)
+		}
+		dim, err := s.EmbeddingDimension()
+		if err != nil {
+			return fmt.Errorf("failed to read embedding dimension: %w", err)
+		}
+		
+		var incompatible int
+		for i := range env.Memories {
+			m := &env.Memories[i]
+			if len(m.Embedding) > 0 && m.EmbeddingModel == "" {
+				m.EmbeddingModel = env.EmbeddingModel
+			}
+			if export.CompatibleEmbedding(m, model, dim) {
+				continue
+			}
+			incompatible++
+			if dropIncompatible {
+				m.Embedding = nil
+				m.EmbeddingModel = ""
+			}
+		}
+		
+		if incompatible > 0 && !dropIncompatible {
+			fmt.Fprintf(os.Stderr, "Warning: %d imported embeddings (%s) don't match this store's (%s); "+
+				"semantic search can't compare them. Re-run with --drop-incompatible-embeddings to regenerate them instead.\n",
+				incompatible, describeEmbeddings(env.EmbeddingModel, env.EmbeddingDimension), describeEmbeddings(model, dim))
+		}
+		
+		result, err := s.ImportAll(env.Memories, env.Deleted, strategy)
+		if err != nil {
+			return fmt.Errorf("import failed: %w", err)
+		}
+		
+		fmt.Printf("✅ Imported %d memories\n", len(env.Memories))
+		fmt.Printf("   Created:  %d\n", result.Created)
+		fmt.Printf("   Updated:  %d\n", result.Updated)
+		fmt.Printf("   Merged:   %d\n", result.Merged)
+		fmt.Printf("   Skipped:  %d\n", result.Skipped)
+		if len(env.Deleted) > 0 {
+			fmt.Printf("   Deleted:  %d\n", result.Deleted)
+		}
+		if incompatible > 0 && dropIncompatible {
+			fmt.Printf("   Dropped %d incompatible embeddings; run 'memorypilot reindex' to regenerate them\n", incompatible)
+		}
+		
+		return nil
+	},
+}
+
+// describeEmbeddings names an embedding model and dimension for messages
+func describeEmbeddings(model string, dim int) string {
+	if model == "" {
+		model = "unknown model"
+	}
+	return fmt.Sprintf("%s, %d dimensions", model, dim)
+}
+
+// importMarkdown imports the sections of the Markdown files at root (a file
+// or a directory) as memories of type memType
+func importMarkdown(s *store.Store, root string, memType models.MemoryType) error {
+	if !memType.Valid() {
+		return fmt.Errorf("invalid type %q", memType)
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	
+	var files []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && (path == root || strings.EqualFold(filepath.Ext(path), ".md")) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	
+	counts := make(map[store.SectionAction]int)
+	var failed int
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			failed++
+			continue
+		}
+		title := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		doc := markdown.Parse(string(data), title)
+		
+		now := time.Now()
+		for _, section := range doc.Sections {
+			memory := &models.Memory{
+				ID:      ids.New(),
+				Type:    memType,
+				Content: section.Body,
+				Summary: section.Heading,
+				Scope:   models.MemoryScopePersonal,
+				Source: models.Source{
+					Type:      models.SourceTypeImport,
+					Reference: path,
+					Timestamp: now,
+				},
+				Confidence:     1.0,
+				Importance:     1.0,
+				Topics:         doc.Tags,
+				Metadata:       map[string]string{store.SectionMetadataKey: section.Key},
+				CreatedAt:      now,
+				LastAccessedAt: now,
+			}
+			action, err := s.UpsertSection(memory)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %s (%s): %v\n", path, section.Key, err)
+				failed++
+				continue
+			}
+			counts[action]++
+		}
+	}
+	
+	fmt.Printf("✅ Imported %d Markdown files\n", len(files))
+	fmt.Printf("   Created:   %d\n", counts[store.SectionCreated])
+	fmt.Printf("   Updated:   %d\n", counts[store.SectionUpdated])
+	fmt.Printf("   Unchanged: %d\n", counts[store.SectionUnchanged])
+	if failed > 0 {
+		fmt.Printf("   Failed:    %d\n", failed)
+	}
+	if counts[store.SectionCreated]+counts[store.SectionUpdated] > 0 {
+		fmt.Println("   Run 'memorypilot reindex' to generate embeddings for semantic search")
+	}
+	return nil
+}
+
+// importShellHistory imports the meaningful commands of a shell history file
+// (the first existing default file when file is empty) as memories of type
+// memType. Only commands starting with one of prefixes, if any, and matching
+// pattern, if set, are imported.
+func importShellHistory(s *store.Store, file string, memType models.MemoryType, prefixes []string, pattern *regexp.Regexp) error {
+	if !memType.Valid() {
+		return fmt.Errorf("invalid type %q", memType)
+	}
+	if file == "" {
+		for _, f := range shellhistory.DefaultFiles() {
+			if _, err := os.Stat(f); err == nil {
+				file = f
+				break
+			}
+		}
+		if file == "" {
+			return fmt.Errorf("no shell history found; pass --file")
+		}
+	}
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	entries, err := shellhistory.Parse(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	entries = shellhistory.Dedup(entries)
+	
+	var memories []*models.Memory
+	var trivial, filtered, existing int
+	now := time.Now()
+	for _, e := range entries {
+		if shellhistory.Trivial(e.Command) {
+			trivial++
+			continue
+		}
+		if !hasAnyPrefix(e.Command, prefixes) || (pattern != nil && !pattern.MatchString(e.Command)) {
+			filtered++
+			continue
+		}
+		if m, err := s.FindByContent(e.Command); err != nil {
+			return err
+		} else if m != nil {
+			existing++
+			continue
+		}
+		
+		created := e.Time
+		if created.IsZero() {
+			created = now
+		}
+		memories = append(memories, &models.Memory{
+			ID:          ids.New(),
+			Type:        memType,
+			Content:     e.Command,
+			Summary:     summary.Truncate(strings.ReplaceAll(e.Command, "\n", " "), summary.DefaultMaxLen),
+			ContentType: models.ContentTypeCommand,
+			Scope:       models.MemoryScopePersonal,
+			Source: models.Source{
+				Type:      models.SourceTypeImport,
+				Reference: file,
+				Timestamp: created,
+			},
+			Confidence:     1.0,
+			Importance:     0.5,
+			Metadata:       map[string]string{"uses": strconv.Itoa(e.Count)},
+			CreatedAt:      created,
+			LastAccessedAt: created,
+		})
+	}
+	
+	errs, err := s.CreateMemories(memories)
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+	var failed int
+	for i, err := range errs {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", memories[i].Content, err)
+			failed++
+		}
+	}
+	
+	fmt.Printf("✅ Imported %d commands from %s\n", len(memories)-failed, file)
+	fmt.Printf("   Trivial:         %d\n", trivial)
+	if len(prefixes) > 0 || pattern != nil {
+		fmt.Printf("   Filtered out:    %d\n", filtered)
+	}
+	fmt.Printf("   Already stored:  %d\n", existing)
+	if failed > 0 {
+		fmt.Printf("   Failed:          %d\n", failed)
+	}
+	if len(memories) > failed {
+		fmt.Println("   Run 'memorypilot reindex' to generate embeddings for semantic search")
+	}
+	return nil
+}
+
+// hasAnyPrefix reports whether s starts with one of prefixes, or prefixes is
+// empty
+func hasAnyPrefix(s string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	importCmd.Flags().String("format", "json", "Input format (json|markdown|shell-history)")
+	importCmd.Flags().StringP("type", "t", "", "Memory type for imported Markdown sections (default fact) or commands (default pattern)")
+	importCmd.Flags().String("file", "", "Shell history file (default $HISTFILE, ~/.zsh_history, ~/.bash_history or the fish history)")
+	importCmd.Flags().StringSlice("prefix", nil, "Only import commands starting with this prefix (repeatable)")
+	importCmd.Flags().String("match", "", "Only import commands matching this regular expression")
+	importCmd.Flags().Bool("drop-incompatible-embeddings", false, "Import memories whose embeddings don't match this store's model without them, for reindexing")
+	importCmd.Flags().String("on-conflict", string(store.ConflictNewestWins), "What to do with memories that already exist (skip|overwrite|newest-wins|merge)")
+}
@@ -11,22 +11,18 @@ import (
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize MemoryPilot",
-	Long: `Initialize MemoryPilot in your home directory.
+	Long: `Initialize MemoryPilot's directories, configuration and database.
 
-This creates:
-  ~/.memorypilot/config.yaml    - Configuration file
-  ~/.memorypilot/data/          - Database and embeddings
-  ~/.memorypilot/logs/          - Log files`,
+This creates a config.yaml, the database and a logs directory in the
+locations shown by 'memorypilot paths' (XDG directories on Linux,
+~/Library on macOS, %APPDATA% on Windows, or $MEMORYPILOT_HOME if set).`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		configDir := getConfigDir()
-		dataDir := getDataDir()
-		logsDir := configDir + "/logs"
+		dirs := getPaths()
 		
 		fmt.Println("🧠 Initializing MemoryPilot...")
 		
 		// Create directories
-		dirs := []string{configDir, dataDir, logsDir}
-		for _, dir := range dirs {
+		for _, dir := range []string{dirs.Config, dirs.Data, dirs.Logs, dirs.Runtime} {
 			if err := os.MkdirAll(dir, 0755); err != nil {
 				return fmt.Errorf("failed to create directory %s: %w", dir, err)
 			}
@@ -34,7 +30,7 @@ This creates:
 		fmt.Println("   ✓ Created directories")
 		
 		// Create config file if it doesn't exist
-		configPath := configDir + "/config.yaml"
+		configPath := dirs.ConfigFile()
 		if _, err := os.Stat(configPath); os.IsNotExist(err) {
 			if err := os.WriteFile(configPath, []byte(defaultConfig), 0644); err != nil {
 				return fmt.Errorf("failed to create config: %w", err)
@@ -45,8 +41,7 @@ This creates:
 		}
 		
 		// Initialize database
-		dbPath := dataDir + "/memories.db"
-		s, err := store.New(dbPath)
+		s, err := store.New(dirs.Database())
 		if err != nil {
 			return fmt.Errorf("failed to initialize database: %w", err)
 		}
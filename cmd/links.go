@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var linksCmd = &cobra.Command{
+	Use:   "links",
+	Short: "Manage links inferred from memories recalled together",
+	Long: `Memories that keep showing up in the same recall results are linked
+automatically: the daemon rebuilds these links from the access log every
+hour, and recall with include_related returns them next to manual links.
+Inferred links are stored apart from manual ones and can be rebuilt or
+pruned on their own.`,
+}
+
+var linksInferCmd = &cobra.Command{
+	Use:   "infer",
+	Short: "Rebuild inferred links from the access log now",
+	Long: `Link memories returned together by at least --threshold recalls, keeping
+each memory's --max most frequent partners. Existing inferred links are
+replaced; manual links are left alone.
+
+Examples:
+  memorypilot links infer
+  memorypilot links infer --threshold 5 --max 3`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		// Open store
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		threshold, _ := cmd.Flags().GetInt("threshold")
+		maxLinks, _ := cmd.Flags().GetInt("max")
+		n, err := s.InferLinks(threshold, maxLinks)
+		if err != nil {
+			return fmt.Errorf("failed to infer links: %w", err)
+		}
+		
+		fmt.Printf("✅ Inferred %d links between memories recalled together\n", n)
+		return nil
+	},
+}
+
+var linksPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete all inferred links",
+	Long:  `Delete every inferred link. Manual links, such as those between the chunks of a long document, are kept.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		// Open store
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		n, err := s.PruneInferredLinks()
+		if err != nil {
+			return fmt.Errorf("failed to prune links: %w", err)
+		}
+		
+		fmt.Printf("✅ Deleted %d inferred links\n", n)
+		return nil
+	},
+}
+
+func init() {
+	linksInferCmd.Flags().Int("threshold", store.DefaultLinkThreshold, "Recalls two memories must appear in together before they are linked")
+	linksInferCmd.Flags().Int("max", store.DefaultMaxInferredLinks, "Most inferred links kept per memory")
+	linksCmd.AddCommand(linksInferCmd)
+	linksCmd.AddCommand(linksPruneCmd)
+}
@@ -2,8 +2,17 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/contextpilot-dev/memorypilot/internal/chunk"
+	"github.com/contextpilot-dev/memorypilot/internal/embedding"
 	"github.com/contextpilot-dev/memorypilot/internal/mcp"
+	"github.com/contextpilot-dev/memorypilot/internal/rerank"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/internal/summary"
+	"github.com/contextpilot-dev/memorypilot/internal/tracing"
 	"github.com/spf13/cobra"
 )
 
@@ -13,17 +22,148 @@ var mcpCmd = &cobra.Command{
 	Long: `Start the Model Context Protocol server for AI tool integration.
 
 This is typically spawned by AI tools like Claude Code or OpenClaw.
-The server communicates over stdio using the MCP protocol.`,
+The server communicates over stdio using the MCP protocol.
+
+--tools and --disable-tools choose which tools are offered, by name with or
+without the memorypilot_ prefix. Other tools are left out of tools/list and
+calls to them are refused. For a server that can recall but not change
+memories:
+
+  memorypilot mcp --disable-tools remember,remember_batch,update,curate,rename_topic,feedback`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		dataDir := getDataDir()
-		dbPath := dataDir + "/memories.db"
+		dbPath := getPaths().Database()
 		
 		server, err := mcp.NewServer(dbPath)
 		if err != nil {
 			return fmt.Errorf("failed to create MCP server: %w", err)
 		}
 		
+		noAccessLog, _ := cmd.Flags().GetBool("no-access-log")
+		server.SetAccessLog(!noAccessLog)
+		
+		maxContentLength, _ := cmd.Flags().GetInt("max-content-length")
+		chunkLong, _ := cmd.Flags().GetBool("chunk")
+		server.SetContentLimit(maxContentLength, chunkLong)
+		
+		maxLimit, _ := cmd.Flags().GetInt("max-limit")
+		server.SetMaxLimit(maxLimit)
+		
+		maxMessageSize, _ := cmd.Flags().GetInt("max-message-size")
+		server.SetMaxMessageSize(maxMessageSize)
+		
+		maxResponseSize, _ := cmd.Flags().GetInt("max-response-size")
+		server.SetMaxResponseSize(maxResponseSize)
+		
+		embedTimeout, _ := cmd.Flags().GetDuration("embed-timeout")
+		server.SetEmbedTimeout(embedTimeout)
+		
+		cacheSize, _ := cmd.Flags().GetInt("recall-cache-size")
+		cacheTTL, _ := cmd.Flags().GetDuration("recall-cache-ttl")
+		server.SetRecallCache(cacheSize, cacheTTL)
+		
+		lifetimes, err := loadLifetimes()
+		if err != nil {
+			return err
+		}
+		server.SetLifetimes(lifetimes)
+		
+		warnSimilar, _ := cmd.Flags().GetFloat32("warn-similar")
+		if warnSimilar < 0 || warnSimilar > 1 {
+			return fmt.Errorf("invalid --warn-similar %g (expected 0 to disable, or a similarity up to 1)", warnSimilar)
+		}
+		server.SetSimilarWarning(warnSimilar)
+		
+		allowClear, _ := cmd.Flags().GetBool("allow-clear")
+		server.SetAllowClear(allowClear)
+		
+		enabledTools, _ := cmd.Flags().GetStringSlice("tools")
+		disabledTools, _ := cmd.Flags().GetStringSlice("disable-tools")
+		if err := server.SetToolAccess(enabledTools, disabledTools); err != nil {
+			return fmt.Errorf("invalid --tools or --disable-tools: %w", err)
+		}
+		
+		toolTimeout, _ := cmd.Flags().GetDuration("tool-timeout")
+		server.SetToolTimeout(toolTimeout)
+		toolTimeouts, _ := cmd.Flags().GetStringToString("tool-timeouts")
+		timeouts := make(map[string]time.Duration, len(toolTimeouts))
+		for tool, value := range toolTimeouts {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid timeout %q for %s: %w", value, tool, err)
+			}
+			timeouts[tool] = d
+		}
+		if err := server.SetToolTimeouts(timeouts); err != nil {
+			return fmt.Errorf("invalid --tool-timeouts: %w", err)
+		}
+		
+		structured, _ := cmd.Flags().GetBool("structured")
+		server.SetStructured(structured)
+		
+		sum, err := newSummarizer(cmd)
+		if err != nil {
+			return err
+		}
+		server.SetSummarizer(sum)
+		
+		recallFormat, _ := cmd.Flags().GetString("recall-format")
+		if strings.HasPrefix(recallFormat, "@") {
+			data, err := os.ReadFile(recallFormat[1:])
+			if err != nil {
+				return fmt.Errorf("failed to read recall template: %w", err)
+			}
+			recallFormat = string(data)
+		}
+		if err := server.SetRecallFormat(recallFormat); err != nil {
+			return err
+		}
+		
+		if rerankModel, _ := cmd.Flags().GetString("rerank"); rerankModel != "" {
+			server.SetReranker(rerank.NewOllamaReranker("", rerankModel))
+		}
+		
+		if notify, _ := cmd.Flags().GetBool("notify"); notify {
+			threshold, _ := cmd.Flags().GetFloat32("notify-threshold")
+			interval, _ := cmd.Flags().GetDuration("notify-interval")
+			server.EnableNotifications(getSocketPath(), threshold, interval)
+		}
+		
+		if metricsAddr, _ := cmd.Flags().GetString("metrics"); metricsAddr != "" {
+			server.EnableMetrics(metricsAddr)
+		}
+		
+		// Export tracing spans if an OTLP endpoint is configured
+		shutdownTracing := tracing.Init("memorypilot")
+		defer shutdownTracing()
+		
 		// Run the server (blocks until stdin closes)
 		return server.Run()
 	},
 }
+
+func init() {
+	mcpCmd.Flags().Bool("structured", false, "Always include structured JSON content in tool results")
+	mcpCmd.Flags().String("summarizer", "truncate", "Summary generation backend (truncate|ollama)")
+	mcpCmd.Flags().Int("summary-length", summary.DefaultMaxLen, "Maximum summary length in characters of types without their own in summaries.json")
+	mcpCmd.Flags().String("rerank", "", "Rerank hybrid recall candidates with this Ollama model (e.g. llama3.2); disabled if empty")
+	mcpCmd.Flags().Int("max-content-length", chunk.DefaultMaxContentLength, "Longest memory content accepted, in characters (0 for no limit)")
+	mcpCmd.Flags().Bool("chunk", false, "Split content over --max-content-length into linked chunk memories instead of rejecting it")
+	mcpCmd.Flags().Int("max-limit", mcp.DefaultMaxLimit, "Most results a single recall returns; larger requested limits are clamped")
+	mcpCmd.Flags().Int("max-message-size", mcp.DefaultMaxMessageSize, "Largest JSON-RPC message accepted, in bytes; larger ones are rejected with an error")
+	mcpCmd.Flags().Int("max-response-size", mcp.DefaultMaxResponseSize, "Longest tool result text, in bytes; the rest is fetched with memorypilot_continue (0 for no limit)")
+	mcpCmd.Flags().Duration("tool-timeout", mcp.DefaultToolTimeout, "How long a tool call that only reads may run before it is answered with a timeout error")
+	mcpCmd.Flags().StringToString("tool-timeouts", nil, "Timeouts of individual read-only tools, e.g. recall=5s,similar=10s")
+	mcpCmd.Flags().Duration("embed-timeout", embedding.DefaultQueryTimeout, "How long recall waits for the query embedding before falling back to keyword search")
+	mcpCmd.Flags().Int("recall-cache-size", store.DefaultRecallCacheSize, "Recall results kept for repeated identical queries (0 disables the cache)")
+	mcpCmd.Flags().Duration("recall-cache-ttl", store.DefaultRecallCacheTTL, "How long cached recall results are reused")
+	mcpCmd.Flags().Float32("warn-similar", 0, "Warn in memorypilot_remember results when a new memory is at least this similar (0-1) to an existing one (0 disables the check)")
+	mcpCmd.Flags().Bool("allow-clear", false, "Expose the memorypilot_clear tool, which lets the client delete all memories")
+	mcpCmd.Flags().StringSlice("tools", nil, "Only offer these tools, e.g. recall,get,status for a read-only server (default all)")
+	mcpCmd.Flags().StringSlice("disable-tools", nil, "Don't offer these tools, e.g. remember,remember_batch,update")
+	mcpCmd.Flags().Bool("no-access-log", false, "Don't record recalled memories and queries in the access log")
+	mcpCmd.Flags().Bool("notify", false, "Push notifications/memorypilot/new when the daemon captures a memory close to a recent recall query")
+	mcpCmd.Flags().Float32("notify-threshold", mcp.DefaultNotifyThreshold, "Similarity (0-1) to a recent query a new memory needs to be pushed")
+	mcpCmd.Flags().Duration("notify-interval", mcp.DefaultNotifyInterval, "Minimum time between pushed notifications")
+	mcpCmd.Flags().String("metrics", "", "Serve Prometheus metrics at /metrics on this address (e.g. :9100)")
+	mcpCmd.Flags().String("recall-format", "detailed", "Recall output format: compact|detailed|markdown, a Go template, or @file with a template")
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the database schema",
+	Long: `Apply pending database schema migrations, or list them with --status.
+
+Migrations also run automatically whenever the database is opened. Each
+step runs in its own transaction, so a failing step leaves the database as
+it was before that step and is reported by number and name.
+
+Examples:
+  memorypilot migrate
+  memorypilot migrate --status`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		statusOnly, _ := cmd.Flags().GetBool("status")
+		
+		if !statusOnly {
+			applied, err := store.Migrate(dbPath)
+			if err != nil {
+				return err
+			}
+			if !jsonOutput {
+				if len(applied) == 0 {
+					fmt.Printf("✅ Database is up to date (schema version %d)\n", store.LatestSchemaVersion())
+				} else {
+					fmt.Printf("✅ Applied %d migrations:\n", len(applied))
+					for _, m := range applied {
+						fmt.Printf("   %3d  %s\n", m.Version, m.Name)
+					}
+				}
+				return nil
+			}
+		}
+		
+		infos, err := store.MigrationStatus(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to read migration status: %w", err)
+		}
+		
+		if jsonOutput {
+			data, _ := json.MarshalIndent(infos, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		
+		fmt.Println("🗄️  Schema migrations:")
+		for _, m := range infos {
+			if m.AppliedAt != nil {
+				fmt.Printf("   ✅ %3d  %-20s applied %s\n", m.Version, m.Name, m.AppliedAt.Format("2006-01-02 15:04"))
+			} else {
+				fmt.Printf("   ⏳ %3d  %-20s pending\n", m.Version, m.Name)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	migrateCmd.Flags().Bool("status", false, "List migrations without applying any")
+	migrateCmd.Flags().Bool("json", false, "Output as JSON")
+}
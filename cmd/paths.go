@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/contextpilot-dev/memorypilot/internal/paths"
+	"github.com/spf13/cobra"
+)
+
+var pathsCmd = &cobra.Command{
+	Use:   "paths",
+	Short: "Show where MemoryPilot keeps its files",
+	Long: `Show the resolved configuration, data, log and runtime locations.
+
+Locations are chosen in this order:
+  1. $MEMORYPILOT_HOME, if set (laid out like ~/.memorypilot)
+  2. ~/.memorypilot, if it already exists
+  3. The platform's conventions:
+       Linux:   $XDG_CONFIG_HOME/memorypilot, $XDG_DATA_HOME/memorypilot,
+                $XDG_STATE_HOME/memorypilot/logs, $XDG_RUNTIME_DIR/memorypilot
+       macOS:   ~/Library/Application Support/MemoryPilot, ~/Library/Logs/MemoryPilot
+       Windows: %APPDATA%\MemoryPilot, %LOCALAPPDATA%\MemoryPilot`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := paths.Resolve()
+		if err != nil {
+			return err
+		}
+
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		if jsonOutput {
+			data, _ := json.MarshalIndent(map[string]string{
+				"config":    p.Config,
+				"data":      p.Data,
+				"logs":      p.Logs,
+				"runtime":   p.Runtime,
+				"database":  p.Database(),
+				"snapshots": p.Snapshots(),
+				"pidFile":   p.PidFile(),
+				"socket":    p.Socket(),
+				"source":    p.Source,
+			}, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Config:    %s\n", p.Config)
+		fmt.Printf("Data:      %s\n", p.Data)
+		fmt.Printf("Database:  %s\n", p.Database())
+		fmt.Printf("Snapshots: %s\n", p.Snapshots())
+		fmt.Printf("Logs:      %s\n", p.Logs)
+		fmt.Printf("PID file:  %s\n", p.PidFile())
+		fmt.Printf("Socket:    %s\n", p.Socket())
+		fmt.Printf("\nResolved from: %s\n", describeSource(p.Source))
+		return nil
+	},
+}
+
+// describeSource explains how the locations were chosen
+func describeSource(source string) string {
+	switch source {
+	case "env":
+		return "$" + paths.HomeEnv
+	case "legacy":
+		return "existing ~/.memorypilot directory"
+	default:
+		return "platform conventions"
+	}
+}
+
+func init() {
+	pathsCmd.Flags().Bool("json", false, "Output as JSON")
+}
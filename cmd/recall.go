@@ -1,12 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+	"github.com/contextpilot-dev/memorypilot/internal/highlight"
+	querylang "github.com/contextpilot-dev/memorypilot/internal/query"
 	"github.com/contextpilot-dev/memorypilot/internal/store"
 	"github.com/contextpilot-dev/memorypilot/pkg/models"
 	"github.com/spf13/cobra"
@@ -20,13 +24,20 @@ var recallCmd = &cobra.Command{
 Examples:
   memorypilot recall "authentication patterns"
   memorypilot recall "how did we handle rate limiting"
-  memorypilot recall --type decision "database choice"`,
-	Args: cobra.MinimumNArgs(1),
+  memorypilot recall --type decision "database choice"
+  memorypilot recall --meta ticket=PROJ-123 "rollout plan"
+  memorypilot recall --filter 'type:decision topic:auth since:2024-01 importance:>0.5 "session tokens"'
+  memorypilot recall --store ~/work/api/memories.db "deploy steps"`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if filter, _ := cmd.Flags().GetString("filter"); filter == "" {
+			return cobra.MinimumNArgs(1)(cmd, args)
+		}
+		return nil
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		query := strings.Join(args, " ")
 		
-		dataDir := getDataDir()
-		dbPath := dataDir + "/memories.db"
+		dbPath := getPaths().Database()
 		
 		// Check if database exists
 		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
@@ -42,51 +53,138 @@ Examples:
 		}
 		defer s.Close()
 		
+		noAccessLog, _ := cmd.Flags().GetBool("no-access-log")
+		s.SetAccessLog(!noAccessLog)
+		
 		// Build recall request
 		limit, _ := cmd.Flags().GetInt("limit")
 		typeFilter, _ := cmd.Flags().GetString("type")
 		scopeFilter, _ := cmd.Flags().GetStringSlice("scope")
 		semantic, _ := cmd.Flags().GetBool("semantic")
 		
+		sourceFilter, _ := cmd.Flags().GetStringSlice("source")
+		topicFilter, _ := cmd.Flags().GetStringSlice("topic")
+		excludeTopics, _ := cmd.Flags().GetStringSlice("exclude-topic")
+		metaFilter, _ := cmd.Flags().GetStringToString("meta")
+		if err := store.ValidateMetadata(metaFilter); err != nil {
+			return err
+		}
+		
+		req := models.RecallRequest{
+			Query:         query,
+			Limit:         limit,
+			Topics:        topicFilter,
+			ExcludeTopics: excludeTopics,
+			Metadata:      metaFilter,
+		}
+		
+		// Filters and search terms written in the query language
+		if filter, _ := cmd.Flags().GetString("filter"); filter != "" {
+			if err := querylang.Apply(&req, filter); err != nil {
+				return fmt.Errorf("invalid --filter: %w", err)
+			}
+			if err := store.ValidateMetadata(req.Metadata); err != nil {
+				return fmt.Errorf("invalid --filter: %w", err)
+			}
+			query = req.Query
+		}
+		// Without search terms, filtered memories are listed by importance
+		if query == "" {
+			semantic = false
+		}
+		req.Explain, _ = cmd.Flags().GetBool("explain")
+		req.IncludeLow, _ = cmd.Flags().GetBool("include-low")
+		req.Diversity, _ = cmd.Flags().GetFloat64("diversity")
+		if req.Diversity < 0 || req.Diversity > 1 {
+			return fmt.Errorf("--diversity must be between 0 and 1")
+		}
+		
+		if typeFilter != "" {
+			req.Types = append(req.Types, models.MemoryType(typeFilter))
+		}
+		
+		for _, sc := range scopeFilter {
+			req.Scope = append(req.Scope, models.MemoryScope(sc))
+		}
+		
+		for _, src := range sourceFilter {
+			req.SourceTypes = append(req.SourceTypes, models.SourceType(src))
+		}
+		
+		contentTypeFilter, _ := cmd.Flags().GetStringSlice("content-type")
+		for _, t := range contentTypeFilter {
+			req.ContentTypes = append(req.ContentTypes, models.ContentType(t))
+		}
+		
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		embedText := query
+		if expand, _ := cmd.Flags().GetBool("expand"); expand {
+			terms, err := s.ExpandQuery(query)
+			if err != nil {
+				return fmt.Errorf("query expansion failed: %w", err)
+			}
+			req.Expand = terms
+			if len(terms) > 0 {
+				embedText += " " + strings.Join(terms, " ")
+			}
+			if verbose {
+				fmt.Fprintf(os.Stderr, "🔎 Expanded query with: %s\n", orNone(terms))
+			}
+		}
+		
+		federated, _ := cmd.Flags().GetBool("federated")
+		extraStores, _ := cmd.Flags().GetStringArray("store")
+		if federated || len(extraStores) > 0 {
+			return federatedRecall(cmd, s, dbPath, req, embedText, semantic, federated, extraStores)
+		}
+		
 		var memories []models.Memory
+		var stats store.SearchStats
+		path := "keyword"
 		
 		if semantic {
 			// Try semantic search with embeddings
 			embedder := embedding.NewOllamaEmbedder("", "nomic-embed-text")
-			queryEmb, err := embedder.Embed(query)
+			embedStart := time.Now()
+			queryEmb, err := embedder.Embed(embedText)
+			embedTook := time.Since(embedStart)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: Semantic search unavailable (%v), falling back to keyword search\n", err)
 				semantic = false
+			} else if !embedding.Valid(queryEmb) {
+				fmt.Fprintf(os.Stderr, "Warning: Embedding backend returned an empty or zero vector, falling back to keyword search\n")
+				semantic = false
 			} else {
-				memories, err = s.HybridSearch(query, queryEmb, limit)
+				if verbose {
+					fmt.Fprintf(os.Stderr, "🔎 Embedder responded in %s (%d dimensions)\n", embedTook.Round(time.Microsecond), len(queryEmb))
+				}
+				path = "hybrid"
+				ctx := store.WithSearchStats(context.Background(), &stats)
+				memories, err = s.HybridSearchContext(ctx, req, queryEmb)
 				if err != nil {
 					return fmt.Errorf("hybrid search failed: %w", err)
 				}
 			}
+			if verbose && !semantic {
+				fmt.Fprintf(os.Stderr, "🔎 Embedder failed after %s\n", embedTook.Round(time.Microsecond))
+			}
+		} else if verbose {
+			fmt.Fprintln(os.Stderr, "🔎 Semantic search disabled, embedder not called")
 		}
 		
 		if !semantic {
 			// Keyword search
-			req := models.RecallRequest{
-				Query: query,
-				Limit: limit,
-			}
-			
-			if typeFilter != "" {
-				req.Types = []models.MemoryType{models.MemoryType(typeFilter)}
-			}
-			
-			if len(scopeFilter) > 0 {
-				for _, sc := range scopeFilter {
-					req.Scope = append(req.Scope, models.MemoryScope(sc))
-				}
-			}
-			
 			var err error
+			start := time.Now()
 			memories, err = s.Recall(req)
 			if err != nil {
 				return fmt.Errorf("recall failed: %w", err)
 			}
+			stats = store.SearchStats{Keyword: len(memories), Merged: len(memories), Duration: time.Since(start)}
+		}
+		
+		if verbose {
+			printSearchStats(path, stats, len(memories))
 		}
 		
 		// Check if JSON output requested
@@ -98,21 +196,25 @@ Examples:
 		}
 		
 		// Pretty print
+		shown := query
+		if filter, _ := cmd.Flags().GetString("filter"); filter != "" {
+			shown = strings.TrimSpace(strings.Join(args, " ") + " " + filter)
+		}
 		if len(memories) == 0 {
-			fmt.Printf("🔍 No memories found for: %q\n", query)
+			fmt.Printf("🔍 No memories found for: %q\n", shown)
 			return nil
 		}
 		
-		fmt.Printf("🧠 Found %d memories for: %q\n\n", len(memories), query)
+		fmt.Printf("🧠 Found %d memories for: %q\n\n", len(memories), shown)
+		
+		// Mark the query terms behind keyword matches in bold
+		var h *highlight.Highlighter
+		if doHighlight, _ := cmd.Flags().GetBool("highlight"); doHighlight && isTerminal(os.Stdout) {
+			h = highlight.New(query, false, highlight.ANSI)
+		}
 		
 		for i, m := range memories {
-			typeEmoji := getTypeEmoji(m.Type)
-			fmt.Printf("%s [%s] %s\n", typeEmoji, m.Type, m.Summary)
-			fmt.Printf("   %s\n", m.Content)
-			fmt.Printf("   📅 %s | 🎯 %.0f%% confidence\n", m.CreatedAt.Format("2006-01-02"), m.Confidence*100)
-			if len(m.Topics) > 0 {
-				fmt.Printf("   🏷️  %s\n", strings.Join(m.Topics, ", "))
-			}
+			printRecallResult(m, h)
 			if i < len(memories)-1 {
 				fmt.Println()
 			}
@@ -122,6 +224,59 @@ Examples:
 	},
 }
 
+// printRecallResult prints one recalled memory, marking query terms with h
+// if it matched them as keywords
+func printRecallResult(m models.Memory, h *highlight.Highlighter) {
+	if m.KeywordMatch {
+		m.Summary = h.Mark(m.Summary)
+		m.Content = h.Mark(m.Content)
+	}
+	typeEmoji := getTypeEmoji(m.Type)
+	fmt.Printf("%s [%s] %s\n", typeEmoji, m.Type, m.Summary)
+	fmt.Printf("   %s\n", m.Content)
+	fmt.Printf("   📅 %s | 🎯 %.0f%% confidence | 📎 %s\n", m.CreatedAt.Format("2006-01-02"), m.Confidence*100, m.Source.String())
+	if len(m.Topics) > 0 {
+		fmt.Printf("   🏷️  %s\n", strings.Join(m.Topics, ", "))
+	}
+	if len(m.Metadata) > 0 {
+		fmt.Printf("   🔖 %s\n", models.FormatMetadata(m.Metadata))
+	}
+	if m.Explanation != nil {
+		fmt.Printf("   📊 %s\n", m.Explanation)
+	}
+}
+
+// isTerminal reports whether f is a terminal, where escape codes render
+// instead of showing up as text
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// printSearchStats reports to stderr how a recall found its results
+func printSearchStats(path string, stats store.SearchStats, results int) {
+	switch {
+	case stats.Cached:
+		fmt.Fprintf(os.Stderr, "🔎 Search: %s, served from the recall cache\n", path)
+	case path == "hybrid":
+		fmt.Fprintf(os.Stderr, "🔎 Search: hybrid, %d semantic + %d keyword candidates, %d after merging\n", stats.Semantic, stats.Keyword, stats.Merged)
+	default:
+		fmt.Fprintf(os.Stderr, "🔎 Search: keyword, %d candidates\n", stats.Keyword)
+	}
+	if stats.Reranked {
+		fmt.Fprintln(os.Stderr, "🔎 Candidates reranked")
+	}
+	fmt.Fprintf(os.Stderr, "🔎 %d results in %s\n", results, stats.Duration.Round(time.Microsecond))
+}
+
+// orNone joins values with commas, or returns "(none)" if there are none
+func orNone(values []string) string {
+	if len(values) == 0 {
+		return "(none)"
+	}
+	return strings.Join(values, ", ")
+}
+
 func getTypeEmoji(t models.MemoryType) string {
 	switch t {
 	case models.MemoryTypeDecision:
@@ -145,6 +300,21 @@ func init() {
 	recallCmd.Flags().IntP("limit", "l", 5, "Maximum number of results")
 	recallCmd.Flags().StringP("type", "t", "", "Filter by memory type (decision|pattern|fact|preference|mistake|learning)")
 	recallCmd.Flags().StringSliceP("scope", "s", []string{}, "Filter by scope (personal|project|team)")
+	recallCmd.Flags().StringSlice("source", []string{}, "Filter by source (git|file|terminal|chat|manual|import)")
+	recallCmd.Flags().StringSlice("content-type", []string{}, "Filter by content type (prose|code|command|config)")
+	recallCmd.Flags().StringSlice("topic", []string{}, "Filter by topic (aliases match their canonical topic)")
+	recallCmd.Flags().StringSlice("exclude-topic", []string{}, "Exclude memories tagged with this topic, even if they match --topic")
+	recallCmd.Flags().StringToString("meta", map[string]string{}, "Filter by metadata key=value (repeatable)")
+	recallCmd.Flags().String("filter", "", `Filters and search terms in the query language, e.g. 'type:decision topic:auth since:2024-01 "terms"'`)
 	recallCmd.Flags().Bool("json", false, "Output as JSON")
+	recallCmd.Flags().Bool("explain", false, "Show how each result's score was computed")
+	recallCmd.Flags().Bool("include-low", false, "Include auto-captured memories below the importance floor (see 'config ranking')")
+	recallCmd.Flags().Bool("highlight", false, "Highlight matched query terms in bold when printing to a terminal")
+	recallCmd.Flags().Bool("no-access-log", false, "Don't record this recall in the access log")
 	recallCmd.Flags().BoolP("semantic", "S", true, "Use semantic search (requires Ollama)")
+	recallCmd.Flags().BoolP("verbose", "v", false, "Print which search ran, candidate counts and timing to stderr")
+	recallCmd.Flags().Float64("diversity", 0, "Trade relevance for variety (0-1) to skip near-duplicate results")
+	recallCmd.Flags().Bool("expand", false, "Expand the query with related topics")
+	recallCmd.Flags().Bool("federated", false, "Also search the databases listed in federation.json")
+	recallCmd.Flags().StringArray("store", nil, "Also search this database, read-only (repeatable)")
 }
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Generate embeddings for memories",
+	Long: `Generate embeddings for memories that don't have one yet, so they can
+be found by semantic search. Use --all to re-embed every memory, e.g.
+after switching embedding models.
+
+Examples:
+  memorypilot reindex
+  memorypilot reindex --all --concurrency 8`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		// Open store
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		memories, err := s.ListMemories(store.ListOptions{MissingEmbedding: !all})
+		if err != nil {
+			return fmt.Errorf("failed to list memories: %w", err)
+		}
+		if len(memories) == 0 {
+			fmt.Println("✅ All memories already have embeddings")
+			return nil
+		}
+		
+		embedder := embedding.NewAutoEmbedder(embedding.DefaultProviders())
+		if embedder.Backend() == "null" {
+			fmt.Println("❌ No embedding backend available")
+			fmt.Println("   Start Ollama with 'ollama serve' or set OPENAI_API_KEY")
+			return nil
+		}
+		
+		// Stop dispatching new work on Ctrl+C; in-flight calls finish
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		
+		fmt.Printf("🔄 Embedding %d memories with %s (concurrency %d)...\n", len(memories), embedder.Backend(), concurrency)
+		
+		texts := make([]string, len(memories))
+		for i, m := range memories {
+			texts[i] = m.Content
+		}
+		
+		embeddings, errs := embedding.EmbedAll(ctx, embedder, texts, concurrency)
+		
+		var succeeded, failed int
+		for i, m := range memories {
+			if errs[i] != nil || embeddings[i] == nil {
+				failed++
+				continue
+			}
+			if err := s.UpdateMemoryEmbedding(m.ID, embeddings[i], embedding.ModelName(embedder)); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to store embedding for %s: %v\n", m.ID, err)
+				failed++
+				continue
+			}
+			succeeded++
+		}
+		
+		fmt.Printf("✅ Embedded %d memories", succeeded)
+		if failed > 0 {
+			fmt.Printf(" (%d failed)", failed)
+		}
+		fmt.Println()
+		
+		return nil
+	},
+}
+
+func init() {
+	reindexCmd.Flags().Bool("all", false, "Re-embed all memories, not just those missing embeddings")
+	reindexCmd.Flags().IntP("concurrency", "c", 4, "Number of parallel embedding requests")
+}
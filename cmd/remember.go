@@ -6,10 +6,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/contextpilot-dev/memorypilot/internal/chunk"
 	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+	"github.com/contextpilot-dev/memorypilot/internal/ids"
+	"github.com/contextpilot-dev/memorypilot/internal/lifetime"
 	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/internal/summary"
 	"github.com/contextpilot-dev/memorypilot/pkg/models"
-	"github.com/oklog/ulid/v2"
 	"github.com/spf13/cobra"
 )
 
@@ -21,13 +24,14 @@ var rememberCmd = &cobra.Command{
 Examples:
   memorypilot remember "Always validate JWT tokens server-side"
   memorypilot remember --type decision "Chose PostgreSQL for ACID compliance"
-  memorypilot remember --type mistake "Don't use float for currency"`,
+  memorypilot remember --type mistake "Don't use float for currency"
+  memorypilot remember --meta ticket=PROJ-123 --meta author=sam "Rollout is behind a flag"
+  memorypilot remember --ttl 3d "Staging is frozen until the release"`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		content := strings.Join(args, " ")
 		
-		dataDir := getDataDir()
-		dbPath := dataDir + "/memories.db"
+		dbPath := getPaths().Database()
 		
 		// Check if database exists
 		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
@@ -46,57 +50,128 @@ Examples:
 		// Get flags
 		memoryType, _ := cmd.Flags().GetString("type")
 		topics, _ := cmd.Flags().GetStringSlice("topics")
+		metadata, _ := cmd.Flags().GetStringToString("meta")
+		if err := store.ValidateMetadata(metadata); err != nil {
+			return err
+		}
+		contentType, _ := cmd.Flags().GetString("content-type")
+		if contentType != "" && !models.ContentType(contentType).Valid() {
+			return fmt.Errorf("invalid content type %q (expected prose, code, command or config)", contentType)
+		}
+		lifetimes, err := loadLifetimes()
+		if err != nil {
+			return err
+		}
+		var ttl lifetime.Duration
+		ttlFlag, _ := cmd.Flags().GetString("ttl")
+		if ttlFlag != "" {
+			if ttl, err = lifetime.ParseDuration(ttlFlag); err != nil {
+				return err
+			}
+		}
+		sum, err := newSummarizer(cmd)
+		if err != nil {
+			return err
+		}
+		summaryText, err := sum.SummarizeType(content, models.MemoryType(memoryType))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Summarizer failed, using truncated summary: %v\n", err)
+		}
 		
-		// Create memory
+		maxContentLength, _ := cmd.Flags().GetInt("max-content-length")
+		chunkLong, _ := cmd.Flags().GetBool("chunk")
+		parts := []string{content}
+		if err := chunk.Check(content, maxContentLength); err != nil {
+			if !chunkLong {
+				return fmt.Errorf("%w; split it up or pass --chunk", err)
+			}
+			parts = chunk.Split(content, maxContentLength, chunk.DefaultOverlap)
+		}
+		
+		// Create memories (one per chunk)
 		now := time.Now()
-		memory := models.Memory{
-			ID:      ulid.Make().String(),
-			Type:    models.MemoryType(memoryType),
-			Content: content,
-			Summary: truncate(content, 100),
-			Scope:   models.MemoryScopePersonal,
-			Source: models.Source{
-				Type:      models.SourceTypeManual,
-				Reference: "cli",
-				Timestamp: now,
-			},
-			Confidence:     1.0, // Manual memories have full confidence
-			Importance:     1.0,
-			Topics:         topics,
-			CreatedAt:      now,
-			LastAccessedAt: now,
-			AccessCount:    0,
+		var memories []*models.Memory
+		for i, part := range parts {
+			memory := &models.Memory{
+				ID:          ids.New(),
+				Type:        models.MemoryType(memoryType),
+				Content:     part,
+				Summary:     summaryText,
+				ContentType: models.ContentType(contentType), // Detected when empty
+				Scope:       models.MemoryScopePersonal,
+				Source: models.Source{
+					Type:      models.SourceTypeManual,
+					Reference: "cli",
+					Timestamp: now,
+				},
+				Confidence:     1.0, // Manual memories have full confidence
+				Importance:     1.0,
+				Topics:         topics,
+				Metadata:       metadata,
+				CreatedAt:      now,
+				LastAccessedAt: now,
+				AccessCount:    0,
+			}
+			if len(parts) > 1 {
+				if memory.Summary, err = sum.SummarizeType(part, memory.Type); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: Summarizer failed for chunk %d, using truncated summary: %v\n", i+1, err)
+				}
+				memory.Metadata = map[string]string{"chunk": fmt.Sprintf("%d/%d", i+1, len(parts))}
+				for k, v := range metadata {
+					memory.Metadata[k] = v
+				}
+			}
+			if ttlFlag != "" {
+				memory.ExpiresAt = lifetime.Expiry(now, ttl)
+			} else {
+				lifetimes.Apply(memory)
+			}
+			memories = append(memories, memory)
 		}
 		
 		// Save
-		if err := s.CreateMemory(&memory); err != nil {
+		if _, err := s.CreateLinkedMemories(memories); err != nil {
 			return fmt.Errorf("failed to save memory: %w", err)
 		}
 		
-		// Generate embedding for semantic search (best effort)
+		// Generate embeddings for semantic search (best effort)
 		embedder := embedding.NewOllamaEmbedder("", "nomic-embed-text")
-		if emb, err := embedder.Embed(memory.Content); err == nil && emb != nil {
-			if err := s.UpdateMemoryEmbedding(memory.ID, emb); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: Failed to generate embedding: %v\n", err)
+		for _, memory := range memories {
+			if emb, err := embedder.Embed(memory.Content); err == nil && emb != nil {
+				if err := s.UpdateMemoryEmbedding(memory.ID, emb, embedding.ModelName(embedder)); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: Failed to generate embedding: %v\n", err)
+				}
 			}
 		}
 		
+		if len(memories) > 1 {
+			fmt.Printf("✅ Memory created in %d linked chunks\n", len(memories))
+			for _, memory := range memories {
+				fmt.Printf("   %s  %s\n", memory.ID, memory.Summary)
+			}
+			return nil
+		}
+		
+		memory := memories[0]
 		fmt.Printf("✅ Memory created: %s\n", memory.ID)
-		fmt.Printf("   Type: %s\n", memory.Type)
+		fmt.Printf("   Type: %s (%s)\n", memory.Type, memory.ContentType)
+		if memory.ExpiresAt != nil {
+			fmt.Printf("   Expires: %s\n", memory.ExpiresAt.Format("2006-01-02 15:04"))
+		}
 		fmt.Printf("   %s\n", memory.Content)
 		
 		return nil
 	},
 }
 
-func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
-	}
-	return s[:maxLen-3] + "..."
-}
-
 func init() {
 	rememberCmd.Flags().StringP("type", "t", "fact", "Memory type (decision|pattern|fact|preference|mistake|learning)")
 	rememberCmd.Flags().StringSliceP("topics", "T", []string{}, "Topics/tags for this memory")
+	rememberCmd.Flags().String("content-type", "", "Content type (prose|code|command|config); detected from the content if omitted")
+	rememberCmd.Flags().String("ttl", "", "How long to keep this memory (e.g. 12h, 30d or never); defaults to the lifetime configured for its type")
+	rememberCmd.Flags().StringToString("meta", map[string]string{}, "Metadata key=value for this memory (repeatable)")
+	rememberCmd.Flags().String("summarizer", "truncate", "Summary generation backend (truncate|ollama)")
+	rememberCmd.Flags().Int("summary-length", summary.DefaultMaxLen, "Maximum summary length in characters of types without their own in summaries.json")
+	rememberCmd.Flags().Int("max-content-length", chunk.DefaultMaxContentLength, "Longest memory content accepted, in characters (0 for no limit)")
+	rememberCmd.Flags().Bool("chunk", false, "Split content over --max-content-length into linked chunk memories instead of rejecting it")
 }
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var repairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Check the database for corruption and recover it",
+	Long: `Run SQLite's integrity check on the database and, if it fails, recover it.
+
+The damaged file is moved aside to memories.db.corrupt-<timestamp> (it is
+never deleted), a new database is created, and every row that can still be
+read is copied into it. If nothing can be read, MemoryPilot starts with an
+empty database and the old file is kept for manual recovery.
+
+The daemon refuses to start on a corrupt database. Stop the daemon and any
+MCP or API servers before running a repair, since they would keep writing
+to the file that is moved aside.
+
+Examples:
+  memorypilot repair
+  memorypilot repair --force   # rebuild even if the check passes`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath := getPaths().Database()
+
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		checkErr := store.CheckIntegrity(dbPath)
+		if checkErr != nil && !errors.Is(checkErr, store.ErrCorrupt) {
+			return fmt.Errorf("integrity check failed: %w", checkErr)
+		}
+		if checkErr == nil && !force {
+			if jsonOutput {
+				fmt.Println(`{"corrupt": false}`)
+				return nil
+			}
+			fmt.Println("✅ Database passed the integrity check; nothing to repair")
+			return nil
+		}
+
+		if !jsonOutput {
+			if checkErr != nil {
+				fmt.Printf("⚠️  %v\n", checkErr)
+			}
+			fmt.Println("🔧 Repairing database...")
+		}
+
+		result, err := store.Repair(dbPath)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"corrupt": checkErr != nil,
+				"repair":  result,
+			}, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if result.Fresh {
+			fmt.Printf("⚠️  Nothing could be read from the damaged database (%s)\n", result.Error)
+			fmt.Println("   Started with an empty database")
+		} else {
+			tables := make([]string, 0, len(result.Recovered))
+			for table := range result.Recovered {
+				tables = append(tables, table)
+			}
+			sort.Strings(tables)
+			fmt.Println("   Recovered rows:")
+			for _, table := range tables {
+				fmt.Printf("     %-18s %d\n", table, result.Recovered[table])
+			}
+			if len(result.Partial) > 0 {
+				fmt.Printf("⚠️  Only partly readable, some rows may be lost: %v\n", result.Partial)
+			}
+		}
+		fmt.Printf("   Damaged database kept at %s\n", result.Backup)
+		fmt.Println("✅ Repair complete")
+		return nil
+	},
+}
+
+func init() {
+	repairCmd.Flags().Bool("force", false, "Rebuild the database even if the integrity check passes")
+	repairCmd.Flags().Bool("json", false, "Output as JSON")
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report memories that need cleaning up",
+	Long: `Check the store for memories that need attention and suggest how to fix
+them:
+
+  - memories without topics, which topic filters can't find
+  - memories without embeddings, which semantic search can't find
+  - memories whose content is empty or only whitespace
+  - duplicates: memories with the same content (ignoring case and
+    surrounding whitespace) as an older one
+  - memories past their expiry that the daemon hasn't swept yet
+
+Each check lists a few memory IDs, the most important first. Nothing is
+changed.
+
+Examples:
+  memorypilot report
+  memorypilot report --samples 20 --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		// Open store
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		samples, _ := cmd.Flags().GetInt("samples")
+		report, err := s.QualityReport(samples)
+		if err != nil {
+			return fmt.Errorf("failed to check memories: %w", err)
+		}
+		
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		if jsonOutput {
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		
+		printQualityReport(report)
+		return nil
+	},
+}
+
+// printQualityReport shows what each quality check found and how to fix it
+func printQualityReport(report *store.QualityReport) {
+	fmt.Printf("🩺 Quality report (%d memories)\n\n", report.Total)
+	if report.Clean() {
+		fmt.Println("✅ Nothing to clean up")
+		return
+	}
+	
+	checks := []struct {
+		issue *store.QualityIssue
+		what  string
+		fix   string
+	}{
+		{&report.NoTopics, "without topics",
+			"Add topics with 'memorypilot edit <id>' so topic filters find them"},
+		{&report.NoEmbedding, "without embeddings",
+			"Run 'memorypilot reindex' so semantic search finds them"},
+		{&report.EmptyContent, "with empty content",
+			"Rewrite them with 'memorypilot edit <id>' or delete them in 'memorypilot tui'"},
+		{&report.Duplicates, "duplicating an older memory",
+			"Consolidate: keep the oldest of each group and delete the copies in 'memorypilot tui'"},
+		{&report.Expired, "expired but not yet swept",
+			"The daemon deletes expired memories hourly; start it with 'memorypilot daemon start'"},
+	}
+	for _, c := range checks {
+		if c.issue.Count == 0 {
+			fmt.Printf("✅ No memories %s\n", c.what)
+			continue
+		}
+		fmt.Printf("⚠️  %d memories %s\n", c.issue.Count, c.what)
+		if c.issue == &report.Duplicates {
+			for _, group := range report.DuplicateGroups {
+				fmt.Printf("   %s\n", strings.Join(group, " = "))
+			}
+		} else {
+			fmt.Printf("   e.g. %s\n", strings.Join(c.issue.Samples, ", "))
+		}
+		fmt.Printf("   💡 %s\n", c.fix)
+	}
+}
+
+func init() {
+	reportCmd.Flags().Int("samples", store.DefaultReportSamples, "How many memory IDs (or duplicate groups) to list per check")
+	reportCmd.Flags().Bool("json", false, "Output as JSON")
+}
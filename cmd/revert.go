@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var revertCmd = &cobra.Command{
+	Use:   "revert <id> <version>",
+	Short: "Restore a memory to an earlier version",
+	Long: `Restore a memory to a version recorded in its history.
+
+The current content is saved as a new history entry, so a revert can
+itself be reverted.
+
+Examples:
+  memorypilot revert 01HQ3K5Z8X 2`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %w", args[1], err)
+		}
+		
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		// Open store
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		v, err := s.RevertMemory(id, version, "cli")
+		if err != nil {
+			return fmt.Errorf("revert failed: %w", err)
+		}
+		
+		// Regenerate embedding for the restored content (best effort)
+		embedder := embedding.NewOllamaEmbedder("", "nomic-embed-text")
+		if emb, err := embedder.Embed(v.Content); err == nil && emb != nil {
+			if err := s.UpdateMemoryEmbedding(id, emb, embedding.ModelName(embedder)); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to store embedding: %v\n", err)
+			}
+		}
+		
+		fmt.Printf("✅ Reverted %s to version %d\n", id, version)
+		fmt.Printf("   %s\n", v.Content)
+		
+		return nil
+	},
+}
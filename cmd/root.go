@@ -1,9 +1,16 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/contextpilot-dev/memorypilot/internal/ids"
+	"github.com/contextpilot-dev/memorypilot/internal/lifetime"
+	"github.com/contextpilot-dev/memorypilot/internal/paths"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/internal/summary"
 	"github.com/spf13/cobra"
 )
 
@@ -22,35 +29,126 @@ terminal commands) and makes it available to any AI tool through MCP or REST API
 
 Your AI tools will finally remember you.`,
 	Version: version,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return configureIDs()
+	},
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	registerCompletions()
+	err := rootCmd.Execute()
+	if errors.Is(err, store.ErrCorrupt) {
+		fmt.Fprintln(os.Stderr, "Run 'memorypilot repair' to back up the damaged database and recover what can be read.")
+	}
+	return err
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ~/.memorypilot/config.yaml)")
+	// Replaced by completionCmd, which also documents installation
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is config.yaml in the directory shown by 'memorypilot paths')")
 	
 	// Add subcommands
 	rootCmd.AddCommand(daemonCmd)
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(statsCmd)
 	rootCmd.AddCommand(recallCmd)
 	rootCmd.AddCommand(rememberCmd)
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(mcpCmd)
+	rootCmd.AddCommand(healthCmd)
+	rootCmd.AddCommand(revertCmd)
+	rootCmd.AddCommand(editCmd)
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(reindexCmd)
+	rootCmd.AddCommand(clusterCmd)
+	rootCmd.AddCommand(topicsCmd)
+	rootCmd.AddCommand(linksCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(clearCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(tokenizerCmd)
+	rootCmd.AddCommand(apiCmd)
+	rootCmd.AddCommand(pathsCmd)
+	rootCmd.AddCommand(repairCmd)
+	rootCmd.AddCommand(snapshotCmd)
+	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(tuiCmd)
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.AddCommand(shellHookCmd)
+	rootCmd.AddCommand(reportCommandCmd)
 }
 
-// getConfigDir returns the MemoryPilot config directory
-func getConfigDir() string {
-	home, err := os.UserHomeDir()
+// getPaths resolves the MemoryPilot directories, exiting if they can't be
+// determined
+func getPaths() paths.Paths {
+	p, err := paths.Resolve()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting home directory: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
-	return home + "/.memorypilot"
+	return p
+}
+
+// lifetimesFile is the per-type lifetime configuration in the config
+// directory
+const lifetimesFile = "lifetimes.json"
+
+// loadLifetimes reads the per-type memory lifetimes, returning the default
+// policy if none are configured
+func loadLifetimes() (lifetime.Policy, error) {
+	policy, err := lifetime.LoadPolicy(filepath.Join(getPaths().Config, lifetimesFile))
+	if os.IsNotExist(err) {
+		return policy, nil
+	}
+	return policy, err
+}
+
+// idsFile selects the format of new memory IDs, in the config directory
+const idsFile = "ids.json"
+
+// configureIDs switches new memory IDs to the configured format, keeping
+// ULIDs if none is configured
+func configureIDs() error {
+	g, err := ids.LoadConfig(filepath.Join(getPaths().Config, idsFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	ids.SetGenerator(g)
+	return nil
 }
 
-// getDataDir returns the MemoryPilot data directory
-func getDataDir() string {
-	return getConfigDir() + "/data"
+// summariesFile sets summary lengths per memory type, in the config
+// directory
+const summariesFile = "summaries.json"
+
+// loadSummaryLengths reads the per-type summary lengths, returning the
+// built-in lengths if none are configured
+func loadSummaryLengths() (summary.Lengths, error) {
+	lengths, err := summary.LoadLengths(filepath.Join(getPaths().Config, summariesFile))
+	if os.IsNotExist(err) {
+		return lengths, nil
+	}
+	return lengths, err
+}
+
+// newSummarizer creates the summarizer chosen with --summarizer, using the
+// per-type summary lengths. --summary-length, if given, sets the length of
+// types without one of their own.
+func newSummarizer(cmd *cobra.Command) (summary.TypeSummarizer, error) {
+	lengths, err := loadSummaryLengths()
+	if err != nil {
+		return nil, err
+	}
+	if cmd.Flags().Changed("summary-length") {
+		lengths.Default, _ = cmd.Flags().GetInt("summary-length")
+	}
+	name, _ := cmd.Flags().GetString("summarizer")
+	return summary.NewTyped(name, lengths)
 }
@@ -0,0 +1,211 @@
+//go:build windows
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/internal/agent"
+	"github.com/spf13/cobra"
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+const serviceName = "MemoryPilot"
+
+var serviceCmd = &cobra.Command{
+	Use:   "service",
+	Short: "Manage the MemoryPilot Windows service",
+	Long: `Install, uninstall, start, or stop MemoryPilot as a Windows service.
+
+Running as a service keeps the daemon alive across logout and starts it
+at boot. These commands must be run from an elevated (administrator) prompt.`,
+}
+
+var serviceInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Register MemoryPilot as a Windows service",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to get executable path: %w", err)
+		}
+
+		m, err := connectServiceManager()
+		if err != nil {
+			return err
+		}
+		defer m.Disconnect()
+
+		if s, err := m.OpenService(serviceName); err == nil {
+			s.Close()
+			return fmt.Errorf("service %s is already installed", serviceName)
+		}
+
+		s, err := m.CreateService(serviceName, exe, mgr.Config{
+			DisplayName: "MemoryPilot",
+			Description: "Passive memory layer for AI-assisted development",
+			StartType:   mgr.StartAutomatic,
+		}, "service", "run")
+		if err != nil {
+			return fmt.Errorf("failed to create service: %w", err)
+		}
+		defer s.Close()
+
+		// Restart on crash after 5s, 30s, then every minute
+		recovery := []mgr.RecoveryAction{
+			{Type: mgr.ServiceRestart, Delay: 5 * time.Second},
+			{Type: mgr.ServiceRestart, Delay: 30 * time.Second},
+			{Type: mgr.ServiceRestart, Delay: time.Minute},
+		}
+		if err := s.SetRecoveryActions(recovery, 86400); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to set recovery actions: %v\n", err)
+		}
+
+		fmt.Printf("✅ Service %s installed\n", serviceName)
+		fmt.Println("   Use 'memorypilot service start' to start it now")
+		return nil
+	},
+}
+
+var serviceUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the MemoryPilot Windows service",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := connectServiceManager()
+		if err != nil {
+			return err
+		}
+		defer m.Disconnect()
+
+		s, err := m.OpenService(serviceName)
+		if err != nil {
+			return fmt.Errorf("service %s is not installed", serviceName)
+		}
+		defer s.Close()
+
+		// Best effort stop before removal
+		s.Control(svc.Stop)
+
+		if err := s.Delete(); err != nil {
+			return fmt.Errorf("failed to delete service: %w", err)
+		}
+
+		fmt.Printf("✅ Service %s uninstalled\n", serviceName)
+		return nil
+	},
+}
+
+var serviceStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the MemoryPilot Windows service",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := connectServiceManager()
+		if err != nil {
+			return err
+		}
+		defer m.Disconnect()
+
+		s, err := m.OpenService(serviceName)
+		if err != nil {
+			return fmt.Errorf("service %s is not installed", serviceName)
+		}
+		defer s.Close()
+
+		if err := s.Start(); err != nil {
+			return fmt.Errorf("failed to start service: %w", err)
+		}
+
+		fmt.Printf("✅ Service %s started\n", serviceName)
+		return nil
+	},
+}
+
+var serviceStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the MemoryPilot Windows service",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := connectServiceManager()
+		if err != nil {
+			return err
+		}
+		defer m.Disconnect()
+
+		s, err := m.OpenService(serviceName)
+		if err != nil {
+			return fmt.Errorf("service %s is not installed", serviceName)
+		}
+		defer s.Close()
+
+		if _, err := s.Control(svc.Stop); err != nil {
+			return fmt.Errorf("failed to stop service: %w", err)
+		}
+
+		fmt.Printf("🛑 Service %s stopped\n", serviceName)
+		return nil
+	},
+}
+
+var serviceRunCmd = &cobra.Command{
+	Use:    "run",
+	Short:  "Run as a Windows service (invoked by the service manager)",
+	Hidden: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return svc.Run(serviceName, &memoryPilotService{})
+	},
+}
+
+// memoryPilotService runs the agent loop under the Windows service manager
+type memoryPilotService struct{}
+
+func (m *memoryPilotService) Execute(args []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	status <- svc.Status{State: svc.StartPending}
+
+	a, err := startAgent(agent.DefaultConfig())
+	if err != nil {
+		return true, 1
+	}
+
+	status <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+
+	for req := range requests {
+		switch req.Cmd {
+		case svc.Interrogate:
+			status <- req.CurrentStatus
+		case svc.Stop, svc.Shutdown:
+			status <- svc.Status{State: svc.StopPending}
+			a.Stop()
+			return false, 0
+		}
+	}
+
+	a.Stop()
+	return false, 0
+}
+
+// connectServiceManager connects to the service control manager, reporting
+// a clear error when the process lacks administrator rights
+func connectServiceManager() (*mgr.Mgr, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		if errors.Is(err, windows.ERROR_ACCESS_DENIED) {
+			return nil, fmt.Errorf("access denied: run this command from an administrator prompt")
+		}
+		return nil, fmt.Errorf("failed to connect to service manager: %w", err)
+	}
+	return m, nil
+}
+
+func init() {
+	serviceCmd.AddCommand(serviceInstallCmd)
+	serviceCmd.AddCommand(serviceUninstallCmd)
+	serviceCmd.AddCommand(serviceStartCmd)
+	serviceCmd.AddCommand(serviceStopCmd)
+	serviceCmd.AddCommand(serviceRunCmd)
+
+	rootCmd.AddCommand(serviceCmd)
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Take and restore point-in-time copies of the database",
+	Long: `Snapshots are exact copies of the database file, kept in the snapshots
+directory shown by 'memorypilot paths'. Unlike export, they keep everything:
+history, links, embeddings, captured events and settings. Take one before a
+large consolidation, prune or import to be able to go back.`,
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Snapshot the database",
+	Long: `Write a consistent copy of the database to the snapshots directory, named
+after the current time. The daemon can keep running while it is taken.
+
+Examples:
+  memorypilot snapshot create`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		snapshot, err := s.CreateSnapshot(getPaths().Snapshots())
+		if err != nil {
+			return err
+		}
+		
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		if jsonOutput {
+			data, _ := json.MarshalIndent(snapshot, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		
+		fmt.Printf("📸 Snapshot %s (%s)\n", snapshot.Name, formatSize(snapshot.Size))
+		fmt.Printf("   Saved to %s\n", snapshot.Path)
+		return nil
+	},
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List snapshots with their sizes and times",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snapshots, err := store.ListSnapshots(getPaths().Snapshots())
+		if err != nil {
+			return fmt.Errorf("failed to list snapshots: %w", err)
+		}
+		
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		if jsonOutput {
+			if snapshots == nil {
+				snapshots = []store.Snapshot{}
+			}
+			data, _ := json.MarshalIndent(snapshots, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		
+		if len(snapshots) == 0 {
+			fmt.Println("📸 No snapshots yet")
+			fmt.Println("   Take one with 'memorypilot snapshot create'")
+			return nil
+		}
+		
+		fmt.Printf("📸 %d snapshots in %s:\n", len(snapshots), getPaths().Snapshots())
+		for _, snapshot := range snapshots {
+			fmt.Printf("   %-32s %10s  %s\n", snapshot.Name, formatSize(snapshot.Size),
+				snapshot.CreatedAt.Format("2006-01-02 15:04:05"))
+		}
+		return nil
+	},
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore <snapshot>",
+	Short: "Replace the database with a snapshot",
+	Long: `Replace the database with a snapshot, given by name (as listed by
+'memorypilot snapshot list') or path. The current database is moved aside
+to memories.db.pre-restore-<timestamp>, never deleted, so a restore can be
+undone.
+
+Stop the daemon, MCP servers and API servers first: restore refuses to run
+while the daemon is. Nothing is replaced unless --yes is given.
+
+Examples:
+  memorypilot snapshot restore memories-20240115-093000.db --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := store.FindSnapshot(getPaths().Snapshots(), args[0])
+		if err != nil {
+			return err
+		}
+		
+		if pid, err := readPidFile(); err == nil && isProcessRunning(pid) {
+			return fmt.Errorf("the daemon is running (PID %d); stop it with 'memorypilot daemon stop' before restoring", pid)
+		}
+		
+		dbPath := getPaths().Database()
+		yes, _ := cmd.Flags().GetBool("yes")
+		if !yes {
+			fmt.Printf("⚠️  This would replace %s with %s\n", dbPath, path)
+			fmt.Println("   Memories saved since the snapshot would be moved aside with the current database")
+			fmt.Println("   Re-run with --yes to confirm")
+			return nil
+		}
+		
+		backup, err := store.RestoreSnapshot(dbPath, path)
+		if err != nil {
+			if backup != "" {
+				fmt.Printf("   Previous database kept at %s\n", backup)
+			}
+			return err
+		}
+		fmt.Printf("✅ Restored %s\n", path)
+		fmt.Printf("   Previous database kept at %s\n", backup)
+		return nil
+	},
+}
+
+// formatSize formats a size in bytes for display
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGT"[exp])
+}
+
+func init() {
+	snapshotCreateCmd.Flags().Bool("json", false, "Output as JSON")
+	snapshotListCmd.Flags().Bool("json", false, "Output as JSON")
+	snapshotRestoreCmd.Flags().Bool("yes", false, "Confirm replacing the database")
+	snapshotCmd.AddCommand(snapshotCreateCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show memory counts by type, or by project and type",
+	Long: `Count memories by type. With --by-project, count them per project and
+type, projects with the most memories first, to see which projects are
+under-documented. Memories captured by the daemon belong to the repository
+they came from; memories saved by hand are counted under "(no project)".
+
+Examples:
+  memorypilot stats
+  memorypilot stats --by-project
+  memorypilot stats --by-project --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		byProject, _ := cmd.Flags().GetBool("by-project")
+		if byProject {
+			projects, err := s.StatsByProject()
+			if err != nil {
+				return fmt.Errorf("failed to get project stats: %w", err)
+			}
+			if jsonOutput {
+				data, _ := json.MarshalIndent(projects, "", "  ")
+				fmt.Println(string(data))
+				return nil
+			}
+			printProjectStats(projects)
+			return nil
+		}
+		
+		stats, err := s.GetStats()
+		if err != nil {
+			return fmt.Errorf("failed to get stats: %w", err)
+		}
+		if jsonOutput {
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"total":  stats.TotalMemories,
+				"byType": stats.ByType,
+			}, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		
+		fmt.Println("📊 Memories by Type")
+		fmt.Println("━━━━━━━━━━━━━━━━━━━")
+		for _, t := range projectStatTypes {
+			fmt.Printf("   %-12s %8d\n", t, stats.ByType[t])
+		}
+		fmt.Printf("   %-12s %8d\n", "total", stats.TotalMemories)
+		return nil
+	},
+}
+
+// projectStatTypes are the columns of the per-project table
+var projectStatTypes = []string{"decision", "pattern", "fact", "preference", "mistake", "learning"}
+
+func printProjectStats(projects []store.ProjectStats) {
+	fmt.Println("📁 Memories by Project")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━")
+	if len(projects) == 0 {
+		fmt.Println("   No memories yet")
+		return
+	}
+	
+	fmt.Printf("   %-24s", "PROJECT")
+	for _, t := range projectStatTypes {
+		fmt.Printf(" %10s", t)
+	}
+	fmt.Printf(" %8s\n", "total")
+	
+	for _, p := range projects {
+		name := p.Name
+		if p.ProjectID == "" {
+			name = "(no project)"
+		}
+		if len([]rune(name)) > 24 {
+			name = string([]rune(name)[:23]) + "…"
+		}
+		fmt.Printf("   %-24s", name)
+		for _, t := range projectStatTypes {
+			fmt.Printf(" %10d", p.ByType[t])
+		}
+		fmt.Printf(" %8d\n", p.Total)
+	}
+}
+
+func init() {
+	statsCmd.Flags().Bool("by-project", false, "Count memories per project and type")
+	statsCmd.Flags().Bool("json", false, "Output as JSON")
+}
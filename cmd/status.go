@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	querylang "github.com/contextpilot-dev/memorypilot/internal/query"
 	"github.com/contextpilot-dev/memorypilot/internal/store"
 	"github.com/spf13/cobra"
 )
@@ -13,8 +16,7 @@ var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show MemoryPilot status and statistics",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		dataDir := getDataDir()
-		dbPath := dataDir + "/memories.db"
+		dbPath := getPaths().Database()
 		
 		// Check if database exists
 		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
@@ -36,10 +38,66 @@ var statusCmd = &cobra.Command{
 			return fmt.Errorf("failed to get stats: %w", err)
 		}
 		
-		// Check if JSON output requested
 		jsonOutput, _ := cmd.Flags().GetBool("json")
+		
+		// Embedding breakdown replaces the overview
+		if embeddings, _ := cmd.Flags().GetBool("embeddings"); embeddings {
+			embStats, err := s.GetEmbeddingStats()
+			if err != nil {
+				return fmt.Errorf("failed to get embedding stats: %w", err)
+			}
+			if jsonOutput {
+				data, _ := json.MarshalIndent(embStats, "", "  ")
+				fmt.Println(string(data))
+				return nil
+			}
+			printEmbeddingStats(embStats)
+			return nil
+		}
+		
+		// Activity since a point in time replaces the overview too
+		if cmd.Flags().Changed("since") {
+			sinceValue, _ := cmd.Flags().GetString("since")
+			now := time.Now()
+			since, err := activitySince(s, sinceValue, now)
+			if err != nil {
+				return err
+			}
+			activity, err := s.ActivitySince(since, nil)
+			if err != nil {
+				return fmt.Errorf("failed to get activity: %w", err)
+			}
+			if err := s.MarkSeen(activityReader, now); err != nil {
+				return fmt.Errorf("failed to record last seen: %w", err)
+			}
+			if jsonOutput {
+				data, _ := json.MarshalIndent(activity, "", "  ")
+				fmt.Println(string(data))
+				return nil
+			}
+			printActivity(activity)
+			return nil
+		}
+		
+		// Creation trend (optional)
+		trendBucket, _ := cmd.Flags().GetString("trend")
+		periods, _ := cmd.Flags().GetInt("periods")
+		var trend []store.TrendBucket
+		if trendBucket != "" {
+			trend, err = s.GetCreationTrend(trendBucket, periods)
+			if err != nil {
+				return fmt.Errorf("failed to get trend: %w", err)
+			}
+		}
+		
+		// Check if JSON output requested
 		if jsonOutput {
-			data, _ := json.MarshalIndent(stats, "", "  ")
+			var data []byte
+			if trend != nil {
+				data, _ = json.MarshalIndent(map[string]interface{}{"stats": stats, "trend": trend}, "", "  ")
+			} else {
+				data, _ = json.MarshalIndent(stats, "", "  ")
+			}
 			fmt.Println(string(data))
 			return nil
 		}
@@ -63,11 +121,134 @@ var statusCmd = &cobra.Command{
 		fmt.Println("📁 Projects")
 		fmt.Println("━━━━━━━━━━━━━━━━━━━━━")
 		fmt.Printf("   Tracked:    %d\n", stats.ProjectCount)
+		fmt.Println()
+		fmt.Println("🔢 Embeddings")
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Printf("   Embedded:   %d of %d\n", stats.Embedded, stats.TotalMemories)
+		if stats.StaleEmbeddings > 0 {
+			fmt.Printf("   Stale:      %d (run 'memorypilot status --embeddings')\n", stats.StaleEmbeddings)
+		}
+		
+		if trend != nil {
+			fmt.Println()
+			fmt.Printf("📈 Created per %s\n", trendBucket)
+			fmt.Println("━━━━━━━━━━━━━━━━━━━━━")
+			for _, b := range trend {
+				fmt.Printf("   %-11s %4d %s\n", b.Period, b.Count, strings.Repeat("▇", min(b.Count, 40)))
+			}
+		}
 		
 		return nil
 	},
 }
 
+func printEmbeddingStats(e *store.EmbeddingStats) {
+	fmt.Println("🔢 Embedding Coverage")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("   Embedded:   %d of %d (%.0f%%)\n", e.Embedded, e.Total, e.Coverage()*100)
+	fmt.Printf("   Missing:    %d\n", e.Unembedded)
+	fmt.Printf("   Stale:      %d\n", e.Stale)
+	
+	if len(e.Groups) > 0 {
+		fmt.Println()
+		fmt.Printf("   %-32s %9s %8s\n", "MODEL", "DIMENSION", "COUNT")
+		for _, g := range e.Groups {
+			model := g.Model
+			if model == "" {
+				model = "(unknown)"
+			}
+			if len([]rune(model)) > 32 {
+				model = string([]rune(model)[:31]) + "…"
+			}
+			note := ""
+			if g.Stale {
+				note = "  stale"
+			}
+			fmt.Printf("   %-32s %9d %8d%s\n", model, g.Dimension, g.Count, note)
+		}
+	}
+	
+	fmt.Println()
+	switch {
+	case e.Stale > 0:
+		fmt.Printf("   ⚠️  %d embeddings don't match the current model (%s, %d dimensions).\n", e.Stale, modelLabel(e.CurrentModel), e.CurrentDimension)
+		fmt.Println("   Semantic search can't compare them with the rest; run 'memorypilot reindex --all' to re-embed everything.")
+	case e.Unembedded > 0:
+		fmt.Println("   Run 'memorypilot reindex' to embed the missing memories.")
+	default:
+		fmt.Println("   ✅ Every memory has a current embedding")
+	}
+}
+
+// modelLabel names an embedding model, which is unknown for embeddings
+// stored before models were recorded
+func modelLabel(model string) string {
+	if model == "" {
+		return "unknown model"
+	}
+	return model
+}
+
+// activityReader is the reader whose last-seen mark 'status --since' keeps
+const activityReader = "cli"
+
+// activitySince resolves a --since value: "last" is when the activity was
+// last looked at from the CLI, or DefaultActivityWindow ago the first time
+func activitySince(s *store.Store, value string, now time.Time) (time.Time, error) {
+	if value != "last" {
+		since, err := querylang.ParseSince(value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --since %q: %w", value, err)
+		}
+		return since, nil
+	}
+	lastSeen, err := s.LastSeen(activityReader)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read last seen: %w", err)
+	}
+	if lastSeen.IsZero() {
+		return now.Add(-store.DefaultActivityWindow), nil
+	}
+	return lastSeen, nil
+}
+
+func printActivity(a *store.Activity) {
+	fmt.Printf("🕒 Activity since %s\n", a.Since.Local().Format("2006-01-02 15:04"))
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━")
+	if a.Total == 0 {
+		fmt.Println("   Nothing new")
+		return
+	}
+	fmt.Printf("   %d new: %s\n", a.Total, formatTypeCounts(a.ByType))
+	
+	for _, p := range a.Projects {
+		name := p.Name
+		if p.ProjectID == "" {
+			name = "(no project)"
+		}
+		fmt.Println()
+		fmt.Printf("📁 %s (%s)\n", name, formatTypeCounts(p.ByType))
+		for _, m := range p.Memories {
+			fmt.Printf("   • %s [%s] %s\n", m.CreatedAt.Local().Format("01-02 15:04"), m.Type, m.Summary)
+		}
+	}
+}
+
+// formatTypeCounts lists memory counts by type, e.g. "2 decisions, 1 fact"
+func formatTypeCounts(byType map[string]int) string {
+	var parts []string
+	for _, t := range projectStatTypes {
+		switch n := byType[t]; n {
+		case 0:
+		case 1:
+			parts = append(parts, "1 "+t)
+		default:
+			parts = append(parts, fmt.Sprintf("%d %ss", n, t))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
 func getStatusEmoji(running bool) string {
 	if running {
 		return "🟢 Running"
@@ -77,4 +258,8 @@ func getStatusEmoji(running bool) string {
 
 func init() {
 	statusCmd.Flags().Bool("json", false, "Output as JSON")
+	statusCmd.Flags().String("trend", "", "Show memory creation trend by bucket (day|week|month)")
+	statusCmd.Flags().Int("periods", 7, "Number of recent buckets in the trend")
+	statusCmd.Flags().Bool("embeddings", false, "Show embedding coverage and a breakdown by model and dimension")
+	statusCmd.Flags().String("since", "", "Show memories captured since this date or duration ago, or since you last looked (last)")
 }
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/internal/tokenize"
+	"github.com/spf13/cobra"
+)
+
+var tokenizerCmd = &cobra.Command{
+	Use:   "tokenizer",
+	Short: "Show or change how keyword search tokenizes text",
+	Long: `Keyword search lowercases text, drops stopwords and stems words, both when
+memories are stored and when they are searched. The configuration lives in
+the database, so every process uses the same one; running daemons and
+servers pick up a change on their next write or search.`,
+}
+
+var tokenizerShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the tokenizer configuration as JSON",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openTokenizerStore()
+		if s == nil || err != nil {
+			return err
+		}
+		defer s.Close()
+		
+		cfg, err := s.TokenizerConfig()
+		if err != nil {
+			return fmt.Errorf("failed to read tokenizer config: %w", err)
+		}
+		data, _ := json.MarshalIndent(cfg, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	},
+}
+
+var tokenizerSetCmd = &cobra.Command{
+	Use:   "set <config.json>",
+	Short: "Change the tokenizer and rebuild the keyword index",
+	Long: `Load a tokenizer configuration from a JSON file and rebuild the keyword
+index of every memory with it. Fields left out keep their defaults:
+
+  {
+    "stopwords": ["a", "the"],        replaces the built-in English list
+    "extraStopwords": ["todo", "wip"], added to the list
+    "stemming": false                  on by default
+  }
+
+Examples:
+  memorypilot tokenizer set ~/.memorypilot/tokenizer.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", args[0], err)
+		}
+		var cfg tokenize.Config
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return fmt.Errorf("invalid tokenizer config %s: %w", args[0], err)
+		}
+		
+		s, err := openTokenizerStore()
+		if s == nil || err != nil {
+			return err
+		}
+		defer s.Close()
+		
+		n, err := s.SetTokenizerConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to set tokenizer: %w", err)
+		}
+		fmt.Printf("✅ Tokenizer updated, reindexed %d memories\n", n)
+		return nil
+	},
+}
+
+var tokenizerResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Restore the default tokenizer and rebuild the keyword index",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openTokenizerStore()
+		if s == nil || err != nil {
+			return err
+		}
+		defer s.Close()
+		
+		n, err := s.SetTokenizerConfig(tokenize.Config{})
+		if err != nil {
+			return fmt.Errorf("failed to reset tokenizer: %w", err)
+		}
+		fmt.Printf("✅ Tokenizer reset to defaults, reindexed %d memories\n", n)
+		return nil
+	},
+}
+
+// openTokenizerStore opens the store, or prints a hint and returns nil if
+// MemoryPilot isn't initialized
+func openTokenizerStore() (*store.Store, error) {
+	dbPath := getPaths().Database()
+	
+	// Check if database exists
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		fmt.Println("❌ MemoryPilot not initialized")
+		fmt.Println("   Run 'memorypilot init' to get started")
+		return nil, nil
+	}
+	
+	s, err := store.New(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store: %w", err)
+	}
+	return s, nil
+}
+
+func init() {
+	tokenizerCmd.AddCommand(tokenizerShowCmd)
+	tokenizerCmd.AddCommand(tokenizerSetCmd)
+	tokenizerCmd.AddCommand(tokenizerResetCmd)
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var topicsCmd = &cobra.Command{
+	Use:   "topics",
+	Short: "Manage topics and topic aliases",
+	Long:  `Map alternative topic names to a canonical topic, rename topics and find topics that look like duplicates.`,
+}
+
+var topicsAliasCmd = &cobra.Command{
+	Use:   "alias <alias> <canonical>",
+	Short: "Make one topic an alias of another",
+	Long: `Make alias an alternative name for canonical.
+
+Memories already tagged with the alias are retagged with the canonical
+topic, new memories are normalized on write, and recall by either name
+matches both.
+
+Examples:
+  memorypilot topics alias k8s kubernetes`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		// Open store
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		if err := s.AddTopicAlias(args[0], args[1]); err != nil {
+			return fmt.Errorf("failed to add alias: %w", err)
+		}
+		
+		fmt.Printf("✅ %q is now an alias of %q\n", args[0], args[1])
+		return nil
+	},
+}
+
+var topicsRenameCmd = &cobra.Command{
+	Use:   "rename <from> <to>",
+	Short: "Rename a topic on every memory",
+	Long: `Rename a topic on every memory tagged with it, and in the alias table.
+
+If the new name is already in use the topics merge, and memories tagged with
+both keep it once. Unlike alias, the old name is not remembered: memories
+tagged with it later keep it.
+
+Examples:
+  memorypilot topics rename auth authentication`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		// Open store
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		changed, err := s.RenameTopic(args[0], args[1])
+		if err != nil {
+			return fmt.Errorf("failed to rename topic: %w", err)
+		}
+		
+		fmt.Printf("✅ Renamed %q to %q on %d memories\n", args[0], args[1], changed)
+		return nil
+	},
+}
+
+var topicsSuggestCmd = &cobra.Command{
+	Use:   "suggest",
+	Short: "Suggest topics that could be merged",
+	Long: `List pairs of topics whose names are textually similar, such as
+"react-js" and "reactjs" or "database" and "databases".`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath := getPaths().Database()
+		
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+		
+		// Open store
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+		
+		suggestions, err := s.SuggestTopicMerges()
+		if err != nil {
+			return fmt.Errorf("failed to compare topics: %w", err)
+		}
+		
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		if jsonOutput {
+			data, _ := json.MarshalIndent(suggestions, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		
+		if len(suggestions) == 0 {
+			fmt.Println("✅ No similar topics found")
+			return nil
+		}
+		
+		fmt.Printf("🏷️  %d possible merges:\n\n", len(suggestions))
+		for _, sg := range suggestions {
+			fmt.Printf("  %s (%d) → %s (%d)\n", sg.Alias, sg.AliasCount, sg.Canonical, sg.CanonicalCount)
+			fmt.Printf("     memorypilot topics alias %q %q\n", sg.Alias, sg.Canonical)
+		}
+		return nil
+	},
+}
+
+func init() {
+	topicsCmd.AddCommand(topicsAliasCmd)
+	topicsCmd.AddCommand(topicsRenameCmd)
+	topicsCmd.AddCommand(topicsSuggestCmd)
+	
+	topicsSuggestCmd.Flags().Bool("json", false, "Output as JSON")
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse, search and edit memories interactively",
+	Long: `Open a terminal UI for exploring your memories.
+
+Results update as you type (keyword search; an empty search lists the
+newest memories) and more are loaded as you scroll past the end.
+
+Keys:
+  type            Search
+  ↑ ↓ / ^P ^N     Move through results
+  PgUp PgDn       Page through results
+  Enter           View the full memory
+  ^E              Edit the memory in $EDITOR
+  ^D              Delete the memory (asks for confirmation)
+  ^T              Cycle the type filter
+  ^F              Set the topic filter
+  ^U              Clear the search
+  Esc             Clear the search, or quit when it is empty
+  ^C              Quit`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath := getPaths().Database()
+
+		// Check if database exists
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			fmt.Println("❌ MemoryPilot not initialized")
+			fmt.Println("   Run 'memorypilot init' to get started")
+			return nil
+		}
+
+		s, err := store.New(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer s.Close()
+
+		// Live search would flood the access log with partial queries
+		s.SetAccessLog(false)
+
+		sum, err := newSummarizer(cmd)
+		if err != nil {
+			return err
+		}
+
+		return tui.Run(s, tui.Options{
+			Embedder:   embedding.NewOllamaEmbedder("", "nomic-embed-text"),
+			Summarizer: sum,
+			Editor:     editorCommand(),
+		})
+	},
+}
+
+func init() {
+	tuiCmd.Flags().String("summarizer", "truncate", "Summary generation backend for edited memories (truncate|ollama)")
+}
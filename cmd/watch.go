@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/contextpilot-dev/memorypilot/internal/ipc"
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Stream newly created memories from the daemon",
+	Long: `Connect to the running daemon and print memories as they are created.
+
+Useful for verifying that git commits, file changes and terminal commands
+are producing the memories you expect.
+
+Examples:
+  memorypilot watch
+  memorypilot watch --type decision,mistake`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		types, _ := cmd.Flags().GetStringSlice("type")
+		
+		pid, err := readPidFile()
+		if err != nil || !isProcessRunning(pid) {
+			fmt.Println("🔴 MemoryPilot daemon is not running")
+			fmt.Println("   Start it with 'memorypilot daemon start'")
+			return nil
+		}
+		
+		fmt.Printf("👀 Watching for new memories (daemon PID %d)...\n", pid)
+		fmt.Println("   Press Ctrl+C to stop")
+		fmt.Println()
+		
+		return ipc.Watch(getSocketPath(), types, func(m models.Memory) error {
+			fmt.Printf("%s %s [%s] %s\n", m.CreatedAt.Format("15:04:05"), getTypeEmoji(m.Type), m.Type, m.Summary)
+			fmt.Printf("   %s\n", m.Content)
+			if len(m.Topics) > 0 {
+				fmt.Printf("   🏷️  %s\n", strings.Join(m.Topics, ", "))
+			}
+			fmt.Println()
+			return nil
+		})
+	},
+}
+
+func init() {
+	watchCmd.Flags().StringSliceP("type", "t", []string{}, "Only show these memory types (decision|pattern|fact|preference|mistake|learning)")
+}
@@ -2,17 +2,31 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/contextpilot-dev/memorypilot/internal/embedding"
 	"github.com/contextpilot-dev/memorypilot/internal/extractor"
+	"github.com/contextpilot-dev/memorypilot/internal/hooks"
+	"github.com/contextpilot-dev/memorypilot/internal/ids"
+	"github.com/contextpilot-dev/memorypilot/internal/importance"
+	"github.com/contextpilot-dev/memorypilot/internal/ipc"
+	"github.com/contextpilot-dev/memorypilot/internal/lifetime"
+	"github.com/contextpilot-dev/memorypilot/internal/metrics"
+	"github.com/contextpilot-dev/memorypilot/internal/paths"
+	"github.com/contextpilot-dev/memorypilot/internal/redact"
+	"github.com/contextpilot-dev/memorypilot/internal/repos"
 	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/internal/summary"
 	"github.com/contextpilot-dev/memorypilot/internal/watcher"
 	"github.com/contextpilot-dev/memorypilot/pkg/models"
-	"github.com/oklog/ulid/v2"
 )
 
 // Config holds agent configuration
@@ -23,6 +37,23 @@ type Config struct {
 	BatchSize       int
 	BatchWait       time.Duration
 	ExtractionModel string
+	SocketPath      string // IPC socket for watch clients; disabled if empty
+	ImportanceRules string // JSON importance scoring rules; defaults if empty or missing
+	Lifetimes       string // JSON per-type memory lifetimes; nothing expires if empty or missing
+	Repos           string // JSON repositories to capture from or ignore; all are captured if empty or missing
+	Summaries       string // JSON per-type summary lengths; built-in lengths if empty or missing
+	Hooks           string // JSON commands run for captured memories; none if empty or missing
+	Redaction       string // JSON secret patterns redacted from captured text; built-in patterns if empty or missing
+	MetricsAddr     string // Address serving Prometheus metrics; disabled if empty
+
+	LinkThreshold    int // Recalls two memories must share to be linked
+	MaxInferredLinks int // Most inferred links kept per memory
+	MinDiffLines     int // Fewest changed lines for a file edit to be captured
+	EmbedWorkers     int // Memories embedded concurrently in the background
+
+	// Take terminal commands and their exit codes from shell hooks over the
+	// IPC socket instead of shell history
+	CaptureCommands bool
 }
 
 // DefaultConfig returns the default agent configuration
@@ -33,6 +64,11 @@ func DefaultConfig() *Config {
 		BatchSize:       10,
 		BatchWait:       5 * time.Second,
 		ExtractionModel: "llama3.2",
+
+		LinkThreshold:    store.DefaultLinkThreshold,
+		MaxInferredLinks: store.DefaultMaxInferredLinks,
+		MinDiffLines:     watcher.DefaultMinDiffLines,
+		EmbedWorkers:     DefaultEmbedWorkers,
 	}
 }
 
@@ -42,8 +78,18 @@ type Agent struct {
 	store      *store.Store
 	extractor  extractor.Extractor
 	embedder   embedding.Embedder
+	scorer     importance.Scorer
+	lifetimes  lifetime.Policy
+	summaries  summary.Lengths
+	repos      repos.Config
+	hooks      *hooks.Runner
+	redactor   *redact.Redactor
 	eventQueue chan models.Event
-	watchers   []watcher.Watcher
+	embedWake  chan struct{} // Wakes embedLoop when memories are stored
+	watchers   []*watched
+	watchersMu sync.Mutex
+	terminal   *watcher.TerminalWatcher
+	ipc        *ipc.Server
 	ctx        context.Context
 	cancel     context.CancelFunc
 	wg         sync.WaitGroup
@@ -52,8 +98,13 @@ type Agent struct {
 // New creates a new agent instance
 func New(cfg *Config) (*Agent, error) {
 	// Open store
-	dbPath := cfg.DataDir + "/memories.db"
+	dbPath := filepath.Join(cfg.DataDir, paths.DatabaseFile)
 	s, err := store.New(dbPath)
+	if errors.Is(err, store.ErrCorrupt) {
+		// Repairing moves the files aside, which other processes may still
+		// have open, so leave it to the user once they are stopped
+		log.Printf("⚠️ %v; run 'memorypilot repair' after stopping every MemoryPilot process", err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to open store: %w", err)
 	}
@@ -61,8 +112,74 @@ func New(cfg *Config) (*Agent, error) {
 	// Initialize extractor (Ollama)
 	ext := extractor.NewOllamaExtractor("", cfg.ExtractionModel)
 
-	// Initialize embedder (Ollama)
-	emb := embedding.NewOllamaEmbedder("", "nomic-embed-text")
+	// Initialize embedder (first responsive backend: Ollama, OpenAI, none)
+	emb := embedding.NewAutoEmbedder(embedding.DefaultProviders())
+
+	// Load importance scoring rules
+	rules := importance.DefaultRules()
+	if cfg.ImportanceRules != "" {
+		rules, err = importance.LoadRules(cfg.ImportanceRules)
+		if err != nil && !os.IsNotExist(err) {
+			s.Close()
+			return nil, err
+		}
+	}
+
+	// Load per-type lifetimes
+	lifetimes := lifetime.DefaultPolicy()
+	if cfg.Lifetimes != "" {
+		lifetimes, err = lifetime.LoadPolicy(cfg.Lifetimes)
+		if err != nil && !os.IsNotExist(err) {
+			s.Close()
+			return nil, err
+		}
+	}
+
+	// Load per-type summary lengths
+	summaries := summary.DefaultLengths()
+	if cfg.Summaries != "" {
+		summaries, err = summary.LoadLengths(cfg.Summaries)
+		if err != nil && !os.IsNotExist(err) {
+			s.Close()
+			return nil, err
+		}
+	}
+	ext.SetSummaryLengths(summaries)
+
+	// Load the repositories to capture from
+	var repoConfig repos.Config
+	if cfg.Repos != "" {
+		repoConfig, err = repos.Load(cfg.Repos)
+		if err != nil && !os.IsNotExist(err) {
+			s.Close()
+			return nil, err
+		}
+	}
+
+	// Load the secret patterns redacted from captured text
+	var redactConfig redact.Config
+	if cfg.Redaction != "" {
+		redactConfig, err = redact.Load(cfg.Redaction)
+		if err != nil && !os.IsNotExist(err) {
+			s.Close()
+			return nil, err
+		}
+	}
+	redactor, err := redact.New(redactConfig)
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	// Load the commands run for captured memories
+	var hookConfig hooks.Config
+	if cfg.Hooks != "" {
+		hookConfig, err = hooks.Load(cfg.Hooks)
+		if err != nil && !os.IsNotExist(err) {
+			s.Close()
+			return nil, err
+		}
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -71,7 +188,14 @@ func New(cfg *Config) (*Agent, error) {
 		store:      s,
 		extractor:  ext,
 		embedder:   emb,
+		scorer:     importance.NewRuleScorer(rules),
+		lifetimes:  lifetimes,
+		summaries:  summaries,
+		repos:      repoConfig,
+		hooks:      hooks.NewRunner(hookConfig),
+		redactor:   redactor,
 		eventQueue: make(chan models.Event, 10000),
+		embedWake:  make(chan struct{}, 1),
 		ctx:        ctx,
 		cancel:     cancel,
 	}
@@ -79,6 +203,11 @@ func New(cfg *Config) (*Agent, error) {
 	return a, nil
 }
 
+// SetScorer replaces the importance scorer applied to captured memories
+func (a *Agent) SetScorer(scorer importance.Scorer) {
+	a.scorer = scorer
+}
+
 // Start begins the agent's background processing
 func (a *Agent) Start() error {
 	log.Println("Starting MemoryPilot agent...")
@@ -92,9 +221,41 @@ func (a *Agent) Start() error {
 		return fmt.Errorf("failed to start watchers: %w", err)
 	}
 
-	// Start importance decay (daily)
-	a.wg.Add(1)
+	// Start IPC server for watch clients
+	if a.config.SocketPath != "" {
+		a.ipc = ipc.NewServer(a.config.SocketPath)
+		if err := a.ipc.Start(); err != nil {
+			log.Printf("Warning: IPC server failed to start: %v", err)
+			a.ipc = nil
+		}
+	}
+	if a.config.CaptureCommands {
+		if a.ipc != nil && a.terminal != nil {
+			a.ipc.SetCommandHandler(func(r ipc.CommandReport) {
+				a.terminal.Report(r.Command, r.ExitCode, r.Dir)
+			})
+		} else {
+			log.Printf("Warning: Terminal commands can't be captured without the IPC server")
+		}
+	}
+	if a.ipc != nil {
+		a.ipc.SetHealthHandler(a.WatcherHealth)
+	}
+
+	// Serve metrics
+	if a.config.MetricsAddr != "" {
+		a.store.RegisterMetrics()
+		metrics.Serve(a.config.MetricsAddr)
+	}
+
+	// Start importance decay (daily), expiry sweeps and link inference
+	// (hourly), the embedding worker and watcher health checks
+	a.wg.Add(5)
 	go a.decayLoop()
+	go a.sweepLoop()
+	go a.linkLoop()
+	go a.embedLoop()
+	go a.healthLoop()
 
 	log.Println("MemoryPilot agent started")
 	return nil
@@ -108,12 +269,20 @@ func (a *Agent) Stop() {
 	a.cancel()
 
 	// Stop watchers
+	a.watchersMu.Lock()
 	for _, w := range a.watchers {
 		w.Stop()
 	}
+	a.watchersMu.Unlock()
 
-	// Wait for goroutines
+	// Wait for goroutines, then for hooks they fired
 	a.wg.Wait()
+	a.hooks.Wait()
+
+	// Disconnect watch clients
+	if a.ipc != nil {
+		a.ipc.Stop()
+	}
 
 	// Close store
 	a.store.Close()
@@ -123,29 +292,23 @@ func (a *Agent) Stop() {
 
 // startWatchers initializes and starts all watchers
 func (a *Agent) startWatchers() error {
-	// Git watcher
-	gitWatcher := watcher.NewGitWatcher(a.config.GitInterval, a.eventQueue)
-	if err := gitWatcher.Start(); err != nil {
-		log.Printf("Warning: Git watcher failed to start: %v", err)
-	} else {
-		a.watchers = append(a.watchers, gitWatcher)
-	}
-
-	// File watcher
-	fileWatcher := watcher.NewFileWatcher(a.config.FileDebounce, a.eventQueue)
-	if err := fileWatcher.Start(); err != nil {
-		log.Printf("Warning: File watcher failed to start: %v", err)
-	} else {
-		a.watchers = append(a.watchers, fileWatcher)
-	}
-
-	// Terminal watcher
-	termWatcher := watcher.NewTerminalWatcher(a.eventQueue)
-	if err := termWatcher.Start(); err != nil {
-		log.Printf("Warning: Terminal watcher failed to start: %v", err)
-	} else {
-		a.watchers = append(a.watchers, termWatcher)
-	}
+	a.startWatcher("Git", func() watcher.Watcher {
+		return watcher.NewGitWatcher(a.config.GitInterval, a.eventQueue)
+	})
+
+	a.startWatcher("File", func() watcher.Watcher {
+		fileWatcher := watcher.NewFileWatcher(a.config.FileDebounce, a.eventQueue)
+		fileWatcher.SetMinDiffLines(a.config.MinDiffLines)
+		return fileWatcher
+	})
+
+	// The terminal watcher can't die, so it is only ever created once and
+	// shell hooks can report to it
+	a.terminal = watcher.NewTerminalWatcher(a.eventQueue)
+	a.terminal.SetReadHistory(!a.config.CaptureCommands)
+	a.startWatcher("Terminal", func() watcher.Watcher {
+		return a.terminal
+	})
 
 	return nil
 }
@@ -167,8 +330,12 @@ func (a *Agent) processEvents() {
 			return
 
 		case event := <-a.eventQueue:
-			// Store event
-			if err := a.store.CreateEvent(&event); err != nil {
+			// Store event, without the secrets it may hold
+			a.redactor.RedactEvent(&event)
+			event.ProjectID = a.projectFor(event)
+			err := a.store.CreateEvent(&event)
+			metrics.DaemonEvents.Inc(string(event.Type), metrics.Result(err))
+			if err != nil {
 				log.Printf("Failed to store event: %v", err)
 				continue
 			}
@@ -194,33 +361,204 @@ func (a *Agent) processEvents() {
 func (a *Agent) processBatch(events []models.Event) {
 	log.Printf("Processing batch of %d events...", len(events))
 
-	// Extract memories using LLM
+	// Drop events from ignored repositories and group the rest by the
+	// repository rule they fall under, so its overrides apply to the
+	// memories extracted from them. File diffs are remembered as they are;
+	// everything else goes through the extractor.
+	var rules []*repos.Rule
+	groups := make(map[*repos.Rule][]models.Event)
+	ignored := 0
+	for _, e := range events {
+		rule, ok := a.repoRule(e)
+		if !ok {
+			ignored++
+			continue
+		}
+		if _, isDiff := e.Data["diff"].(string); isDiff && e.Type == "file_change" {
+			a.captureDiff(e, rule)
+			continue
+		}
+		if code, _ := e.Data["exit_code"].(int); code != 0 && e.Type == "terminal_cmd" {
+			a.captureFailedCommand(e, rule)
+			continue
+		}
+		if _, seen := groups[rule]; !seen {
+			rules = append(rules, rule)
+		}
+		groups[rule] = append(groups[rule], e)
+	}
+	if ignored > 0 {
+		log.Printf("Skipped %d events from ignored repositories", ignored)
+	}
+
+	for _, rule := range rules {
+		a.extractMemories(groups[rule], rule)
+	}
+
+	// Mark events as processed, even if extraction failed, to avoid
+	// reprocessing
+	for _, e := range events {
+		if err := a.store.MarkEventProcessed(e.ID); err != nil {
+			log.Printf("Failed to mark event processed: %v", err)
+		}
+	}
+
+	log.Printf("Batch processed")
+}
+
+// captured queues a newly stored memory for embedding in the background,
+// publishes it to watch clients and fires the hooks matching it
+func (a *Agent) captured(memory models.Memory) {
+	a.queueEmbedding()
+
+	if a.ipc != nil {
+		a.ipc.PublishMemory(memory)
+	}
+	a.hooks.Fire(memory)
+
+	log.Printf("Created memory: [%s] %s (importance %.2f)", memory.Type, memory.Summary, memory.Importance)
+}
+
+// repoRule returns the repository rule an event falls under, if any, and
+// whether to capture from it at all. Events not tied to a repository or
+// file are always captured.
+func (a *Agent) repoRule(e models.Event) (*repos.Rule, bool) {
+	path, _ := e.Data["repo"].(string)
+	if path == "" {
+		path, _ = e.Data["path"].(string)
+	}
+	if path == "" {
+		path, _ = e.Data["dir"].(string)
+	}
+	if path == "" {
+		return nil, true
+	}
+	return a.repos.Match(path)
+}
+
+// projectFor returns the ID of the project an event happened in: the
+// repository it names, or the one containing its file or directory. Events
+// outside a repository have none.
+func (a *Agent) projectFor(e models.Event) *string {
+	root, _ := e.Data["repo"].(string)
+	if root == "" {
+		dir, _ := e.Data["dir"].(string)
+		if path, ok := e.Data["path"].(string); ok && path != "" {
+			dir = filepath.Dir(path)
+		}
+		root = repoRoot(dir)
+	}
+	if root == "" {
+		return nil
+	}
+	project, err := a.store.EnsureProject(root)
+	if err != nil || project == nil {
+		log.Printf("Failed to track project %s: %v", root, err)
+		return nil
+	}
+	return &project.ID
+}
+
+// repoRoot returns the root of the git repository containing dir, or ""
+func repoRoot(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	for dir = filepath.Clean(dir); ; {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// sharedProject returns the project all events happened in, or nil if they
+// span several or none
+func sharedProject(events []models.Event) *string {
+	var project *string
+	for i, e := range events {
+		switch {
+		case e.ProjectID == nil:
+			return nil
+		case i == 0:
+			project = e.ProjectID
+		case *e.ProjectID != *project:
+			return nil
+		}
+	}
+	return project
+}
+
+// applyRepoRule applies a repository rule's importance and scope overrides
+// to a memory
+func applyRepoRule(m *models.Memory, rule *repos.Rule) {
+	if rule == nil {
+		return
+	}
+	if rule.Importance != nil {
+		m.Importance = *rule.Importance
+	}
+	if rule.Scope != "" {
+		m.Scope = rule.Scope
+	}
+}
+
+// extractMemories extracts memories from events with the LLM and stores
+// them, applying the overrides of the repository rule the events fall
+// under. Commits are extracted one at a time so their memories can be keyed
+// by SHA: capturing a commit again, say after a restart, updates them
+// instead of adding duplicates.
+func (a *Agent) extractMemories(events []models.Event, rule *repos.Rule) {
+	var rest []models.Event
+	for _, e := range events {
+		if hash, _ := e.Data["hash"].(string); e.Type == "git_commit" && hash != "" {
+			a.extract([]models.Event{e}, hash, rule)
+			continue
+		}
+		rest = append(rest, e)
+	}
+	if len(rest) > 0 {
+		a.extract(rest, "", rule)
+	}
+}
+
+// extract extracts memories from events and stores them. Memories from a
+// commit reference its SHA, the second and later ones with "#n" appended.
+func (a *Agent) extract(events []models.Event, commit string, rule *repos.Rule) {
 	extracted, err := a.extractor.Extract(events)
 	if err != nil {
 		log.Printf("Extraction failed: %v", err)
-		// Still mark events as processed to avoid reprocessing
-		for _, e := range events {
-			a.store.MarkEventProcessed(e.ID)
-		}
 		return
 	}
 
 	log.Printf("Extracted %d memories from batch", len(extracted))
 
 	// Create memories in store
-	for _, ext := range extracted {
+	for i, ext := range extracted {
+		reference := "batch"
+		if commit != "" {
+			reference = commit
+			if i > 0 {
+				reference += "#" + strconv.Itoa(i+1)
+			}
+		}
 		now := time.Now()
 		memory := models.Memory{
-			ID:      ulid.Make().String(),
+			ID:      ids.New(),
 			Type:    models.MemoryType(ext.Type),
 			Content: ext.Content,
 			Summary: ext.Summary,
 			Scope:   models.MemoryScopePersonal,
 			Source: models.Source{
 				Type:      models.SourceTypeGit, // Default, could be smarter
-				Reference: "batch",
+				Reference: reference,
 				Timestamp: now,
 			},
+			ProjectID:      sharedProject(events),
 			Confidence:     ext.Confidence,
 			Importance:     1.0,
 			Topics:         ext.Topics,
@@ -228,6 +566,19 @@ func (a *Agent) processBatch(events []models.Event) {
 			LastAccessedAt: now,
 			AccessCount:    0,
 		}
+		// The model may overshoot the summary length of the type, or
+		// leave the summary out
+		if memory.Summary == "" {
+			memory.Summary = memory.Content
+		}
+		memory.Summary = summary.Truncate(memory.Summary, a.summaries.For(memory.Type))
+
+		// The extractor doesn't say which events a memory came from, so
+		// score against the whole batch
+		memory.Importance = a.scorer.Score(&memory, events)
+		applyRepoRule(&memory, rule)
+		a.lifetimes.Apply(&memory)
+		a.redactor.RedactMemory(&memory)
 
 		// Save memory
 		if err := a.store.CreateMemory(&memory); err != nil {
@@ -235,27 +586,116 @@ func (a *Agent) processBatch(events []models.Event) {
 			continue
 		}
 
-		// Generate and store embedding
-		emb, err := a.embedder.Embed(memory.Content)
-		if err != nil {
-			log.Printf("Failed to generate embedding: %v", err)
-		} else if emb != nil {
-			if err := a.store.UpdateMemoryEmbedding(memory.ID, emb); err != nil {
-				log.Printf("Failed to store embedding: %v", err)
-			}
-		}
+		a.captured(memory)
+	}
+}
 
-		log.Printf("Created memory: [%s] %s", memory.Type, memory.Summary)
+// captureDiff remembers a file change event's diff against HEAD as a
+// pattern memory referencing the file, applying the overrides of the
+// repository rule it falls under
+func (a *Agent) captureDiff(e models.Event, rule *repos.Rule) {
+	path, _ := e.Data["path"].(string)
+	rel, _ := e.Data["relpath"].(string)
+	diff, _ := e.Data["diff"].(string)
+	added, _ := e.Data["added"].(int)
+	removed, _ := e.Data["removed"].(int)
+	functions, _ := e.Data["functions"].([]string)
+
+	summary := fmt.Sprintf("Changed %s (+%d -%d)", rel, added, removed)
+	if len(functions) > 0 {
+		summary = fmt.Sprintf("Changed %s in %s (+%d -%d)", strings.Join(functions[:min(3, len(functions))], ", "), rel, added, removed)
 	}
 
-	// Mark events as processed
-	for _, e := range events {
-		if err := a.store.MarkEventProcessed(e.ID); err != nil {
-			log.Printf("Failed to mark event processed: %v", err)
-		}
+	now := time.Now()
+	memory := models.Memory{
+		ID:          ids.New(),
+		Type:        models.MemoryTypePattern,
+		Content:     rel + "\n" + diff,
+		Summary:     summary,
+		ContentType: models.ContentTypeCode,
+		Scope:       models.MemoryScopePersonal,
+		ProjectID:   e.ProjectID,
+		Source: models.Source{
+			Type:      models.SourceTypeFile,
+			Reference: path,
+			Timestamp: e.Timestamp,
+		},
+		Confidence:     1.0,
+		Topics:         []string{},
+		CreatedAt:      now,
+		LastAccessedAt: now,
+	}
+	if repo, ok := e.Data["repo"].(string); ok {
+		memory.Metadata = map[string]string{"repo": repo}
 	}
+	memory.Importance = a.scorer.Score(&memory, []models.Event{e})
+	applyRepoRule(&memory, rule)
+	a.lifetimes.Apply(&memory)
+	a.redactor.RedactMemory(&memory)
 
-	log.Printf("Batch processed")
+	if existing, err := a.store.FindByContent(memory.Content); err == nil && existing != nil {
+		return
+	}
+	if err := a.store.CreateMemory(&memory); err != nil {
+		log.Printf("Failed to save memory: %v", err)
+		return
+	}
+
+	a.captured(memory)
+}
+
+// captureFailedCommand remembers a terminal command reported as failed as
+// a potential mistake, applying the overrides of the repository rule it
+// falls under. The same command failing with the same exit code again is
+// remembered once.
+func (a *Agent) captureFailedCommand(e models.Event, rule *repos.Rule) {
+	command, _ := e.Data["command"].(string)
+	code, _ := e.Data["exit_code"].(int)
+	dir, _ := e.Data["dir"].(string)
+
+	content := fmt.Sprintf("Command failed with exit code %d: %s", code, command)
+	topics := []string{"failed-command"}
+	if fields := strings.Fields(command); len(fields) > 0 {
+		topics = append(topics, filepath.Base(fields[0]))
+	}
+
+	now := time.Now()
+	memory := models.Memory{
+		ID:        ids.New(),
+		Type:      models.MemoryTypeMistake,
+		Content:   content,
+		Summary:   summary.Truncate(content, a.summaries.For(models.MemoryTypeMistake)),
+		Scope:     models.MemoryScopePersonal,
+		ProjectID: e.ProjectID,
+		Source: models.Source{
+			Type:      models.SourceTypeTerminal,
+			Reference: command,
+			Timestamp: e.Timestamp,
+		},
+		// A failure is only a potential lesson
+		Confidence:     0.7,
+		Topics:         topics,
+		Metadata:       map[string]string{"outcome": "failed", "exit_code": strconv.Itoa(code)},
+		CreatedAt:      now,
+		LastAccessedAt: now,
+	}
+	if dir != "" {
+		memory.Metadata["dir"] = dir
+	}
+	memory.Importance = a.scorer.Score(&memory, []models.Event{e})
+	applyRepoRule(&memory, rule)
+	a.lifetimes.Apply(&memory)
+	a.redactor.RedactMemory(&memory)
+
+	if existing, err := a.store.FindByContent(memory.Content); err == nil && existing != nil {
+		return
+	}
+	if err := a.store.CreateMemory(&memory); err != nil {
+		log.Printf("Failed to save memory: %v", err)
+		return
+	}
+
+	a.captured(memory)
 }
 
 // decayLoop periodically decays memory importance
@@ -270,9 +710,60 @@ func (a *Agent) decayLoop() {
 		case <-a.ctx.Done():
 			return
 		case <-ticker.C:
-			if err := a.store.DecayImportance(); err != nil {
+			if err := a.store.DecayImportance(a.lifetimes.DecayRates()); err != nil {
 				log.Printf("Failed to decay importance: %v", err)
 			}
 		}
 	}
 }
+
+// sweepLoop periodically deletes expired memories, then evicts memories
+// over the store's cap
+func (a *Agent) sweepLoop() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if n, err := a.store.DeleteExpired(); err != nil {
+			log.Printf("Failed to delete expired memories: %v", err)
+		} else if n > 0 {
+			log.Printf("Deleted %d expired memories", n)
+		}
+		if n, err := a.store.EvictMemories(); err != nil {
+			log.Printf("Failed to evict memories: %v", err)
+		} else if n > 0 {
+			log.Printf("Evicted %d memories over the memory cap", n)
+		}
+
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// linkLoop periodically rebuilds the links inferred from memories recalled
+// together
+func (a *Agent) linkLoop() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := a.store.InferLinks(a.config.LinkThreshold, a.config.MaxInferredLinks)
+			if err != nil {
+				log.Printf("Failed to infer links: %v", err)
+			} else {
+				log.Printf("Inferred %d links between memories recalled together", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+)
+
+// Embedding worker defaults
+const (
+	DefaultEmbedWorkers = 2
+	embedBatchSize      = 50
+	embedPollInterval   = time.Minute
+	embedRetryMin       = 30 * time.Second
+	embedRetryMax       = 10 * time.Minute
+)
+
+// queueEmbedding wakes the embedding worker after a memory was stored
+func (a *Agent) queueEmbedding() {
+	select {
+	case a.embedWake <- struct{}{}:
+	default:
+	}
+}
+
+// embedLoop embeds memories stored without an embedding, whether captured
+// by the daemon or saved elsewhere while the embedder was down. It drains
+// the backlog when woken by queueEmbedding and every embedPollInterval,
+// backing off exponentially while the embedder fails.
+func (a *Agent) embedLoop() {
+	defer a.wg.Done()
+
+	retry := embedRetryMin
+	for {
+		wait := embedPollInterval
+		switch err := a.embedPending(); {
+		case err != nil:
+			log.Printf("Embedding backlog paused for %s: %v", retry, err)
+			wait = retry
+			retry = min(retry*2, embedRetryMax)
+		default:
+			retry = embedRetryMin
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-a.ctx.Done():
+			timer.Stop()
+			return
+		case <-a.embedWake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// embedPending embeds batches of memories without an embedding, up to
+// Config.EmbedWorkers at a time, until none are left. It stops at the
+// first batch with a failure, leaving the rest for a retry; memories
+// already embedded keep their embedding.
+func (a *Agent) embedPending() error {
+	workers := a.config.EmbedWorkers
+	if workers <= 0 {
+		workers = DefaultEmbedWorkers
+	}
+
+	for a.ctx.Err() == nil {
+		memories, err := a.store.ListMemories(store.ListOptions{MissingEmbedding: true, Limit: embedBatchSize})
+		if err != nil || len(memories) == 0 {
+			return err
+		}
+
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			firstErr error
+			embedded int
+		)
+		sem := make(chan struct{}, workers)
+		for _, m := range memories {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(id, content string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				emb, err := embedding.EmbedContext(a.ctx, a.embedder, content)
+				if err == nil && emb != nil {
+					err = a.store.UpdateMemoryEmbedding(id, emb, embedding.ModelName(a.embedder))
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				switch {
+				case err != nil:
+					if firstErr == nil {
+						firstErr = err
+					}
+				case emb != nil:
+					embedded++
+				}
+			}(m.ID, m.Content)
+		}
+		wg.Wait()
+
+		if embedded > 0 {
+			log.Printf("Embedded %d memories", embedded)
+		}
+		if firstErr != nil {
+			return firstErr
+		}
+		if embedded == 0 {
+			// No embedding backend; try again on the next poll
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"log"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/internal/metrics"
+	"github.com/contextpilot-dev/memorypilot/internal/watcher"
+)
+
+// watcherHealthInterval is how often watchers are checked and dead ones
+// restarted
+const watcherHealthInterval = time.Minute
+
+// watched is a watcher the agent keeps alive
+type watched struct {
+	watcher.Watcher
+	create      func() watcher.Watcher // Makes a fresh watcher to replace a dead one
+	restarts    int
+	lastRestart *time.Time
+}
+
+// startWatcher creates and starts a watcher and keeps it alive. One that
+// fails to start is kept anyway, dead, so the health check retries it.
+func (a *Agent) startWatcher(name string, create func() watcher.Watcher) {
+	w := &watched{Watcher: create(), create: create}
+	if err := w.Start(); err != nil {
+		log.Printf("Warning: %s watcher failed to start: %v", name, err)
+	}
+
+	a.watchersMu.Lock()
+	a.watchers = append(a.watchers, w)
+	a.watchersMu.Unlock()
+}
+
+// healthLoop restarts dead watchers every watcherHealthInterval, so a
+// watcher that stopped (its event stream closed, or a watched directory
+// was unmounted) doesn't leave the daemon running without capturing
+func (a *Agent) healthLoop() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(watcherHealthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			a.restartDeadWatchers()
+		}
+	}
+}
+
+// restartDeadWatchers replaces each dead watcher with a fresh one
+func (a *Agent) restartDeadWatchers() {
+	a.watchersMu.Lock()
+	defer a.watchersMu.Unlock()
+
+	for _, w := range a.watchers {
+		h := w.Health()
+		if h.Alive {
+			continue
+		}
+
+		log.Printf("⚠️ %s watcher died: %s; restarting it", h.Name, h.Error)
+		w.Stop()
+		w.Watcher = w.create()
+		now := time.Now()
+		w.restarts++
+		w.lastRestart = &now
+
+		err := w.Start()
+		metrics.WatcherRestarts.Inc(h.Name, metrics.Result(err))
+		if err != nil {
+			log.Printf("Warning: %s watcher failed to restart: %v", h.Name, err)
+			continue
+		}
+		log.Printf("%s watcher recovered (restart %d)", h.Name, w.restarts)
+	}
+}
+
+// WatcherHealth reports whether each watcher is capturing and how often it
+// was restarted
+func (a *Agent) WatcherHealth() []watcher.Health {
+	a.watchersMu.Lock()
+	defer a.watchersMu.Unlock()
+
+	health := make([]watcher.Health, 0, len(a.watchers))
+	for _, w := range a.watchers {
+		h := w.Health()
+		h.Restarts = w.restarts
+		h.LastRestart = w.lastRestart
+		health = append(health, h)
+	}
+	return health
+}
@@ -0,0 +1,492 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+	"github.com/contextpilot-dev/memorypilot/internal/ids"
+	"github.com/contextpilot-dev/memorypilot/internal/lifetime"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/internal/summary"
+	"github.com/contextpilot-dev/memorypilot/internal/tracing"
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// DefaultLimit is the number of results when no limit is given
+const DefaultLimit = 20
+
+// MaxLimit is the most results one request returns
+const MaxLimit = 500
+
+// Server exposes the memory store as a JSON REST API:
+//
+//	GET    /memories          list (type, topic, project, since, limit)
+//	POST   /memories          create
+//	GET    /memories/{id}     fetch one
+//	PUT    /memories/{id}     replace content
+//	DELETE /memories/{id}     delete
+//	POST   /recall            search
+type Server struct {
+	store      *store.Store
+	embedder   embedding.Embedder
+	summarizer summary.Summarizer
+	token      string // Required bearer token; empty disables auth
+	listenHost string // Host the server listens on; empty accepts any Host header
+
+	embedTimeout time.Duration // How long recall waits for the query embedding
+
+	lifetimes lifetime.Policy // Default expiry of new memories by type
+}
+
+// NewServer creates an API server for the store
+func NewServer(s *store.Store, embedder embedding.Embedder) *Server {
+	return &Server{
+		store:      s,
+		embedder:   embedder,
+		summarizer: summary.NewTruncatingSummarizer(summary.DefaultMaxLen),
+
+		embedTimeout: embedding.DefaultQueryTimeout,
+
+		lifetimes: lifetime.DefaultPolicy(),
+	}
+}
+
+// SetToken requires every request to carry "Authorization: Bearer <token>".
+// An empty token disables authentication.
+func (s *Server) SetToken(token string) {
+	s.token = token
+}
+
+// SetListenAddr restricts the Host header to the host of the listen address
+// or a loopback name, so a DNS-rebinding page can't reach a loopback server
+// under its own domain. A wildcard address accepts any Host header.
+func (s *Server) SetListenAddr(addr string) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = ""
+	}
+	s.listenHost = host
+}
+
+// SetSummarizer sets how summaries are generated for new and updated memories
+func (s *Server) SetSummarizer(sum summary.Summarizer) {
+	s.summarizer = sum
+}
+
+// SetEmbedTimeout sets how long recall waits for the query embedding before
+// falling back to keyword search (or failing, in semantic mode). 0 or less
+// restores the default.
+func (s *Server) SetEmbedTimeout(d time.Duration) {
+	if d <= 0 {
+		d = embedding.DefaultQueryTimeout
+	}
+	s.embedTimeout = d
+}
+
+// SetLifetimes sets the per-type lifetimes that give new memories their
+// default expiry
+func (s *Server) SetLifetimes(p lifetime.Policy) {
+	s.lifetimes = p
+}
+
+// Handler returns the HTTP handler serving the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /memories", s.handleList)
+	mux.HandleFunc("POST /memories", s.handleCreate)
+	mux.HandleFunc("GET /memories/{id}", s.handleGet)
+	mux.HandleFunc("PUT /memories/{id}", s.handleUpdate)
+	mux.HandleFunc("DELETE /memories/{id}", s.handleDelete)
+	mux.HandleFunc("POST /recall", s.handleRecall)
+	return s.checkHost(s.authenticate(mux))
+}
+
+// checkHost rejects requests whose Host header names neither the listen
+// address nor a loopback host
+func (s *Server) checkHost(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.listenHost != "" && !s.allowedHost(r.Host) {
+			writeError(w, http.StatusMisdirectedRequest, fmt.Sprintf("host %q not allowed", r.Host))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowedHost reports whether a Host header value may reach the server
+func (s *Server) allowedHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = strings.Trim(hostport, "[]")
+	}
+	if strings.EqualFold(host, s.listenHost) || strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// authenticate rejects requests without the configured bearer token
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="memorypilot"`)
+				writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, err := parseLimit(q.Get("limit"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	opts := store.ListOptions{
+		Topics:    q["topic"],
+		ProjectID: q.Get("project"),
+		Limit:     limit,
+	}
+	for _, t := range q["type"] {
+		opts.Types = append(opts.Types, models.MemoryType(t))
+	}
+	if since := q.Get("since"); since != "" {
+		opts.Since, err = time.Parse(time.RFC3339, since)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "since must be an RFC 3339 time")
+			return
+		}
+	}
+
+	memories, err := s.store.ListMemories(opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if memories == nil {
+		memories = []models.Memory{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"memories": memories, "count": len(memories)})
+}
+
+// memoryInput is the body of create and update requests
+type memoryInput struct {
+	Content     string             `json:"content"`
+	Type        models.MemoryType  `json:"type"`
+	ContentType models.ContentType `json:"contentType"` // Detected when empty
+	Topics      []string           `json:"topics"`
+	Metadata    map[string]string  `json:"metadata"`
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var in memoryInput
+	if !decodeBody(w, r, &in) {
+		return
+	}
+	if in.Type == "" {
+		in.Type = models.MemoryTypeFact
+	}
+	if strings.TrimSpace(in.Content) == "" {
+		writeError(w, http.StatusBadRequest, "content is required")
+		return
+	}
+	if !in.Type.Valid() {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid type %q", in.Type))
+		return
+	}
+	if in.ContentType != "" && !in.ContentType.Valid() {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid content type %q", in.ContentType))
+		return
+	}
+	if err := store.ValidateMetadata(in.Metadata); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	now := time.Now()
+	m := &models.Memory{
+		ID:          ids.New(),
+		Type:        in.Type,
+		Content:     in.Content,
+		Summary:     s.summarize(in.Content, in.Type),
+		ContentType: in.ContentType,
+		Scope:       models.MemoryScopePersonal,
+		Source: models.Source{
+			Type:      models.SourceTypeManual,
+			Reference: "api",
+			Timestamp: now,
+		},
+		Confidence:     1.0,
+		Importance:     1.0,
+		Topics:         in.Topics,
+		Metadata:       in.Metadata,
+		CreatedAt:      now,
+		LastAccessedAt: now,
+	}
+	s.lifetimes.Apply(m)
+	if err := s.store.CreateMemory(m); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.embed(m.ID, m.Content)
+
+	s.writeMemory(w, http.StatusCreated, m.ID)
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	s.writeMemory(w, http.StatusOK, r.PathValue("id"))
+}
+
+func (s *Server) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var in memoryInput
+	if !decodeBody(w, r, &in) {
+		return
+	}
+	if strings.TrimSpace(in.Content) == "" {
+		writeError(w, http.StatusBadRequest, "content is required")
+		return
+	}
+
+	existing, err := s.store.GetMemory(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if existing == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("memory %s not found", id))
+		return
+	}
+
+	if err := s.store.UpdateMemoryContent(id, in.Content, s.summarize(in.Content, existing.Type), "api"); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.embed(id, in.Content)
+
+	s.writeMemory(w, http.StatusOK, id)
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	deleted, err := s.store.DeleteMemory(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !deleted {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("memory %s not found", id))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// recallInput is the body of a recall request. It accepts the same filters
+// as the memorypilot_recall MCP tool.
+type recallInput struct {
+	Query         string            `json:"query"`
+	Limit         int               `json:"limit"`
+	Mode          string            `json:"mode"`
+	MinScore      float32           `json:"min_score"`
+	Types         []string          `json:"types"`
+	Source        []string          `json:"source"`
+	ContentTypes  []string          `json:"content_type"`
+	Topics        []string          `json:"topics"`
+	ExcludeTopics []string          `json:"exclude_topics"`
+	Metadata      map[string]string `json:"metadata"`
+	Explain       bool              `json:"explain"`
+	IncludeLow    bool              `json:"include_low"`
+}
+
+func (s *Server) handleRecall(w http.ResponseWriter, r *http.Request) {
+	var in recallInput
+	if !decodeBody(w, r, &in) {
+		return
+	}
+	if err := store.ValidateMetadata(in.Metadata); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if in.Limit < 0 || in.Limit > MaxLimit {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 0 and %d", MaxLimit))
+		return
+	}
+
+	req := models.RecallRequest{
+		Query:         in.Query,
+		Limit:         in.Limit,
+		MinScore:      in.MinScore,
+		Topics:        in.Topics,
+		ExcludeTopics: in.ExcludeTopics,
+		Metadata:      in.Metadata,
+		Explain:       in.Explain,
+		IncludeLow:    in.IncludeLow,
+	}
+	if req.Limit == 0 {
+		req.Limit = DefaultLimit
+	}
+	for _, t := range in.Types {
+		req.Types = append(req.Types, models.MemoryType(t))
+	}
+	for _, src := range in.Source {
+		req.SourceTypes = append(req.SourceTypes, models.SourceType(src))
+	}
+	for _, t := range in.ContentTypes {
+		req.ContentTypes = append(req.ContentTypes, models.ContentType(t))
+	}
+
+	// Continue the caller's trace if it sent a traceparent header
+	ctx, span := tracing.Start(tracing.WithTraceparent(r.Context(), r.Header.Get("Traceparent")), "api.recall")
+	defer span.End()
+	span.SetAttr("query.length", len(in.Query))
+	span.SetAttr("recall.mode", in.Mode)
+
+	var memories []models.Memory
+	var err error
+	switch in.Mode {
+	case "", "hybrid":
+		if queryEmb, embErr := embedding.EmbedTimeout(ctx, s.embedder, in.Query, s.embedTimeout); embErr == nil && embedding.Valid(queryEmb) {
+			memories, err = s.store.HybridSearchContext(ctx, req, queryEmb)
+		} else {
+			memories, err = s.store.Recall(req)
+		}
+	case "semantic":
+		queryEmb, embErr := embedding.EmbedTimeout(ctx, s.embedder, in.Query, s.embedTimeout)
+		if errors.Is(embErr, embedding.ErrTimeout) {
+			writeError(w, http.StatusServiceUnavailable, "semantic search unavailable: "+embErr.Error())
+			return
+		}
+		if embErr != nil || !embedding.Valid(queryEmb) {
+			writeError(w, http.StatusServiceUnavailable, "semantic search unavailable: no working embedding backend")
+			return
+		}
+		memories, err = s.store.SemanticSearch(req, queryEmb)
+	case "keyword":
+		memories, err = s.store.Recall(req)
+	case "exact":
+		req.Exact = true
+		memories, err = s.store.Recall(req)
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid mode %q (expected hybrid, semantic, keyword or exact)", in.Mode))
+		return
+	}
+	if err != nil {
+		span.SetError(err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if memories == nil {
+		memories = []models.Memory{}
+	}
+	span.SetAttr("result.count", len(memories))
+
+	writeJSON(w, http.StatusOK, models.RecallResponse{
+		Memories: memories,
+		Total:    len(memories),
+		Query:    in.Query,
+	})
+}
+
+// summarize generates a summary of the length configured for memories of
+// type t, logging (but tolerating) backend failures
+func (s *Server) summarize(content string, t models.MemoryType) string {
+	text, err := summary.ForType(s.summarizer, content, t)
+	if err != nil {
+		log.Printf("Summarizer failed, using truncated summary: %v", err)
+	}
+	return text
+}
+
+// embed stores an embedding for a memory, best effort
+func (s *Server) embed(id, content string) {
+	emb, err := s.embedder.Embed(content)
+	if err != nil || emb == nil {
+		return
+	}
+	if err := s.store.UpdateMemoryEmbedding(id, emb, embedding.ModelName(s.embedder)); err != nil {
+		log.Printf("Failed to store embedding for %s: %v", id, err)
+	}
+}
+
+// writeMemory responds with the stored memory, or 404 if it doesn't exist
+func (s *Server) writeMemory(w http.ResponseWriter, status int, id string) {
+	m, err := s.store.GetMemory(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if m == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("memory %s not found", id))
+		return
+	}
+	writeJSON(w, status, m)
+}
+
+// parseLimit parses a limit query parameter, defaulting and capping it
+func parseLimit(value string) (int, error) {
+	if value == "" {
+		return DefaultLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > MaxLimit {
+		return 0, fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+	}
+	return limit, nil
+}
+
+// maxBodySize bounds request bodies
+const maxBodySize = 1 << 20
+
+// decodeBody decodes a JSON request body, responding with 415 when it isn't
+// sent as JSON and 400 when it fails to decode
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	// Only JSON bodies are accepted, so browsers must send a CORS preflight
+	// before a page can write to the API
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return false
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return false
+		}
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
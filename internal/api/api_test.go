@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+)
+
+// newTestHandler returns the handler of a server on a fresh database,
+// listening on addr without a token
+func newTestHandler(t *testing.T, addr string) http.Handler {
+	t.Helper()
+	s, err := store.New(filepath.Join(t.TempDir(), "memories.db"))
+	if err != nil {
+		t.Fatalf("store.New: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	server := NewServer(s, &embedding.NullEmbedder{})
+	server.SetListenAddr(addr)
+	return server.Handler()
+}
+
+func TestCreateRequiresJSONContentType(t *testing.T) {
+	h := newTestHandler(t, "127.0.0.1:9090")
+	tests := []struct {
+		contentType string
+		want        int
+	}{
+		{"application/json", http.StatusCreated},
+		{"application/json; charset=utf-8", http.StatusCreated},
+		{"text/plain", http.StatusUnsupportedMediaType},
+		{"application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"", http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9090/memories", strings.NewReader(`{"content":"planted"}`))
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("Content-Type %q: status %d, want %d", tt.contentType, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestHostHeader(t *testing.T) {
+	tests := []struct {
+		listen string
+		host   string
+		want   int
+	}{
+		{"127.0.0.1:9090", "127.0.0.1:9090", http.StatusOK},
+		{"127.0.0.1:9090", "localhost:9090", http.StatusOK},
+		{"127.0.0.1:9090", "[::1]:9090", http.StatusOK},
+		{"127.0.0.1:9090", "attacker.example:9090", http.StatusMisdirectedRequest},
+		{"127.0.0.1:9090", "attacker.example", http.StatusMisdirectedRequest},
+		{"memory.lan:9090", "memory.lan:9090", http.StatusOK},
+		{":9090", "attacker.example:9090", http.StatusOK},
+	}
+	for _, tt := range tests {
+		h := newTestHandler(t, tt.listen)
+		req := httptest.NewRequest(http.MethodGet, "/memories", nil)
+		req.Host = tt.host
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("listen %s, Host %q: status %d, want %d", tt.listen, tt.host, rec.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package chunk
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultMaxContentLength is the default maximum memory content length in
+// characters
+const DefaultMaxContentLength = 16000
+
+// DefaultOverlap is how many characters of context consecutive chunks share
+const DefaultOverlap = 200
+
+// paragraphBreak matches a blank line between paragraphs
+var paragraphBreak = regexp.MustCompile(`\n[ \t]*\n\s*`)
+
+// TooLongError reports content exceeding the maximum length
+type TooLongError struct {
+	Length int
+	Max    int
+}
+
+func (e *TooLongError) Error() string {
+	return fmt.Sprintf("content is %d characters, exceeding the maximum of %d", e.Length, e.Max)
+}
+
+// Check returns a TooLongError if text is longer than maxLen characters.
+// A maxLen of 0 or less disables the check.
+func Check(text string, maxLen int) error {
+	if n := utf8.RuneCountInString(text); maxLen > 0 && n > maxLen {
+		return &TooLongError{Length: n, Max: maxLen}
+	}
+	return nil
+}
+
+// Split breaks text into chunks of at most maxLen characters, preferring
+// paragraph boundaries, then sentence boundaries, then a hard cut.
+// Consecutive chunks repeat up to overlap characters of whole paragraphs or
+// sentences from the end of the previous chunk, so context isn't lost at
+// the seams.
+func Split(text string, maxLen, overlap int) []string {
+	text = strings.TrimSpace(text)
+	if maxLen <= 0 || utf8.RuneCountInString(text) <= maxLen {
+		return []string{text}
+	}
+	if overlap > maxLen/4 {
+		overlap = maxLen / 4
+	}
+
+	var chunks []string
+	var current []string
+	currentLen := 0
+
+	emit := func() {
+		if chunk := strings.TrimSpace(strings.Join(current, "")); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	segs := segments(text, maxLen)
+	for i := 0; i < len(segs); {
+		segLen := utf8.RuneCountInString(segs[i])
+		if len(current) == 0 || currentLen+segLen <= maxLen {
+			current = append(current, segs[i])
+			currentLen += segLen
+			i++
+			continue
+		}
+
+		emit()
+
+		// Carry trailing segments over as overlap, keeping room for the
+		// next segment so the loop always makes progress
+		var keep []string
+		keepLen := 0
+		for j := len(current) - 1; j >= 0; j-- {
+			n := utf8.RuneCountInString(current[j])
+			if keepLen+n > overlap || keepLen+n+segLen > maxLen {
+				break
+			}
+			keep = append([]string{current[j]}, keep...)
+			keepLen += n
+		}
+		current, currentLen = keep, keepLen
+	}
+	emit()
+
+	return chunks
+}
+
+// segments splits text into paragraphs, splitting any paragraph longer
+// than maxLen into sentences and any sentence longer than maxLen into
+// fixed-size pieces. Each segment keeps its trailing whitespace, so joining
+// them reproduces the text.
+func segments(text string, maxLen int) []string {
+	var segs []string
+	for _, para := range splitAfter(text, paragraphBreak.FindAllStringIndex(text, -1)) {
+		if utf8.RuneCountInString(para) <= maxLen {
+			segs = append(segs, para)
+			continue
+		}
+		for _, sentence := range sentences(para) {
+			runes := []rune(sentence)
+			for len(runes) > maxLen {
+				segs = append(segs, string(runes[:maxLen]))
+				runes = runes[maxLen:]
+			}
+			segs = append(segs, string(runes))
+		}
+	}
+	return segs
+}
+
+// splitAfter cuts text at the end of each [start, end) match
+func splitAfter(text string, matches [][]int) []string {
+	var parts []string
+	start := 0
+	for _, m := range matches {
+		parts = append(parts, text[start:m[1]])
+		start = m[1]
+	}
+	if start < len(text) {
+		parts = append(parts, text[start:])
+	}
+	return parts
+}
+
+// sentences splits text after sentence-ending punctuation followed by
+// whitespace, keeping the whitespace with the preceding sentence
+func sentences(text string) []string {
+	var parts []string
+	runes := []rune(text)
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		if !strings.ContainsRune(".!?", runes[i]) || i+1 >= len(runes) || !unicode.IsSpace(runes[i+1]) {
+			continue
+		}
+		end := i + 1
+		for end < len(runes) && unicode.IsSpace(runes[end]) {
+			end++
+		}
+		parts = append(parts, string(runes[start:end]))
+		start = end
+		i = end - 1
+	}
+	if start < len(runes) {
+		parts = append(parts, string(runes[start:]))
+	}
+	return parts
+}
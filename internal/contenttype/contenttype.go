@@ -0,0 +1,148 @@
+// Package contenttype guesses whether memory content is prose, source code,
+// a shell command or configuration, so recall can tell them apart.
+package contenttype
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// fenceLanguages maps the info string of a fenced block to a content type;
+// other languages are code
+var fenceLanguages = map[string]models.ContentType{
+	"sh": models.ContentTypeCommand, "bash": models.ContentTypeCommand, "zsh": models.ContentTypeCommand,
+	"shell": models.ContentTypeCommand, "console": models.ContentTypeCommand, "powershell": models.ContentTypeCommand,
+	"json": models.ContentTypeConfig, "yaml": models.ContentTypeConfig, "yml": models.ContentTypeConfig,
+	"toml": models.ContentTypeConfig, "ini": models.ContentTypeConfig, "env": models.ContentTypeConfig,
+}
+
+// commands are programs whose invocation at the start of a line marks it as
+// a shell command
+var commands = map[string]bool{
+	"apt": true, "apt-get": true, "aws": true, "brew": true, "cargo": true, "cat": true, "cd": true,
+	"chmod": true, "chown": true, "cp": true, "curl": true, "docker": true, "docker-compose": true,
+	"echo": true, "export": true, "find": true, "gcloud": true, "git": true, "go": true, "grep": true,
+	"helm": true, "kubectl": true, "ls": true, "make": true, "mkdir": true, "mv": true, "node": true,
+	"npm": true, "npx": true, "pip": true, "pip3": true, "pnpm": true, "psql": true, "python": true,
+	"python3": true, "rm": true, "rsync": true, "scp": true, "sed": true, "ssh": true, "sudo": true,
+	"systemctl": true, "tar": true, "terraform": true, "wget": true, "yarn": true,
+}
+
+// maxCommandLines is the longest content still considered a command
+const maxCommandLines = 5
+
+var (
+	fence     = regexp.MustCompile("(?m)^\\s*```\\s*([\\w+-]*)")
+	yamlLine  = regexp.MustCompile(`^\s*(- )?[\w.-]+:(\s|$)|^\s*- \S`)
+	tomlLine  = regexp.MustCompile(`^\s*(\[[\w.-]+\]|[\w.-]+\s*=\s*\S)`)
+	codeStart = regexp.MustCompile(`^\s*(func|def|class|import|package|from \S+ import|const|let|var|return|public|private|fn|struct|interface|#include|if\s*\(|for\s*\(|while\s*\()\b`)
+	codeEnd   = regexp.MustCompile(`[;{}]\s*$|\)\s*:\s*$|=>`)
+)
+
+// Detect returns the most likely content type of content. It recognises
+// fenced code blocks (using their language tag), shell commands, JSON, YAML
+// and TOML configuration and source code; anything else is prose.
+func Detect(content string) models.ContentType {
+	text := strings.TrimSpace(content)
+	if text == "" {
+		return models.ContentTypeProse
+	}
+
+	if m := fence.FindStringSubmatch(text); m != nil {
+		if t, ok := fenceLanguages[strings.ToLower(m[1])]; ok {
+			return t
+		}
+		return models.ContentTypeCode
+	}
+
+	if (text[0] == '{' || text[0] == '[') && json.Valid([]byte(text)) {
+		return models.ContentTypeConfig
+	}
+
+	lines := nonEmptyLines(text)
+	if isCommand(lines) {
+		return models.ContentTypeCommand
+	}
+	if isConfig(lines) {
+		return models.ContentTypeConfig
+	}
+	if isCode(lines) {
+		return models.ContentTypeCode
+	}
+	return models.ContentTypeProse
+}
+
+// nonEmptyLines splits text into lines, dropping blank ones
+func nonEmptyLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// isCommand reports whether every line is a shell command: a known program
+// or a "$ " prompt, followed by arguments rather than a sentence
+func isCommand(lines []string) bool {
+	if len(lines) > maxCommandLines {
+		return false
+	}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(line, "$ "); ok {
+			if strings.TrimSpace(rest) == "" {
+				return false
+			}
+			continue
+		}
+		fields := strings.Fields(line)
+		if !commands[fields[0]] || strings.HasSuffix(line, ".") {
+			return false
+		}
+	}
+	return true
+}
+
+// isConfig reports whether the lines are YAML or TOML: at least two lines,
+// nearly all of them keys, list items, tables or comments
+func isConfig(lines []string) bool {
+	if len(lines) < 2 {
+		return false
+	}
+	yaml, toml := 0, 0
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			yaml++
+			toml++
+			continue
+		}
+		if yamlLine.MatchString(line) {
+			yaml++
+		}
+		if tomlLine.MatchString(line) {
+			toml++
+		}
+	}
+	return float64(max(yaml, toml)) >= 0.9*float64(len(lines))
+}
+
+// isCode reports whether enough lines look like source code: statements
+// ending in ; { or }, block openers, arrows or language keywords
+func isCode(lines []string) bool {
+	codeLike := 0
+	for _, line := range lines {
+		if codeStart.MatchString(line) || codeEnd.MatchString(line) {
+			codeLike++
+		}
+	}
+	if len(lines) == 1 {
+		return codeLike == 1 && codeEnd.MatchString(lines[0])
+	}
+	return float64(codeLike) >= 0.4*float64(len(lines))
+}
@@ -0,0 +1,37 @@
+package contenttype
+
+import (
+	"testing"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    models.ContentType
+	}{
+		{"empty", "  ", models.ContentTypeProse},
+		{"prose", "We chose SQLite because the daemon and the MCP server share one file.", models.ContentTypeProse},
+		{"prose starting with a command word", "Make sure the cache is cleared before release.", models.ContentTypeProse},
+		{"command", "docker compose up -d --build", models.ContentTypeCommand},
+		{"prompt", "$ ./scripts/reset-db.sh --force", models.ContentTypeCommand},
+		{"commands", "git fetch origin\ngit rebase origin/main", models.ContentTypeCommand},
+		{"fenced bash", "Run this:\n```bash\nmake test\n```", models.ContentTypeCommand},
+		{"fenced go", "```go\nfmt.Println(\"hi\")\n```", models.ContentTypeCode},
+		{"fenced yaml", "```yaml\nport: 8080\n```", models.ContentTypeConfig},
+		{"json", `{"port": 8080, "debug": true}`, models.ContentTypeConfig},
+		{"yaml", "server:\n  port: 8080\n  host: localhost", models.ContentTypeConfig},
+		{"toml", "[server]\nport = 8080\nhost = \"localhost\"", models.ContentTypeConfig},
+		{"go", "func add(a, b int) int {\n\treturn a + b\n}", models.ContentTypeCode},
+		{"python", "def add(a, b):\n    return a + b", models.ContentTypeCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.content); got != tt.want {
+				t.Errorf("Detect(%q) = %s, want %s", tt.content, got, tt.want)
+			}
+		})
+	}
+}
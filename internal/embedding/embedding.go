@@ -2,12 +2,20 @@ package embedding
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"math"
 	"net/http"
+	"os"
+	"sync"
 	"time"
+
+	"github.com/contextpilot-dev/memorypilot/internal/metrics"
+	"github.com/contextpilot-dev/memorypilot/internal/tracing"
 )
 
 // Embedder generates vector embeddings for text
@@ -16,6 +24,20 @@ type Embedder interface {
 	EmbedBatch(texts []string) ([][]float32, error)
 }
 
+// contextEmbedder is implemented by embedders whose requests can be
+// cancelled through a context
+type contextEmbedder interface {
+	EmbedContext(ctx context.Context, text string) ([]float32, error)
+}
+
+// DefaultQueryTimeout is how long recall waits for a query embedding before
+// falling back to keyword search
+const DefaultQueryTimeout = 2 * time.Second
+
+// ErrTimeout is returned by EmbedTimeout when the backend doesn't answer in
+// time
+var ErrTimeout = errors.New("embedding timed out")
+
 // OllamaEmbedder uses Ollama for embeddings
 type OllamaEmbedder struct {
 	endpoint string
@@ -23,6 +45,16 @@ type OllamaEmbedder struct {
 	client   *http.Client
 }
 
+// ModelName identifies the model behind an embedder as "provider/model",
+// or returns "" if it is unknown or produces no embeddings. Vectors from
+// different models can't be compared.
+func ModelName(e Embedder) string {
+	if m, ok := e.(interface{ Model() string }); ok {
+		return m.Model()
+	}
+	return ""
+}
+
 // NewOllamaEmbedder creates a new Ollama embedder
 func NewOllamaEmbedder(endpoint, model string) *OllamaEmbedder {
 	if endpoint == "" {
@@ -40,6 +72,11 @@ func NewOllamaEmbedder(endpoint, model string) *OllamaEmbedder {
 	}
 }
 
+// Model returns "ollama/" and the model name
+func (e *OllamaEmbedder) Model() string {
+	return "ollama/" + e.model
+}
+
 type ollamaEmbedRequest struct {
 	Model  string `json:"model"`
 	Prompt string `json:"prompt"`
@@ -51,6 +88,12 @@ type ollamaEmbedResponse struct {
 
 // Embed generates an embedding for a single text
 func (e *OllamaEmbedder) Embed(text string) ([]float32, error) {
+	return e.EmbedContext(context.Background(), text)
+}
+
+// EmbedContext generates an embedding for a single text, abandoning the
+// request when ctx is done
+func (e *OllamaEmbedder) EmbedContext(ctx context.Context, text string) ([]float32, error) {
 	req := ollamaEmbedRequest{
 		Model:  e.model,
 		Prompt: text,
@@ -61,7 +104,13 @@ func (e *OllamaEmbedder) Embed(text string) ([]float32, error) {
 		return nil, err
 	}
 
-	resp, err := e.client.Post(e.endpoint+"/api/embeddings", "application/json", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint+"/api/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("ollama request failed: %w", err)
 	}
@@ -99,6 +148,89 @@ func (e *OllamaEmbedder) EmbedBatch(texts []string) ([][]float32, error) {
 	return embeddings, nil
 }
 
+// EmbedContext embeds text with e inside a tracing span that is a child of
+// the span in ctx. It returns ctx's error if ctx is done first.
+func EmbedContext(ctx context.Context, e Embedder, text string) ([]float32, error) {
+	_, span := tracing.Start(ctx, "embedding.Embed")
+	if span == nil {
+		return embedContext(ctx, e, text)
+	}
+	defer span.End()
+
+	if b, ok := e.(interface{ Backend() string }); ok {
+		span.SetAttr("embedding.backend", b.Backend())
+	}
+	span.SetAttr("embedding.model", ModelName(e))
+	span.SetAttr("text.length", len(text))
+
+	vec, err := embedContext(ctx, e, text)
+	span.SetError(err)
+	span.SetAttr("embedding.dimension", len(vec))
+	return vec, err
+}
+
+// EmbedTimeout embeds text like EmbedContext but gives up after timeout (0
+// for no limit), logging that it did and returning an error wrapping
+// ErrTimeout
+func EmbedTimeout(ctx context.Context, e Embedder, text string, timeout time.Duration) ([]float32, error) {
+	if timeout <= 0 {
+		return EmbedContext(ctx, e, text)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	vec, err := EmbedContext(ctx, e, text)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		log.Printf("Embedding backend didn't answer within %s; skipping semantic search", timeout)
+		return nil, fmt.Errorf("%w after %s", ErrTimeout, timeout)
+	}
+	return vec, err
+}
+
+// embedContext embeds text, cancelling the request when ctx is done if e
+// supports it and otherwise abandoning the call
+func embedContext(ctx context.Context, e Embedder, text string) ([]float32, error) {
+	if ce, ok := e.(contextEmbedder); ok {
+		return ce.EmbedContext(ctx, text)
+	}
+	if ctx.Done() == nil {
+		return e.Embed(text)
+	}
+
+	type result struct {
+		vec []float32
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		vec, err := e.Embed(text)
+		done <- result{vec, err}
+	}()
+	select {
+	case r := <-done:
+		return r.vec, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Valid reports whether v can be compared by cosine similarity: it must be
+// non-empty, contain only finite values and have a non-zero norm. Some
+// backends return an empty or all-zero vector instead of an error.
+func Valid(v []float32) bool {
+	if len(v) == 0 {
+		return false
+	}
+	var norm float64
+	for _, x := range v {
+		if math.IsNaN(float64(x)) || math.IsInf(float64(x), 0) {
+			return false
+		}
+		norm += float64(x) * float64(x)
+	}
+	return norm > 0
+}
+
 // CosineSimilarity computes the cosine similarity between two vectors
 func CosineSimilarity(a, b []float32) float32 {
 	if len(a) != len(b) {
@@ -129,3 +261,268 @@ func (e *NullEmbedder) Embed(text string) ([]float32, error) {
 func (e *NullEmbedder) EmbedBatch(texts []string) ([][]float32, error) {
 	return make([][]float32, len(texts)), nil
 }
+
+// OpenAIEmbedder uses the OpenAI embeddings API
+type OpenAIEmbedder struct {
+	endpoint string
+	model    string
+	apiKey   string
+	client   *http.Client
+}
+
+// NewOpenAIEmbedder creates a new OpenAI embedder
+func NewOpenAIEmbedder(endpoint, model, apiKey string) *OpenAIEmbedder {
+	if endpoint == "" {
+		endpoint = "https://api.openai.com/v1"
+	}
+	if model == "" {
+		model = "text-embedding-3-small"
+	}
+	return &OpenAIEmbedder{
+		endpoint: endpoint,
+		model:    model,
+		apiKey:   apiKey,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Model returns "openai/" and the model name
+func (e *OpenAIEmbedder) Model() string {
+	return "openai/" + e.model
+}
+
+type openAIEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type openAIEmbedResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed generates an embedding for a single text
+func (e *OpenAIEmbedder) Embed(text string) ([]float32, error) {
+	return e.EmbedContext(context.Background(), text)
+}
+
+// EmbedContext generates an embedding for a single text, abandoning the
+// request when ctx is done
+func (e *OpenAIEmbedder) EmbedContext(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := e.embedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// EmbedBatch generates embeddings for multiple texts in one request
+func (e *OpenAIEmbedder) EmbedBatch(texts []string) ([][]float32, error) {
+	return e.embedBatch(context.Background(), texts)
+}
+
+func (e *OpenAIEmbedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(openAIEmbedRequest{Model: e.model, Input: texts})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.apiKey)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("openai error: %s", string(body))
+	}
+
+	var result openAIEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	embeddings := make([][]float32, len(texts))
+	for _, d := range result.Data {
+		if d.Index >= 0 && d.Index < len(embeddings) {
+			embeddings[d.Index] = d.Embedding
+		}
+	}
+	return embeddings, nil
+}
+
+// ProviderConfig describes one embedding backend in a fallback chain
+type ProviderConfig struct {
+	Provider string // ollama | openai | null
+	Endpoint string
+	Model    string
+	APIKey   string
+}
+
+// DefaultProviders returns the default fallback chain: local Ollama, then
+// OpenAI if OPENAI_API_KEY is set, then the no-op embedder
+func DefaultProviders() []ProviderConfig {
+	providers := []ProviderConfig{
+		{Provider: "ollama", Model: "nomic-embed-text"},
+	}
+	if key := os.Getenv("OPENAI_API_KEY"); key != "" {
+		providers = append(providers, ProviderConfig{Provider: "openai", APIKey: key})
+	}
+	return append(providers, ProviderConfig{Provider: "null"})
+}
+
+// probeTimeout bounds how long a backend may take to answer the probe
+const probeTimeout = 3 * time.Second
+
+// AutoEmbedder selects the first responsive backend from a prioritized
+// list on first use and caches the choice
+type AutoEmbedder struct {
+	configs  []ProviderConfig
+	once     sync.Once
+	selected Embedder
+	name     string
+}
+
+// NewAutoEmbedder creates an embedder that falls back through configs
+func NewAutoEmbedder(configs []ProviderConfig) *AutoEmbedder {
+	return &AutoEmbedder{configs: configs}
+}
+
+// Backend returns the name of the selected backend, probing if necessary
+func (a *AutoEmbedder) Backend() string {
+	a.once.Do(a.selectBackend)
+	return a.name
+}
+
+// Model returns the model name of the selected backend, probing if necessary
+func (a *AutoEmbedder) Model() string {
+	a.once.Do(a.selectBackend)
+	return ModelName(a.selected)
+}
+
+// Embed generates an embedding using the selected backend
+func (a *AutoEmbedder) Embed(text string) ([]float32, error) {
+	a.once.Do(a.selectBackend)
+	start := time.Now()
+	vec, err := a.selected.Embed(text)
+	a.observe(start, err)
+	return vec, err
+}
+
+// EmbedContext generates an embedding using the selected backend, giving
+// up when ctx is done (even while the backend is still being probed)
+func (a *AutoEmbedder) EmbedContext(ctx context.Context, text string) ([]float32, error) {
+	selected := make(chan struct{})
+	go func() {
+		a.once.Do(a.selectBackend)
+		close(selected)
+	}()
+	select {
+	case <-selected:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	start := time.Now()
+	vec, err := embedContext(ctx, a.selected, text)
+	a.observe(start, err)
+	return vec, err
+}
+
+// EmbedBatch generates embeddings using the selected backend
+func (a *AutoEmbedder) EmbedBatch(texts []string) ([][]float32, error) {
+	a.once.Do(a.selectBackend)
+	start := time.Now()
+	vecs, err := a.selected.EmbedBatch(texts)
+	a.observe(start, err)
+	return vecs, err
+}
+
+// observe records a backend call in the embedding metrics. The null
+// backend makes no calls, so it isn't counted.
+func (a *AutoEmbedder) observe(start time.Time, err error) {
+	if a.name == "null" {
+		return
+	}
+	metrics.EmbeddingRequests.Inc(a.name, metrics.Result(err))
+	metrics.EmbeddingDuration.ObserveSince(start, a.name)
+}
+
+func (a *AutoEmbedder) selectBackend() {
+	for _, cfg := range a.configs {
+		emb, err := newProvider(cfg)
+		if err != nil {
+			log.Printf("Skipping embedding backend %s: %v", cfg.Provider, err)
+			continue
+		}
+		if _, isNull := emb.(*NullEmbedder); !isNull {
+			if err := probe(emb); err != nil {
+				log.Printf("Embedding backend %s unavailable: %v", cfg.Provider, err)
+				metrics.EmbeddingRequests.Inc(cfg.Provider, "unavailable")
+				continue
+			}
+		}
+		a.selected = emb
+		a.name = cfg.Provider
+		log.Printf("Using embedding backend: %s", cfg.Provider)
+		return
+	}
+
+	a.selected = &NullEmbedder{}
+	a.name = "null"
+	log.Printf("No embedding backend available, semantic search disabled")
+}
+
+func newProvider(cfg ProviderConfig) (Embedder, error) {
+	switch cfg.Provider {
+	case "ollama":
+		return NewOllamaEmbedder(cfg.Endpoint, cfg.Model), nil
+	case "openai":
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("no API key")
+		}
+		return NewOpenAIEmbedder(cfg.Endpoint, cfg.Model, cfg.APIKey), nil
+	case "null", "noop":
+		return &NullEmbedder{}, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", cfg.Provider)
+	}
+}
+
+// probe checks that a backend returns a non-empty embedding quickly
+func probe(emb Embedder) error {
+	type result struct {
+		vec []float32
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		vec, err := emb.Embed("probe")
+		done <- result{vec, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return r.err
+		}
+		if len(r.vec) == 0 {
+			return fmt.Errorf("empty embedding")
+		}
+		return nil
+	case <-time.After(probeTimeout):
+		return fmt.Errorf("no response within %s", probeTimeout)
+	}
+}
@@ -0,0 +1,31 @@
+package embedding
+
+import (
+	"math"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	nan := float32(math.NaN())
+	inf := float32(math.Inf(1))
+	tests := []struct {
+		name string
+		v    []float32
+		want bool
+	}{
+		{"nil", nil, false},
+		{"empty", []float32{}, false},
+		{"all zeros", make([]float32, 384), false},
+		{"NaN", []float32{0.1, nan, 0.2}, false},
+		{"infinite", []float32{0.1, inf}, false},
+		{"one non-zero value", []float32{0, 0, 1e-3}, true},
+		{"normal", []float32{0.3, -0.2, 0.9}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Valid(tt.v); got != tt.want {
+				t.Errorf("Valid(%v) = %v, want %v", tt.v, got, tt.want)
+			}
+		})
+	}
+}
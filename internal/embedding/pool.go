@@ -0,0 +1,48 @@
+package embedding
+
+import (
+	"context"
+	"sync"
+)
+
+// EmbedAll embeds texts using up to concurrency parallel calls to e.
+// The output preserves input order; errs[i] holds the error for texts[i].
+// Texts not yet started when ctx is cancelled get ctx.Err().
+func EmbedAll(ctx context.Context, e Embedder, texts []string, concurrency int) ([][]float32, []error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	embeddings := make([][]float32, len(texts))
+	errs := make([]error, len(texts))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				embeddings[i], errs[i] = e.Embed(texts[i])
+			}
+		}()
+	}
+
+	next := 0
+feed:
+	for ; next < len(texts); next++ {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- next:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := next; i < len(texts); i++ {
+		errs[i] = ctx.Err()
+	}
+
+	return embeddings, errs
+}
@@ -0,0 +1,111 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// FormatVersion is the version of the export file format written by Write
+const FormatVersion = 1
+
+// Filter records the criteria an export was made with, so a shared file
+// says which slice of the store it holds
+type Filter struct {
+	Types      []string   `json:"types,omitempty"`
+	Topics     []string   `json:"topics,omitempty"`
+	Project    string     `json:"project,omitempty"`
+	Since      *time.Time `json:"since,omitempty"`
+	Limit      int        `json:"limit,omitempty"`
+	Embeddings bool       `json:"embeddings"`
+}
+
+// Envelope is the top-level document of an export file
+type Envelope struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+	Filter     Filter    `json:"filter"`
+	Count      int       `json:"count"`
+
+	// Model and dimension of the exported embeddings, so an import can
+	// detect vectors that can't be compared with its own
+	EmbeddingModel     string `json:"embeddingModel,omitempty"`
+	EmbeddingDimension int    `json:"embeddingDimension,omitempty"`
+
+	Memories []models.Memory `json:"memories"`
+
+	// Deleted lists memories deleted since Filter.Since, so an incremental
+	// import can remove them too
+	Deleted []models.Tombstone `json:"deleted,omitempty"`
+}
+
+// New wraps memories in an envelope for the current format version
+func New(memories []models.Memory, filter Filter) *Envelope {
+	if memories == nil {
+		memories = []models.Memory{}
+	}
+	env := &Envelope{
+		Version:    FormatVersion,
+		ExportedAt: time.Now().UTC(),
+		Filter:     filter,
+		Count:      len(memories),
+		Memories:   memories,
+	}
+
+	// Record the most common model among the exported embeddings
+	counts := make(map[string]int)
+	for _, m := range memories {
+		if len(m.Embedding) == 0 {
+			continue
+		}
+		if env.EmbeddingDimension == 0 {
+			env.EmbeddingDimension = len(m.Embedding)
+		}
+		if m.EmbeddingModel != "" {
+			counts[m.EmbeddingModel]++
+		}
+	}
+	for model, n := range counts {
+		if n > counts[env.EmbeddingModel] || (n == counts[env.EmbeddingModel] && model < env.EmbeddingModel) {
+			env.EmbeddingModel = model
+		}
+	}
+	return env
+}
+
+// CompatibleEmbedding reports whether an imported memory's embedding can be
+// compared with those of a store using model with dim dimensions. An
+// unknown model, or a store without embeddings (dim 0), is assumed
+// compatible as long as the dimensions agree.
+func CompatibleEmbedding(m *models.Memory, model string, dim int) bool {
+	if len(m.Embedding) == 0 || dim == 0 {
+		return true
+	}
+	if len(m.Embedding) != dim {
+		return false
+	}
+	return m.EmbeddingModel == "" || model == "" || m.EmbeddingModel == model
+}
+
+// Write encodes an envelope as indented JSON
+func Write(w io.Writer, env *Envelope) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(env)
+}
+
+// Read decodes an envelope, rejecting versions newer than this build
+// understands
+func Read(r io.Reader) (*Envelope, error) {
+	var env Envelope
+	if err := json.NewDecoder(r).Decode(&env); err != nil {
+		return nil, fmt.Errorf("invalid export file: %w", err)
+	}
+	if env.Version < 1 || env.Version > FormatVersion {
+		return nil, fmt.Errorf("unsupported export format version %d (this build reads up to %d)", env.Version, FormatVersion)
+	}
+	return &env, nil
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/contextpilot-dev/memorypilot/internal/summary"
 	"github.com/contextpilot-dev/memorypilot/pkg/models"
 )
 
@@ -28,9 +29,10 @@ type ExtractedMemory struct {
 
 // OllamaExtractor uses Ollama for memory extraction
 type OllamaExtractor struct {
-	endpoint string
-	model    string
-	client   *http.Client
+	endpoint       string
+	model          string
+	client         *http.Client
+	summaryLengths summary.Lengths
 }
 
 // NewOllamaExtractor creates a new Ollama-based extractor
@@ -47,16 +49,23 @@ func NewOllamaExtractor(endpoint, model string) *OllamaExtractor {
 		client: &http.Client{
 			Timeout: 120 * time.Second, // LLM can be slow
 		},
+		summaryLengths: summary.DefaultLengths(),
 	}
 }
 
+// SetSummaryLengths sets how long the model is asked to keep the summary of
+// each memory type
+func (e *OllamaExtractor) SetSummaryLengths(l summary.Lengths) {
+	e.summaryLengths = l
+}
+
 const extractionPrompt = `You are a memory extraction system for a software developer.
 Analyze the following development events and extract memories worth remembering.
 
 For each memory, provide:
 - type: One of: decision, pattern, fact, preference, mistake, learning
 - content: The full memory (1-3 sentences, be specific)
-- summary: Short version (under 80 characters)
+- summary: Short version (at most this many characters by type: %s)
 - confidence: 0.0-1.0 how confident this is worth remembering
 - topics: Array of relevant topics (2-5 keywords)
 
@@ -95,7 +104,7 @@ func (e *OllamaExtractor) Extract(events []models.Event) ([]ExtractedMemory, err
 
 	// Format events for the prompt
 	eventsText := formatEvents(events)
-	prompt := fmt.Sprintf(extractionPrompt, eventsText)
+	prompt := fmt.Sprintf(extractionPrompt, e.summaryLengths.Describe(), eventsText)
 
 	req := ollamaGenerateRequest{
 		Model:  e.model,
@@ -168,9 +177,7 @@ func formatEvents(events []models.Event) string {
 			}
 			if diff, ok := e.Data["diff"].(string); ok && len(diff) > 0 {
 				// Truncate diff
-				if len(diff) > 500 {
-					diff = diff[:500] + "..."
-				}
+				diff = truncateRunes(diff, 500)
 				sb.WriteString(fmt.Sprintf("  Diff summary: %s\n", diff))
 			}
 
@@ -180,9 +187,7 @@ func formatEvents(events []models.Event) string {
 			}
 			if content, ok := e.Data["content"].(string); ok && len(content) > 0 {
 				// Truncate content
-				if len(content) > 300 {
-					content = content[:300] + "..."
-				}
+				content = truncateRunes(content, 300)
 				sb.WriteString(fmt.Sprintf("  Content preview: %s\n", content))
 			}
 
@@ -190,6 +195,14 @@ func formatEvents(events []models.Event) string {
 			if cmd, ok := e.Data["command"].(string); ok {
 				sb.WriteString(fmt.Sprintf("  Command: %s\n", cmd))
 			}
+			// Reported by shell hooks only
+			if code, ok := e.Data["exit_code"].(int); ok {
+				outcome := "succeeded"
+				if code != 0 {
+					outcome = fmt.Sprintf("failed with exit code %d", code)
+				}
+				sb.WriteString(fmt.Sprintf("  Outcome: %s\n", outcome))
+			}
 		}
 
 		sb.WriteString("\n")
@@ -198,6 +211,15 @@ func formatEvents(events []models.Event) string {
 	return sb.String()
 }
 
+// truncateRunes cuts s to maxLen runes plus "...", never splitting a character
+func truncateRunes(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	return string(runes[:maxLen]) + "..."
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -0,0 +1,32 @@
+package extractor
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		maxLen int
+		want   string
+	}{
+		{"ascii fits", "fix login", 20, "fix login"},
+		{"emoji fits", "ship 🚀", 6, "ship 🚀"},
+		{"emoji cut", "🚀🚀🚀🚀", 2, "🚀🚀..."},
+		{"CJK cut", "数据库迁移已完成", 5, "数据库迁移..."},
+		{"mixed cut", "修复 bug 🐛 in 登录", 9, "修复 bug 🐛 ..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateRunes(tt.s, tt.maxLen)
+			if got != tt.want {
+				t.Errorf("truncateRunes(%q, %d) = %q, want %q", tt.s, tt.maxLen, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("truncateRunes(%q, %d) = %q is not valid UTF-8", tt.s, tt.maxLen, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package highlight
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Markdown wraps matches in bold markdown
+var Markdown = Style{Open: "**", Close: "**"}
+
+// ANSI wraps matches in bold terminal escape codes
+var ANSI = Style{Open: "\033[1m", Close: "\033[0m"}
+
+// Style is the markup placed around each match
+type Style struct {
+	Open  string
+	Close string
+}
+
+// Highlighter marks occurrences of query terms in text
+type Highlighter struct {
+	pattern *regexp.Regexp
+	style   Style
+}
+
+// New returns a highlighter for the words of query, matched
+// case-insensitively. With exact set, the whole query is matched as one
+// case-sensitive phrase instead. It returns nil if there is nothing to match.
+func New(query string, exact bool, style Style) *Highlighter {
+	var terms []string
+	if exact {
+		if query != "" {
+			terms = []string{query}
+		}
+	} else {
+		terms = Terms(query)
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+
+	// Prefer the longest term where several match at the same position
+	sort.Slice(terms, func(i, j int) bool { return len(terms[i]) > len(terms[j]) })
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = regexp.QuoteMeta(t)
+	}
+
+	expr := strings.Join(quoted, "|")
+	if !exact {
+		expr = "(?i)" + expr
+	}
+	return &Highlighter{pattern: regexp.MustCompile(expr), style: style}
+}
+
+// Terms splits a query into the distinct words worth highlighting,
+// dropping punctuation and single characters
+func Terms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		word = strings.TrimFunc(word, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if len([]rune(word)) < 2 || seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// Mark returns text with every match wrapped in the highlighter's style.
+// A nil highlighter returns text unchanged.
+func (h *Highlighter) Mark(text string) string {
+	if h == nil {
+		return text
+	}
+	return h.pattern.ReplaceAllStringFunc(text, func(match string) string {
+		return h.style.Open + match + h.style.Close
+	})
+}
+
+// Ellipsis marks text cut from a snippet
+const Ellipsis = "…"
+
+// Snippet returns the window of text around the region with the most
+// matches, extending up to context characters either side of it and
+// trimmed to word boundaries, with an ellipsis where text was cut. Text
+// without matches (or a nil highlighter) yields its opening window.
+func (h *Highlighter) Snippet(text string, context int) string {
+	runes := []rune(text)
+	window := 2 * context
+	if context <= 0 || len(runes) <= window {
+		return text
+	}
+
+	// Find the match start with the most other matches within the window
+	start := 0
+	if h != nil {
+		var positions []int
+		for _, loc := range h.pattern.FindAllStringIndex(text, -1) {
+			positions = append(positions, len([]rune(text[:loc[0]])))
+		}
+		best := 0
+		for i, pos := range positions {
+			count := 0
+			for _, other := range positions[i:] {
+				if other-pos > window {
+					break
+				}
+				count++
+			}
+			if count > best {
+				best = count
+				start = pos - context/2
+			}
+		}
+	}
+
+	if start < 0 {
+		start = 0
+	}
+	end := start + window
+	if end > len(runes) {
+		end = len(runes)
+		start = max(0, end-window)
+	}
+
+	// Move cuts inward to the nearest word boundary
+	if start > 0 {
+		for i := start; i < end && i-start < 20; i++ {
+			if unicode.IsSpace(runes[i]) {
+				start = i + 1
+				break
+			}
+		}
+	}
+	if end < len(runes) {
+		for i := end; i > start && end-i < 20; i-- {
+			if unicode.IsSpace(runes[i-1]) {
+				end = i - 1
+				break
+			}
+		}
+	}
+
+	snippet := strings.TrimSpace(string(runes[start:end]))
+	if start > 0 {
+		snippet = Ellipsis + snippet
+	}
+	if end < len(runes) {
+		snippet += Ellipsis
+	}
+	return snippet
+}
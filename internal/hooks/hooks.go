@@ -0,0 +1,190 @@
+// Package hooks runs external commands when the daemon captures memories,
+// passing each matching memory to the command as JSON on stdin.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// DefaultTimeout is how long a hook may run when it doesn't set a timeout
+const DefaultTimeout = 30 * time.Second
+
+// maxRunning is how many hook commands run at once; further runs wait
+const maxRunning = 4
+
+// maxOutput is how much of a failed hook's output is logged
+const maxOutput = 500
+
+// Hook runs Command through the shell for each captured memory of one of
+// Types and Scopes. Empty lists match every type or scope.
+type Hook struct {
+	Name    string               `json:"name,omitempty"`
+	Command string               `json:"command"`
+	Types   []models.MemoryType  `json:"types,omitempty"`
+	Scopes  []models.MemoryScope `json:"scopes,omitempty"`
+	Timeout string               `json:"timeout,omitempty"` // Go duration; DefaultTimeout if empty
+
+	timeout time.Duration
+}
+
+// Config lists the hooks to run
+type Config struct {
+	Hooks []Hook `json:"hooks"`
+}
+
+// Load reads hooks from a JSON file
+func Load(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid hooks config %s: %w", path, err)
+	}
+	for i := range cfg.Hooks {
+		if err := cfg.Hooks[i].validate(); err != nil {
+			return cfg, fmt.Errorf("invalid hooks config %s: %w", path, err)
+		}
+	}
+	return cfg, nil
+}
+
+func (h *Hook) validate() error {
+	if strings.TrimSpace(h.Command) == "" {
+		return fmt.Errorf("hook %s has no command", h.label())
+	}
+	for _, t := range h.Types {
+		if !t.Valid() {
+			return fmt.Errorf("unknown type %q for hook %s", t, h.label())
+		}
+	}
+	for _, s := range h.Scopes {
+		switch s {
+		case models.MemoryScopePersonal, models.MemoryScopeProject, models.MemoryScopeTeam, models.MemoryScopeOrg:
+		default:
+			return fmt.Errorf("unknown scope %q for hook %s", s, h.label())
+		}
+	}
+	h.timeout = DefaultTimeout
+	if h.Timeout != "" {
+		d, err := time.ParseDuration(h.Timeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeout %q for hook %s", h.Timeout, h.label())
+		}
+		h.timeout = d
+	}
+	return nil
+}
+
+// label names a hook in logs and errors, by its command if it has no name
+func (h *Hook) label() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return fmt.Sprintf("%q", h.Command)
+}
+
+// Matches reports whether the hook fires for m
+func (h *Hook) Matches(m models.Memory) bool {
+	return (len(h.Types) == 0 || slices.Contains(h.Types, m.Type)) &&
+		(len(h.Scopes) == 0 || slices.Contains(h.Scopes, m.Scope))
+}
+
+// Runner runs the hooks of a configuration in the background
+type Runner struct {
+	hooks   []Hook
+	running chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewRunner creates a runner for the hooks in cfg
+func NewRunner(cfg Config) *Runner {
+	return &Runner{hooks: cfg.Hooks, running: make(chan struct{}, maxRunning)}
+}
+
+// Fire starts every hook matching m without waiting for them. Failures and
+// timeouts are logged.
+func (r *Runner) Fire(m models.Memory) {
+	for i := range r.hooks {
+		h := &r.hooks[i]
+		if !h.Matches(m) {
+			continue
+		}
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.running <- struct{}{}
+			defer func() { <-r.running }()
+			if err := h.run(m); err != nil {
+				log.Printf("Hook %s failed for memory %s: %v", h.label(), m.ID, err)
+			}
+		}()
+	}
+}
+
+// Wait waits for running hooks to finish, which their timeouts bound
+func (r *Runner) Wait() {
+	r.wg.Wait()
+}
+
+// run runs the hook's command with m as JSON on stdin
+func (h *Hook) run(m models.Memory) error {
+	input, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, h.Command)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(),
+		"MEMORYPILOT_MEMORY_ID="+m.ID,
+		"MEMORYPILOT_MEMORY_TYPE="+string(m.Type),
+		"MEMORYPILOT_MEMORY_SCOPE="+string(m.Scope),
+	)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	// Don't wait on children the shell left holding the output once killed
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", h.timeout)
+	}
+	if err != nil {
+		if out := strings.TrimSpace(output.String()); out != "" {
+			if len(out) > maxOutput {
+				out = out[:maxOutput] + "..."
+			}
+			return fmt.Errorf("%w: %s", err, out)
+		}
+		return err
+	}
+	return nil
+}
+
+// shellCommand runs command through the platform's shell, so hooks can use
+// pipes, redirects and ~
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
@@ -0,0 +1,141 @@
+// Package ids generates memory IDs. IDs are ULIDs by default or UUIDv7s;
+// both are time-sortable strings, so memories created under either format
+// live side by side.
+package ids
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// ID formats
+const (
+	FormatULID   = "ulid"
+	FormatUUIDv7 = "uuidv7"
+)
+
+// Generator creates unique IDs
+type Generator interface {
+	New() string
+}
+
+// ULID generates ULIDs, e.g. 01HQ3K5Z8R2V7XW9YB4C6D0EFG
+type ULID struct{}
+
+// New returns a ULID, monotonic within the same millisecond
+func (ULID) New() string {
+	return ulid.Make().String()
+}
+
+// UUIDv7 generates version 7 UUIDs (RFC 9562), e.g.
+// 018f3c2a-7b4e-7c1d-9a2b-3c4d5e6f7a8b. IDs from one process increase
+// monotonically, using a counter within the same millisecond.
+type UUIDv7 struct {
+	mu   sync.Mutex
+	last int64  // Milliseconds of the last ID
+	seq  uint16 // 12-bit counter within last
+}
+
+// New returns a UUIDv7
+func (g *UUIDv7) New() string {
+	var b [16]byte
+	if _, err := rand.Read(b[6:]); err != nil {
+		panic(fmt.Sprintf("ids: reading random bytes: %v", err))
+	}
+
+	g.mu.Lock()
+	ms := time.Now().UnixMilli()
+	if ms <= g.last {
+		// Same (or an earlier, if the clock stepped back) millisecond:
+		// count up, borrowing the next millisecond on overflow
+		ms = g.last
+		g.seq++
+		if g.seq > 0xfff {
+			ms++
+			g.seq = 0
+		}
+	} else {
+		// Start the counter low enough to leave room for increments
+		g.seq = binary.BigEndian.Uint16(b[6:8]) & 0x7ff
+	}
+	g.last = ms
+	seq := g.seq
+	g.mu.Unlock()
+
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	b[2] = byte(ms >> 24)
+	b[3] = byte(ms >> 16)
+	b[4] = byte(ms >> 8)
+	b[5] = byte(ms)
+	b[6] = 0x70 | byte(seq>>8) // Version 7
+	b[7] = byte(seq)
+	b[8] = 0x80 | b[8]&0x3f // RFC 9562 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// ForFormat returns the generator for an ID format
+func ForFormat(format string) (Generator, error) {
+	switch format {
+	case "", FormatULID:
+		return ULID{}, nil
+	case FormatUUIDv7, "uuid":
+		return &UUIDv7{}, nil
+	default:
+		return nil, fmt.Errorf("unknown ID format %q (expected %s or %s)", format, FormatULID, FormatUUIDv7)
+	}
+}
+
+var (
+	mu      sync.RWMutex
+	current Generator = ULID{}
+)
+
+// New returns a new ID from the configured generator
+func New() string {
+	mu.RLock()
+	g := current
+	mu.RUnlock()
+	return g.New()
+}
+
+// SetGenerator changes the generator used by New. nil restores ULIDs.
+func SetGenerator(g Generator) {
+	if g == nil {
+		g = ULID{}
+	}
+	mu.Lock()
+	current = g
+	mu.Unlock()
+}
+
+// Config is the ID configuration file
+type Config struct {
+	Format string `json:"format"` // ulid (default) or uuidv7
+}
+
+// LoadConfig reads the ID configuration from a JSON file and returns its
+// generator
+func LoadConfig(path string) (Generator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ULID{}, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return ULID{}, fmt.Errorf("invalid ID config %s: %w", path, err)
+	}
+	g, err := ForFormat(cfg.Format)
+	if err != nil {
+		return ULID{}, fmt.Errorf("invalid ID config %s: %w", path, err)
+	}
+	return g, nil
+}
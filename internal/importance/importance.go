@@ -0,0 +1,182 @@
+package importance
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// Scorer assigns an importance (0.0-1.0) to a captured memory, given the
+// events it was extracted from
+type Scorer interface {
+	Score(m *models.Memory, events []models.Event) float64
+}
+
+// Rules configure a RuleScorer. Adjustments are added to Base and the
+// result is clamped to 0.0-1.0.
+type Rules struct {
+	// Base is the starting importance
+	Base float64 `json:"base"`
+
+	// Types adjusts importance by memory type
+	Types map[models.MemoryType]float64 `json:"types"`
+
+	// Keywords adjusts importance when the memory content contains the
+	// keyword (case-insensitive). Each keyword counts once.
+	Keywords map[string]float64 `json:"keywords"`
+
+	// CriticalPaths are glob patterns matched against the base name and
+	// full path of files touched by the source events
+	CriticalPaths []string `json:"criticalPaths"`
+	// CriticalPathBoost is added once if any critical file was touched
+	CriticalPathBoost float64 `json:"criticalPathBoost"`
+
+	// LargeCommitFiles is the number of files a commit must touch to count
+	// as large (0 disables the check)
+	LargeCommitFiles int `json:"largeCommitFiles"`
+	// LargeCommitBoost is added once if any source commit was large
+	LargeCommitBoost float64 `json:"largeCommitBoost"`
+}
+
+// DefaultRules returns the built-in scoring rules
+func DefaultRules() Rules {
+	return Rules{
+		Base: 0.5,
+		Types: map[models.MemoryType]float64{
+			models.MemoryTypeDecision:   0.2,
+			models.MemoryTypeMistake:    0.2,
+			models.MemoryTypePattern:    0.1,
+			models.MemoryTypePreference: 0.1,
+			models.MemoryTypeLearning:   0.05,
+			models.MemoryTypeFact:       0,
+		},
+		Keywords: map[string]float64{
+			"breaking":      0.2,
+			"security":      0.2,
+			"vulnerability": 0.2,
+			"migration":     0.1,
+			"fix":           0.05,
+			"typo":          -0.2,
+			"formatting":    -0.1,
+			"wip":           -0.1,
+		},
+		CriticalPaths: []string{
+			"go.mod", "package.json", "Cargo.toml", "requirements.txt",
+			"Dockerfile", "*.sql", "migrations/*", "*.tf", ".github/workflows/*",
+		},
+		CriticalPathBoost: 0.1,
+		LargeCommitFiles:  10,
+		LargeCommitBoost:  0.1,
+	}
+}
+
+// LoadRules reads rules from a JSON file. Fields missing from the file keep
+// their default values.
+func LoadRules(path string) (Rules, error) {
+	rules := DefaultRules()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rules, err
+	}
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return rules, fmt.Errorf("invalid importance rules %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// RuleScorer scores memories with a fixed set of rules
+type RuleScorer struct {
+	rules Rules
+}
+
+// NewRuleScorer creates a scorer for the given rules
+func NewRuleScorer(rules Rules) *RuleScorer {
+	return &RuleScorer{rules: rules}
+}
+
+// Score applies the rules to a memory and its source events
+func (s *RuleScorer) Score(m *models.Memory, events []models.Event) float64 {
+	score := s.rules.Base
+	score += s.rules.Types[m.Type]
+
+	content := strings.ToLower(m.Content + " " + m.Summary)
+	for keyword, weight := range s.rules.Keywords {
+		if strings.Contains(content, strings.ToLower(keyword)) {
+			score += weight
+		}
+	}
+
+	var critical, large bool
+	for _, e := range events {
+		files := eventFiles(e)
+		if e.Type == "git_commit" && s.rules.LargeCommitFiles > 0 && len(files) >= s.rules.LargeCommitFiles {
+			large = true
+		}
+		for _, f := range files {
+			if s.isCritical(f) {
+				critical = true
+			}
+		}
+	}
+	if critical {
+		score += s.rules.CriticalPathBoost
+	}
+	if large {
+		score += s.rules.LargeCommitBoost
+	}
+
+	return clamp(score)
+}
+
+// isCritical reports whether a file matches one of the critical patterns
+func (s *RuleScorer) isCritical(path string) bool {
+	path = filepath.ToSlash(path)
+	for _, pattern := range s.rules.CriticalPaths {
+		if ok, _ := filepath.Match(pattern, filepath.Base(path)); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+		// Directory patterns such as "migrations/*" match at any depth
+		if dir := strings.TrimSuffix(pattern, "/*"); dir != pattern && strings.Contains("/"+path, "/"+dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// eventFiles returns the files an event touched: the changed files of a
+// commit or the path of a file change
+func eventFiles(e models.Event) []string {
+	switch files := e.Data["files"].(type) {
+	case []string:
+		return files
+	case []interface{}:
+		var result []string
+		for _, f := range files {
+			if s, ok := f.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	if path, ok := e.Data["path"].(string); ok {
+		return []string{path}
+	}
+	return nil
+}
+
+func clamp(score float64) float64 {
+	if score < 0 {
+		return 0
+	}
+	if score > 1 {
+		return 1
+	}
+	return score
+}
@@ -0,0 +1,320 @@
+package ipc
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/contextpilot-dev/memorypilot/internal/watcher"
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// Request is sent by a client as the first line on a connection
+type Request struct {
+	Method  string         `json:"method"`            // "watch", "command" or "health"
+	Types   []string       `json:"types,omitempty"`   // Memory type filter for watch
+	Command *CommandReport `json:"command,omitempty"` // The command run, for command
+}
+
+// CommandReport is a terminal command reported by a shell hook once it
+// finished
+type CommandReport struct {
+	Command  string `json:"command"`
+	ExitCode int    `json:"exitCode"`
+	Dir      string `json:"dir,omitempty"` // Working directory
+}
+
+// Server exposes the daemon over a local Unix socket
+type Server struct {
+	path     string
+	listener net.Listener
+
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+	onCommand   func(CommandReport) // Receives reported commands; nil refuses them
+	onHealth    func() []watcher.Health
+}
+
+type subscriber struct {
+	types  map[models.MemoryType]bool
+	events chan models.Memory
+}
+
+// NewServer creates a new IPC server listening on the given socket path
+func NewServer(path string) *Server {
+	return &Server{
+		path:        path,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Start begins accepting connections
+func (s *Server) Start() error {
+	// Remove a stale socket left by a daemon that didn't shut down cleanly
+	os.Remove(s.path)
+
+	l, err := net.Listen("unix", s.path)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.path, err)
+	}
+	s.listener = l
+
+	go s.accept()
+	return nil
+}
+
+// Stop closes the listener and disconnects subscribers
+func (s *Server) Stop() {
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	os.Remove(s.path)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscribers {
+		close(sub.events)
+		delete(s.subscribers, sub)
+	}
+}
+
+// SetCommandHandler accepts commands reported by shell hooks and passes
+// them to fn. Without a handler reports are refused.
+func (s *Server) SetCommandHandler(fn func(CommandReport)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onCommand = fn
+}
+
+// SetHealthHandler answers watcher health requests with fn
+func (s *Server) SetHealthHandler(fn func() []watcher.Health) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onHealth = fn
+}
+
+// PublishMemory sends a newly created memory to all matching subscribers.
+// Slow subscribers drop events rather than blocking the caller.
+func (s *Server) PublishMemory(m models.Memory) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subscribers {
+		if len(sub.types) > 0 && !sub.types[m.Type] {
+			continue
+		}
+		select {
+		case sub.events <- m:
+		default:
+			log.Printf("IPC subscriber too slow, dropping memory event")
+		}
+	}
+}
+
+func (s *Server) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return
+	}
+
+	var req Request
+	if err := json.Unmarshal(line, &req); err != nil {
+		writeError(conn, "invalid request")
+		return
+	}
+
+	switch req.Method {
+	case "watch":
+		s.watch(conn, reader, req)
+	case "command":
+		s.command(conn, req)
+	case "health":
+		s.health(conn)
+	default:
+		writeError(conn, fmt.Sprintf("unknown method %q", req.Method))
+	}
+}
+
+// watch streams memories to the client until either side disconnects
+func (s *Server) watch(conn net.Conn, reader *bufio.Reader, req Request) {
+	sub := &subscriber{
+		types:  make(map[models.MemoryType]bool),
+		events: make(chan models.Memory, 64),
+	}
+	for _, t := range req.Types {
+		sub.types[models.MemoryType(t)] = true
+	}
+
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+
+	// Detect client disconnect
+	closed := make(chan struct{})
+	go func() {
+		reader.WriteTo(discard{})
+		close(closed)
+	}()
+
+	defer func() {
+		s.mu.Lock()
+		if _, ok := s.subscribers[sub]; ok {
+			delete(s.subscribers, sub)
+			close(sub.events)
+		}
+		s.mu.Unlock()
+	}()
+
+	encoder := json.NewEncoder(conn)
+	for {
+		select {
+		case <-closed:
+			return
+		case m, ok := <-sub.events:
+			if !ok {
+				return
+			}
+			if err := encoder.Encode(m); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// command passes a reported command to the command handler
+func (s *Server) command(conn net.Conn, req Request) {
+	s.mu.Lock()
+	onCommand := s.onCommand
+	s.mu.Unlock()
+
+	switch {
+	case onCommand == nil:
+		writeError(conn, "command capture is disabled (start the daemon with --capture-commands)")
+	case req.Command == nil || req.Command.Command == "":
+		writeError(conn, "missing command")
+	default:
+		onCommand(*req.Command)
+		json.NewEncoder(conn).Encode(map[string]bool{"ok": true})
+	}
+}
+
+// health replies with the health of the daemon's watchers
+func (s *Server) health(conn net.Conn) {
+	s.mu.Lock()
+	onHealth := s.onHealth
+	s.mu.Unlock()
+
+	if onHealth == nil {
+		writeError(conn, "watcher health is unavailable")
+		return
+	}
+	json.NewEncoder(conn).Encode(map[string][]watcher.Health{"watchers": onHealth()})
+}
+
+func writeError(conn net.Conn, message string) {
+	json.NewEncoder(conn).Encode(map[string]string{"error": message})
+}
+
+type discard struct{}
+
+func (discard) Write(p []byte) (int, error) { return len(p), nil }
+
+// Watch connects to the daemon and calls fn for each newly created memory
+// until the connection closes or fn returns an error
+func Watch(path string, types []string, fn func(models.Memory) error) error {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(Request{Method: "watch", Types: types}); err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	decoder := json.NewDecoder(conn)
+	for {
+		var msg struct {
+			models.Memory
+			Error string `json:"error"`
+		}
+		if err := decoder.Decode(&msg); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to read from daemon: %w", err)
+		}
+		if msg.Error != "" {
+			return fmt.Errorf("daemon error: %s", msg.Error)
+		}
+		if err := fn(msg.Memory); err != nil {
+			return err
+		}
+	}
+}
+
+// ReportCommand sends a finished terminal command to the daemon
+func ReportCommand(path string, report CommandReport) error {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(Request{Method: "command", Command: &report}); err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	var reply struct {
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(conn).Decode(&reply); err != nil {
+		return fmt.Errorf("failed to read from daemon: %w", err)
+	}
+	if reply.Error != "" {
+		return fmt.Errorf("daemon error: %s", reply.Error)
+	}
+	return nil
+}
+
+// WatcherHealth asks the daemon whether its watchers are capturing
+func WatcherHealth(path string) ([]watcher.Health, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(Request{Method: "health"}); err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	var reply struct {
+		Watchers []watcher.Health `json:"watchers"`
+		Error    string           `json:"error"`
+	}
+	if err := json.NewDecoder(conn).Decode(&reply); err != nil {
+		return nil, fmt.Errorf("failed to read from daemon: %w", err)
+	}
+	if reply.Error != "" {
+		return nil, fmt.Errorf("daemon error: %s", reply.Error)
+	}
+	return reply.Watchers, nil
+}
@@ -0,0 +1,146 @@
+// Package lifetime configures how long memories of each type live: when
+// they expire and how fast their importance decays.
+package lifetime
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// DefaultDecay is the daily importance decay factor of memories whose type
+// has no configured lifetime
+const DefaultDecay = 0.99
+
+// Never marks a type whose memories never expire and never decay
+const Never = Duration(-1)
+
+// Duration is a lifetime, written in JSON as a Go duration ("72h"), a number
+// of days ("30d") or "never"
+type Duration time.Duration
+
+// UnmarshalJSON parses a lifetime
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("lifetime must be a string such as \"72h\", \"30d\" or \"never\"")
+	}
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON writes a lifetime in the form ParseDuration reads
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// String formats a lifetime, using days for whole days
+func (d Duration) String() string {
+	if d == Never {
+		return "never"
+	}
+	day := 24 * time.Hour
+	if t := time.Duration(d); t > 0 && t%day == 0 {
+		return fmt.Sprintf("%dd", t/day)
+	}
+	return time.Duration(d).String()
+}
+
+// ParseDuration parses "never", a number of days ("30d") or a Go duration
+func ParseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "never" {
+		return Never, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid lifetime %q", s)
+		}
+		return Duration(time.Duration(n) * 24 * time.Hour), nil
+	}
+	t, err := time.ParseDuration(s)
+	if err != nil || t <= 0 {
+		return 0, fmt.Errorf("invalid lifetime %q", s)
+	}
+	return Duration(t), nil
+}
+
+// Policy maps memory types to their lifetime. Types that aren't listed
+// don't expire and decay at DefaultDecay.
+type Policy struct {
+	Types map[models.MemoryType]Duration `json:"types"`
+}
+
+// DefaultPolicy returns the built-in policy, under which nothing expires
+func DefaultPolicy() Policy {
+	return Policy{Types: map[models.MemoryType]Duration{}}
+}
+
+// LoadPolicy reads a policy from a JSON file
+func LoadPolicy(path string) (Policy, error) {
+	policy := DefaultPolicy()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return policy, err
+	}
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return policy, fmt.Errorf("invalid lifetimes %s: %w", path, err)
+	}
+	for t := range policy.Types {
+		if !t.Valid() {
+			return policy, fmt.Errorf("invalid lifetimes %s: unknown memory type %q", path, t)
+		}
+	}
+	return policy, nil
+}
+
+// Apply sets the expiry of a new memory from its type's lifetime, unless it
+// already has one
+func (p Policy) Apply(m *models.Memory) {
+	if m.ExpiresAt != nil {
+		return
+	}
+	created := m.CreatedAt
+	if created.IsZero() {
+		created = time.Now()
+	}
+	m.ExpiresAt = Expiry(created, p.Types[m.Type])
+}
+
+// Expiry returns when something created at from with lifetime d expires,
+// or nil if it doesn't
+func Expiry(from time.Time, d Duration) *time.Time {
+	if d <= 0 {
+		return nil
+	}
+	expires := from.Add(time.Duration(d))
+	return &expires
+}
+
+// DecayRates returns the daily importance decay factor of each configured
+// type. Memories of a type that never expires keep their importance; those
+// of a type with a lifetime fade from full importance to the 0.1 floor
+// within it (never slower than DefaultDecay).
+func (p Policy) DecayRates() map[models.MemoryType]float64 {
+	rates := make(map[models.MemoryType]float64, len(p.Types))
+	for t, d := range p.Types {
+		if d == Never {
+			rates[t] = 1
+			continue
+		}
+		days := time.Duration(d).Hours() / 24
+		rates[t] = min(DefaultDecay, math.Pow(0.1, 1/max(days, 1)))
+	}
+	return rates
+}
@@ -0,0 +1,156 @@
+// Package markdown splits Markdown notes into sections by heading, for
+// importing them as memories.
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Document is a parsed Markdown file
+type Document struct {
+	Title    string    // Frontmatter title, if any
+	Tags     []string  // Frontmatter tags (or topics)
+	Sections []Section // Sections with content, in document order
+}
+
+// Section is the text under one heading, up to the next heading
+type Section struct {
+	Heading string // The heading text, or the document title for text before the first heading
+	Key     string // Path of headings from the top ("Setup > Linux"), unique within the document
+	Body    string
+}
+
+var (
+	heading = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	fence   = regexp.MustCompile("^\\s*(```|~~~)")
+)
+
+// Parse splits text into sections. Text before the first heading becomes a
+// section titled title (the frontmatter title if it has one). Headings
+// without text of their own, such as a chapter heading directly followed by
+// a subheading, produce no section but are part of their subsections' keys.
+func Parse(text, title string) Document {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	var doc Document
+	text = doc.parseFrontmatter(text)
+	if doc.Title != "" {
+		title = doc.Title
+	}
+
+	var path []pathEntry // Enclosing headings, outermost first
+	current := Section{Heading: title, Key: title}
+	var body []string
+	seen := make(map[string]int)
+	inFence := false
+
+	flush := func() {
+		current.Body = strings.TrimSpace(strings.Join(body, "\n"))
+		if current.Body != "" {
+			if n := seen[current.Key]; n > 0 {
+				seen[current.Key] = n + 1
+				current.Key = fmt.Sprintf("%s (%d)", current.Key, n+1)
+			} else {
+				seen[current.Key] = 1
+			}
+			doc.Sections = append(doc.Sections, current)
+		}
+		body = nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if fence.MatchString(line) {
+			inFence = !inFence
+		}
+		m := heading.FindStringSubmatch(line)
+		if inFence || m == nil {
+			body = append(body, line)
+			continue
+		}
+
+		flush()
+		level := len(m[1])
+		for len(path) > 0 && path[len(path)-1].level >= level {
+			path = path[:len(path)-1]
+		}
+		path = append(path, pathEntry{level, m[2]})
+		current = Section{Heading: m[2], Key: joinPath(path)}
+	}
+	flush()
+	return doc
+}
+
+// pathEntry is a heading enclosing the current line
+type pathEntry struct {
+	level int
+	text  string
+}
+
+// joinPath joins the headings of a path
+func joinPath(path []pathEntry) string {
+	parts := make([]string, len(path))
+	for i, p := range path {
+		parts[i] = p.text
+	}
+	return strings.Join(parts, " > ")
+}
+
+// parseFrontmatter reads a leading YAML frontmatter block's title and tags
+// and returns the text after it
+func (d *Document) parseFrontmatter(text string) string {
+	if !strings.HasPrefix(text, "---\n") {
+		return text
+	}
+	end := strings.Index(text[4:], "\n---")
+	if end < 0 {
+		return text
+	}
+	block := text[4 : 4+end]
+	rest := text[4+end+4:]
+	if i := strings.IndexByte(rest, '\n'); i >= 0 {
+		rest = rest[i+1:]
+	} else {
+		rest = ""
+	}
+
+	listKey := ""
+	for _, line := range strings.Split(block, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok && listKey != "" {
+			d.Tags = append(d.Tags, unquote(item))
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		listKey = ""
+		switch key {
+		case "title":
+			d.Title = unquote(value)
+		case "tags", "topics":
+			if value == "" {
+				listKey = key
+				continue
+			}
+			value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+			for _, tag := range strings.Split(value, ",") {
+				if tag = unquote(tag); tag != "" {
+					d.Tags = append(d.Tags, tag)
+				}
+			}
+		}
+	}
+	return rest
+}
+
+// unquote trims whitespace and surrounding quotes from a YAML scalar
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		s = s[1 : len(s)-1]
+	}
+	return strings.TrimPrefix(s, "#")
+}
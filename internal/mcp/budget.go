@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// entryOverheadTokens approximates the tokens a recall result spends on its
+// ID, type, source and date lines besides summary, content and topics
+const entryOverheadTokens = 20
+
+// estimateTokens approximates how many LLM tokens text takes, at roughly
+// four characters per token
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// estimateMemoryTokens approximates the tokens m takes in a recall result
+func estimateMemoryTokens(m models.Memory) int {
+	n := entryOverheadTokens + estimateTokens(m.Summary) + estimateTokens(m.Content)
+	for _, t := range m.Topics {
+		n += estimateTokens(t) + 1
+	}
+	return n
+}
+
+// recallBudget reports how recall results were cut to fit a token budget
+type recallBudget struct {
+	MaxTokens  int `json:"maxTokens"`
+	UsedTokens int `json:"usedTokens"` // Estimated
+	Included   int `json:"included"`
+	Dropped    int `json:"dropped"`
+}
+
+// fitBudget keeps results in ranked order while their estimated size in
+// display fits maxTokens, stopping at the first one that doesn't.
+// memories and display hold the same results, as stored and as shown.
+func fitBudget(memories, display []models.Memory, maxTokens int) ([]models.Memory, []models.Memory, *recallBudget) {
+	budget := &recallBudget{MaxTokens: maxTokens}
+	for _, m := range display {
+		n := estimateMemoryTokens(m)
+		if budget.UsedTokens+n > maxTokens {
+			break
+		}
+		budget.UsedTokens += n
+		budget.Included++
+	}
+	budget.Dropped = len(display) - budget.Included
+	return memories[:budget.Included], display[:budget.Included], budget
+}
+
+// format renders the budget report appended to the text result
+func (b *recallBudget) format() string {
+	return fmt.Sprintf("\nToken budget: %d memories fit in ~%d of %d tokens, %d dropped for budget\n",
+		b.Included, b.UsedTokens, b.MaxTokens, b.Dropped)
+}
@@ -0,0 +1,32 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// busyRetryTimeout bounds how long a tool call keeps retrying while the
+// database is locked, typically by the daemon writing captured memories
+const busyRetryTimeout = 10 * time.Second
+
+// retryBusy runs a store operation, retrying while the database is locked
+// until ctx is done or busyRetryTimeout passes; see store.RetryBusy
+func retryBusy(ctx context.Context, op func() error) error {
+	ctx, cancel := context.WithTimeout(ctx, busyRetryTimeout)
+	defer cancel()
+	return store.RetryBusy(ctx, op)
+}
+
+// searchBusy runs a store search with retryBusy
+func searchBusy(ctx context.Context, search func() ([]models.Memory, error)) ([]models.Memory, error) {
+	var memories []models.Memory
+	err := retryBusy(ctx, func() error {
+		var err error
+		memories, err = search()
+		return err
+	})
+	return memories, err
+}
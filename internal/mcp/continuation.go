@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultMaxResponseSize is the default size limit of a tool result's text,
+// in bytes
+const DefaultMaxResponseSize = 64 << 10
+
+// continuationTTL is how long the rest of a truncated result can be fetched
+const continuationTTL = 5 * time.Minute
+
+// maxContinuations bounds how many truncated results are held at once; the
+// oldest is dropped to make room
+const maxContinuations = 32
+
+// continuation is the unsent rest of a truncated tool result
+type continuation struct {
+	text    string
+	expires time.Time
+}
+
+// continuations holds the rest of truncated tool results until the client
+// fetches them with memorypilot_continue or they expire
+type continuations struct {
+	mu      sync.Mutex
+	entries map[string]continuation
+}
+
+func newContinuations() *continuations {
+	return &continuations{entries: make(map[string]continuation)}
+}
+
+// put stores text and returns the token that fetches it
+func (c *continuations) put(text string) (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	oldest := ""
+	for t, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, t)
+		} else if oldest == "" || e.expires.Before(c.entries[oldest].expires) {
+			oldest = t
+		}
+	}
+	if len(c.entries) >= maxContinuations {
+		delete(c.entries, oldest)
+	}
+
+	key := hex.EncodeToString(token)
+	c.entries[key] = continuation{text: text, expires: now.Add(continuationTTL)}
+	return key, nil
+}
+
+// take returns and forgets the text stored under token, reporting false if
+// the token is unknown or expired
+func (c *continuations) take(token string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[token]
+	delete(c.entries, token)
+	if !ok || time.Now().After(e.expires) {
+		return "", false
+	}
+	return e.text, true
+}
+
+// truncateText cuts text to at most max bytes, preferring to end at a line
+// break in the second half and never splitting a character. It returns the
+// kept part and the rest.
+func truncateText(text string, max int) (string, string) {
+	if len(text) <= max {
+		return text, ""
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	if i := strings.LastIndexByte(text[:cut], '\n'); i >= max/2 {
+		cut = i + 1
+	}
+	return text[:cut], text[cut:]
+}
+
+// truncationNoteSize is the room kept for the note appended to truncated
+// text, so the text and note together stay within the configured size
+const truncationNoteSize = 160
+
+// boundText keeps a tool result's text, followed by note, within the
+// configured size. Longer text is truncated and the rest stored for
+// memorypilot_continue, with a note telling the client how to fetch it.
+func (s *Server) boundText(text, note string) string {
+	limit := s.maxResponseSize - len(note)
+	if s.maxResponseSize <= 0 || len(text) <= limit {
+		return text + note
+	}
+	head, rest := truncateText(text, max(limit-truncationNoteSize, s.maxResponseSize/2))
+	token, err := s.continuations.put(rest)
+	if err != nil {
+		return head + fmt.Sprintf("\n\n[Truncated: %d more bytes could not be kept]\n", len(rest)) + note
+	}
+	return head + fmt.Sprintf("\n\n[Truncated: %d more bytes. Call memorypilot_continue with token %q within %s for the rest]\n",
+		len(rest), token, continuationTTL) + note
+}
+
+// boundStructured returns structured content if its JSON fits within the
+// configured size. Larger content can't be truncated meaningfully, so it is
+// left out and a note for the text block is returned instead.
+func (s *Server) boundStructured(structured interface{}) (interface{}, string) {
+	if s.maxResponseSize <= 0 {
+		return structured, ""
+	}
+	data, err := json.Marshal(structured)
+	if err != nil || len(data) <= s.maxResponseSize {
+		return structured, ""
+	}
+	return nil, fmt.Sprintf("\n[Structured content left out: %d bytes is over the %d byte limit]\n", len(data), s.maxResponseSize)
+}
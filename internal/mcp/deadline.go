@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// DefaultToolTimeout is how long a tool call may run before it is answered
+// with a timeout error
+const DefaultToolTimeout = 30 * time.Second
+
+// toolPrefix starts every tool name; per-tool timeouts may leave it out
+const toolPrefix = "memorypilot_"
+
+// writeTools change the store. They run to completion without a deadline:
+// a write abandoned at its deadline could still commit, and a client
+// retrying the timed-out call would then write it twice.
+var writeTools = map[string]bool{
+	"memorypilot_remember":       true,
+	"memorypilot_remember_batch": true,
+	"memorypilot_update":         true,
+	"memorypilot_curate":         true,
+	"memorypilot_rename_topic":   true,
+	"memorypilot_feedback":       true,
+	"memorypilot_clear":          true,
+}
+
+// SetToolTimeout sets how long a tool call may run before it is answered
+// with a timeout error, for read-only tools without their own timeout. 0 or
+// less restores the default.
+func (s *Server) SetToolTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultToolTimeout
+	}
+	s.toolTimeout = d
+}
+
+// SetToolTimeouts sets the timeouts of individual tools, by name with or
+// without the memorypilot_ prefix (e.g. "recall": 5s). Unknown tools, write
+// tools and timeouts of 0 or less are errors.
+func (s *Server) SetToolTimeouts(timeouts map[string]time.Duration) error {
+	byTool := make(map[string]time.Duration, len(timeouts))
+	for name, d := range timeouts {
+		name, err := s.toolName(name)
+		if err != nil {
+			return err
+		}
+		if writeTools[name] {
+			return fmt.Errorf("%s writes to the store and runs without a timeout", name)
+		}
+		if d <= 0 {
+			return fmt.Errorf("timeout of %s must be positive", name)
+		}
+		byTool[name] = d
+	}
+	s.toolTimeouts = byTool
+	return nil
+}
+
+// timeoutFor returns how long the named tool may run
+func (s *Server) timeoutFor(name string) time.Duration {
+	if d, ok := s.toolTimeouts[name]; ok {
+		return d
+	}
+	return s.toolTimeout
+}
+
+// runTool runs a tool handler under the tool's deadline. If the deadline
+// passes first, the call is answered with a timeout error and abandoned:
+// its context is cancelled, so retries and embedding requests give up, and
+// whatever it sends afterwards is dropped. Work that can't be interrupted,
+// such as a running query, finishes in the background and releases its
+// connection. Write tools run to completion instead.
+func (s *Server) runTool(req *JSONRPCRequest, name string, handler func(ctx context.Context)) {
+	if writeTools[name] {
+		handler(context.Background())
+		return
+	}
+
+	timeout := s.timeoutFor(name)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	s.handlers.Add(1)
+	go func() {
+		defer s.handlers.Done()
+		defer close(done)
+		handler(ctx)
+	}()
+
+	select {
+	case <-done:
+		return
+	case <-ctx.Done():
+	}
+
+	// The timeout error is the call's response; anything it sends later is
+	// dropped until it finishes
+	key := idKey(req.ID)
+	data, _ := json.Marshal(JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Error:   &RPCError{Code: -32001, Message: fmt.Sprintf("Request timed out after %s", timeout)},
+	})
+	s.writeMu.Lock()
+	s.abandoned[key] = true
+	s.failed = true
+	fmt.Fprintf(s.writer, "%s\n", data)
+	s.writeMu.Unlock()
+	log.Printf("Tool call %s timed out after %s", name, timeout)
+
+	go func() {
+		<-done
+		s.writeMu.Lock()
+		delete(s.abandoned, key)
+		s.writeMu.Unlock()
+	}()
+}
+
+// idKey turns a request ID into a map key
+func idKey(id interface{}) string {
+	data, _ := json.Marshal(id)
+	return string(data)
+}
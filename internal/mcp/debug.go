@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+)
+
+// recallDebug describes how a recall arrived at its results: which search
+// ran, whether the embedder answered, where the candidates came from and
+// how long each step took
+type recallDebug struct {
+	Mode          string   `json:"mode"`                // Requested mode
+	Path          string   `json:"path"`                // Search that actually ran
+	Expansion     []string `json:"expansion,omitempty"` // Topics added to the query
+	Embedder      string   `json:"embedder"`            // ok, failed, timeout, invalid, none or skipped
+	EmbedderError string   `json:"embedderError,omitempty"`
+	EmbedMillis   float64  `json:"embedMs"`
+	store.SearchStats
+	SearchMillis float64 `json:"searchMs"`
+}
+
+// embedded records the outcome of embedding the query
+func (d *recallDebug) embedded(vec []float32, err error, took time.Duration) {
+	d.EmbedMillis = millis(took)
+	switch {
+	case errors.Is(err, embedding.ErrTimeout):
+		d.Embedder = "timeout"
+	case err != nil:
+		d.Embedder = "failed"
+	case vec == nil:
+		d.Embedder = "none"
+	case !embedding.Valid(vec):
+		d.Embedder = "invalid"
+	default:
+		d.Embedder = "ok"
+	}
+	if err != nil {
+		d.EmbedderError = err.Error()
+	}
+}
+
+// format renders the debug details as a text section
+func (d *recallDebug) format() string {
+	text := "\nDebug:\n"
+	text += fmt.Sprintf("  mode=%s path=%s\n", d.Mode, d.Path)
+	if len(d.Expansion) > 0 {
+		text += fmt.Sprintf("  expanded with: %s\n", strings.Join(d.Expansion, ", "))
+	}
+	text += fmt.Sprintf("  embedder=%s (%.1fms)", d.Embedder, d.EmbedMillis)
+	if d.EmbedderError != "" {
+		text += ": " + d.EmbedderError
+	}
+	text += "\n"
+	text += fmt.Sprintf("  candidates: semantic=%d keyword=%d merged=%d cached=%t reranked=%t\n",
+		d.Semantic, d.Keyword, d.Merged, d.Cached, d.Reranked)
+	text += fmt.Sprintf("  search=%.1fms\n", d.SearchMillis)
+	return text
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
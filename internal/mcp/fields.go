@@ -0,0 +1,153 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// recallFieldNames are the fields recall's fields argument can select, in
+// the order they are listed in errors
+var recallFieldNames = []string{
+	"id", "type", "summary", "content", "score", "importance", "confidence", "topics",
+	"content_type", "metadata", "related", "source", "created", "curated",
+}
+
+// recallFieldKeys maps a field to the recallResult keys it selects in
+// structured results, where they differ from its name
+var recallFieldKeys = map[string][]string{
+	"content_type": {"contentType"},
+	"related":      {"related", "inferredRelated"},
+	"created":      {"createdAt"},
+	"curated":      {"curatedAt", "curatedBy"},
+}
+
+// parseRecallFields parses a comma-separated field list such as
+// "id,summary,score". An empty list selects every field.
+func parseRecallFields(list string) ([]string, error) {
+	var fields []string
+	for _, f := range strings.Split(list, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" || slices.Contains(fields, f) {
+			continue
+		}
+		if !slices.Contains(recallFieldNames, f) {
+			return nil, fmt.Errorf("unknown field %q in fields (expected %s)", f, strings.Join(recallFieldNames, ", "))
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// trimToFields clears the summary and content of display memories if the
+// fields leave them out, so token estimates only count what is shown.
+// Topics are kept for grouping by topic.
+func trimToFields(display []models.Memory, fields []string) []models.Memory {
+	trimmed := make([]models.Memory, len(display))
+	for i, m := range display {
+		if !slices.Contains(fields, "summary") {
+			m.Summary = ""
+		}
+		if !slices.Contains(fields, "content") {
+			m.Content = ""
+		}
+		trimmed[i] = m
+	}
+	return trimmed
+}
+
+// renderFields renders memories as one line each holding the selected
+// fields in the order given, numbered from offset+1. Content, which may
+// span lines, follows on its own indented line.
+func renderFields(data recallTemplateData, memories []models.Memory, offset int) string {
+	if len(memories) == 0 {
+		return fmt.Sprintf("No memories found for: %q", data.Query)
+	}
+
+	var b strings.Builder
+	if data.Group == "" {
+		fmt.Fprintf(&b, "Found %d memories:\n", len(memories))
+	}
+	now := time.Now()
+	for i, m := range memories {
+		var parts []string
+		for _, f := range data.Fields {
+			if f == "content" {
+				continue
+			}
+			if value := fieldText(m, f, now); value != "" {
+				parts = append(parts, f+": "+value)
+			}
+		}
+		fmt.Fprintf(&b, "%d. %s\n", offset+i+1, strings.Join(parts, " | "))
+		if slices.Contains(data.Fields, "content") && m.Content != "" {
+			fmt.Fprintf(&b, "   %s\n", m.Content)
+		}
+	}
+	return b.String()
+}
+
+// fieldText renders one field of m, or "" if it is empty
+func fieldText(m models.Memory, field string, now time.Time) string {
+	switch field {
+	case "id":
+		return m.ID
+	case "type":
+		return string(m.Type)
+	case "summary":
+		return m.Summary
+	case "score":
+		return fmt.Sprintf("%.3f", m.Score)
+	case "importance":
+		return fmt.Sprintf("%.2f", m.Importance)
+	case "confidence":
+		return fmt.Sprintf("%.2f", m.Confidence)
+	case "topics":
+		return strings.Join(m.Topics, ", ")
+	case "content_type":
+		return string(m.ContentType)
+	case "metadata":
+		return models.FormatMetadata(m.Metadata)
+	case "related":
+		return strings.Join(append(slices.Clone(m.RelatedMemories), m.InferredRelated...), ", ")
+	case "source":
+		return m.Source.String()
+	case "created":
+		return m.CreatedAt.Format("2006-01-02 15:04") + " (" + relativeAge(m.CreatedAt, now) + ")"
+	case "curated":
+		if m.CuratedAt != nil {
+			return formatCuration(&m)
+		}
+	}
+	return ""
+}
+
+// selectFields returns the structured form of a recalled memory holding
+// only the selected fields, plus its ranking explanation if it has one.
+// With no fields selected it returns the full result.
+func selectFields(r recallResult, fields []string) interface{} {
+	if len(fields) == 0 {
+		return r
+	}
+	data, _ := json.Marshal(r)
+	var all map[string]interface{}
+	json.Unmarshal(data, &all)
+
+	selected := make(map[string]interface{}, len(fields)+1)
+	for _, f := range append(slices.Clone(fields), "explanation") {
+		keys, ok := recallFieldKeys[f]
+		if !ok {
+			keys = []string{f}
+		}
+		for _, key := range keys {
+			if v, ok := all[key]; ok {
+				selected[key] = v
+			}
+		}
+	}
+	return selected
+}
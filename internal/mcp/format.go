@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// recallTemplateData is passed to recall templates
+type recallTemplateData struct {
+	Query    string
+	Group    string   // Set when rendering one group of grouped results
+	Fields   []string // Fields selected by the fields argument; all if empty
+	Count    int
+	Memories []recallTemplateMemory
+}
+
+// recallTemplateMemory exposes all memory fields plus display helpers
+type recallTemplateMemory struct {
+	models.Memory
+	Index int    // 1-based position in the results
+	Age   string // e.g. "3 days ago"
+}
+
+// builtinRecallFormats are the named recall output formats
+var builtinRecallFormats = map[string]string{
+	"compact": `{{if not .Memories}}No memories found for: {{printf "%q" .Query}}{{else}}` +
+		`{{range .Memories}}{{.Index}}. [{{.Type}}] {{.Summary}} ({{.Age}})
+{{end}}{{end}}`,
+
+	"detailed": `{{if not .Memories}}No memories found for: {{printf "%q" .Query}}{{else}}{{if not .Group}}Found {{.Count}} memories:
+
+{{end}}{{range .Memories}}{{.Index}}. [{{.Type}}] {{.Summary}}
+   {{.Content}}
+   Created: {{date .CreatedAt}} ({{.Age}})
+   Source: {{source .Source}}
+   Confidence: {{percent .Confidence}}{{if .ContentType}}
+   Content type: {{.ContentType}}{{end}}{{if .Topics}}
+   Topics: {{.Topics}}{{end}}{{if .Metadata}}
+   Metadata: {{meta .Metadata}}{{end}}{{if .RelatedMemories}}
+   Related: {{join .RelatedMemories ", "}}{{end}}{{if .InferredRelated}}
+   Related (inferred): {{join .InferredRelated ", "}}{{end}}{{if .CuratedAt}}
+   Curated: by {{.CuratedBy}} on {{date .CuratedAt}}{{end}}
+
+{{end}}{{end}}`,
+
+	"markdown": `{{if not .Memories}}_No memories found for {{printf "%q" .Query}}._{{else}}{{if not .Group}}## {{.Count}} memories for "{{.Query}}"
+{{end}}{{range .Memories}}
+### {{.Index}}. {{.Summary}}
+
+{{.Content}}
+
+- **Type:** {{.Type}}
+- **Created:** {{date .CreatedAt}} ({{.Age}})
+- **Source:** {{source .Source}}
+- **Confidence:** {{percent .Confidence}}{{if .ContentType}}
+- **Content type:** {{.ContentType}}{{end}}{{if .Topics}}
+- **Topics:** {{join .Topics ", "}}{{end}}{{if .Metadata}}
+- **Metadata:** {{meta .Metadata}}{{end}}{{if .CuratedAt}}
+- **Curated:** by {{.CuratedBy}} on {{date .CuratedAt}}{{end}}
+{{end}}{{end}}`,
+}
+
+// defaultRecallFormat is used when no format is requested
+const defaultRecallFormat = "detailed"
+
+var recallTemplateFuncs = template.FuncMap{
+	"date":   func(t time.Time) string { return t.Format("2006-01-02 15:04") },
+	"source": models.Source.String,
+	"join":   strings.Join,
+	"meta":   models.FormatMetadata,
+	"percent": func(f float64) string {
+		return fmt.Sprintf("%.0f%%", f*100)
+	},
+}
+
+// parseRecallTemplate parses and validates a recall template by rendering
+// it against sample data, so errors surface at load time
+func parseRecallTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(recallTemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recall template %q: %w", name, err)
+	}
+
+	sample := recallTemplateData{
+		Query: "sample",
+		Count: 1,
+		Memories: []recallTemplateMemory{{
+			Memory: models.Memory{Type: models.MemoryTypeFact, Summary: "sample", Content: "sample", ContentType: models.ContentTypeProse, Topics: []string{"sample"}, Metadata: map[string]string{"sample": "sample"}},
+			Index:  1,
+			Age:    "just now",
+		}},
+	}
+	if err := tmpl.Execute(&bytes.Buffer{}, sample); err != nil {
+		return nil, fmt.Errorf("invalid recall template %q: %w", name, err)
+	}
+
+	return tmpl, nil
+}
+
+// loadBuiltinRecallFormats parses the built-in recall templates
+func loadBuiltinRecallFormats() map[string]*template.Template {
+	formats := make(map[string]*template.Template)
+	for name, text := range builtinRecallFormats {
+		tmpl, err := parseRecallTemplate(name, text)
+		if err != nil {
+			panic(err) // Built-in templates are fixed at compile time
+		}
+		formats[name] = tmpl
+	}
+	return formats
+}
+
+// recallFormatNames lists the recall formats a client may request: the
+// built-in ones, and "custom" once a template is set
+func (s *Server) recallFormatNames() []string {
+	names := make([]string, 0, len(s.recallFormats))
+	for name := range s.recallFormats {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// formatRecall renders recall results using the named format, or as lines
+// of the selected fields if any are
+func (s *Server) formatRecall(format, query string, fields []string, memories []models.Memory) (string, error) {
+	if format == "" {
+		format = s.recallFormat
+	}
+	return s.renderRecall(format, recallTemplateData{Query: query, Fields: fields}, memories, 0)
+}
+
+// renderRecall executes the named format for memories, numbering them from
+// offset+1. Selected fields replace the format.
+func (s *Server) renderRecall(format string, data recallTemplateData, memories []models.Memory, offset int) (string, error) {
+	if len(data.Fields) > 0 {
+		return renderFields(data, memories, offset), nil
+	}
+	tmpl, ok := s.recallFormats[format]
+	if !ok {
+		return "", fmt.Errorf("unknown format %q", format)
+	}
+
+	data.Count = len(memories)
+	now := time.Now()
+	for i, m := range memories {
+		data.Memories = append(data.Memories, recallTemplateMemory{
+			Memory: m,
+			Index:  offset + i + 1,
+			Age:    relativeAge(m.CreatedAt, now),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %q format: %w", format, err)
+	}
+	return buf.String(), nil
+}
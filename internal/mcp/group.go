@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// Group names for memories without a topic or project
+const (
+	noTopicGroup   = "(no topic)"
+	noProjectGroup = "(no project)"
+)
+
+// memoryGroup is a section of grouped recall results
+type memoryGroup struct {
+	Name     string
+	Memories []models.Memory
+}
+
+// groupMemories splits ranked memories into groups by type, topic (the
+// first one) or project. Groups are ordered by their best-ranked memory and
+// keep the ranking within each group.
+func (s *Server) groupMemories(memories []models.Memory, by string) ([]memoryGroup, error) {
+	var key func(m models.Memory) (string, error)
+	switch by {
+	case "type":
+		key = func(m models.Memory) (string, error) { return string(m.Type), nil }
+	case "topic":
+		key = func(m models.Memory) (string, error) {
+			if len(m.Topics) == 0 {
+				return noTopicGroup, nil
+			}
+			return m.Topics[0], nil
+		}
+	case "project":
+		names := make(map[string]string)
+		key = func(m models.Memory) (string, error) {
+			if m.ProjectID == nil || *m.ProjectID == "" {
+				return noProjectGroup, nil
+			}
+			id := *m.ProjectID
+			if name, ok := names[id]; ok {
+				return name, nil
+			}
+			p, err := s.store.FindProject(id)
+			if err != nil {
+				return "", err
+			}
+			names[id] = id
+			if p != nil {
+				names[id] = p.Name
+			}
+			return names[id], nil
+		}
+	default:
+		return nil, fmt.Errorf("invalid group_by %q (expected type, topic or project)", by)
+	}
+
+	var groups []memoryGroup
+	index := make(map[string]int)
+	for _, m := range memories {
+		name, err := key(m)
+		if err != nil {
+			return nil, err
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, memoryGroup{Name: name})
+		}
+		groups[i].Memories = append(groups[i].Memories, m)
+	}
+	return groups, nil
+}
+
+// formatGroupedRecall renders grouped recall results as one section per
+// group, each with a header and count followed by its memories in the named
+// format, or as lines of the selected fields. Numbering continues across
+// sections.
+func (s *Server) formatGroupedRecall(format, query string, fields []string, groups []memoryGroup) (string, error) {
+	if len(groups) == 0 {
+		return s.formatRecall(format, query, fields, nil)
+	}
+	if format == "" {
+		format = s.recallFormat
+	}
+
+	total := 0
+	for _, g := range groups {
+		total += len(g.Memories)
+	}
+
+	count := fmt.Sprintf("%d groups", len(groups))
+	if len(groups) == 1 {
+		count = "1 group"
+	}
+
+	var b strings.Builder
+	if format == "markdown" {
+		fmt.Fprintf(&b, "## %d memories for %q in %s\n", total, query, count)
+	} else {
+		fmt.Fprintf(&b, "Found %d memories in %s:\n", total, count)
+	}
+
+	offset := 0
+	for _, g := range groups {
+		if format == "markdown" {
+			fmt.Fprintf(&b, "\n## %s (%d)\n", g.Name, len(g.Memories))
+		} else {
+			fmt.Fprintf(&b, "\n== %s (%d) ==\n", g.Name, len(g.Memories))
+		}
+		text, err := s.renderRecall(format, recallTemplateData{Query: query, Group: g.Name, Fields: fields}, g.Memories, offset)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(text)
+		offset += len(g.Memories)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,176 @@
+package mcp
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+	"github.com/contextpilot-dev/memorypilot/internal/ipc"
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// newMemoryNotification is the method of notifications pushed for newly
+// captured memories
+const newMemoryNotification = "notifications/memorypilot/new"
+
+// DefaultNotifyThreshold is the default similarity a new memory needs to a
+// recent recall query to be pushed
+const DefaultNotifyThreshold = 0.75
+
+// DefaultNotifyInterval is the default minimum time between notifications
+const DefaultNotifyInterval = time.Minute
+
+// recentQueries is how many recall queries new memories are compared with
+const recentQueries = 10
+
+// notifyReconnectDelay is how long to wait before reconnecting to the daemon
+const notifyReconnectDelay = 30 * time.Second
+
+// notifier matches memories captured by the daemon against the client's
+// recent recall queries
+type notifier struct {
+	socketPath string
+	threshold  float32
+	interval   time.Duration
+
+	mu       sync.Mutex
+	queries  []recentQuery // Oldest first
+	lastSent time.Time
+}
+
+// recentQuery is a recall query and its embedding, nil until computed
+type recentQuery struct {
+	text      string
+	embedding []float32
+}
+
+// EnableNotifications subscribes to memories captured by the daemon at
+// socketPath and pushes a notifications/memorypilot/new message for each
+// one whose similarity to a recent recall query reaches threshold, at most
+// once per interval. Zero values use the defaults.
+func (s *Server) EnableNotifications(socketPath string, threshold float32, interval time.Duration) {
+	if threshold <= 0 {
+		threshold = DefaultNotifyThreshold
+	}
+	if interval <= 0 {
+		interval = DefaultNotifyInterval
+	}
+	s.notifier = &notifier{socketPath: socketPath, threshold: threshold, interval: interval}
+}
+
+// rememberQuery records a recall query for matching. emb may be nil (for
+// keyword recalls); it is then computed when a memory arrives.
+func (n *notifier) rememberQuery(query string, emb []float32) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if !embedding.Valid(emb) {
+		emb = nil
+	}
+	for i, q := range n.queries {
+		if q.text == query {
+			n.queries = append(n.queries[:i], n.queries[i+1:]...)
+			if emb == nil {
+				emb = q.embedding
+			}
+			break
+		}
+	}
+	n.queries = append(n.queries, recentQuery{text: query, embedding: emb})
+	if len(n.queries) > recentQueries {
+		n.queries = n.queries[len(n.queries)-recentQueries:]
+	}
+}
+
+// runNotifier watches the daemon until ctx is done, reconnecting when the
+// daemon isn't running or restarts
+func (s *Server) runNotifier(ctx context.Context) {
+	for {
+		err := ipc.Watch(s.notifier.socketPath, nil, func(m models.Memory) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.considerMemory(m)
+			return nil
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("Notifications: %v; retrying in %s", err, notifyReconnectDelay)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(notifyReconnectDelay):
+		}
+	}
+}
+
+// considerMemory pushes m to the client if it is close enough to a recent
+// query and the rate limit allows
+func (s *Server) considerMemory(m models.Memory) {
+	n := s.notifier
+
+	n.mu.Lock()
+	if len(n.queries) == 0 || time.Since(n.lastSent) < n.interval {
+		n.mu.Unlock()
+		return
+	}
+	queries := append([]recentQuery(nil), n.queries...)
+	n.mu.Unlock()
+
+	memEmb, err := s.embedder.Embed(m.Content)
+	if err != nil || !embedding.Valid(memEmb) {
+		return
+	}
+
+	var best recentQuery
+	var bestScore float32
+	for i, q := range queries {
+		if q.embedding == nil {
+			q.embedding, err = s.embedder.Embed(q.text)
+			if err != nil || !embedding.Valid(q.embedding) {
+				continue
+			}
+			queries[i] = q
+			n.cacheEmbedding(q)
+		}
+		if score := embedding.CosineSimilarity(memEmb, q.embedding); score > bestScore {
+			best, bestScore = q, score
+		}
+	}
+	if bestScore < n.threshold {
+		return
+	}
+
+	// Another memory may have been sent while embedding
+	n.mu.Lock()
+	if time.Since(n.lastSent) < n.interval {
+		n.mu.Unlock()
+		return
+	}
+	n.lastSent = time.Now()
+	n.mu.Unlock()
+
+	result := newRecallResult(m)
+	result.Score = bestScore
+	s.sendNotification(newMemoryNotification, map[string]interface{}{
+		"query":  best.text,
+		"memory": result,
+	})
+}
+
+// cacheEmbedding stores a lazily computed query embedding
+func (n *notifier) cacheEmbedding(q recentQuery) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for i := range n.queries {
+		if n.queries[i].text == q.text && n.queries[i].embedding == nil {
+			n.queries[i].embedding = q.embedding
+		}
+	}
+}
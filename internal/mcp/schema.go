@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// inputSchema returns the input schema advertised for the named tool, or
+// nil if there is no such tool
+func (s *Server) inputSchema(name string) map[string]interface{} {
+	for _, tool := range s.tools() {
+		if tool["name"] == name {
+			schema, _ := tool["inputSchema"].(map[string]interface{})
+			return schema
+		}
+	}
+	return nil
+}
+
+// validateArgs checks tool arguments against an input schema: required
+// fields, types, enum values, numeric bounds, array items and nested
+// objects. It returns one problem per offending field, or nil if the
+// arguments are valid. Fields the schema doesn't declare are ignored.
+func validateArgs(schema map[string]interface{}, args json.RawMessage) []string {
+	var value interface{} = map[string]interface{}{}
+	if len(args) > 0 && string(args) != "null" {
+		if err := json.Unmarshal(args, &value); err != nil {
+			return []string{fmt.Sprintf("arguments are not valid JSON: %v", err)}
+		}
+	}
+	return validateValue(schema, value, "")
+}
+
+// validateValue checks value against schema; path names it in problems
+func validateValue(schema map[string]interface{}, value interface{}, path string) []string {
+	if typ, ok := schema["type"].(string); ok && !hasType(value, typ) {
+		return []string{fmt.Sprintf("%s must be %s, got %s", fieldName(path), article(typ), jsonType(value))}
+	}
+
+	var problems []string
+	switch v := value.(type) {
+	case string:
+		if enum, ok := schema["enum"].([]string); ok && !slices.Contains(enum, v) {
+			problems = append(problems, fmt.Sprintf("%s must be one of %s, got %q", fieldName(path), strings.Join(enum, ", "), v))
+		}
+	case float64:
+		if limit, ok := number(schema["minimum"]); ok && v < limit {
+			problems = append(problems, fmt.Sprintf("%s must be at least %v, got %v", fieldName(path), limit, v))
+		}
+		if limit, ok := number(schema["maximum"]); ok && v > limit {
+			problems = append(problems, fmt.Sprintf("%s must be at most %v, got %v", fieldName(path), limit, v))
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				problems = append(problems, validateValue(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case map[string]interface{}:
+		required, _ := schema["required"].([]string)
+		for _, name := range required {
+			if field, ok := v[name]; !ok || field == nil {
+				problems = append(problems, fmt.Sprintf("missing required field %s", fieldName(joinPath(path, name))))
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		extra, _ := schema["additionalProperties"].(map[string]interface{})
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			field := v[name]
+			if field == nil {
+				// Treated as omitted
+				continue
+			}
+			if prop, ok := properties[name].(map[string]interface{}); ok {
+				problems = append(problems, validateValue(prop, field, joinPath(path, name))...)
+			} else if extra != nil {
+				problems = append(problems, validateValue(extra, field, joinPath(path, name))...)
+			}
+		}
+	}
+	return problems
+}
+
+// hasType reports whether a decoded JSON value is of the JSON Schema type
+func hasType(value interface{}, typ string) bool {
+	switch typ {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == float64(int64(n))
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	}
+	return true
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// article prefixes a type name with "a" or "an"
+func article(typ string) string {
+	switch typ {
+	case "array", "integer", "object":
+		return "an " + typ
+	}
+	return "a " + typ
+}
+
+// number converts a schema bound to float64
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// joinPath appends a field name to a path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// fieldName quotes a path for use in a problem
+func fieldName(path string) string {
+	if path == "" {
+		return "arguments"
+	}
+	return fmt.Sprintf("%q", path)
+}
@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidateToolArguments(t *testing.T) {
+	s, _ := newTestServer(t)
+	s.SetAllowClear(true)
+
+	tests := []struct {
+		tool string
+		args string
+		want []string // Problems expected, in order; none if valid
+	}{
+		// Missing required fields
+		{"memorypilot_remember", `{}`, []string{`missing required field "content"`}},
+		{"memorypilot_remember_batch", `{}`, []string{`missing required field "memories"`}},
+		{"memorypilot_remember_batch", `{"memories": [{"type": "fact"}]}`, []string{`missing required field "memories[0].content"`}},
+		{"memorypilot_update", `{"id": "01ABC"}`, []string{`missing required field "content"`}},
+		{"memorypilot_update", `{}`, []string{`missing required field "id"`, `missing required field "content"`}},
+		{"memorypilot_curate", `{}`, []string{`missing required field "id"`}},
+		{"memorypilot_get", `{}`, []string{`missing required field "id"`}},
+		{"memorypilot_similar", `{}`, []string{`missing required field "id"`}},
+		{"memorypilot_continue", `{}`, []string{`missing required field "token"`}},
+		{"memorypilot_rename_topic", `{"from": "db"}`, []string{`missing required field "to"`}},
+		{"memorypilot_history", `{"id": null}`, []string{`missing required field "id"`}},
+		{"memorypilot_feedback", `{"id": "01ABC"}`, []string{`missing required field "useful"`}},
+
+		// Wrong types
+		{"memorypilot_recall", `{"query": "auth", "limit": "ten"}`, []string{`"limit" must be a number, got string`}},
+		{"memorypilot_recall", `{"query": 42}`, []string{`"query" must be a string, got number`}},
+		{"memorypilot_recall", `{"query": "auth", "source": "git"}`, []string{`"source" must be an array, got string`}},
+		{"memorypilot_remember", `{"content": ["a", "b"]}`, []string{`"content" must be a string, got array`}},
+		{"memorypilot_remember", `{"content": "x", "embedding": [0.1, "y"]}`, []string{`"embedding[1]" must be a number, got string`}},
+		{"memorypilot_remember_batch", `{"memories": {"content": "x"}}`, []string{`"memories" must be an array, got object`}},
+		{"memorypilot_curate", `{"id": "01ABC", "importance": true}`, []string{`"importance" must be a number, got boolean`}},
+		{"memorypilot_feedback", `{"id": "01ABC", "useful": "yes"}`, []string{`"useful" must be a boolean, got string`}},
+		{"memorypilot_recent", `{"limit": {}}`, []string{`"limit" must be a number, got object`}},
+		{"memorypilot_clear", `{"trash": 1}`, []string{`"trash" must be a boolean, got number`}},
+
+		// Unknown enum values
+		{"memorypilot_recall", `{"query": "auth", "mode": "fuzzy"}`, []string{`"mode" must be one of hybrid, semantic, keyword, exact, got "fuzzy"`}},
+		{"memorypilot_recall", `{"query": "auth", "format": "custom"}`, []string{`"format" must be one of compact, detailed, markdown, got "custom"`}},
+		{"memorypilot_recall", `{"query": "auth", "source": ["git", "email"]}`, []string{`"source[1]" must be one of git, file, terminal, chat, manual, import, got "email"`}},
+		{"memorypilot_recall", `{"query": "auth", "group_by": "day"}`, []string{`"group_by" must be one of type, topic, project, got "day"`}},
+		{"memorypilot_remember", `{"content": "x", "type": "rumour"}`, []string{`"type" must be one of decision, pattern, fact, preference, mistake, learning, got "rumour"`}},
+		{"memorypilot_remember", `{"content": "x", "content_type": "prose "}`, []string{`"content_type" must be one of prose, code, command, config, got "prose "`}},
+		{"memorypilot_remember_batch", `{"memories": [{"content": "x", "type": "Fact"}]}`, []string{`"memories[0].type" must be one of decision, pattern, fact, preference, mistake, learning, got "Fact"`}},
+		{"memorypilot_recent", `{"type": "note"}`, []string{`"type" must be one of decision, pattern, fact, preference, mistake, learning, got "note"`}},
+		{"memorypilot_status", `{"bucket": "year"}`, []string{`"bucket" must be one of day, week, month, got "year"`}},
+
+		// Several problems are all reported
+		{"memorypilot_remember", `{"type": "rumour", "topics": "db"}`, []string{
+			`missing required field "content"`,
+			`"topics" must be an array, got string`,
+			`"type" must be one of decision, pattern, fact, preference, mistake, learning, got "rumour"`,
+		}},
+
+		// Valid arguments, and undeclared ones, pass
+		{"memorypilot_recall", `{"query": "auth", "mode": "keyword", "limit": 3}`, nil},
+		{"memorypilot_remember", `{"content": "x", "type": "decision", "unknown": 1}`, nil},
+		{"memorypilot_feedback", `{"id": "01ABC", "useful": false}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool+" "+tt.args, func(t *testing.T) {
+			schema := s.inputSchema(tt.tool)
+			if schema == nil {
+				t.Fatalf("no input schema for %s", tt.tool)
+			}
+			got := validateArgs(schema, json.RawMessage(tt.args))
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("validateArgs = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolCallReportsSchemaProblems(t *testing.T) {
+	s, out := newTestServer(t)
+
+	resp := callTool(t, s, out, `{"name": "memorypilot_recall", "arguments": {"query": 1, "mode": "fuzzy"}}`)
+	if resp.Error == nil {
+		t.Fatalf("got result %s, want an error", resp.Result)
+	}
+	want := `Invalid tool arguments: "mode" must be one of hybrid, semantic, keyword, exact, got "fuzzy"; "query" must be a string, got number`
+	if resp.Error.Code != -32602 || resp.Error.Message != want {
+		t.Errorf("error = %d %q, want -32602 %q", resp.Error.Code, resp.Error.Message, want)
+	}
+}
@@ -2,24 +2,101 @@ package mcp
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
 	"time"
 
+	"github.com/contextpilot-dev/memorypilot/internal/chunk"
+	"github.com/contextpilot-dev/memorypilot/internal/contenttype"
 	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+	"github.com/contextpilot-dev/memorypilot/internal/highlight"
+	"github.com/contextpilot-dev/memorypilot/internal/ids"
+	"github.com/contextpilot-dev/memorypilot/internal/lifetime"
+	"github.com/contextpilot-dev/memorypilot/internal/metrics"
+	querylang "github.com/contextpilot-dev/memorypilot/internal/query"
+	"github.com/contextpilot-dev/memorypilot/internal/rerank"
 	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/internal/summary"
+	"github.com/contextpilot-dev/memorypilot/internal/tracing"
 	"github.com/contextpilot-dev/memorypilot/pkg/models"
-	"github.com/oklog/ulid/v2"
 )
 
+// defaultLimit is the number of recall results when no limit is given
+const defaultLimit = 5
+
+// DefaultMaxLimit is the default ceiling on recall results
+const DefaultMaxLimit = 50
+
+// DefaultMaxMessageSize is the default size limit of an incoming JSON-RPC
+// message, in bytes
+const DefaultMaxMessageSize = 4 << 20
+
+// errMessageTooLarge is returned by readMessage for a line over the size
+// limit, after the rest of it has been discarded
+var errMessageTooLarge = errors.New("message too large")
+
+// defaultSnippetContext is how many characters recall snippets keep on
+// each side of the matched region
+const defaultSnippetContext = 150
+
+// embedConcurrency bounds parallel embedding calls for batch operations
+const embedConcurrency = 4
+
 // Server implements the MCP protocol over stdio
 type Server struct {
-	store  *store.Store
-	reader *bufio.Reader
-	writer io.Writer
+	store      *store.Store
+	reader     *bufio.Reader
+	writer     io.Writer
+	structured bool // Include structuredContent in tool results; guarded by stateMu
+	summarizer summary.Summarizer
+	embedder   embedding.Embedder
+
+	recallFormats map[string]*template.Template
+	recallFormat  string // Default format name for recall results
+
+	maxContentLength int  // Longest content accepted, in characters (0 = unlimited)
+	chunkLong        bool // Split content over maxContentLength instead of rejecting it
+
+	maxLimit int // Most results a single recall returns; larger limits are clamped
+
+	maxMessageSize int // Longest incoming message, in bytes; longer ones are rejected
+
+	maxResponseSize int            // Longest tool result text, in bytes (0 = unlimited)
+	continuations   *continuations // Rest of truncated results, for memorypilot_continue
+
+	embedTimeout time.Duration // How long recall waits for the query embedding
+
+	toolTimeout  time.Duration            // How long a tool call may run
+	toolTimeouts map[string]time.Duration // Timeouts of tools that differ from toolTimeout
+	abandoned    map[string]bool          // Calls answered with a timeout error still running, by ID; guarded by writeMu
+
+	lifetimes lifetime.Policy // Default expiry of new memories by type
+
+	similarWarning float32 // Similarity to an existing memory at which remember warns; 0 disables
+
+	disabledTools map[string]bool // Tools left out of tools/list and refused in tools/call
+
+	allowClear bool // Expose memorypilot_clear
+
+	notifier *notifier // Pushes new daemon memories relevant to recent recalls; nil if disabled
+
+	// Set by initialize, which may arrive while an abandoned call still runs
+	stateMu    sync.Mutex
+	clientName string // Name the client gave in initialize, recorded as curator and keying its last-seen mark
+
+	handlers sync.WaitGroup // Running tool calls, including abandoned ones; Run waits for them before closing the store
+
+	failed  bool       // Whether the request being handled was answered with an error; guarded by writeMu
+	writeMu sync.Mutex // Serializes responses and notifications
 }
 
 // NewServer creates a new MCP server
@@ -30,22 +107,192 @@ func NewServer(dbPath string) (*Server, error) {
 	}
 
 	return &Server{
-		store:  s,
-		reader: bufio.NewReader(os.Stdin),
-		writer: os.Stdout,
+		store:      s,
+		reader:     bufio.NewReader(os.Stdin),
+		writer:     os.Stdout,
+		summarizer: summary.NewTruncatingSummarizer(summary.DefaultMaxLen),
+		embedder:   embedding.NewAutoEmbedder(embedding.DefaultProviders()),
+
+		recallFormats: loadBuiltinRecallFormats(),
+		recallFormat:  defaultRecallFormat,
+
+		maxContentLength: chunk.DefaultMaxContentLength,
+
+		maxLimit: DefaultMaxLimit,
+
+		maxMessageSize: DefaultMaxMessageSize,
+
+		maxResponseSize: DefaultMaxResponseSize,
+		continuations:   newContinuations(),
+
+		embedTimeout: embedding.DefaultQueryTimeout,
+
+		toolTimeout: DefaultToolTimeout,
+		abandoned:   make(map[string]bool),
+
+		lifetimes: lifetime.DefaultPolicy(),
 	}, nil
 }
 
+// EnableMetrics serves Prometheus metrics on addr, including the store's
+// size gauges
+func (s *Server) EnableMetrics(addr string) {
+	s.store.RegisterMetrics()
+	metrics.Serve(addr)
+}
+
+// SetLifetimes sets the per-type lifetimes that give new memories their
+// default expiry
+func (s *Server) SetLifetimes(p lifetime.Policy) {
+	s.lifetimes = p
+}
+
+// SetStructured enables structured JSON content in tool results regardless
+// of the protocol version negotiated by the client
+func (s *Server) SetStructured(enabled bool) {
+	s.stateMu.Lock()
+	s.structured = enabled
+	s.stateMu.Unlock()
+}
+
+// SetSummarizer sets how summaries are generated for new and updated memories
+func (s *Server) SetSummarizer(sum summary.Summarizer) {
+	s.summarizer = sum
+}
+
+// summarize generates a summary of the length configured for memories of
+// type t, logging (but tolerating) backend failures
+func (s *Server) summarize(content string, t models.MemoryType) string {
+	text, err := summary.ForType(s.summarizer, content, t)
+	if err != nil {
+		log.Printf("Summarizer failed, using truncated summary: %v", err)
+	}
+	return text
+}
+
+// SetReranker enables reranking of hybrid recall candidates
+func (s *Server) SetReranker(r rerank.Reranker) {
+	s.store.SetReranker(r)
+}
+
+// SetRecallCache sets how many recall results are cached and for how
+// long; see store.SetRecallCache
+func (s *Server) SetRecallCache(size int, ttl time.Duration) {
+	s.store.SetRecallCache(size, ttl)
+}
+
+// SetContentLimit sets the longest content remember accepts, in characters
+// (0 for no limit). Longer content is rejected, or split into linked chunk
+// memories when chunkLong is set.
+func (s *Server) SetContentLimit(maxLen int, chunkLong bool) {
+	s.maxContentLength = maxLen
+	s.chunkLong = chunkLong
+}
+
+// SetMaxLimit sets the most results a single recall returns. Requested
+// limits above it are clamped; 0 or less restores the default.
+func (s *Server) SetMaxLimit(n int) {
+	if n <= 0 {
+		n = DefaultMaxLimit
+	}
+	s.maxLimit = n
+}
+
+// SetMaxMessageSize sets the longest incoming message, in bytes. Longer
+// messages are answered with an error and skipped without being buffered;
+// 0 or less restores the default.
+func (s *Server) SetMaxMessageSize(n int) {
+	if n <= 0 {
+		n = DefaultMaxMessageSize
+	}
+	s.maxMessageSize = n
+}
+
+// SetMaxResponseSize sets the longest text a tool result carries, in bytes.
+// Longer text is truncated and the rest can be fetched with
+// memorypilot_continue, and larger structured content is left out; 0 or
+// less sends results whole.
+func (s *Server) SetMaxResponseSize(n int) {
+	s.maxResponseSize = max(n, 0)
+}
+
+// SetEmbedTimeout sets how long recall waits for the query embedding before
+// falling back to keyword search (or failing, in semantic mode). 0 or less
+// restores the default.
+func (s *Server) SetEmbedTimeout(d time.Duration) {
+	if d <= 0 {
+		d = embedding.DefaultQueryTimeout
+	}
+	s.embedTimeout = d
+}
+
+// clampLimit applies the default and the ceiling to a requested result limit
+func (s *Server) clampLimit(limit int) int {
+	if limit <= 0 {
+		return defaultLimit
+	}
+	return min(limit, s.maxLimit)
+}
+
+// SetAllowClear exposes the memorypilot_clear tool. It is off by default so
+// a client can't wipe the store unless the user started the server for it.
+func (s *Server) SetAllowClear(allow bool) {
+	s.allowClear = allow
+}
+
+// SetSimilarWarning makes memorypilot_remember warn when a new memory's
+// embedding is at least threshold similar (0-1) to an existing memory's,
+// so the client can consolidate them. 0 disables the check, which costs a
+// scan of the stored embeddings on every remember.
+func (s *Server) SetSimilarWarning(threshold float32) {
+	s.similarWarning = threshold
+}
+
+// SetAccessLog enables or disables recording recalls in the access log
+func (s *Server) SetAccessLog(enabled bool) {
+	s.store.SetAccessLog(enabled)
+}
+
+// SetRecallFormat sets the default recall output format. Built-in names are
+// compact, detailed and markdown; any other value is parsed as a Go
+// text/template and registered under the name "custom".
+func (s *Server) SetRecallFormat(format string) error {
+	if _, ok := s.recallFormats[format]; ok {
+		s.recallFormat = format
+		return nil
+	}
+
+	tmpl, err := parseRecallTemplate("custom", format)
+	if err != nil {
+		return err
+	}
+	s.recallFormats["custom"] = tmpl
+	s.recallFormat = "custom"
+	return nil
+}
+
 // Run starts the MCP server (blocks until stdin closes)
 func (s *Server) Run() error {
 	log.SetOutput(os.Stderr) // Log to stderr, not stdout
+	defer s.store.Close()
+	defer s.handlers.Wait() // Abandoned calls may still use the store
 
 	// Send server info
 	s.sendServerInfo()
 
+	if s.notifier != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go s.runNotifier(ctx)
+	}
+
 	// Main loop - read JSON-RPC messages from stdin
 	for {
-		line, err := s.reader.ReadString('\n')
+		line, err := s.readMessage()
+		if err == errMessageTooLarge {
+			s.sendError(nil, -32600, fmt.Sprintf("Invalid Request: message exceeds %d bytes", s.maxMessageSize))
+			continue
+		}
 		if err == io.EOF {
 			return nil
 		}
@@ -55,7 +302,7 @@ func (s *Server) Run() error {
 
 		// Parse JSON-RPC request
 		var req JSONRPCRequest
-		if err := json.Unmarshal([]byte(line), &req); err != nil {
+		if err := json.Unmarshal(line, &req); err != nil {
 			s.sendError(nil, -32700, "Parse error")
 			continue
 		}
@@ -65,6 +312,35 @@ func (s *Server) Run() error {
 	}
 }
 
+// readMessage reads the next newline-delimited message. A final message
+// without a newline is returned before io.EOF. A message over the size
+// limit is discarded up to its newline and errMessageTooLarge returned, so
+// it is never held in memory whole.
+func (s *Server) readMessage() ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := s.reader.ReadSlice('\n')
+		if len(line)+len(chunk) > s.maxMessageSize+1 { // +1 for the newline
+			for err == bufio.ErrBufferFull {
+				_, err = s.reader.ReadSlice('\n')
+			}
+			if err != nil && err != io.EOF {
+				return nil, err
+			}
+			return nil, errMessageTooLarge
+		}
+		line = append(line, chunk...)
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF && len(line) > 0:
+			return line, nil
+		default:
+			return line, err
+		}
+	}
+}
+
 type JSONRPCRequest struct {
 	JSONRPC string          `json:"jsonrpc"`
 	ID      interface{}     `json:"id"`
@@ -91,41 +367,89 @@ func (s *Server) sendServerInfo() {
 			"name":    "memorypilot",
 			"version": "0.1.0",
 		},
-		"capabilities": map[string]interface{}{
-			"tools": map[string]interface{}{},
-		},
+		"capabilities": s.capabilities(),
 	}
 	s.sendResult(nil, info)
 }
 
 func (s *Server) handleRequest(req *JSONRPCRequest) {
+	start := time.Now()
+	s.writeMu.Lock()
+	s.failed = false
+	s.writeMu.Unlock()
+
+	method := req.Method
 	switch req.Method {
 	case "initialize":
 		s.handleInitialize(req)
 	case "tools/list":
 		s.handleToolsList(req)
 	case "tools/call":
-		s.handleToolsCall(req)
+		method = s.handleToolsCall(req)
 	default:
+		method = "unknown"
 		s.sendError(req.ID, -32601, "Method not found")
 	}
+
+	result := "ok"
+	s.writeMu.Lock()
+	if s.failed {
+		result = "error"
+	}
+	s.writeMu.Unlock()
+	metrics.MCPRequests.Inc(method, result)
+	metrics.MCPDuration.ObserveSince(start, method)
 }
 
 func (s *Server) handleInitialize(req *JSONRPCRequest) {
+	var params struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		ClientInfo      struct {
+			Name string `json:"name"`
+		} `json:"clientInfo"`
+	}
+	json.Unmarshal(req.Params, &params)
+	version := negotiateProtocolVersion(params.ProtocolVersion)
+
+	s.stateMu.Lock()
+	s.clientName = params.ClientInfo.Name
+	// structuredContent was introduced in the 2025-06-18 protocol revision
+	if version >= "2025-06-18" {
+		s.structured = true
+	}
+	s.stateMu.Unlock()
+
 	result := map[string]interface{}{
-		"protocolVersion": "2024-11-05",
+		"protocolVersion": version,
 		"serverInfo": map[string]string{
 			"name":    "memorypilot",
 			"version": "0.1.0",
 		},
-		"capabilities": map[string]interface{}{
-			"tools": map[string]interface{}{},
-		},
+		"capabilities": s.capabilities(),
 	}
 	s.sendResult(req.ID, result)
 }
 
+// protocolVersions are the MCP protocol revisions this server speaks,
+// oldest first
+var protocolVersions = []string{"2024-11-05", "2025-03-26", "2025-06-18"}
+
+// negotiateProtocolVersion returns the protocol revision to answer
+// initialize with: the client's, if supported, or else the latest one
+func negotiateProtocolVersion(requested string) string {
+	if slices.Contains(protocolVersions, requested) {
+		return requested
+	}
+	return protocolVersions[len(protocolVersions)-1]
+}
+
 func (s *Server) handleToolsList(req *JSONRPCRequest) {
+	s.sendResult(req.ID, map[string]interface{}{"tools": s.tools()})
+}
+
+// allTools describes every tool this server offers, disabled or not. Their
+// input schemas are also what tool arguments are validated against.
+func (s *Server) allTools() []map[string]interface{} {
 	tools := []map[string]interface{}{
 		{
 			"name":        "memorypilot_recall",
@@ -137,13 +461,136 @@ func (s *Server) handleToolsList(req *JSONRPCRequest) {
 						"type":        "string",
 						"description": "What to search for",
 					},
+					"filter": map[string]interface{}{
+						"type":        "string",
+						"description": "Filters and search terms in one string, e.g. 'type:decision topic:auth since:2024-01 importance:>0.5 \"search terms\"'. Filters: type, topic, -topic, source, content_type, scope (comma-separated lists match any), meta:key=value, since/until (2024, 2024-01, 2024-01-15 or 30d, 2w, 72h), importance and score (e.g. >0.5), curated:true. Quote text holding a colon. Search terms are added to query, and query may be left out",
+					},
 					"limit": map[string]interface{}{
 						"type":        "number",
-						"description": "Maximum results",
-						"default":     5,
+						"description": fmt.Sprintf("Maximum results; values above %d are clamped to %d", s.maxLimit, s.maxLimit),
+						"default":     defaultLimit,
+					},
+					"mode": map[string]interface{}{
+						"type": "string",
+						"description": "Search strategy. hybrid: semantic results first, then keyword matches, " +
+							"merged and cut to limit (keyword only if embeddings are unavailable). " +
+							"semantic: vector similarity only, top limit results, errors if embeddings are unavailable. " +
+							"keyword: substring match on content, summary and topics, up to limit. " +
+							"exact: case-sensitive literal phrase match on content or summary, up to limit.",
+						"enum":    []string{"hybrid", "semantic", "keyword", "exact"},
+						"default": "hybrid",
+					},
+					"min_score": map[string]interface{}{
+						"type":        "number",
+						"description": "Drop results scoring below this relevance threshold (0-1); may return fewer than limit",
+						"minimum":     0,
+						"maximum":     1,
+						"default":     0,
+					},
+					"format": map[string]interface{}{
+						"type":        "string",
+						"description": "Output format for the text result (defaults to the server's configured format)",
+						"enum":        s.recallFormatNames(),
+					},
+					"source": map[string]interface{}{
+						"type":        "array",
+						"description": "Only return memories from these sources (manual = explicitly remembered, others auto-captured)",
+						"items": map[string]interface{}{
+							"type": "string",
+							"enum": []string{"git", "file", "terminal", "chat", "manual", "import"},
+						},
+					},
+					"explain": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Include a per-result ranking breakdown: the semantic or keyword match, importance, feedback, curation and confidence contributions adding up to the final score, and the rerank score if reranked",
+						"default":     false,
+					},
+					"debug": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Report which search ran, whether the embedder answered, candidate counts per source and timing",
+						"default":     false,
+					},
+					"diversity": map[string]interface{}{
+						"type":        "number",
+						"description": "Trade relevance for variety (0-1) using Maximal Marginal Relevance over stored embeddings, so near-duplicates don't crowd out other results; 0 keeps the plain ranking",
+						"minimum":     0,
+						"maximum":     1,
+						"default":     0,
+					},
+					"expand": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Expand the query with related topics (aliases, shared stems and topics the query words start), so terse queries find more; ignored in exact mode",
+						"default":     false,
+					},
+					"fields": map[string]interface{}{
+						"type": "string",
+						"description": "Comma-separated fields to return instead of the full results, one line per memory, e.g. \"id,summary\" to triage many hits cheaply and memorypilot_get the ones worth reading. " +
+							"Fields: " + strings.Join(recallFieldNames, ", "),
+					},
+					"snippet": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Return only a window of content around the best-matching terms instead of the full content (use memorypilot_get for the rest)",
+						"default":     false,
+					},
+					"max_tokens": map[string]interface{}{
+						"type":        "number",
+						"description": "Return as many results as fit in roughly this many tokens instead of a fixed count, with content trimmed to snippets; limit still caps the count if given",
+						"minimum":     0,
+					},
+					"snippet_context": map[string]interface{}{
+						"type":        "number",
+						"description": "Characters of content to keep on each side of the matched region in snippet mode",
+						"default":     defaultSnippetContext,
+					},
+					"highlight": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Wrap query terms in **bold** markdown in the text result of memories matched by keyword search (semantic matches are left plain); leave off for plain text",
+						"default":     false,
+					},
+					"include_related": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Also return memories linked to the results, such as the other chunks of a long document or memories often recalled together with them",
+						"default":     false,
+					},
+					"topics": map[string]interface{}{
+						"type":        "array",
+						"description": "Only return memories tagged with any of these topics (aliases match their canonical topic)",
+						"items":       map[string]interface{}{"type": "string"},
+					},
+					"exclude_topics": map[string]interface{}{
+						"type":        "array",
+						"description": "Never return memories tagged with any of these topics; exclusion wins over topics",
+						"items":       map[string]interface{}{"type": "string"},
+					},
+					"curated_only": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Only return curated memories (see memorypilot_curate)",
+						"default":     false,
+					},
+					"include_low": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Also return auto-captured memories below the importance floor, which are left out unless filtering by type or topic",
+						"default":     false,
+					},
+					"metadata": map[string]interface{}{
+						"type":                 "object",
+						"description":          "Only return memories whose metadata has all of these key/value pairs",
+						"additionalProperties": map[string]interface{}{"type": "string"},
+					},
+					"content_type": map[string]interface{}{
+						"type":        "array",
+						"description": "Only return memories whose content is of these types (detected when stored)",
+						"items": map[string]interface{}{
+							"type": "string",
+							"enum": []string{"prose", "code", "command", "config"},
+						},
+					},
+					"group_by": map[string]interface{}{
+						"type":        "string",
+						"description": "Organize the results into sections by memory type, first topic or project, with a header and count each; structured output then maps each group to its memories",
+						"enum":        []string{"type", "topic", "project"},
 					},
 				},
-				"required": []string{"query"},
 			},
 		},
 		{
@@ -154,7 +601,7 @@ func (s *Server) handleToolsList(req *JSONRPCRequest) {
 				"properties": map[string]interface{}{
 					"content": map[string]interface{}{
 						"type":        "string",
-						"description": "What to remember",
+						"description": "What to remember. Content over the server's length limit is rejected, or split into linked chunks if the server allows it",
 					},
 					"type": map[string]interface{}{
 						"type":        "string",
@@ -162,185 +609,1908 @@ func (s *Server) handleToolsList(req *JSONRPCRequest) {
 						"enum":        []string{"decision", "pattern", "fact", "preference", "mistake", "learning"},
 						"default":     "fact",
 					},
+					"content_type": map[string]interface{}{
+						"type":        "string",
+						"description": "Form of the content; detected from the content (fenced code, shell commands, JSON/YAML) if omitted",
+						"enum":        []string{"prose", "code", "command", "config"},
+					},
 					"topics": map[string]interface{}{
 						"type":        "array",
 						"description": "Topics/tags for this memory",
 						"items":       map[string]interface{}{"type": "string"},
 					},
+					"metadata": map[string]interface{}{
+						"type":                 "object",
+						"description":          "Key/value annotations for this memory (e.g. ticket, author)",
+						"additionalProperties": map[string]interface{}{"type": "string"},
+					},
+					"embedding": map[string]interface{}{
+						"type":        "array",
+						"description": "Precomputed embedding vector to store as-is instead of calling the embedding backend; must match the dimension of stored embeddings",
+						"items":       map[string]interface{}{"type": "number"},
+					},
+					"idempotency_key": map[string]interface{}{
+						"type":        "string",
+						"description": "Client-chosen key for this write; retrying with the same key within 24h returns the original memory instead of creating a duplicate",
+					},
+					"ttl": map[string]interface{}{
+						"type":        "string",
+						"description": "How long to keep this memory (e.g. \"12h\", \"30d\" or \"never\"); defaults to the lifetime configured for its type",
+					},
+					"dry_run": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Preview the summary, type and duplicate check without storing anything",
+						"default":     false,
+					},
 				},
 				"required": []string{"content"},
 			},
 		},
 		{
-			"name":        "memorypilot_status",
-			"description": "Get memory statistics",
+			"name":        "memorypilot_remember_batch",
+			"description": "Remember several things in one call; each item succeeds or fails independently",
 			"inputSchema": map[string]interface{}{
-				"type":       "object",
-				"properties": map[string]interface{}{},
+				"type": "object",
+				"properties": map[string]interface{}{
+					"memories": map[string]interface{}{
+						"type":        "array",
+						"description": "Memories to store",
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"content": map[string]interface{}{
+									"type":        "string",
+									"description": "What to remember",
+								},
+								"type": map[string]interface{}{
+									"type":        "string",
+									"description": "Memory type",
+									"enum":        []string{"decision", "pattern", "fact", "preference", "mistake", "learning"},
+									"default":     "fact",
+								},
+								"topics": map[string]interface{}{
+									"type":        "array",
+									"description": "Topics/tags for this memory",
+									"items":       map[string]interface{}{"type": "string"},
+								},
+								"metadata": map[string]interface{}{
+									"type":                 "object",
+									"description":          "Key/value annotations for this memory",
+									"additionalProperties": map[string]interface{}{"type": "string"},
+								},
+							},
+							"required": []string{"content"},
+						},
+					},
+				},
+				"required": []string{"memories"},
 			},
 		},
-	}
-
-	s.sendResult(req.ID, map[string]interface{}{"tools": tools})
-}
-
-func (s *Server) handleToolsCall(req *JSONRPCRequest) {
-	var params struct {
-		Name      string          `json:"name"`
-		Arguments json.RawMessage `json:"arguments"`
-	}
-
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		s.sendError(req.ID, -32602, "Invalid params")
-		return
-	}
-
-	switch params.Name {
-	case "memorypilot_recall":
-		s.handleRecall(req, params.Arguments)
-	case "memorypilot_remember":
-		s.handleRemember(req, params.Arguments)
-	case "memorypilot_status":
-		s.handleStatus(req)
-	default:
-		s.sendError(req.ID, -32602, "Unknown tool")
-	}
-}
-
-func (s *Server) handleRecall(req *JSONRPCRequest, args json.RawMessage) {
-	var params struct {
-		Query    string `json:"query"`
-		Limit    int    `json:"limit"`
-		Semantic bool   `json:"semantic"`
-	}
-	json.Unmarshal(args, &params)
-
-	if params.Limit == 0 {
-		params.Limit = 5
-	}
-
-	var memories []models.Memory
-	var err error
-
-	// Try semantic search first (hybrid: semantic + keyword)
-	embedder := embedding.NewOllamaEmbedder("", "nomic-embed-text")
-	if queryEmb, embErr := embedder.Embed(params.Query); embErr == nil && queryEmb != nil {
-		memories, err = s.store.HybridSearch(params.Query, queryEmb, params.Limit)
-	} else {
-		// Fall back to keyword search
-		memories, err = s.store.Recall(models.RecallRequest{
-			Query: params.Query,
-			Limit: params.Limit,
-		})
-	}
-
-	if err != nil {
-		s.sendError(req.ID, -32000, err.Error())
-		return
-	}
-
-	// Format as text
-	var text string
-	if len(memories) == 0 {
-		text = fmt.Sprintf("No memories found for: %q", params.Query)
-	} else {
-		text = fmt.Sprintf("Found %d memories:\n\n", len(memories))
-		for i, m := range memories {
-			topicsStr := ""
-			if len(m.Topics) > 0 {
-				topicsStr = fmt.Sprintf("\n   Topics: %v", m.Topics)
-			}
-			text += fmt.Sprintf("%d. [%s] %s\n   %s%s\n\n",
-				i+1, m.Type, m.Summary, m.Content, topicsStr)
-		}
-	}
-
-	s.sendResult(req.ID, map[string]interface{}{
-		"content": []map[string]interface{}{
-			{"type": "text", "text": text},
+		{
+			"name":        "memorypilot_update",
+			"description": "Replace the content of an existing memory (the previous version is kept in history)",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "ID of the memory to update",
+					},
+					"content": map[string]interface{}{
+						"type":        "string",
+						"description": "New content",
+					},
+				},
+				"required": []string{"id", "content"},
+			},
 		},
-	})
-}
-
-func (s *Server) handleRemember(req *JSONRPCRequest, args json.RawMessage) {
-	var params struct {
-		Content string   `json:"content"`
-		Type    string   `json:"type"`
-		Topics  []string `json:"topics"`
-	}
-	json.Unmarshal(args, &params)
-
-	if params.Type == "" {
-		params.Type = "fact"
-	}
-
-	// Create memory
-	now := time.Now()
-	memory := models.Memory{
-		ID:      ulid.Make().String(),
-		Type:    models.MemoryType(params.Type),
-		Content: params.Content,
-		Summary: truncateStr(params.Content, 100),
-		Scope:   models.MemoryScopePersonal,
-		Source: models.Source{
-			Type:      models.SourceTypeManual,
-			Reference: "mcp",
-			Timestamp: now,
+		{
+			"name":        "memorypilot_curate",
+			"description": "Keep a memory worth trusting: mark it curated, raise its importance and optionally rewrite its summary. Curated memories rank above raw capture, don't decay, and can be recalled on their own with curated_only",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "ID of the memory to curate",
+					},
+					"summary": map[string]interface{}{
+						"type":        "string",
+						"description": "New summary; the previous one is kept in history",
+					},
+					"importance": map[string]interface{}{
+						"type":        "number",
+						"description": "Importance to set (0-1); by default it is raised to at least 0.9",
+						"minimum":     0,
+						"maximum":     1,
+					},
+					"curator": map[string]interface{}{
+						"type":        "string",
+						"description": "Who curated the memory, recorded for auditing; defaults to the client's name",
+					},
+				},
+				"required": []string{"id"},
+			},
 		},
-		Confidence:     1.0,
-		Importance:     1.0,
-		Topics:         params.Topics,
-		CreatedAt:      now,
-		LastAccessedAt: now,
-		AccessCount:    0,
-	}
-
-	// Save memory
-	if err := s.store.CreateMemory(&memory); err != nil {
-		s.sendError(req.ID, -32000, fmt.Sprintf("Failed to save memory: %v", err))
-		return
-	}
-
-	// Generate embedding (best effort)
-	embedder := embedding.NewOllamaEmbedder("", "nomic-embed-text")
-	if emb, err := embedder.Embed(memory.Content); err == nil && emb != nil {
-		s.store.UpdateMemoryEmbedding(memory.ID, emb)
-	}
-
-	text := fmt.Sprintf("✅ Remembered: %s\n   Type: %s\n   ID: %s", params.Content, params.Type, memory.ID)
-
-	s.sendResult(req.ID, map[string]interface{}{
-		"content": []map[string]interface{}{
-			{"type": "text", "text": text},
+		{
+			"name":        "memorypilot_get",
+			"description": "Get the full content and details of a memory by ID",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "ID of the memory",
+					},
+				},
+				"required": []string{"id"},
+			},
+		},
+		{
+			"name":        "memorypilot_similar",
+			"description": "Find the memories most similar to a given memory by embedding, for reviewing and consolidating near-duplicates",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "ID of the memory to compare against",
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": fmt.Sprintf("Maximum results; values above %d are clamped to %d", s.maxLimit, s.maxLimit),
+						"default":     defaultLimit,
+					},
+				},
+				"required": []string{"id"},
+			},
+		},
+		{
+			"name":        "memorypilot_continue",
+			"description": "Fetch the rest of a tool result that was truncated for size, using the token given at its end",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"token": map[string]interface{}{
+						"type":        "string",
+						"description": "Continuation token from the truncated result",
+					},
+				},
+				"required": []string{"token"},
+			},
+		},
+		{
+			"name":        "memorypilot_rename_topic",
+			"description": "Rename a topic on every memory and in the alias table, e.g. to standardize \"auth\" as \"authentication\"; renaming onto an existing topic merges them",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"from": map[string]interface{}{
+						"type":        "string",
+						"description": "Current topic name",
+					},
+					"to": map[string]interface{}{
+						"type":        "string",
+						"description": "New topic name",
+					},
+				},
+				"required": []string{"from", "to"},
+			},
+		},
+		{
+			"name":        "memorypilot_history",
+			"description": "List prior versions of a memory",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "ID of the memory",
+					},
+				},
+				"required": []string{"id"},
+			},
+		},
+		{
+			"name":        "memorypilot_recent",
+			"description": "List the most recently created memories, including activity captured from git, files and the terminal, without a search query; use at the start of a session to catch up. With since, summarize what was captured since then, grouped by type and project",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": fmt.Sprintf("Maximum results; values above %d are clamped to %d", s.maxLimit, s.maxLimit),
+						"default":     defaultLimit,
+					},
+					"type": map[string]interface{}{
+						"type":        "string",
+						"description": "Only list memories of this type",
+						"enum":        []string{"decision", "pattern", "fact", "preference", "mistake", "learning"},
+					},
+					"since": map[string]interface{}{
+						"type":        "string",
+						"description": "Summarize memories captured since this date (2024-01-15), RFC 3339 time or duration ago (30d, 2w, 72h), or \"last\" for since this client last asked",
+					},
+				},
+			},
+		},
+		{
+			"name":        "memorypilot_feedback",
+			"description": "Tell memory whether a recalled memory was actually useful, so future recalls rank it higher or lower",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "ID of the recalled memory",
+					},
+					"useful": map[string]interface{}{
+						"type":        "boolean",
+						"description": "true if the memory helped, false if it was irrelevant",
+					},
+				},
+				"required": []string{"id", "useful"},
+			},
+		},
+		{
+			"name":        "memorypilot_status",
+			"description": "Get memory statistics, including embedding coverage by model and how many memories were created per day, week or month",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"bucket": map[string]interface{}{
+						"type":        "string",
+						"description": "Time bucket for the creation trend",
+						"enum":        []string{"day", "week", "month"},
+						"default":     "day",
+					},
+					"periods": map[string]interface{}{
+						"type":        "number",
+						"description": "Number of recent buckets to include",
+						"default":     7,
+					},
+				},
+			},
+		},
+	}
+
+	if s.allowClear {
+		tools = append(tools, map[string]interface{}{
+			"name": "memorypilot_clear",
+			"description": "Delete ALL memories at once. Only use when the user has explicitly asked to wipe their memory, " +
+				"and prefer trash so they can be recovered.",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"trash": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Move memories to the trash table instead of deleting them outright",
+						"default":     false,
+					},
+				},
+			},
+		})
+	}
+
+	return tools
+}
+
+// handleToolsCall dispatches a tool call and returns the tool name for
+// metrics, or "unknown" if there is no such tool
+func (s *Server) handleToolsCall(req *JSONRPCRequest) string {
+	var params struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		s.sendError(req.ID, -32602, "Invalid params")
+		return "unknown"
+	}
+
+	if s.disabledTools[params.Name] {
+		s.sendError(req.ID, -32602, fmt.Sprintf("Tool %s is disabled on this server", params.Name))
+		return params.Name
+	}
+
+	// Unknown tools fall through to the dispatch below
+	if schema := s.inputSchema(params.Name); schema != nil {
+		if problems := validateArgs(schema, params.Arguments); len(problems) > 0 {
+			s.sendError(req.ID, -32602, "Invalid tool arguments: "+strings.Join(problems, "; "))
+			return params.Name
+		}
+	}
+
+	var handler func(ctx context.Context, req *JSONRPCRequest, args json.RawMessage)
+	switch params.Name {
+	case "memorypilot_recall":
+		handler = s.handleRecall
+	case "memorypilot_remember":
+		handler = s.handleRemember
+	case "memorypilot_remember_batch":
+		handler = s.handleRememberBatch
+	case "memorypilot_update":
+		handler = s.handleUpdate
+	case "memorypilot_curate":
+		handler = s.handleCurate
+	case "memorypilot_get":
+		handler = s.handleGet
+	case "memorypilot_similar":
+		handler = s.handleSimilar
+	case "memorypilot_continue":
+		handler = s.handleContinue
+	case "memorypilot_rename_topic":
+		handler = s.handleRenameTopic
+	case "memorypilot_history":
+		handler = s.handleHistory
+	case "memorypilot_recent":
+		handler = s.handleRecent
+	case "memorypilot_feedback":
+		handler = s.handleFeedback
+	case "memorypilot_status":
+		handler = s.handleStatus
+	case "memorypilot_clear":
+		if !s.allowClear {
+			s.sendError(req.ID, -32602, "Unknown tool")
+			return "unknown"
+		}
+		handler = s.handleClear
+	default:
+		s.sendError(req.ID, -32602, "Unknown tool")
+		return "unknown"
+	}
+	s.runTool(req, params.Name, func(ctx context.Context) {
+		handler(ctx, req, params.Arguments)
+	})
+	return params.Name
+}
+
+func (s *Server) handleRecall(ctx context.Context, req *JSONRPCRequest, args json.RawMessage) {
+	var params struct {
+		Query         string            `json:"query"`
+		Limit         int               `json:"limit"`
+		Mode          string            `json:"mode"`
+		MinScore      float32           `json:"min_score"`
+		Source        []string          `json:"source"`
+		Format        string            `json:"format"`
+		Topics        []string          `json:"topics"`
+		ExcludeTopics []string          `json:"exclude_topics"`
+		Metadata      map[string]string `json:"metadata"`
+		CuratedOnly   bool              `json:"curated_only"`
+		IncludeLow    bool              `json:"include_low"`
+		Related       bool              `json:"include_related"`
+		Highlight     bool              `json:"highlight"`
+		Explain       bool              `json:"explain"`
+		Debug         bool              `json:"debug"`
+		Expand        bool              `json:"expand"`
+		Diversity     float64           `json:"diversity"`
+		Snippet       bool              `json:"snippet"`
+		Context       int               `json:"snippet_context"`
+		MaxTokens     int               `json:"max_tokens"`
+		ContentTypes  []string          `json:"content_type"`
+		GroupBy       string            `json:"group_by"`
+		Fields        string            `json:"fields"`
+		Filter        string            `json:"filter"`
+	}
+	if !s.decodeArgs(req, args, &params) {
+		return
+	}
+
+	if strings.TrimSpace(params.Query) == "" && strings.TrimSpace(params.Filter) == "" {
+		s.sendError(req.ID, -32602, "Invalid tool arguments: query or filter is required")
+		return
+	}
+
+	if err := store.ValidateMetadata(params.Metadata); err != nil {
+		s.sendError(req.ID, -32602, "Invalid tool arguments: "+err.Error())
+		return
+	}
+
+	fields, err := parseRecallFields(params.Fields)
+	if err != nil {
+		s.sendError(req.ID, -32602, "Invalid tool arguments: "+err.Error())
+		return
+	}
+
+	// A token budget rather than a count decides how many results fit
+	if params.MaxTokens > 0 && params.Limit == 0 {
+		params.Limit = s.maxLimit
+	}
+	params.Limit = s.clampLimit(params.Limit)
+	if params.Mode == "" {
+		params.Mode = "hybrid"
+	}
+
+	recallReq := models.RecallRequest{
+		Query:         params.Query,
+		Limit:         params.Limit,
+		MinScore:      params.MinScore,
+		Topics:        params.Topics,
+		ExcludeTopics: params.ExcludeTopics,
+		Metadata:      params.Metadata,
+		CuratedOnly:   params.CuratedOnly,
+		IncludeLow:    params.IncludeLow,
+		Explain:       params.Explain,
+		Diversity:     params.Diversity,
+	}
+	for _, src := range params.Source {
+		recallReq.SourceTypes = append(recallReq.SourceTypes, models.SourceType(src))
+	}
+	for _, t := range params.ContentTypes {
+		if !models.ContentType(t).Valid() {
+			s.sendError(req.ID, -32602, fmt.Sprintf("Invalid tool arguments: content_type %q (expected prose, code, command or config)", t))
+			return
+		}
+		recallReq.ContentTypes = append(recallReq.ContentTypes, models.ContentType(t))
+	}
+
+	// Filters and search terms written in the query language
+	if params.Filter != "" {
+		if err := querylang.Apply(&recallReq, params.Filter); err != nil {
+			s.sendError(req.ID, -32602, "Invalid tool arguments: filter: "+err.Error())
+			return
+		}
+		if err := store.ValidateMetadata(recallReq.Metadata); err != nil {
+			s.sendError(req.ID, -32602, "Invalid tool arguments: filter: "+err.Error())
+			return
+		}
+		params.Query, params.MinScore = recallReq.Query, recallReq.MinScore
+	}
+
+	// Without search terms, filtered memories are listed by importance
+	if strings.TrimSpace(params.Query) == "" {
+		if params.Mode != "hybrid" && params.Mode != "keyword" {
+			s.sendError(req.ID, -32602, fmt.Sprintf("Invalid tool arguments: %s search needs search terms", params.Mode))
+			return
+		}
+		params.Mode = "keyword"
+	}
+
+	ctx, span := tracing.Start(ctx, "mcp.recall")
+	defer span.End()
+	span.SetAttr("query.length", len(params.Query))
+	span.SetAttr("recall.mode", params.Mode)
+
+	var memories []models.Memory
+	var queryEmb []float32
+	debug := &recallDebug{Mode: params.Mode, Path: params.Mode, Embedder: "skipped"}
+
+	// Expansion terms widen keyword matching and join the embedded text
+	embedText := params.Query
+	if params.Expand && params.Mode != "exact" {
+		terms, err := s.store.ExpandQuery(params.Query)
+		if err != nil {
+			s.sendError(req.ID, -32000, err.Error())
+			return
+		}
+		recallReq.Expand = terms
+		debug.Expansion = terms
+		if len(terms) > 0 {
+			embedText += " " + strings.Join(terms, " ")
+		}
+	}
+
+	// embed embeds the query, recording the outcome for debugging
+	embed := func() error {
+		start := time.Now()
+		var embErr error
+		queryEmb, embErr = embedding.EmbedTimeout(ctx, s.embedder, embedText, s.embedTimeout)
+		debug.embedded(queryEmb, embErr, time.Since(start))
+		return embErr
+	}
+	searchStart := time.Now()
+
+	switch params.Mode {
+	case "hybrid":
+		// Try semantic search first (hybrid: semantic + keyword)
+		if embErr := embed(); embErr == nil && embedding.Valid(queryEmb) {
+			searchStart = time.Now()
+			memories, err = searchBusy(ctx, func() ([]models.Memory, error) {
+				return s.store.HybridSearchContext(store.WithSearchStats(ctx, &debug.SearchStats), recallReq, queryEmb)
+			})
+		} else {
+			// Fall back to keyword search
+			debug.Path = "keyword"
+			searchStart = time.Now()
+			memories, err = searchBusy(ctx, func() ([]models.Memory, error) { return s.store.Recall(recallReq) })
+			debug.Keyword = len(memories)
+		}
+	case "semantic":
+		embErr := embed()
+		if embErr != nil {
+			span.SetError(embErr)
+			s.sendError(req.ID, -32000, fmt.Sprintf("Semantic search unavailable: %v", embErr))
+			return
+		}
+		if queryEmb == nil {
+			s.sendError(req.ID, -32000, "Semantic search unavailable: no embedding backend")
+			return
+		}
+		if !embedding.Valid(queryEmb) {
+			s.sendError(req.ID, -32000, "Semantic search unavailable: the embedding backend returned an empty or zero vector")
+			return
+		}
+		searchStart = time.Now()
+		memories, err = searchBusy(ctx, func() ([]models.Memory, error) { return s.store.SemanticSearch(recallReq, queryEmb) })
+		debug.Semantic = len(memories)
+	case "keyword":
+		memories, err = searchBusy(ctx, func() ([]models.Memory, error) { return s.store.Recall(recallReq) })
+		debug.Keyword = len(memories)
+	case "exact":
+		recallReq.Exact = true
+		memories, err = searchBusy(ctx, func() ([]models.Memory, error) { return s.store.Recall(recallReq) })
+		debug.Keyword = len(memories)
+	default:
+		s.sendError(req.ID, -32602, fmt.Sprintf("Invalid mode %q (expected hybrid, semantic, keyword or exact)", params.Mode))
+		return
+	}
+	debug.SearchMillis = millis(time.Since(searchStart))
+	if debug.Path != "hybrid" {
+		debug.Merged = debug.Semantic + debug.Keyword
+	}
+	span.SetAttr("recall.path", debug.Path)
+
+	if err != nil {
+		span.SetError(err)
+		s.sendError(req.ID, -32000, err.Error())
+		return
+	}
+
+	memories = store.FilterByScore(memories, params.MinScore)
+	span.SetAttr("result.count", len(memories))
+
+	if s.notifier != nil {
+		s.notifier.rememberQuery(params.Query, queryEmb)
+	}
+
+	if params.Related {
+		memories, err = s.appendRelated(memories)
+		if err != nil {
+			s.sendError(req.ID, -32000, err.Error())
+			return
+		}
+	}
+
+	// Snippets and highlighting only change the text result; structured
+	// content keeps the stored text. Semantic matches have no literal terms
+	// to mark, so only keyword matches are highlighted. A token budget
+	// always trims content to snippets.
+	if params.MaxTokens > 0 {
+		params.Snippet = true
+	}
+	display := memories
+	if params.Snippet || params.Highlight {
+		var h *highlight.Highlighter
+		if params.Mode != "semantic" {
+			h = highlight.New(params.Query, params.Mode == "exact", highlight.Markdown)
+		}
+		if params.Context <= 0 {
+			params.Context = defaultSnippetContext
+		}
+		display = make([]models.Memory, len(memories))
+		for i, m := range memories {
+			if params.Snippet {
+				m.Content = h.Snippet(m.Content, params.Context)
+			}
+			if params.Highlight && m.KeywordMatch {
+				m.Summary = h.Mark(m.Summary)
+				m.Content = h.Mark(m.Content)
+			}
+			display[i] = m
+		}
+	}
+	if len(fields) > 0 {
+		display = trimToFields(display, fields)
+	}
+
+	var budget *recallBudget
+	if params.MaxTokens > 0 {
+		memories, display, budget = fitBudget(memories, display, params.MaxTokens)
+	}
+
+	if !params.Debug {
+		debug = nil
+	}
+
+	if params.GroupBy != "" {
+		s.sendGroupedRecall(req, params.Query, params.Format, params.GroupBy, fields, memories, display, params.Explain, budget, debug)
+		return
+	}
+
+	text, err := s.formatRecall(params.Format, params.Query, fields, display)
+	if err != nil {
+		s.sendError(req.ID, -32602, err.Error())
+		return
+	}
+
+	if params.Explain {
+		text += formatExplanations(memories)
+	}
+
+	results := make([]interface{}, 0, len(memories))
+	for _, m := range memories {
+		results = append(results, selectFields(newRecallResult(m), fields))
+	}
+
+	structured := map[string]interface{}{
+		"query":    params.Query,
+		"memories": results,
+	}
+	if budget != nil {
+		text += budget.format()
+		structured["budget"] = budget
+	}
+	if debug != nil {
+		text += debug.format()
+		structured["debug"] = debug
+	}
+	s.sendToolResult(req.ID, text, structured)
+}
+
+// sendGroupedRecall sends recall results organized into groups. memories
+// are the stored results and display the same results prepared for the text
+// output, limited to fields if any are selected. budget and debug, if set,
+// are reported alongside.
+func (s *Server) sendGroupedRecall(req *JSONRPCRequest, query, format, groupBy string, fields []string, memories, display []models.Memory, explain bool, budget *recallBudget, debug *recallDebug) {
+	groups, err := s.groupMemories(display, groupBy)
+	if err != nil {
+		s.sendError(req.ID, -32000, err.Error())
+		return
+	}
+	text, err := s.formatGroupedRecall(format, query, fields, groups)
+	if err != nil {
+		s.sendError(req.ID, -32602, err.Error())
+		return
+	}
+
+	// Explanations and structured results follow the grouped order
+	stored := make(map[string]models.Memory, len(memories))
+	for _, m := range memories {
+		stored[m.ID] = m
+	}
+	var ordered []models.Memory
+	structured := make(map[string][]interface{}, len(groups))
+	names := make([]string, len(groups))
+	for i, g := range groups {
+		names[i] = g.Name
+		results := make([]interface{}, 0, len(g.Memories))
+		for _, m := range g.Memories {
+			ordered = append(ordered, stored[m.ID])
+			results = append(results, selectFields(newRecallResult(stored[m.ID]), fields))
+		}
+		structured[g.Name] = results
+	}
+
+	if explain {
+		text += formatExplanations(ordered)
+	}
+
+	result := map[string]interface{}{
+		"query":   query,
+		"groupBy": groupBy,
+		"order":   names,
+		"groups":  structured,
+	}
+	if budget != nil {
+		text += budget.format()
+		result["budget"] = budget
+	}
+	if debug != nil {
+		text += debug.format()
+		result["debug"] = debug
+	}
+	s.sendToolResult(req.ID, text, result)
+}
+
+// formatExplanations renders the ranking breakdown of recall results
+func formatExplanations(memories []models.Memory) string {
+	text := "\nRanking:\n"
+	for i, m := range memories {
+		e := m.Explanation
+		if e == nil {
+			// Related memories are appended unranked
+			text += fmt.Sprintf("  %d. %s (not ranked)\n", i+1, m.ID)
+			continue
+		}
+		text += fmt.Sprintf("  %d. %s %s\n", i+1, m.ID, e)
+	}
+	return text
+}
+
+// appendRelated appends the memories linked to the results, manually or
+// inferred from being recalled together, that aren't already among them
+func (s *Server) appendRelated(memories []models.Memory) ([]models.Memory, error) {
+	seen := make(map[string]bool)
+	resultIDs := make([]string, len(memories))
+	for i, m := range memories {
+		seen[m.ID] = true
+		resultIDs[i] = m.ID
+	}
+
+	inferred, err := s.store.InferredLinks(resultIDs)
+	if err != nil {
+		return nil, err
+	}
+	byMemory := make(map[string][]string)
+	for _, l := range inferred {
+		byMemory[l.MemoryID] = append(byMemory[l.MemoryID], l.RelatedID)
+	}
+
+	var ids []string
+	for i := range memories {
+		m := &memories[i]
+		m.InferredRelated = byMemory[m.ID]
+		for _, id := range append(slices.Clone(m.RelatedMemories), m.InferredRelated...) {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	related, err := s.store.GetMemories(ids)
+	if err != nil {
+		return nil, err
+	}
+	return append(memories, related...), nil
+}
+
+// recallResult is the structured form of a recalled memory
+type recallResult struct {
+	ID          string                   `json:"id"`
+	Type        string                   `json:"type"`
+	Summary     string                   `json:"summary"`
+	Content     string                   `json:"content"`
+	ContentType models.ContentType       `json:"contentType,omitempty"`
+	Topics      []string                 `json:"topics,omitempty"`
+	Metadata    map[string]string        `json:"metadata,omitempty"`
+	Related     []string                 `json:"related,omitempty"`
+	Inferred    []string                 `json:"inferredRelated,omitempty"`
+	Importance  float64                  `json:"importance"`
+	Confidence  float64                  `json:"confidence"`
+	Score       float32                  `json:"score,omitempty"`
+	Explanation *models.ScoreExplanation `json:"explanation,omitempty"`
+	Source      models.Source            `json:"source"`
+	CreatedAt   time.Time                `json:"createdAt"`
+	CuratedAt   *time.Time               `json:"curatedAt,omitempty"`
+	CuratedBy   string                   `json:"curatedBy,omitempty"`
+}
+
+func newRecallResult(m models.Memory) recallResult {
+	return recallResult{
+		ID:          m.ID,
+		Type:        string(m.Type),
+		Summary:     m.Summary,
+		Content:     m.Content,
+		ContentType: m.ContentType,
+		Topics:      m.Topics,
+		Metadata:    m.Metadata,
+		Related:     m.RelatedMemories,
+		Inferred:    m.InferredRelated,
+		Importance:  m.Importance,
+		Confidence:  m.Confidence,
+		Score:       m.Score,
+		Explanation: m.Explanation,
+		Source:      m.Source,
+		CreatedAt:   m.CreatedAt,
+		CuratedAt:   m.CuratedAt,
+		CuratedBy:   m.CuratedBy,
+	}
+}
+
+// newMemory builds a manually created memory, defaulting the type to fact
+func (s *Server) newMemory(content, memType string, topics []string) models.Memory {
+	if memType == "" {
+		memType = "fact"
+	}
+
+	now := time.Now()
+	m := models.Memory{
+		ID:          ids.New(),
+		Type:        models.MemoryType(memType),
+		Content:     content,
+		Summary:     s.summarize(content, models.MemoryType(memType)),
+		ContentType: contenttype.Detect(content),
+		Scope:       models.MemoryScopePersonal,
+		Source: models.Source{
+			Type:      models.SourceTypeManual,
+			Reference: "mcp",
+			Timestamp: now,
 		},
+		Confidence:     1.0,
+		Importance:     1.0,
+		Topics:         topics,
+		CreatedAt:      now,
+		LastAccessedAt: now,
+		AccessCount:    0,
+	}
+	s.lifetimes.Apply(&m)
+	return m
+}
+
+// newMemories builds the memory for content. Content over the maximum
+// length is split into chunk memories when chunking is enabled; otherwise
+// the single memory is returned along with the length error.
+func (s *Server) newMemories(content, memType string, topics []string, metadata map[string]string) ([]*models.Memory, error) {
+	err := chunk.Check(content, s.maxContentLength)
+	if err == nil || !s.chunkLong {
+		m := s.newMemory(content, memType, topics)
+		m.Metadata = metadata
+		return []*models.Memory{&m}, err
+	}
+
+	parts := chunk.Split(content, s.maxContentLength, chunk.DefaultOverlap)
+	memories := make([]*models.Memory, len(parts))
+	for i, part := range parts {
+		m := s.newMemory(part, memType, topics)
+		m.Metadata = map[string]string{"chunk": fmt.Sprintf("%d/%d", i+1, len(parts))}
+		for k, v := range metadata {
+			m.Metadata[k] = v
+		}
+		memories[i] = &m
+	}
+	return memories, nil
+}
+
+// checkEmbedding validates a precomputed embedding against the stored ones
+func (s *Server) checkEmbedding(emb []float32) error {
+	if !embedding.Valid(emb) {
+		return fmt.Errorf("embedding must be non-empty, finite and not all zeros")
+	}
+
+	dim, err := s.store.EmbeddingDimension()
+	if err != nil {
+		return err
+	}
+	if dim > 0 && len(emb) != dim {
+		return fmt.Errorf("embedding has %d dimensions but stored embeddings have %d; "+
+			"use the same model for all memories or re-embed them with 'memorypilot reindex --all'", len(emb), dim)
+	}
+	return nil
+}
+
+// embedMemories generates and stores embeddings for memories in parallel
+// (best effort, until ctx is done)
+func (s *Server) embedMemories(ctx context.Context, memories []*models.Memory) {
+	contents := make([]string, len(memories))
+	for i, m := range memories {
+		contents[i] = m.Content
+	}
+	embeddings, _ := embedding.EmbedAll(ctx, s.embedder, contents, embedConcurrency)
+	for i, emb := range embeddings {
+		if emb != nil {
+			s.store.UpdateMemoryEmbedding(memories[i].ID, emb, embedding.ModelName(s.embedder))
+			memories[i].Embedding = emb
+		}
+	}
+}
+
+// findSimilar returns the existing memory most similar to the new
+// memories, other than themselves, if it reaches the similar warning
+// threshold. Memories that couldn't be embedded aren't checked.
+func (s *Server) findSimilar(memories []*models.Memory) *models.Memory {
+	if s.similarWarning <= 0 {
+		return nil
+	}
+	own := make(map[string]bool, len(memories))
+	for _, m := range memories {
+		own[m.ID] = true
+	}
+
+	var best *models.Memory
+	for _, m := range memories {
+		if m.Embedding == nil {
+			continue
+		}
+		// Sibling chunks may be nearer than any other memory
+		similar, err := s.store.Similar(m.ID, m.Embedding, len(memories))
+		if err != nil {
+			log.Printf("Similarity check failed: %v", err)
+			return nil
+		}
+		for i := range similar {
+			if own[similar[i].ID] {
+				continue
+			}
+			if similar[i].Score >= s.similarWarning && (best == nil || similar[i].Score > best.Score) {
+				best = &similar[i]
+			}
+			break
+		}
+	}
+	return best
+}
+
+func (s *Server) handleRemember(ctx context.Context, req *JSONRPCRequest, args json.RawMessage) {
+	var params struct {
+		Content        string            `json:"content"`
+		Type           string            `json:"type"`
+		ContentType    string            `json:"content_type"`
+		Topics         []string          `json:"topics"`
+		Metadata       map[string]string `json:"metadata"`
+		IdempotencyKey string            `json:"idempotency_key"`
+		Embedding      []float32         `json:"embedding"`
+		TTL            string            `json:"ttl"`
+		DryRun         bool              `json:"dry_run"`
+	}
+	if !s.decodeArgs(req, args, &params) {
+		return
+	}
+
+	if strings.TrimSpace(params.Content) == "" {
+		s.sendError(req.ID, -32602, "Invalid tool arguments: content is required")
+		return
+	}
+
+	if err := store.ValidateMetadata(params.Metadata); err != nil {
+		s.sendError(req.ID, -32602, "Invalid tool arguments: "+err.Error())
+		return
+	}
+
+	if params.ContentType != "" && !models.ContentType(params.ContentType).Valid() {
+		s.sendError(req.ID, -32602, fmt.Sprintf("Invalid tool arguments: content_type %q (expected prose, code, command or config)", params.ContentType))
+		return
+	}
+
+	var ttl lifetime.Duration
+	if params.TTL != "" {
+		var err error
+		if ttl, err = lifetime.ParseDuration(params.TTL); err != nil {
+			s.sendError(req.ID, -32602, "Invalid tool arguments: ttl: "+err.Error())
+			return
+		}
+	}
+
+	if params.Embedding != nil {
+		if err := s.checkEmbedding(params.Embedding); err != nil {
+			s.sendError(req.ID, -32602, "Invalid tool arguments: "+err.Error())
+			return
+		}
+	}
+
+	memories, lengthErr := s.newMemories(params.Content, params.Type, params.Topics, params.Metadata)
+	memories[0].IdempotencyKey = params.IdempotencyKey
+	if params.ContentType != "" {
+		for _, m := range memories {
+			m.ContentType = models.ContentType(params.ContentType)
+		}
+	}
+	if params.TTL != "" {
+		for _, m := range memories {
+			m.ExpiresAt = lifetime.Expiry(m.CreatedAt, ttl)
+		}
+	}
+	if params.Embedding != nil {
+		if len(memories) > 1 {
+			s.sendError(req.ID, -32602, "Invalid tool arguments: embedding can't be used with content that is split into chunks")
+			return
+		}
+		memories[0].Embedding = params.Embedding
+	}
+
+	if params.DryRun {
+		s.previewRemember(req, memories, lengthErr)
+		return
+	}
+
+	if lengthErr != nil {
+		s.sendError(req.ID, -32602, "Invalid tool arguments: "+lengthErr.Error()+"; split it up or start the server with --chunk")
+		return
+	}
+
+	// Save memory, unless this is a retry of an earlier write
+	var existing *models.Memory
+	err := retryBusy(ctx, func() error {
+		var err error
+		existing, err = s.store.CreateLinkedMemories(memories)
+		return err
+	})
+	if err != nil {
+		s.sendError(req.ID, -32000, fmt.Sprintf("Failed to save memory: %v", err))
+		return
+	}
+	if existing != nil {
+		text := fmt.Sprintf("↩️ Already remembered (idempotency key %q)\n   Type: %s\n   ID: %s", params.IdempotencyKey, existing.Type, existing.ID)
+		s.sendToolResult(req.ID, text, map[string]interface{}{
+			"id":        existing.ID,
+			"type":      existing.Type,
+			"createdAt": existing.CreatedAt,
+			"deduped":   true,
+		})
+		return
+	}
+
+	// A precomputed embedding was stored with the memory
+	if params.Embedding == nil {
+		s.embedMemories(ctx, memories)
+	}
+
+	memory := memories[0]
+	text := fmt.Sprintf("✅ Remembered: %s\n   Type: %s (%s)\n   ID: %s", params.Content, memory.Type, memory.ContentType, memory.ID)
+	structured := map[string]interface{}{
+		"id":          memory.ID,
+		"type":        memory.Type,
+		"contentType": memory.ContentType,
+		"createdAt":   memory.CreatedAt,
+		"deduped":     false,
+	}
+	if len(memories) > 1 {
+		ids := make([]string, len(memories))
+		for i, m := range memories {
+			ids[i] = m.ID
+		}
+		text = fmt.Sprintf("✅ Remembered in %d linked chunks\n   Type: %s\n   IDs: %s", len(memories), memory.Type, strings.Join(ids, ", "))
+		structured["chunkIds"] = ids
+	}
+	if len(params.Metadata) > 0 {
+		text += "\n   Metadata: " + models.FormatMetadata(params.Metadata)
+	}
+	if memory.ExpiresAt != nil {
+		text += "\n   Expires: " + memory.ExpiresAt.Format("2006-01-02 15:04")
+		structured["expiresAt"] = memory.ExpiresAt
+	}
+	if similar := s.findSimilar(memories); similar != nil {
+		text += fmt.Sprintf("\n⚠️ Similar to existing memory %s (%.2f): %s", similar.ID, similar.Score, similar.Summary)
+		structured["similarTo"] = map[string]interface{}{
+			"id":      similar.ID,
+			"score":   similar.Score,
+			"summary": similar.Summary,
+		}
+	}
+
+	s.sendToolResult(req.ID, text, structured)
+}
+
+// previewRemember reports how memories would be stored without writing
+// them. lengthErr is the content length error, if any.
+func (s *Server) previewRemember(req *JSONRPCRequest, memories []*models.Memory, lengthErr error) {
+	memory := memories[0]
+	existing, err := s.store.PreviewLinkedMemories(memories)
+	if err != nil {
+		s.sendError(req.ID, -32000, err.Error())
+		return
+	}
+	duplicate, err := s.store.FindByContent(memory.Content)
+	if err != nil {
+		s.sendError(req.ID, -32000, err.Error())
+		return
+	}
+
+	text := fmt.Sprintf("🔍 Dry run (nothing was stored)\n   Content: %s\n   Summary: %s\n   Type: %s\n   Content type: %s",
+		memory.Content, memory.Summary, memory.Type, memory.ContentType)
+	if len(memory.Topics) > 0 {
+		text += fmt.Sprintf("\n   Topics: %v", memory.Topics)
+	}
+	if len(memory.Metadata) > 0 {
+		text += "\n   Metadata: " + models.FormatMetadata(memory.Metadata)
+	}
+	if memory.ExpiresAt != nil {
+		text += "\n   Expires: " + memory.ExpiresAt.Format("2006-01-02 15:04")
+	}
+
+	if len(memories) > 1 {
+		text += fmt.Sprintf("\n   Chunks: %d (content exceeds %d characters)", len(memories), s.maxContentLength)
+	}
+
+	var problems []string
+	if !memory.Type.Valid() {
+		problems = append(problems, fmt.Sprintf("invalid type %q", memory.Type))
+	}
+	if lengthErr != nil {
+		problems = append(problems, lengthErr.Error())
+	}
+	if existing != nil {
+		text += fmt.Sprintf("\n   Already remembered as %s (idempotency key %q); nothing new would be stored", existing.ID, memory.IdempotencyKey)
+	}
+	if duplicate != nil {
+		text += fmt.Sprintf("\n   Duplicate of: %s", duplicate.ID)
+	}
+	if len(problems) > 0 {
+		text += "\n   Would fail: " + strings.Join(problems, ", ")
+	}
+
+	structured := map[string]interface{}{
+		"dryRun":      true,
+		"summary":     memory.Summary,
+		"type":        memory.Type,
+		"contentType": memory.ContentType,
+		"topics":      memory.Topics,
+		"metadata":    memory.Metadata,
+		"chunks":      len(memories),
+		"expiresAt":   memory.ExpiresAt,
+		"valid":       len(problems) == 0,
+	}
+	if existing != nil {
+		structured["deduped"] = true
+		structured["existingId"] = existing.ID
+	}
+	if duplicate != nil {
+		structured["duplicateOf"] = duplicate.ID
+	}
+	s.sendToolResult(req.ID, text, structured)
+}
+
+func (s *Server) handleRememberBatch(ctx context.Context, req *JSONRPCRequest, args json.RawMessage) {
+	var params struct {
+		Memories []struct {
+			Content  string            `json:"content"`
+			Type     string            `json:"type"`
+			Topics   []string          `json:"topics"`
+			Metadata map[string]string `json:"metadata"`
+		} `json:"memories"`
+	}
+	if !s.decodeArgs(req, args, &params) {
+		return
+	}
+	if len(params.Memories) == 0 {
+		s.sendError(req.ID, -32602, "Invalid tool arguments: memories must contain at least one item")
+		return
+	}
+
+	type itemResult struct {
+		Index    int      `json:"index"`
+		ID       string   `json:"id,omitempty"`
+		ChunkIDs []string `json:"chunkIds,omitempty"`
+		Error    string   `json:"error,omitempty"`
+	}
+
+	results := make([]itemResult, len(params.Memories))
+	var memories []*models.Memory
+	var indexes []int
+	var created []*models.Memory
+
+	for i, item := range params.Memories {
+		results[i].Index = i
+		if item.Content == "" {
+			results[i].Error = "content is required"
+			continue
+		}
+		if err := store.ValidateMetadata(item.Metadata); err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		chunks, err := s.newMemories(item.Content, item.Type, item.Topics, item.Metadata)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		if len(chunks) > 1 {
+			// Chunks of one item are stored together, in their own transaction
+			err := retryBusy(ctx, func() error {
+				_, err := s.store.CreateLinkedMemories(chunks)
+				return err
+			})
+			if err != nil {
+				results[i].Error = err.Error()
+				continue
+			}
+			results[i].ID = chunks[0].ID
+			for _, c := range chunks {
+				results[i].ChunkIDs = append(results[i].ChunkIDs, c.ID)
+			}
+			created = append(created, chunks...)
+			continue
+		}
+		memories = append(memories, chunks[0])
+		indexes = append(indexes, i)
+	}
+
+	var errs []error
+	err := retryBusy(ctx, func() error {
+		var err error
+		errs, err = s.store.CreateMemories(memories)
+		return err
+	})
+	if err != nil {
+		s.sendError(req.ID, -32000, fmt.Sprintf("Failed to save memories: %v", err))
+		return
+	}
+
+	for j, m := range memories {
+		i := indexes[j]
+		if errs[j] != nil {
+			results[i].Error = errs[j].Error()
+			continue
+		}
+		results[i].ID = m.ID
+		created = append(created, m)
+	}
+
+	succeeded := 0
+	for _, r := range results {
+		if r.Error == "" {
+			succeeded++
+		}
+	}
+
+	s.embedMemories(ctx, created)
+	failed := len(params.Memories) - succeeded
+
+	text := fmt.Sprintf("Remembered %d of %d memories (%d failed)\n", succeeded, len(params.Memories), failed)
+	for _, r := range results {
+		if r.Error != "" {
+			text += fmt.Sprintf("  %d. ❌ %s\n", r.Index+1, r.Error)
+		} else if len(r.ChunkIDs) > 1 {
+			text += fmt.Sprintf("  %d. ✅ %s (%d chunks)\n", r.Index+1, r.ID, len(r.ChunkIDs))
+		} else {
+			text += fmt.Sprintf("  %d. ✅ %s\n", r.Index+1, r.ID)
+		}
+	}
+
+	s.sendToolResult(req.ID, text, map[string]interface{}{
+		"succeeded": succeeded,
+		"failed":    failed,
+		"results":   results,
+	})
+}
+
+func (s *Server) handleUpdate(ctx context.Context, req *JSONRPCRequest, args json.RawMessage) {
+	var params struct {
+		ID      string `json:"id"`
+		Content string `json:"content"`
+	}
+	if !s.decodeArgs(req, args, &params) {
+		return
+	}
+
+	if params.ID == "" || params.Content == "" {
+		s.sendError(req.ID, -32602, "Invalid tool arguments: id and content are required")
+		return
+	}
+
+	// The summary gets the length of the memory's type, if it exists
+	var memType models.MemoryType
+	if m, err := s.store.GetMemory(params.ID); err == nil && m != nil {
+		memType = m.Type
+	}
+	newSummary := s.summarize(params.Content, memType)
+	err := retryBusy(ctx, func() error {
+		return s.store.UpdateMemoryContent(params.ID, params.Content, newSummary, "mcp")
+	})
+	if err != nil {
+		s.sendError(req.ID, -32000, fmt.Sprintf("Failed to update memory: %v", err))
+		return
+	}
+
+	// Regenerate embedding for the new content (best effort)
+	if emb, err := s.embedder.Embed(params.Content); err == nil && emb != nil {
+		s.store.UpdateMemoryEmbedding(params.ID, emb, embedding.ModelName(s.embedder))
+	}
+
+	text := fmt.Sprintf("✅ Updated: %s\n   ID: %s", params.Content, params.ID)
+
+	s.sendToolResult(req.ID, text, map[string]interface{}{
+		"id": params.ID,
+	})
+}
+
+func (s *Server) handleCurate(ctx context.Context, req *JSONRPCRequest, args json.RawMessage) {
+	var params struct {
+		ID         string  `json:"id"`
+		Summary    string  `json:"summary"`
+		Importance float64 `json:"importance"`
+		Curator    string  `json:"curator"`
+	}
+	if !s.decodeArgs(req, args, &params) {
+		return
+	}
+
+	if params.ID == "" {
+		s.sendError(req.ID, -32602, "Invalid tool arguments: id is required")
+		return
+	}
+	if params.Importance < 0 || params.Importance > 1 {
+		s.sendError(req.ID, -32602, "Invalid tool arguments: importance must be between 0 and 1")
+		return
+	}
+
+	curator := strings.TrimSpace(params.Curator)
+	if curator == "" {
+		curator = s.client()
+	}
+	if curator == "" {
+		curator = "mcp"
+	}
+
+	var m *models.Memory
+	err := retryBusy(ctx, func() error {
+		var err error
+		m, err = s.store.CurateMemory(params.ID, strings.TrimSpace(params.Summary), curator, params.Importance)
+		return err
+	})
+	if err != nil {
+		s.sendError(req.ID, -32000, fmt.Sprintf("Failed to curate memory: %v", err))
+		return
+	}
+
+	text := fmt.Sprintf("✅ Curated: %s\n   ID: %s\n   Importance: %.2f\n   Curated: %s",
+		m.Summary, m.ID, m.Importance, formatCuration(m))
+
+	s.sendToolResult(req.ID, text, newRecallResult(*m))
+}
+
+// formatCuration describes who curated a memory and when
+func formatCuration(m *models.Memory) string {
+	return fmt.Sprintf("by %s on %s", m.CuratedBy, m.CuratedAt.Format("2006-01-02 15:04"))
+}
+
+func (s *Server) handleGet(ctx context.Context, req *JSONRPCRequest, args json.RawMessage) {
+	var params struct {
+		ID string `json:"id"`
+	}
+	if !s.decodeArgs(req, args, &params) {
+		return
+	}
+
+	if params.ID == "" {
+		s.sendError(req.ID, -32602, "Invalid tool arguments: id is required")
+		return
+	}
+
+	m, err := s.store.GetMemory(params.ID)
+	if err != nil {
+		s.sendError(req.ID, -32000, err.Error())
+		return
+	}
+	if m == nil {
+		s.sendError(req.ID, -32602, fmt.Sprintf("Memory %s not found", params.ID))
+		return
+	}
+
+	text := fmt.Sprintf("[%s] %s\n\n%s\n\nID: %s\nCreated: %s\nSource: %s\nConfidence: %.0f%%",
+		m.Type, m.Summary, m.Content, m.ID, m.CreatedAt.Format("2006-01-02 15:04"), m.Source.String(), m.Confidence*100)
+	if m.ContentType != "" {
+		text += "\nContent type: " + string(m.ContentType)
+	}
+	if len(m.Topics) > 0 {
+		text += "\nTopics: " + strings.Join(m.Topics, ", ")
+	}
+	if len(m.Metadata) > 0 {
+		text += "\nMetadata: " + models.FormatMetadata(m.Metadata)
+	}
+	if len(m.RelatedMemories) > 0 {
+		text += "\nRelated: " + strings.Join(m.RelatedMemories, ", ")
+	}
+	if m.CuratedAt != nil {
+		text += "\nCurated: " + formatCuration(m)
+	}
+	inferred, err := s.store.InferredLinks([]string{m.ID})
+	if err != nil {
+		s.sendError(req.ID, -32000, err.Error())
+		return
+	}
+	for _, l := range inferred {
+		m.InferredRelated = append(m.InferredRelated, l.RelatedID)
+	}
+	if len(m.InferredRelated) > 0 {
+		text += "\nRelated (inferred): " + strings.Join(m.InferredRelated, ", ")
+	}
+
+	s.sendToolResult(req.ID, text, newRecallResult(*m))
+}
+
+// handleContinue sends the next part of a tool result truncated by the
+// response size limit; a still-too-long rest is truncated again with a new
+// token
+func (s *Server) handleContinue(ctx context.Context, req *JSONRPCRequest, args json.RawMessage) {
+	var params struct {
+		Token string `json:"token"`
+	}
+	if !s.decodeArgs(req, args, &params) {
+		return
+	}
+
+	text, ok := s.continuations.take(params.Token)
+	if !ok {
+		s.sendError(req.ID, -32602, "Invalid tool arguments: unknown or expired continuation token")
+		return
+	}
+	s.sendToolResult(req.ID, text, nil)
+}
+
+func (s *Server) handleRenameTopic(ctx context.Context, req *JSONRPCRequest, args json.RawMessage) {
+	var params struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+	if !s.decodeArgs(req, args, &params) {
+		return
+	}
+
+	var changed int
+	err := retryBusy(ctx, func() error {
+		var err error
+		changed, err = s.store.RenameTopic(params.From, params.To)
+		return err
+	})
+	if err != nil {
+		s.sendError(req.ID, -32000, fmt.Sprintf("Failed to rename topic: %v", err))
+		return
+	}
+
+	text := fmt.Sprintf("🏷️ Renamed topic %q to %q on %d memories", params.From, params.To, changed)
+	s.sendToolResult(req.ID, text, map[string]interface{}{
+		"from":    params.From,
+		"to":      params.To,
+		"changed": changed,
+	})
+}
+
+func (s *Server) handleHistory(ctx context.Context, req *JSONRPCRequest, args json.RawMessage) {
+	var params struct {
+		ID string `json:"id"`
+	}
+	if !s.decodeArgs(req, args, &params) {
+		return
+	}
+
+	if params.ID == "" {
+		s.sendError(req.ID, -32602, "Invalid tool arguments: id is required")
+		return
+	}
+
+	versions, err := s.store.GetHistory(params.ID)
+	if err != nil {
+		s.sendError(req.ID, -32000, err.Error())
+		return
+	}
+
+	var text string
+	if len(versions) == 0 {
+		text = fmt.Sprintf("No history for memory %s", params.ID)
+	} else {
+		text = fmt.Sprintf("%d prior versions of %s:\n\n", len(versions), params.ID)
+		for _, v := range versions {
+			text += fmt.Sprintf("v%d (%s by %s)\n   %s\n\n",
+				v.Version, v.EditedAt.Format("2006-01-02 15:04"), v.Editor, v.Content)
+		}
+	}
+
+	s.sendToolResult(req.ID, text, map[string]interface{}{
+		"id":       params.ID,
+		"versions": versions,
+	})
+}
+
+func (s *Server) handleSimilar(ctx context.Context, req *JSONRPCRequest, args json.RawMessage) {
+	var params struct {
+		ID    string `json:"id"`
+		Limit int    `json:"limit"`
+	}
+	if !s.decodeArgs(req, args, &params) {
+		return
+	}
+
+	if params.ID == "" {
+		s.sendError(req.ID, -32602, "Invalid tool arguments: id is required")
+		return
+	}
+
+	m, err := s.store.GetMemory(params.ID)
+	if err != nil {
+		s.sendError(req.ID, -32000, err.Error())
+		return
+	}
+	if m == nil {
+		s.sendError(req.ID, -32602, fmt.Sprintf("Memory %s not found", params.ID))
+		return
+	}
+
+	dim, err := s.store.EmbeddingDimension()
+	if err != nil {
+		s.sendError(req.ID, -32000, err.Error())
+		return
+	}
+	if dim == 0 {
+		s.sendToolResult(req.ID, "No memories have embeddings yet, so there is nothing to compare against. "+
+			"Run 'memorypilot reindex' once an embedding backend is available.", map[string]interface{}{
+			"id":       m.ID,
+			"memories": []recallResult{},
+		})
+		return
+	}
+
+	// Embed the memory on the fly if it was stored without an embedding
+	emb, err := s.store.MemoryEmbedding(m.ID)
+	if err != nil {
+		s.sendError(req.ID, -32000, err.Error())
+		return
+	}
+	if emb == nil {
+		emb, err = embedding.EmbedTimeout(ctx, s.embedder, m.Content, s.embedTimeout)
+		if err != nil {
+			s.sendError(req.ID, -32000, fmt.Sprintf("Memory %s has no embedding and embedding it failed: %v", m.ID, err))
+			return
+		}
+		if !embedding.Valid(emb) {
+			s.sendError(req.ID, -32000, fmt.Sprintf("Memory %s has no embedding and no embedding backend is available", m.ID))
+			return
+		}
+	}
+
+	similar, err := s.store.Similar(m.ID, emb, s.clampLimit(params.Limit))
+	if err != nil {
+		s.sendError(req.ID, -32000, err.Error())
+		return
+	}
+
+	var text string
+	if len(similar) == 0 {
+		text = fmt.Sprintf("No other memories to compare with [%s] %s", m.Type, m.Summary)
+	} else {
+		text = fmt.Sprintf("%d memories similar to [%s] %s:\n\n", len(similar), m.Type, m.Summary)
+		for i, sm := range similar {
+			text += fmt.Sprintf("%d. [%s] %s (similarity %.3f)\n   ID: %s\n", i+1, sm.Type, sm.Summary, sm.Score, sm.ID)
+		}
+	}
+
+	results := make([]recallResult, 0, len(similar))
+	for _, sm := range similar {
+		results = append(results, newRecallResult(sm))
+	}
+	s.sendToolResult(req.ID, text, map[string]interface{}{
+		"id":       m.ID,
+		"memories": results,
+	})
+}
+
+func (s *Server) handleRecent(ctx context.Context, req *JSONRPCRequest, args json.RawMessage) {
+	var params struct {
+		Limit int    `json:"limit"`
+		Type  string `json:"type"`
+		Since string `json:"since"`
+	}
+	if !s.decodeArgs(req, args, &params) {
+		return
+	}
+
+	opts := store.ListOptions{Limit: s.clampLimit(params.Limit)}
+	if params.Type != "" {
+		if !models.MemoryType(params.Type).Valid() {
+			s.sendError(req.ID, -32602, fmt.Sprintf("Invalid tool arguments: unknown type %q", params.Type))
+			return
+		}
+		opts.Types = []models.MemoryType{models.MemoryType(params.Type)}
+	}
+	if params.Since != "" {
+		s.sendActivity(ctx, req, params.Since, opts)
+		return
+	}
+
+	memories, err := s.store.ListMemories(opts)
+	if err != nil {
+		s.sendError(req.ID, -32000, err.Error())
+		return
+	}
+	slices.Reverse(memories) // Newest first
+
+	var text string
+	if len(memories) == 0 {
+		text = "No memories yet"
+	} else {
+		text = fmt.Sprintf("%d most recent memories:\n\n", len(memories))
+		now := time.Now()
+		for i, m := range memories {
+			text += fmt.Sprintf("%d. [%s] %s (%s)\n   Source: %s\n   ID: %s\n",
+				i+1, m.Type, m.Summary, relativeAge(m.CreatedAt, now), m.Source.String(), m.ID)
+		}
+	}
+
+	results := make([]recallResult, 0, len(memories))
+	for _, m := range memories {
+		results = append(results, newRecallResult(m))
+	}
+	s.sendToolResult(req.ID, text, map[string]interface{}{
+		"memories": results,
+	})
+}
+
+// client returns the name the client gave in initialize
+func (s *Server) client() string {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	return s.clientName
+}
+
+// activityReader keys the last-seen mark of the connected client
+func (s *Server) activityReader() string {
+	if name := s.client(); name != "" {
+		return "mcp:" + name
+	}
+	return "mcp"
+}
+
+// sendActivity summarizes the memories captured since a point in time, or
+// since the client last asked, listing up to opts.Limit of them. Asking
+// moves the client's last-seen mark to now.
+func (s *Server) sendActivity(ctx context.Context, req *JSONRPCRequest, value string, opts store.ListOptions) {
+	now := time.Now()
+	var since time.Time
+	if value == "last" {
+		lastSeen, err := s.store.LastSeen(s.activityReader())
+		if err != nil {
+			s.sendError(req.ID, -32000, err.Error())
+			return
+		}
+		since = lastSeen
+		if since.IsZero() {
+			since = now.Add(-store.DefaultActivityWindow)
+		}
+	} else {
+		var err error
+		if since, err = querylang.ParseSince(value); err != nil {
+			s.sendError(req.ID, -32602, fmt.Sprintf("Invalid tool arguments: since: %v", err))
+			return
+		}
+	}
+
+	activity, err := s.store.ActivitySince(since, opts.Types)
+	if err != nil {
+		s.sendError(req.ID, -32000, err.Error())
+		return
+	}
+	// A read-only store can't keep the mark; the summary is still worth sending
+	if err := retryBusy(ctx, func() error { return s.store.MarkSeen(s.activityReader(), now) }); err != nil {
+		log.Printf("Failed to record last seen: %v", err)
+	}
+
+	stamp := since.Local().Format("2006-01-02 15:04")
+	text := fmt.Sprintf("Nothing captured since %s", stamp)
+	if activity.Total > 0 {
+		text = fmt.Sprintf("%d memories captured since %s: %s\n", activity.Total, stamp, formatTypeCounts(activity.ByType))
+	}
+	projects := make([]map[string]interface{}, 0, len(activity.Projects))
+	listed := 0
+	for _, p := range activity.Projects {
+		name := p.Name
+		if p.ProjectID == "" {
+			name = "(no project)"
+		}
+		text += fmt.Sprintf("\n%s (%s):\n", name, formatTypeCounts(p.ByType))
+
+		results := make([]recallResult, 0, len(p.Memories))
+		for _, m := range p.Memories {
+			if listed == opts.Limit {
+				break
+			}
+			listed++
+			text += fmt.Sprintf("- [%s] %s (%s, ID: %s)\n", m.Type, m.Summary, relativeAge(m.CreatedAt, now), m.ID)
+			results = append(results, newRecallResult(m))
+		}
+		projects = append(projects, map[string]interface{}{
+			"projectId": p.ProjectID,
+			"name":      p.Name,
+			"byType":    p.ByType,
+			"memories":  results,
+		})
+	}
+	if listed < activity.Total {
+		text += fmt.Sprintf("\n%d more not listed; raise limit to see them\n", activity.Total-listed)
+	}
+
+	s.sendToolResult(req.ID, text, map[string]interface{}{
+		"since":    since,
+		"total":    activity.Total,
+		"byType":   activity.ByType,
+		"projects": projects,
+	})
+}
+
+// formatTypeCounts lists memory counts by type, e.g. "2 decisions, 1 fact"
+func formatTypeCounts(byType map[string]int) string {
+	var parts []string
+	for _, t := range []string{"decision", "pattern", "fact", "preference", "mistake", "learning"} {
+		switch n := byType[t]; n {
+		case 0:
+		case 1:
+			parts = append(parts, "1 "+t)
+		default:
+			parts = append(parts, fmt.Sprintf("%d %ss", n, t))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (s *Server) handleFeedback(ctx context.Context, req *JSONRPCRequest, args json.RawMessage) {
+	var params struct {
+		ID     string `json:"id"`
+		Useful *bool  `json:"useful"`
+	}
+	if !s.decodeArgs(req, args, &params) {
+		return
+	}
+
+	if params.ID == "" || params.Useful == nil {
+		s.sendError(req.ID, -32602, "Invalid tool arguments: id and useful are required")
+		return
+	}
+
+	var weight float64
+	err := retryBusy(ctx, func() error {
+		var err error
+		weight, err = s.store.RecordFeedback(params.ID, *params.Useful)
+		return err
+	})
+	if err != nil {
+		s.sendError(req.ID, -32000, fmt.Sprintf("Failed to record feedback: %v", err))
+		return
+	}
+
+	text := fmt.Sprintf("👍 Marked %s as useful (feedback weight %.2f)", params.ID, weight)
+	if !*params.Useful {
+		text = fmt.Sprintf("👎 Marked %s as not useful (feedback weight %.2f)", params.ID, weight)
+	}
+	s.sendToolResult(req.ID, text, map[string]interface{}{
+		"id":       params.ID,
+		"useful":   *params.Useful,
+		"feedback": weight,
+	})
+}
+
+// handleClear deletes every memory. The tool is only offered when the
+// user started the server with clearing allowed; a confirmation sent back
+// through the model couldn't tell the user's approval from the model's own.
+func (s *Server) handleClear(ctx context.Context, req *JSONRPCRequest, args json.RawMessage) {
+	var params struct {
+		Trash bool `json:"trash"`
+	}
+	if !s.decodeArgs(req, args, &params) {
+		return
+	}
+
+	n, err := s.store.ClearMemories(params.Trash)
+	if err != nil {
+		s.sendError(req.ID, -32000, err.Error())
+		return
+	}
+
+	text := fmt.Sprintf("🗑️ Deleted %d memories", n)
+	if params.Trash {
+		text = fmt.Sprintf("🗑️ Moved %d memories to the trash", n)
+	}
+	s.sendToolResult(req.ID, text, map[string]interface{}{
+		"cleared": true,
+		"count":   n,
+		"trashed": params.Trash,
 	})
 }
 
-func truncateStr(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
+// relativeAge formats the time elapsed since t as a human-readable age
+// such as "just now", "5 minutes ago" or "2 years ago"
+func relativeAge(t, now time.Time) string {
+	d := now.Sub(t)
+	if d < time.Minute {
+		return "just now"
+	}
+
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"year", 365 * 24 * time.Hour},
+		{"month", 30 * 24 * time.Hour},
+		{"week", 7 * 24 * time.Hour},
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+
+	for _, u := range units {
+		if d >= u.size {
+			n := int(d / u.size)
+			if n == 1 {
+				return fmt.Sprintf("1 %s ago", u.name)
+			}
+			return fmt.Sprintf("%d %ss ago", n, u.name)
+		}
 	}
-	return s[:maxLen-3] + "..."
+	return "just now"
 }
 
-func (s *Server) handleStatus(req *JSONRPCRequest) {
+func (s *Server) handleStatus(ctx context.Context, req *JSONRPCRequest, args json.RawMessage) {
+	var params struct {
+		Bucket  string `json:"bucket"`
+		Periods int    `json:"periods"`
+	}
+	if !s.decodeArgs(req, args, &params) {
+		return
+	}
+
+	if params.Bucket == "" {
+		params.Bucket = "day"
+	}
+
 	stats, err := s.store.GetStats()
 	if err != nil {
 		s.sendError(req.ID, -32000, err.Error())
 		return
 	}
 
+	trend, err := s.store.GetCreationTrend(params.Bucket, params.Periods)
+	if err != nil {
+		s.sendError(req.ID, -32602, err.Error())
+		return
+	}
+
+	embeddings, err := s.store.GetEmbeddingStats()
+	if err != nil {
+		s.sendError(req.ID, -32000, err.Error())
+		return
+	}
+
 	text := fmt.Sprintf("MemoryPilot Status\n\nTotal memories: %d\nProjects: %d\n\nBy type:\n",
 		stats.TotalMemories, stats.ProjectCount)
 	for t, count := range stats.ByType {
 		text += fmt.Sprintf("  %s: %d\n", t, count)
 	}
 
-	s.sendResult(req.ID, map[string]interface{}{
+	text += fmt.Sprintf("\nEmbeddings: %d of %d memories embedded, %d missing, %d stale\n",
+		embeddings.Embedded, embeddings.Total, embeddings.Unembedded, embeddings.Stale)
+	for _, g := range embeddings.Groups {
+		model := g.Model
+		if model == "" {
+			model = "unknown model"
+		}
+		stale := ""
+		if g.Stale {
+			stale = " (stale)"
+		}
+		text += fmt.Sprintf("  %s, %d dimensions: %d%s\n", model, g.Dimension, g.Count, stale)
+	}
+	if embeddings.Stale > 0 {
+		text += "Stale embeddings come from another model or dimension; run `memorypilot reindex --all` to re-embed.\n"
+	} else if embeddings.Unembedded > 0 {
+		text += "Run `memorypilot reindex` to embed the missing memories.\n"
+	}
+
+	text += fmt.Sprintf("\nCreated per %s:\n", params.Bucket)
+	for _, b := range trend {
+		text += fmt.Sprintf("  %s: %d\n", b.Period, b.Count)
+	}
+
+	s.sendToolResult(req.ID, text, map[string]interface{}{
+		"stats":      stats,
+		"embeddings": embeddings,
+		"trend":      trend,
+	})
+}
+
+// decodeArgs unmarshals tool arguments into params, replying with -32602
+// and returning false if they are malformed. Missing arguments decode as {}.
+func (s *Server) decodeArgs(req *JSONRPCRequest, args json.RawMessage, params interface{}) bool {
+	if len(args) == 0 || string(args) == "null" {
+		return true
+	}
+	if err := json.Unmarshal(args, params); err != nil {
+		s.sendError(req.ID, -32602, fmt.Sprintf("Invalid tool arguments: %v", err))
+		return false
+	}
+	return true
+}
+
+// sendToolResult sends a tool result with a human-readable text block and,
+// when enabled, the same data as structuredContent for programmatic clients
+func (s *Server) sendToolResult(id interface{}, text string, structured interface{}) {
+	s.stateMu.Lock()
+	enabled := s.structured
+	s.stateMu.Unlock()
+
+	var note string
+	if enabled && structured != nil {
+		structured, note = s.boundStructured(structured)
+	} else {
+		structured = nil
+	}
+
+	result := map[string]interface{}{
 		"content": []map[string]interface{}{
-			{"type": "text", "text": text},
+			{"type": "text", "text": s.boundText(text, note)},
 		},
-	})
+	}
+	if structured != nil {
+		result["structuredContent"] = structured
+	}
+	s.sendResult(id, result)
 }
 
 func (s *Server) sendResult(id interface{}, result interface{}) {
@@ -361,7 +2531,35 @@ func (s *Server) sendError(id interface{}, code int, message string) {
 	s.send(resp)
 }
 
+// sendNotification sends a JSON-RPC notification, which has no ID and
+// expects no response
+func (s *Server) sendNotification(method string, params interface{}) {
+	data, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  params,
+	})
+	s.write(data)
+}
+
 func (s *Server) send(resp JSONRPCResponse) {
 	data, _ := json.Marshal(resp)
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	// Calls abandoned at their deadline were already answered
+	if resp.ID != nil && s.abandoned[idKey(resp.ID)] {
+		return
+	}
+	if resp.Error != nil {
+		s.failed = true
+	}
+	fmt.Fprintf(s.writer, "%s\n", data)
+}
+
+// write sends one message line; notifications are sent from another
+// goroutine, so writes are serialized
+func (s *Server) write(data []byte) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
 	fmt.Fprintf(s.writer, "%s\n", data)
 }
@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+)
+
+// newTestServer returns a server on a fresh database, without an embedding
+// backend, writing its messages to the returned buffer
+func newTestServer(t *testing.T) (*Server, *bytes.Buffer) {
+	t.Helper()
+	s, err := NewServer(filepath.Join(t.TempDir(), "memories.db"))
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	t.Cleanup(func() { s.store.Close() })
+
+	out := &bytes.Buffer{}
+	s.writer = out
+	s.embedder = &embedding.NullEmbedder{}
+	return s, out
+}
+
+// testResponse is a decoded JSON-RPC response
+type testResponse struct {
+	ID     interface{}     `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// callTool sends a tools/call request with params, given as raw JSON, and
+// returns the response
+func callTool(t *testing.T, s *Server, out *bytes.Buffer, params string) testResponse {
+	t.Helper()
+	out.Reset()
+	s.handleRequest(&JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: json.RawMessage(params)})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	var resp testResponse
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &resp); err != nil {
+		t.Fatalf("invalid response %q: %v", out.String(), err)
+	}
+	return resp
+}
+
+func TestMalformedToolArguments(t *testing.T) {
+	s, out := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		params string
+		want   string
+	}{
+		{"params not JSON", `{"name": "memorypilot_recall", "arguments": {"query": }`, "Invalid params"},
+		{"arguments not an object", `{"name": "memorypilot_recall", "arguments": "auth"}`, `arguments must be an object, got string`},
+		{"arguments an array", `{"name": "memorypilot_remember", "arguments": ["content"]}`, `arguments must be an object, got array`},
+		{"recall without query", `{"name": "memorypilot_recall", "arguments": {}}`, "query or filter is required"},
+		{"recall with blank query", `{"name": "memorypilot_recall", "arguments": {"query": "   "}}`, "query or filter is required"},
+		{"remember without arguments", `{"name": "memorypilot_remember"}`, `missing required field "content"`},
+		{"remember with null content", `{"name": "memorypilot_remember", "arguments": {"content": null}}`, `missing required field "content"`},
+		{"remember with blank content", `{"name": "memorypilot_remember", "arguments": {"content": " \n "}}`, "content is required"},
+		{"unknown tool", `{"name": "memorypilot_forget", "arguments": {}}`, "Unknown tool"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := callTool(t, s, out, tt.params)
+			if resp.Error == nil {
+				t.Fatalf("got result %s, want an error", resp.Result)
+			}
+			if resp.Error.Code != -32602 {
+				t.Errorf("error code = %d, want -32602", resp.Error.Code)
+			}
+			if !strings.Contains(resp.Error.Message, tt.want) {
+				t.Errorf("error message = %q, want it to contain %q", resp.Error.Message, tt.want)
+			}
+		})
+	}
+
+	// Nothing was stored by the rejected calls
+	stats, err := s.store.GetStats()
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.TotalMemories != 0 {
+		t.Errorf("store holds %d memories after rejected calls, want 0", stats.TotalMemories)
+	}
+}
+
+func TestOversizedMessage(t *testing.T) {
+	s, out := newTestServer(t)
+	s.SetMaxMessageSize(256)
+
+	huge := `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"memorypilot_remember","arguments":{"content":"` +
+		strings.Repeat("x", 4096) + `"}}}`
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+		huge,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`,
+		huge,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/list"}`, // No final newline
+	}, "\n")
+	// A buffer much smaller than the message, so it is read in pieces
+	s.reader = bufio.NewReaderSize(strings.NewReader(input), 64)
+
+	if err := s.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	var ids []string
+	tooLarge := 0
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var resp testResponse
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("invalid message %q: %v", line, err)
+		}
+		if resp.Error != nil {
+			if resp.Error.Code != -32600 || !strings.Contains(resp.Error.Message, "exceeds 256 bytes") {
+				t.Errorf("unexpected error %+v", resp.Error)
+			}
+			tooLarge++
+			continue
+		}
+		if resp.ID != nil {
+			ids = append(ids, fmt.Sprint(resp.ID))
+		}
+	}
+	if tooLarge != 2 {
+		t.Errorf("got %d message too large errors, want 2", tooLarge)
+	}
+	// The messages after each oversized one are still answered
+	if want := []string{"1", "3", "4"}; !slices.Equal(ids, want) {
+		t.Errorf("answered requests %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// toolName returns the full name of a tool given with or without the
+// memorypilot_ prefix, or an error if the server has no such tool
+func (s *Server) toolName(name string) (string, error) {
+	if !strings.HasPrefix(name, toolPrefix) {
+		name = toolPrefix + name
+	}
+	if name == toolPrefix+"clear" {
+		return name, nil
+	}
+	for _, tool := range s.allTools() {
+		if tool["name"] == name {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("unknown tool %q", name)
+}
+
+// SetToolAccess chooses which tools the server offers, by name with or
+// without the memorypilot_ prefix: only the enabled ones if any are given,
+// otherwise all of them, minus the disabled ones. Other tools are left out
+// of tools/list and calls to them are refused, so a server can for example
+// recall but not remember. memorypilot_clear also needs SetAllowClear.
+// Unknown tools are errors, as is leaving no tool enabled.
+func (s *Server) SetToolAccess(enabled, disabled []string) error {
+	disabledTools := make(map[string]bool)
+	if len(enabled) > 0 {
+		keep := make(map[string]bool, len(enabled))
+		for _, name := range enabled {
+			name, err := s.toolName(name)
+			if err != nil {
+				return err
+			}
+			keep[name] = true
+		}
+		for _, tool := range s.allTools() {
+			if name := tool["name"].(string); !keep[name] {
+				disabledTools[name] = true
+			}
+		}
+		if !keep[toolPrefix+"clear"] {
+			disabledTools[toolPrefix+"clear"] = true
+		}
+	}
+	for _, name := range disabled {
+		name, err := s.toolName(name)
+		if err != nil {
+			return err
+		}
+		disabledTools[name] = true
+	}
+
+	previous := s.disabledTools
+	s.disabledTools = disabledTools
+	if len(s.tools()) == 0 {
+		s.disabledTools = previous
+		return fmt.Errorf("at least one tool must stay enabled")
+	}
+	return nil
+}
+
+// tools describes the tools this server offers and clients may call
+func (s *Server) tools() []map[string]interface{} {
+	all := s.allTools()
+	if len(s.disabledTools) == 0 {
+		return all
+	}
+	tools := make([]map[string]interface{}, 0, len(all))
+	for _, tool := range all {
+		if !s.disabledTools[tool["name"].(string)] {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// capabilities is what the server tells clients it supports, including
+// the names of the tools it offers
+func (s *Server) capabilities() map[string]interface{} {
+	tools := s.tools()
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool["name"].(string)
+	}
+	return map[string]interface{}{
+		"tools": map[string]interface{}{},
+		"experimental": map[string]interface{}{
+			"memorypilot": map[string]interface{}{
+				"tools": names,
+			},
+		},
+	}
+}
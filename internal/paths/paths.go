@@ -0,0 +1,140 @@
+// Package paths resolves where MemoryPilot keeps its configuration,
+// database, logs and daemon runtime files on each platform.
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// HomeEnv overrides every location with a single directory, laid out like
+// the legacy ~/.memorypilot
+const HomeEnv = "MEMORYPILOT_HOME"
+
+// DatabaseFile is the name of the SQLite database in the data directory
+const DatabaseFile = "memories.db"
+
+// legacyDir is the directory used before platform conventions were
+// followed, relative to the home directory. It keeps being used while it
+// exists so upgrading doesn't lose an existing store.
+const legacyDir = ".memorypilot"
+
+// Paths holds the resolved MemoryPilot directories
+type Paths struct {
+	Config  string // config.yaml, importance.json, lifetimes.json, ids.json, repos.json, federation.json, summaries.json, hooks.json, redaction.json
+	Data    string // The database and its snapshots
+	Logs    string // Daemon logs
+	Runtime string // PID file and IPC socket
+	Source  string // How the locations were chosen: env, legacy or platform
+}
+
+// Database returns the path of the SQLite database
+func (p Paths) Database() string {
+	return filepath.Join(p.Data, DatabaseFile)
+}
+
+// Snapshots returns the directory holding database snapshots
+func (p Paths) Snapshots() string {
+	return filepath.Join(p.Data, "snapshots")
+}
+
+// ConfigFile returns the path of config.yaml
+func (p Paths) ConfigFile() string {
+	return filepath.Join(p.Config, "config.yaml")
+}
+
+// PidFile returns the path of the daemon PID file
+func (p Paths) PidFile() string {
+	return filepath.Join(p.Runtime, "memorypilot.pid")
+}
+
+// Socket returns the path of the daemon IPC socket
+func (p Paths) Socket() string {
+	return filepath.Join(p.Runtime, "memorypilot.sock")
+}
+
+// Resolve determines the MemoryPilot directories. In order of precedence:
+// MEMORYPILOT_HOME, an existing ~/.memorypilot, then the platform's
+// conventions (XDG base directories on Linux and other Unix systems,
+// ~/Library on macOS, %APPDATA% and %LOCALAPPDATA% on Windows).
+func Resolve() (Paths, error) {
+	if dir := os.Getenv(HomeEnv); dir != "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return Paths{}, fmt.Errorf("invalid %s: %w", HomeEnv, err)
+		}
+		return singleDir(abs, "env"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return Paths{}, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	legacy := filepath.Join(home, legacyDir)
+	if info, err := os.Stat(legacy); err == nil && info.IsDir() {
+		return singleDir(legacy, "legacy"), nil
+	}
+
+	return platformPaths(runtime.GOOS, home, os.Getenv), nil
+}
+
+// singleDir lays out every location under one directory
+func singleDir(dir, source string) Paths {
+	return Paths{
+		Config:  dir,
+		Data:    filepath.Join(dir, "data"),
+		Logs:    filepath.Join(dir, "logs"),
+		Runtime: dir,
+		Source:  source,
+	}
+}
+
+// platformPaths returns the conventional locations for goos
+func platformPaths(goos, home string, getenv func(string) string) Paths {
+	// envOr returns the directory named by an environment variable, or
+	// fallback if it is unset or (as the XDG spec requires) not absolute
+	envOr := func(key, fallback string) string {
+		if dir := getenv(key); filepath.IsAbs(dir) {
+			return dir
+		}
+		return fallback
+	}
+
+	switch goos {
+	case "darwin":
+		support := filepath.Join(home, "Library", "Application Support", "MemoryPilot")
+		return Paths{
+			Config:  support,
+			Data:    support,
+			Logs:    filepath.Join(home, "Library", "Logs", "MemoryPilot"),
+			Runtime: support,
+			Source:  "platform",
+		}
+	case "windows":
+		roaming := envOr("APPDATA", filepath.Join(home, "AppData", "Roaming"))
+		local := filepath.Join(envOr("LOCALAPPDATA", filepath.Join(home, "AppData", "Local")), "MemoryPilot")
+		return Paths{
+			Config:  filepath.Join(roaming, "MemoryPilot"),
+			Data:    filepath.Join(local, "data"),
+			Logs:    filepath.Join(local, "logs"),
+			Runtime: local,
+			Source:  "platform",
+		}
+	default:
+		state := filepath.Join(envOr("XDG_STATE_HOME", filepath.Join(home, ".local", "state")), "memorypilot")
+		runtimeDir := state
+		if dir := envOr("XDG_RUNTIME_DIR", ""); dir != "" {
+			runtimeDir = filepath.Join(dir, "memorypilot")
+		}
+		return Paths{
+			Config:  filepath.Join(envOr("XDG_CONFIG_HOME", filepath.Join(home, ".config")), "memorypilot"),
+			Data:    filepath.Join(envOr("XDG_DATA_HOME", filepath.Join(home, ".local", "share")), "memorypilot"),
+			Logs:    filepath.Join(state, "logs"),
+			Runtime: runtimeDir,
+			Source:  "platform",
+		}
+	}
+}
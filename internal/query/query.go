@@ -0,0 +1,315 @@
+// Package query parses the recall query language, which packs filters and
+// search terms into one string:
+//
+//	type:decision topic:auth since:2024-01 importance:>0.5 "search terms"
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// Filters lists the filter keys, in the order they are listed in errors
+var Filters = []string{
+	"type", "topic", "-topic", "source", "content_type", "scope", "meta",
+	"since", "until", "importance", "score", "curated",
+}
+
+// Apply parses input and adds its filters to req, appending its search
+// terms to req.Query. A filter is a key:value token; values can be quoted
+// (topic:"machine learning"), and type, topic, source, content_type and
+// scope take comma-separated lists, matching any of them. Everything else,
+// including quoted text, is searched for. Text holding a colon must be
+// quoted so it isn't taken for a filter.
+func Apply(req *models.RecallRequest, input string) error {
+	return apply(req, input, time.Now())
+}
+
+// ParseSince parses a point in time as the since filter does: a year, month,
+// date, RFC 3339 time, or a duration back from now
+func ParseSince(value string) (time.Time, error) {
+	return parseTime(value, time.Now(), false)
+}
+
+func apply(req *models.RecallRequest, input string, now time.Time) error {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return err
+	}
+
+	var terms []string
+	for _, t := range tokens {
+		if t.key == "" {
+			terms = append(terms, t.value)
+			continue
+		}
+		if err := applyFilter(req, t.key, t.value, now); err != nil {
+			return fmt.Errorf("%s:%s: %w", t.key, t.value, err)
+		}
+	}
+
+	if len(terms) > 0 {
+		req.Query = strings.TrimSpace(req.Query + " " + strings.Join(terms, " "))
+	}
+	return nil
+}
+
+// token is a filter, or a search term if key is empty
+type token struct {
+	key   string
+	value string
+}
+
+// tokenize splits input at whitespace outside double quotes
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		// A quoted phrase is always a search term
+		if runes[i] == '"' {
+			end := indexQuote(runes, i+1)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote in %q", string(runes[i:]))
+			}
+			if phrase := strings.TrimSpace(string(runes[i+1 : end])); phrase != "" {
+				tokens = append(tokens, token{value: phrase})
+			}
+			i = end + 1
+			continue
+		}
+
+		start := i
+		for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != ':' && runes[i] != '"' {
+			i++
+		}
+		word := string(runes[start:i])
+		if i < len(runes) && runes[i] == ':' && isKey(word) {
+			key := strings.ToLower(word)
+			i++
+			var value string
+			if i < len(runes) && runes[i] == '"' {
+				end := indexQuote(runes, i+1)
+				if end < 0 {
+					return nil, fmt.Errorf("unterminated quote in %q", string(runes[start:]))
+				}
+				value = string(runes[i+1 : end])
+				i = end + 1
+			} else {
+				valueStart := i
+				for i < len(runes) && !unicode.IsSpace(runes[i]) {
+					i++
+				}
+				value = string(runes[valueStart:i])
+			}
+			if strings.TrimSpace(value) == "" {
+				return nil, fmt.Errorf("%s: has no value", key)
+			}
+			tokens = append(tokens, token{key: key, value: strings.TrimSpace(value)})
+			continue
+		}
+
+		// Not a filter: the rest of the word is a search term
+		for i < len(runes) && !unicode.IsSpace(runes[i]) {
+			i++
+		}
+		tokens = append(tokens, token{value: string(runes[start:i])})
+	}
+	return tokens, nil
+}
+
+// indexQuote returns the index of the first double quote in runes at or
+// after from, or -1
+func indexQuote(runes []rune, from int) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == '"' {
+			return i
+		}
+	}
+	return -1
+}
+
+// isKey reports whether word can be a filter key: letters and underscores,
+// optionally negated with a leading -
+func isKey(word string) bool {
+	word = strings.TrimPrefix(word, "-")
+	if word == "" {
+		return false
+	}
+	for _, r := range word {
+		if !unicode.IsLetter(r) && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// applyFilter adds one filter to req
+func applyFilter(req *models.RecallRequest, key, value string, now time.Time) error {
+	switch key {
+	case "type":
+		for _, v := range list(value) {
+			t := models.MemoryType(v)
+			if !t.Valid() {
+				return fmt.Errorf("unknown type %q (expected decision, pattern, fact, preference, mistake or learning)", v)
+			}
+			req.Types = append(req.Types, t)
+		}
+	case "topic":
+		req.Topics = append(req.Topics, list(value)...)
+	case "-topic":
+		req.ExcludeTopics = append(req.ExcludeTopics, list(value)...)
+	case "source":
+		for _, v := range list(value) {
+			switch src := models.SourceType(v); src {
+			case models.SourceTypeGit, models.SourceTypeFile, models.SourceTypeTerminal,
+				models.SourceTypeChat, models.SourceTypeManual, models.SourceTypeImport:
+				req.SourceTypes = append(req.SourceTypes, src)
+			default:
+				return fmt.Errorf("unknown source %q (expected git, file, terminal, chat, manual or import)", v)
+			}
+		}
+	case "content_type":
+		for _, v := range list(value) {
+			t := models.ContentType(v)
+			if !t.Valid() {
+				return fmt.Errorf("unknown content type %q (expected prose, code, command or config)", v)
+			}
+			req.ContentTypes = append(req.ContentTypes, t)
+		}
+	case "scope":
+		for _, v := range list(value) {
+			switch scope := models.MemoryScope(v); scope {
+			case models.MemoryScopePersonal, models.MemoryScopeProject, models.MemoryScopeTeam, models.MemoryScopeOrg:
+				req.Scope = append(req.Scope, scope)
+			default:
+				return fmt.Errorf("unknown scope %q (expected personal, project, team or org)", v)
+			}
+		}
+	case "meta":
+		k, v, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return fmt.Errorf("expected key=value")
+		}
+		if req.Metadata == nil {
+			req.Metadata = make(map[string]string)
+		}
+		req.Metadata[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	case "since":
+		t, err := parseTime(value, now, false)
+		if err != nil {
+			return err
+		}
+		req.CreatedAfter = &t
+	case "until":
+		t, err := parseTime(value, now, true)
+		if err != nil {
+			return err
+		}
+		req.CreatedBefore = &t
+	case "importance":
+		c, err := parseComparison(value)
+		if err != nil {
+			return err
+		}
+		req.Importance = append(req.Importance, c)
+	case "score":
+		c, err := parseComparison(value)
+		if err != nil {
+			return err
+		}
+		if c.Op != ">=" && c.Op != ">" {
+			return fmt.Errorf("only a minimum score (>= or >) is supported")
+		}
+		req.MinScore = float32(c.Value)
+	case "curated":
+		switch strings.ToLower(value) {
+		case "true", "yes":
+			req.CuratedOnly = true
+		default:
+			return fmt.Errorf("expected curated:true")
+		}
+	default:
+		if strings.HasPrefix(key, "-") {
+			return fmt.Errorf("only topic can be negated")
+		}
+		return fmt.Errorf("unknown filter (expected one of %s; quote text holding a colon to search for it)",
+			strings.Join(Filters, ", "))
+	}
+	return nil
+}
+
+// list splits a comma-separated value, dropping empty entries
+func list(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseComparison parses a bound between 0 and 1 such as ">0.5" or "<=0.3".
+// A bare number is a minimum.
+func parseComparison(value string) (models.Comparison, error) {
+	c := models.Comparison{Op: ">="}
+	for _, op := range []string{"<=", ">=", "<", ">", "="} {
+		if rest, ok := strings.CutPrefix(value, op); ok {
+			c.Op, value = op, rest
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || v < 0 || v > 1 {
+		return c, fmt.Errorf("expected a number between 0 and 1, optionally after <, <=, =, >= or >")
+	}
+	c.Value = v
+	return c, nil
+}
+
+// parseTime parses a year (2024), month (2024-01), date (2024-01-15), RFC
+// 3339 time, or a duration back from now (30d, 2w or Go syntax such as
+// 72h). For an upper bound, a year, month or date means its end.
+func parseTime(value string, now time.Time, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	layouts := []struct {
+		layout      string
+		years, mons int
+		days        int
+	}{
+		{"2006-01-02", 0, 0, 1},
+		{"2006-01", 0, 1, 0},
+		{"2006", 1, 0, 0},
+	}
+	for _, l := range layouts {
+		if t, err := time.ParseInLocation(l.layout, value, now.Location()); err == nil {
+			if end {
+				t = t.AddDate(l.years, l.mons, l.days)
+			}
+			return t, nil
+		}
+	}
+	for suffix, days := range map[string]int{"d": 1, "w": 7} {
+		if n, ok := strings.CutSuffix(value, suffix); ok {
+			if n, err := strconv.Atoi(n); err == nil && n >= 0 {
+				return now.AddDate(0, 0, -n*days), nil
+			}
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("expected a date like 2024, 2024-01 or 2024-01-15, an RFC 3339 time, or a duration like 30d, 2w or 72h")
+}
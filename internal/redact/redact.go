@@ -0,0 +1,220 @@
+// Package redact strips secrets such as API keys, tokens and passwords from
+// text the daemon captures, before it is stored.
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// MetadataKey is the metadata key listing, comma-separated, the kinds of
+// secret redacted from a memory
+const MetadataKey = "redacted"
+
+// placeholder finds the placeholders secrets are replaced with, capturing
+// the pattern name
+var placeholder = regexp.MustCompile(`\[REDACTED:([A-Za-z0-9_-]+)\]`)
+
+// Pattern is a named regular expression (Go RE2 syntax) matching a secret.
+// If it has a capturing group, only the first group's text is replaced, so
+// "password=hunter2" can keep its key: `password=(\S+)`.
+type Pattern struct {
+	Name  string `json:"name"`
+	Regex string `json:"regex"`
+
+	re *regexp.Regexp
+}
+
+// DefaultPatterns returns the built-in patterns: common key formats, then
+// generic credential assignments. More specific patterns come first, since
+// text is redacted by one pattern after another.
+func DefaultPatterns() []Pattern {
+	return []Pattern{
+		{Name: "private-key", Regex: `-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?(?:-----END [A-Z ]*PRIVATE KEY-----|\z)`},
+		{Name: "aws-access-key", Regex: `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`},
+		{Name: "aws-secret-key", Regex: `(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40,})`},
+		{Name: "github-token", Regex: `\b(?:gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{22,255})\b`},
+		{Name: "gitlab-token", Regex: `\bglpat-[A-Za-z0-9_-]{20,}`},
+		{Name: "slack-token", Regex: `\bxox[abposr]-[A-Za-z0-9-]{10,}`},
+		{Name: "stripe-key", Regex: `\b[rs]k_(?:live|test)_[A-Za-z0-9]{16,}`},
+		{Name: "anthropic-key", Regex: `\bsk-ant-[A-Za-z0-9_-]{20,}`},
+		{Name: "openai-key", Regex: `\bsk-(?:proj-)?[A-Za-z0-9_-]{20,}`},
+		{Name: "google-api-key", Regex: `\bAIza[0-9A-Za-z_-]{35}`},
+		{Name: "jwt", Regex: `\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`},
+		{Name: "bearer-token", Regex: `(?i)\bbearer\s+([A-Za-z0-9._~+/=-]{16,})`},
+		{Name: "url-password", Regex: `\b[A-Za-z][A-Za-z0-9+.-]*://[^/\s:@]+:([^/\s@]+)@`},
+		{Name: "password", Regex: `(?i)(?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|private[_-]?key|client[_-]?secret)["']?\s*[:=]\s*["']?([^\s"'` + "`" + `,;(){}\[\]<>]{8,})`},
+	}
+}
+
+// Config configures redaction, read from redaction.json
+type Config struct {
+	// Disabled turns redaction off entirely
+	Disabled bool `json:"disabled"`
+	// Skip lists built-in patterns not to apply, by name
+	Skip []string `json:"skip"`
+	// Patterns are applied after the built-in ones
+	Patterns []Pattern `json:"patterns"`
+}
+
+// Load reads a redaction config from a JSON file
+func Load(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid redaction config %s: %w", path, err)
+	}
+	if _, err := New(cfg); err != nil {
+		return cfg, fmt.Errorf("invalid redaction config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Redactor replaces secrets in text with [REDACTED:<pattern name>]
+type Redactor struct {
+	patterns []Pattern
+}
+
+// New compiles the patterns cfg selects
+func New(cfg Config) (*Redactor, error) {
+	r := &Redactor{}
+	if cfg.Disabled {
+		return r, nil
+	}
+
+	builtin := make(map[string]bool)
+	for _, p := range DefaultPatterns() {
+		builtin[p.Name] = true
+	}
+	for _, name := range cfg.Skip {
+		if !builtin[name] {
+			return nil, fmt.Errorf("unknown built-in pattern %q in skip", name)
+		}
+	}
+
+	var patterns []Pattern
+	for _, p := range DefaultPatterns() {
+		if !slices.Contains(cfg.Skip, p.Name) {
+			patterns = append(patterns, p)
+		}
+	}
+	for _, p := range append(patterns, cfg.Patterns...) {
+		if !placeholder.MatchString("[REDACTED:" + p.Name + "]") {
+			return nil, fmt.Errorf("pattern name %q must be letters, digits, dashes or underscores", p.Name)
+		}
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex for pattern %s: %w", p.Name, err)
+		}
+		p.re = re
+		r.patterns = append(r.patterns, p)
+	}
+	return r, nil
+}
+
+// Redact replaces every secret in text with a placeholder naming the
+// pattern that matched
+func (r *Redactor) Redact(text string) string {
+	for _, p := range r.patterns {
+		text = p.replace(text)
+	}
+	return text
+}
+
+// replace replaces the matches of the pattern in text, or just their first
+// group if the pattern has one
+func (p Pattern) replace(text string) string {
+	matches := p.re.FindAllStringSubmatchIndex(text, -1)
+	if matches == nil {
+		return text
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		if start == end {
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString("[REDACTED:" + p.Name + "]")
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// RedactEvent redacts the text values of a captured event's data, such as
+// a diff or a command line, before the event is stored or extracted from.
+// Text inside lists and nested maps is redacted too.
+func (r *Redactor) RedactEvent(e *models.Event) {
+	for key, value := range e.Data {
+		e.Data[key] = r.redactValue(value)
+	}
+}
+
+// redactValue redacts the text in an event data value, recursing into
+// lists and maps. Other values are returned unchanged.
+func (r *Redactor) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return r.Redact(v)
+	case []string:
+		for i, text := range v {
+			v[i] = r.Redact(text)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.redactValue(item)
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = r.redactValue(item)
+		}
+	case map[string]string:
+		for key, text := range v {
+			v[key] = r.Redact(text)
+		}
+	}
+	return value
+}
+
+// RedactMemory redacts a memory's content, summary and source reference.
+// A memory holding redacted text, whether redacted here or already in the
+// text it was built from, lists the kinds of secret under the "redacted"
+// metadata key. It reports whether the memory holds redacted text.
+func (r *Redactor) RedactMemory(m *models.Memory) bool {
+	m.Content = r.Redact(m.Content)
+	m.Summary = r.Redact(m.Summary)
+	m.Source.Reference = r.Redact(m.Source.Reference)
+
+	var kinds []string
+	for _, text := range []string{m.Content, m.Summary, m.Source.Reference} {
+		for _, match := range placeholder.FindAllStringSubmatch(text, -1) {
+			if !slices.Contains(kinds, match[1]) {
+				kinds = append(kinds, match[1])
+			}
+		}
+	}
+	if len(kinds) == 0 {
+		return false
+	}
+	slices.Sort(kinds)
+	if m.Metadata == nil {
+		m.Metadata = make(map[string]string)
+	}
+	m.Metadata[MetadataKey] = strings.Join(kinds, ",")
+	return true
+}
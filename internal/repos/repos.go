@@ -0,0 +1,163 @@
+// Package repos decides which repositories the daemon captures from, and
+// how memories from each are stored.
+package repos
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// Rule captures from repositories matching Path, optionally overriding the
+// importance and scope of their memories
+type Rule struct {
+	Path       string             `json:"path"`
+	Importance *float64           `json:"importance,omitempty"`
+	Scope      models.MemoryScope `json:"scope,omitempty"`
+}
+
+// Config lists the repositories to capture from and those to ignore. Paths
+// are directories or glob patterns ("~/scratch/*"), matching the
+// repository itself and everything under it. When Include is empty every
+// repository not ignored is captured; Ignore always wins.
+type Config struct {
+	Include []Rule   `json:"include,omitempty"`
+	Ignore  []string `json:"ignore,omitempty"`
+}
+
+// Load reads a configuration from a JSON file
+func Load(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid repository config %s: %w", path, err)
+	}
+	for _, r := range cfg.Include {
+		if err := r.validate(); err != nil {
+			return cfg, fmt.Errorf("invalid repository config %s: %w", path, err)
+		}
+	}
+	return cfg, nil
+}
+
+// Save writes the configuration to a JSON file
+func (c Config) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func (r Rule) validate() error {
+	if r.Importance != nil && (*r.Importance < 0 || *r.Importance > 1) {
+		return fmt.Errorf("importance of %s must be between 0 and 1", r.Path)
+	}
+	switch r.Scope {
+	case "", models.MemoryScopePersonal, models.MemoryScopeProject, models.MemoryScopeTeam, models.MemoryScopeOrg:
+		return nil
+	}
+	return fmt.Errorf("unknown scope %q for %s", r.Scope, r.Path)
+}
+
+// Add captures from the repositories matching rule.Path, replacing any
+// rule for the same path and no longer ignoring it
+func (c *Config) Add(rule Rule) error {
+	if err := rule.validate(); err != nil {
+		return err
+	}
+	c.Ignore = slices.DeleteFunc(c.Ignore, func(p string) bool { return p == rule.Path })
+	for i, r := range c.Include {
+		if r.Path == rule.Path {
+			c.Include[i] = rule
+			return nil
+		}
+	}
+	c.Include = append(c.Include, rule)
+	return nil
+}
+
+// IgnorePath stops capturing from the repositories matching path,
+// dropping any rule including it
+func (c *Config) IgnorePath(path string) {
+	c.Include = slices.DeleteFunc(c.Include, func(r Rule) bool { return r.Path == path })
+	if !slices.Contains(c.Ignore, path) {
+		c.Ignore = append(c.Ignore, path)
+	}
+}
+
+// Remove forgets path in both lists, reporting whether it was listed
+func (c *Config) Remove(path string) bool {
+	n := len(c.Include) + len(c.Ignore)
+	c.Include = slices.DeleteFunc(c.Include, func(r Rule) bool { return r.Path == path })
+	c.Ignore = slices.DeleteFunc(c.Ignore, func(p string) bool { return p == path })
+	return len(c.Include)+len(c.Ignore) < n
+}
+
+// Match reports whether to capture from path, a repository or a file in
+// one, and the include rule it falls under, if any. The longest matching
+// include path wins.
+func (c Config) Match(path string) (*Rule, bool) {
+	for _, pattern := range c.Ignore {
+		if matches(pattern, path) {
+			return nil, false
+		}
+	}
+	var best *Rule
+	for i, r := range c.Include {
+		if matches(r.Path, path) && (best == nil || len(r.Path) > len(best.Path)) {
+			best = &c.Include[i]
+		}
+	}
+	return best, best != nil || len(c.Include) == 0
+}
+
+// matches reports whether path is pattern, is under it or matches it as a
+// glob, either whole or through one of its parent directories
+func matches(pattern, path string) bool {
+	pattern = filepath.Clean(expandHome(pattern))
+	path = filepath.Clean(path)
+	for p := path; ; p = filepath.Dir(p) {
+		if p == pattern {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, p); ok {
+			return true
+		}
+		if parent := filepath.Dir(p); parent == p {
+			return false
+		}
+	}
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// Normalize makes a path typed by the user absolute, keeping a leading ~
+// and glob characters as they are
+func Normalize(path string) (string, error) {
+	if strings.HasPrefix(path, "~") {
+		return filepath.Clean(path), nil
+	}
+	return filepath.Abs(path)
+}
@@ -0,0 +1,119 @@
+package rerank
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Reranker scores candidate documents against a query
+type Reranker interface {
+	// Rerank returns one relevance score (0.0-1.0) per document, in order
+	Rerank(query string, documents []string) ([]float32, error)
+}
+
+// OllamaReranker asks a local LLM to grade each candidate's relevance
+type OllamaReranker struct {
+	endpoint string
+	model    string
+	client   *http.Client
+}
+
+// NewOllamaReranker creates a new Ollama-based reranker
+func NewOllamaReranker(endpoint, model string) *OllamaReranker {
+	if endpoint == "" {
+		endpoint = "http://localhost:11434"
+	}
+	if model == "" {
+		model = "llama3.2"
+	}
+	return &OllamaReranker{
+		endpoint: endpoint,
+		model:    model,
+		client: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}
+}
+
+const rerankPrompt = `You are a search relevance grader.
+Rate how relevant each numbered document is to the query on a scale from 0.0 (irrelevant) to 1.0 (directly answers it).
+
+Query: %s
+
+Documents:
+%s
+Respond ONLY with valid JSON in this exact format (no markdown, no explanation), one score per document in order:
+{"scores": [0.9, 0.1]}`
+
+type ollamaGenerateRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Stream bool   `json:"stream"`
+	Format string `json:"format"`
+}
+
+type ollamaGenerateResponse struct {
+	Response string `json:"response"`
+}
+
+// Rerank grades all documents in a single LLM call
+func (r *OllamaReranker) Rerank(query string, documents []string) ([]float32, error) {
+	if len(documents) == 0 {
+		return nil, nil
+	}
+
+	var docs strings.Builder
+	for i, d := range documents {
+		docs.WriteString(fmt.Sprintf("%d. %s\n", i+1, strings.Join(strings.Fields(d), " ")))
+	}
+
+	body, err := json.Marshal(ollamaGenerateRequest{
+		Model:  r.model,
+		Prompt: fmt.Sprintf(rerankPrompt, query, docs.String()),
+		Stream: false,
+		Format: "json",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.Post(r.endpoint+"/api/generate", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("ollama request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ollama error: %s", string(body))
+	}
+
+	var result ollamaGenerateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	var parsed struct {
+		Scores []float32 `json:"scores"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(result.Response)), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse LLM response: %w (response: %s)", err, result.Response)
+	}
+	if len(parsed.Scores) != len(documents) {
+		return nil, fmt.Errorf("expected %d scores, got %d", len(documents), len(parsed.Scores))
+	}
+
+	for i, score := range parsed.Scores {
+		if score < 0 {
+			parsed.Scores[i] = 0
+		} else if score > 1 {
+			parsed.Scores[i] = 1
+		}
+	}
+	return parsed.Scores, nil
+}
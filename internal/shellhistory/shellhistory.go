@@ -0,0 +1,205 @@
+// Package shellhistory reads zsh, bash and fish history files and picks out
+// the commands worth remembering.
+package shellhistory
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is one command from a history file
+type Entry struct {
+	Command string
+	Time    time.Time // Zero when the history has no timestamps
+	Count   int       // How often the command appears, after Dedup
+}
+
+var (
+	zshExtended   = regexp.MustCompile(`^: *(\d+):\d+;(.*)$`)
+	bashTimestamp = regexp.MustCompile(`^#(\d{9,})$`)
+)
+
+// DefaultFiles returns the history files to try, $HISTFILE first
+func DefaultFiles() []string {
+	home, _ := os.UserHomeDir()
+	var files []string
+	if f := os.Getenv("HISTFILE"); f != "" {
+		files = append(files, f)
+	}
+	return append(files,
+		filepath.Join(home, ".zsh_history"),
+		filepath.Join(home, ".bash_history"),
+		filepath.Join(home, ".local", "share", "fish", "fish_history"),
+	)
+}
+
+// Parse reads a history file in zsh (plain or extended), bash (optionally
+// with HISTTIMEFORMAT timestamps) or fish format. Multi-line zsh commands
+// are joined back together.
+func Parse(r io.Reader) ([]Entry, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var entries []Entry
+	var pending time.Time // bash timestamp for the next command
+	var continued *Entry  // zsh command whose last line ended in a backslash
+	var fish bool
+
+	for scanner.Scan() {
+		line := unmetafy(scanner.Text())
+
+		if continued != nil {
+			continued.Command += "\n" + strings.TrimSuffix(line, `\`)
+			if !strings.HasSuffix(line, `\`) {
+				continued = nil
+			}
+			continue
+		}
+
+		if cmd, ok := strings.CutPrefix(line, "- cmd: "); ok {
+			fish = true
+			entries = append(entries, Entry{Command: unescapeFish(cmd)})
+			continue
+		}
+		if fish {
+			if when, ok := strings.CutPrefix(strings.TrimSpace(line), "when: "); ok && len(entries) > 0 {
+				entries[len(entries)-1].Time = unixTime(when)
+			}
+			continue
+		}
+
+		if m := bashTimestamp.FindStringSubmatch(line); m != nil {
+			pending = unixTime(m[1])
+			continue
+		}
+
+		entry := Entry{Command: line, Time: pending}
+		pending = time.Time{}
+		if m := zshExtended.FindStringSubmatch(line); m != nil {
+			entry = Entry{Command: m[2], Time: unixTime(m[1])}
+		}
+		if strings.TrimSpace(entry.Command) == "" {
+			continue
+		}
+		entries = append(entries, entry)
+		if strings.HasSuffix(entry.Command, `\`) {
+			last := &entries[len(entries)-1]
+			last.Command = strings.TrimSuffix(last.Command, `\`)
+			continued = last
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// unixTime parses a Unix timestamp in seconds
+func unixTime(s string) time.Time {
+	sec, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// unmetafy decodes zsh's history encoding, which stores bytes 0x83 and up
+// as 0x83 followed by the byte XOR 0x20
+func unmetafy(line string) string {
+	if strings.IndexByte(line, 0x83) < 0 {
+		return line
+	}
+	var b strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] == 0x83 && i+1 < len(line) {
+			i++
+			b.WriteByte(line[i] ^ 0x20)
+			continue
+		}
+		b.WriteByte(line[i])
+	}
+	return b.String()
+}
+
+// unescapeFish decodes the escapes fish uses in its history file
+func unescapeFish(s string) string {
+	return strings.NewReplacer(`\n`, "\n", `\\`, `\`).Replace(s)
+}
+
+// Dedup merges entries running the same command (ignoring differences in
+// whitespace), keeping the first occurrence in order with the most recent
+// time and the number of occurrences in Count
+func Dedup(entries []Entry) []Entry {
+	index := make(map[string]int)
+	var result []Entry
+	for _, e := range entries {
+		key := strings.Join(strings.Fields(e.Command), " ")
+		if i, ok := index[key]; ok {
+			result[i].Count++
+			if e.Time.After(result[i].Time) {
+				result[i].Time = e.Time
+			}
+			continue
+		}
+		index[key] = len(result)
+		e.Command = strings.TrimSpace(e.Command)
+		e.Count = 1
+		result = append(result, e)
+	}
+	return result
+}
+
+// noiseCommands are commands that are trivial whatever their arguments
+var noiseCommands = map[string]bool{
+	"ls": true, "ll": true, "la": true, "l": true, "cd": true, "pwd": true,
+	"clear": true, "exit": true, "history": true, "which": true, "whoami": true,
+	"date": true, "cat": true, "less": true, "more": true, "man": true,
+	"top": true, "htop": true, "vi": true, "vim": true, "nvim": true,
+	"nano": true, "code": true, "open": true, "fg": true, "bg": true,
+	"jobs": true, "echo": true, "source": true, ".": true, "z": true,
+	"j": true, "mkdir": true, "rm": true, "touch": true, "reset": true,
+}
+
+// everydayGit are git subcommands that are trivial without further arguments
+var everydayGit = map[string]bool{
+	"status": true, "diff": true, "log": true, "pull": true, "push": true,
+	"fetch": true, "add": true, "show": true, "branch": true, "stash": true,
+	"checkout": true, "switch": true, "commit": true,
+}
+
+// sensitivePrefixes start commands that may carry credentials
+var sensitivePrefixes = []string{
+	"export ", "set ", "unset ",
+	"curl ", "wget ",
+	"mysql ", "psql ", "redis-cli ",
+	"ssh ", "scp ", "sshpass ",
+	"echo $", "cat ~/.",
+}
+
+var secretPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|api[_-]?key|authorization)\s*[=:]|--password\b|\bBearer\s`)
+
+// Trivial reports whether a command is not worth remembering: very short,
+// a single word, everyday navigation and inspection, a bare everyday git
+// command, or a command that may contain credentials
+func Trivial(cmd string) bool {
+	cmd = strings.TrimSpace(cmd)
+	fields := strings.Fields(cmd)
+	if len(cmd) < 4 || len(fields) < 2 {
+		return true
+	}
+	if noiseCommands[fields[0]] {
+		return true
+	}
+	if fields[0] == "git" && len(fields) == 2 && everydayGit[fields[1]] {
+		return true
+	}
+	for _, prefix := range sensitivePrefixes {
+		if strings.HasPrefix(cmd, prefix) {
+			return true
+		}
+	}
+	return secretPattern.MatchString(cmd)
+}
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"log"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// maxAccessLogEntries caps the access log; the oldest entries are pruned
+const maxAccessLogEntries = 10000
+
+// accessLogBuffer is how many entries can be queued before new ones are
+// dropped, so a slow disk never blocks recall
+const accessLogBuffer = 256
+
+// accessLogBatch is the most entries written in one transaction
+const accessLogBatch = 100
+
+// AccessLogEntry records one memory being returned by a recall
+type AccessLogEntry struct {
+	MemoryID   string    `json:"memoryId"`
+	Query      string    `json:"query"`
+	Score      float32   `json:"score"`
+	AccessedAt time.Time `json:"accessedAt"`
+}
+
+// SetAccessLog enables or disables recording recalls in the access log.
+// It is enabled by default, except in read-only stores where it can't be.
+func (s *Store) SetAccessLog(enabled bool) {
+	s.accessLogDisabled = !enabled || s.readOnly
+}
+
+// logAccess queues an access log entry for each recalled memory. Entries
+// are written in batches by a background goroutine and dropped if the
+// queue is full or the store is closed.
+func (s *Store) logAccess(query string, memories []models.Memory) {
+	if s.accessLogDisabled {
+		return
+	}
+
+	s.closeMu.Lock()
+	defer s.closeMu.Unlock()
+	if s.closed {
+		return
+	}
+
+	now := time.Now()
+	for _, m := range memories {
+		select {
+		case s.accessLog <- AccessLogEntry{MemoryID: m.ID, Query: query, Score: m.Score, AccessedAt: now}:
+		default:
+			return
+		}
+	}
+}
+
+// runAccessLog writes queued entries until the queue is closed
+func (s *Store) runAccessLog() {
+	defer close(s.accessLogDone)
+
+	for entry := range s.accessLog {
+		batch := []AccessLogEntry{entry}
+	drain:
+		for len(batch) < accessLogBatch {
+			select {
+			case e, ok := <-s.accessLog:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, e)
+			default:
+				break drain
+			}
+		}
+
+		if err := s.writeAccessLog(batch); err != nil {
+			log.Printf("Failed to write access log: %v", err)
+		}
+	}
+}
+
+// writeAccessLog inserts a batch of entries and prunes old ones
+func (s *Store) writeAccessLog(batch []AccessLogEntry) error {
+	tx, err := s.beginStats()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, e := range batch {
+		if _, err := tx.Exec("INSERT INTO access_log (memory_id, query, score, accessed_at) VALUES (?, ?, ?, ?)",
+			e.MemoryID, e.Query, e.Score, e.AccessedAt); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM access_log WHERE id <= (
+		SELECT id FROM access_log ORDER BY id DESC LIMIT 1 OFFSET ?)`, maxAccessLogEntries); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetAccessLog returns the recalls of a memory, most recent first
+func (s *Store) GetAccessLog(memoryID string) ([]AccessLogEntry, error) {
+	rows, err := s.db.Query(`SELECT memory_id, query, score, accessed_at FROM access_log
+		WHERE memory_id = ? ORDER BY id DESC`, memoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []AccessLogEntry
+	for rows.Next() {
+		var e AccessLogEntry
+		if err := rows.Scan(&e.MemoryID, &e.Query, &e.Score, &e.AccessedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,133 @@
+package store
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// lastSeenSetting prefixes the settings key holding when a reader last
+// looked at activity
+const lastSeenSetting = "last_seen:"
+
+// DefaultActivityWindow is how far back activity goes for a reader that
+// hasn't looked at it before
+const DefaultActivityWindow = 24 * time.Hour
+
+// ProjectActivity is the memories captured for one project in an activity
+// window, newest first. Memories not attached to a project are grouped
+// under an empty ProjectID.
+type ProjectActivity struct {
+	ProjectID string          `json:"projectId"`
+	Name      string          `json:"name"`
+	Path      string          `json:"path,omitempty"`
+	ByType    map[string]int  `json:"byType"`
+	Memories  []models.Memory `json:"memories"`
+}
+
+// Activity summarizes the memories captured since a point in time
+type Activity struct {
+	Since    time.Time         `json:"since"`
+	Total    int               `json:"total"`
+	ByType   map[string]int    `json:"byType"`
+	Projects []ProjectActivity `json:"projects"`
+}
+
+// ActivitySince returns the memories created at or after since, of the given
+// types if any, grouped by project, projects with the most activity first
+func (s *Store) ActivitySince(since time.Time, types []models.MemoryType) (*Activity, error) {
+	projects, err := s.projectNames()
+	if err != nil {
+		return nil, err
+	}
+
+	clause, args := filterClause(models.RecallRequest{Types: types})
+	// Compared to the millisecond, so a memory captured just before the
+	// last look isn't shown again
+	args = append([]interface{}{since.UTC().Format("2006-01-02 15:04:05.000")}, args...)
+	rows, err := s.db.Query("SELECT "+memoryColumns+` FROM memories
+		WHERE julianday(created_at) >= julianday(?)`+clause+` ORDER BY created_at DESC, id DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activity := &Activity{Since: since, ByType: make(map[string]int), Projects: []ProjectActivity{}}
+	byID := make(map[string]int)
+	for rows.Next() {
+		m, err := scanMemory(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		// Memories of a project that no longer exists count as unattached
+		var project ProjectActivity
+		if m.ProjectID != nil {
+			project = projects[*m.ProjectID]
+		}
+		i, ok := byID[project.ProjectID]
+		if !ok {
+			i = len(activity.Projects)
+			byID[project.ProjectID] = i
+			project.ByType = make(map[string]int)
+			activity.Projects = append(activity.Projects, project)
+		}
+		p := &activity.Projects[i]
+		p.ByType[string(m.Type)]++
+		p.Memories = append(p.Memories, *m)
+		activity.ByType[string(m.Type)]++
+		activity.Total++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(activity.Projects, func(i, j int) bool {
+		return len(activity.Projects[i].Memories) > len(activity.Projects[j].Memories)
+	})
+	return activity, nil
+}
+
+// projectNames returns the tracked projects by ID, without memories
+func (s *Store) projectNames() (map[string]ProjectActivity, error) {
+	rows, err := s.db.Query("SELECT id, name, path FROM projects")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	projects := make(map[string]ProjectActivity)
+	for rows.Next() {
+		var p ProjectActivity
+		if err := rows.Scan(&p.ProjectID, &p.Name, &p.Path); err != nil {
+			return nil, err
+		}
+		projects[p.ProjectID] = p
+	}
+	return projects, rows.Err()
+}
+
+// LastSeen returns when reader last looked at activity, or the zero time if
+// it never has. Each reader, such as the CLI or an MCP client, keeps its
+// own mark so one catching up doesn't hide activity from another.
+func (s *Store) LastSeen(reader string) (time.Time, error) {
+	var value string
+	err := s.db.QueryRow("SELECT value FROM settings WHERE key = ?", lastSeenSetting+reader).Scan(&value)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+// MarkSeen records that reader looked at activity up to t
+func (s *Store) MarkSeen(reader string, t time.Time) error {
+	_, err := s.exec(`INSERT INTO settings (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+		lastSeenSetting+reader, t.UTC().Format(time.RFC3339Nano))
+	return err
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"database/sql"
+
+	"github.com/contextpilot-dev/memorypilot/internal/contenttype"
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// backfill fills in columns that memories stored before the migration
+// adding them lack: the keyword index, built with the store's tokenizer,
+// and the content type. New memories get both when inserted, so this only
+// writes after such a migration.
+func (s *Store) backfill() error {
+	var missing int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM memories WHERE keywords IS NULL OR content_type IS NULL").Scan(&missing); err != nil {
+		return err
+	}
+	if missing == 0 {
+		return nil
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	tok, err := s.tokenizerFor(tx)
+	if err != nil {
+		return err
+	}
+	if err := indexKeywords(tx.Tx, tok, "WHERE keywords IS NULL"); err != nil {
+		return err
+	}
+	if err := detectContentTypes(tx.Tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// detectContentTypes sets the content type of memories that have none
+func detectContentTypes(tx *sql.Tx) error {
+	rows, err := tx.Query("SELECT id, content FROM memories WHERE content_type IS NULL")
+	if err != nil {
+		return err
+	}
+
+	detected := make(map[string]models.ContentType)
+	for rows.Next() {
+		var id, content string
+		if err := rows.Scan(&id, &content); err != nil {
+			rows.Close()
+			return err
+		}
+		detected[id] = contenttype.Detect(content)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, t := range detected {
+		if _, err := tx.Exec("UPDATE memories SET content_type = ? WHERE id = ?", t, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultBusyRetries is how many times RetryBusy retries an operation that
+// found the database locked
+const DefaultBusyRetries = 5
+
+// busyBackoff is the wait before the first retry; it doubles after each,
+// up to maxBusyBackoff
+const (
+	busyBackoff    = 50 * time.Millisecond
+	maxBusyBackoff = time.Second
+)
+
+// ErrBusy is returned by RetryBusy when the database stayed locked by
+// another connection, typically the daemon writing, through every retry
+var ErrBusy = errors.New("database is busy")
+
+// RetryBusy runs op, retrying with exponential backoff while it fails
+// because another connection holds a lock SQLite's busy timeout didn't
+// outlast (such as the write lock during a long import).
+// It stops after DefaultBusyRetries retries, or earlier if ctx is done or
+// its deadline would pass before the next attempt, and then returns an
+// error wrapping ErrBusy. Other errors are returned as they are.
+func RetryBusy(ctx context.Context, op func() error) error {
+	backoff := busyBackoff
+	for retries := 0; ; retries++ {
+		err := op()
+		if err == nil || !isBusy(err) {
+			return err
+		}
+		if retries == DefaultBusyRetries {
+			return fmt.Errorf("%w after %d retries, another process may be writing; try again shortly: %v", ErrBusy, retries, err)
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+			return fmt.Errorf("%w after %d retries, out of time: %v", ErrBusy, retries, err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w after %d retries: %v", ErrBusy, retries, ctx.Err())
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, maxBusyBackoff)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !cgo
+
+package store
+
+// isBusy reports false: without cgo the SQLite driver is a stub that can't
+// open databases, so no error means the database is locked
+func isBusy(err error) bool {
+	return false
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// lockDatabase takes the write lock of the database at path from another
+// connection and returns the function releasing it
+func lockDatabase(t *testing.T, path string) (release func()) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", path+"?_txlock=immediate")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		db.Close()
+		t.Fatalf("begin: %v", err)
+	}
+	return func() {
+		tx.Rollback()
+		db.Close()
+	}
+}
+
+// newBusyTestStore opens a store at path on a single connection that gives
+// up on a held lock after 20ms rather than SQLite's 5s busy timeout
+func newBusyTestStore(t *testing.T, path string) *Store {
+	t.Helper()
+	s, err := New(path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	s.db.SetMaxOpenConns(1)
+	if _, err := s.db.Exec("PRAGMA busy_timeout = 20"); err != nil {
+		t.Fatalf("busy_timeout: %v", err)
+	}
+	return s
+}
+
+func TestRetryBusyAcquiresReleasedLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memories.db")
+	s := newBusyTestStore(t, path)
+
+	release := lockDatabase(t, path)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		release()
+	}()
+
+	m := newTestMemory("retried through a held lock")
+	attempts := 0
+	err := RetryBusy(context.Background(), func() error {
+		attempts++
+		return s.CreateMemory(m)
+	})
+	if err != nil {
+		t.Fatalf("RetryBusy: %v", err)
+	}
+	if attempts < 2 {
+		t.Errorf("CreateMemory succeeded on attempt %d, want it to have found the database locked first", attempts)
+	}
+	if got, err := s.GetMemory(m.ID); err != nil || got == nil {
+		t.Errorf("GetMemory after the retry = %v, %v; want the memory", got, err)
+	}
+}
+
+func TestRetryBusyGivesUp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memories.db")
+	s := newBusyTestStore(t, path)
+
+	release := lockDatabase(t, path)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	attempts := 0
+	err := RetryBusy(ctx, func() error {
+		attempts++
+		return s.CreateMemory(newTestMemory("never written"))
+	})
+	if !errors.Is(err, ErrBusy) {
+		t.Fatalf("RetryBusy = %v, want ErrBusy", err)
+	}
+	if attempts < 2 {
+		t.Errorf("gave up after %d attempts, want retries first", attempts)
+	}
+
+	// Errors other than a held lock aren't retried
+	attempts = 0
+	other := errors.New("no such table")
+	if err := RetryBusy(context.Background(), func() error { attempts++; return other }); err != other || attempts != 1 {
+		t.Errorf("RetryBusy = %v after %d attempts, want %v after 1", err, attempts, other)
+	}
+}
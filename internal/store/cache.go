@@ -0,0 +1,194 @@
+package store
+
+import (
+	"container/list"
+	"encoding/binary"
+	"encoding/json"
+	"hash/fnv"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/internal/metrics"
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// Recall cache defaults
+const (
+	DefaultRecallCacheSize = 256
+	DefaultRecallCacheTTL  = 10 * time.Second
+)
+
+// recallCache keeps recent recall results so an identical query within a
+// short window skips the search. Any write through the store clears it;
+// writes by other processes (such as the daemon) show up once entries
+// expire. A nil cache is disabled.
+type recallCache struct {
+	mu         sync.Mutex
+	size       int
+	ttl        time.Duration
+	entries    map[string]*list.Element
+	order      *list.List // Front is most recently used
+	generation uint64     // Bumped by every invalidation
+}
+
+type recallCacheEntry struct {
+	key      string
+	memories []models.Memory
+	expires  time.Time
+}
+
+func newRecallCache(size int, ttl time.Duration) *recallCache {
+	return &recallCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// SetRecallCache sets how many recall results are cached and for how long.
+// A size of 0 or less disables the cache; a ttl of 0 or less restores the
+// default.
+func (s *Store) SetRecallCache(size int, ttl time.Duration) {
+	if size <= 0 {
+		s.cache = nil
+		return
+	}
+	if ttl <= 0 {
+		ttl = DefaultRecallCacheTTL
+	}
+	s.cache = newRecallCache(size, ttl)
+}
+
+// get returns a copy of the cached results for key, along with the
+// generation to pass to put after a miss
+func (c *recallCache) get(key string) ([]models.Memory, uint64, bool) {
+	if c == nil {
+		return nil, 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		metrics.RecallCacheLookups.Inc("miss")
+		return nil, c.generation, false
+	}
+	entry := el.Value.(*recallCacheEntry)
+	if time.Now().After(entry.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		metrics.RecallCacheLookups.Inc("miss")
+		return nil, c.generation, false
+	}
+	c.order.MoveToFront(el)
+	metrics.RecallCacheLookups.Inc("hit")
+	return slices.Clone(entry.memories), c.generation, true
+}
+
+// put stores results computed when the cache was at generation. Results
+// are dropped if a write invalidated the cache in the meantime, since they
+// may predate it.
+func (c *recallCache) put(key string, generation uint64, memories []models.Memory) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	entry := &recallCacheEntry{key: key, memories: slices.Clone(memories), expires: time.Now().Add(c.ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*recallCacheEntry).key)
+	}
+}
+
+// invalidate drops every cached result
+func (c *recallCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	if len(c.entries) > 0 {
+		c.entries = make(map[string]*list.Element)
+		c.order.Init()
+	}
+}
+
+// recallCacheKey identifies a search by method, normalized request and
+// query embedding. Queries differing only in case (outside exact mode) or
+// whitespace, and filters listed in a different order, share a key.
+func recallCacheKey(method string, req models.RecallRequest, queryEmbedding []float32) string {
+	req.Query = strings.Join(strings.Fields(req.Query), " ")
+	if !req.Exact {
+		req.Query = strings.ToLower(req.Query)
+	}
+	req.Scope = sortedCopy(req.Scope)
+	req.Types = sortedCopy(req.Types)
+	req.SourceTypes = sortedCopy(req.SourceTypes)
+	req.ContentTypes = sortedCopy(req.ContentTypes)
+	req.Topics = sortedCopy(lowerAll(req.Topics))
+	req.ExcludeTopics = sortedCopy(lowerAll(req.ExcludeTopics))
+	if req.Limit <= 0 {
+		req.Limit = 5
+	}
+
+	data, _ := json.Marshal(req) // Map keys are marshaled in sorted order
+	key := method + "\x00" + string(data)
+	if queryEmbedding != nil {
+		h := fnv.New64a()
+		var buf [4]byte
+		for _, v := range queryEmbedding {
+			binary.LittleEndian.PutUint32(buf[:], math.Float32bits(v))
+			h.Write(buf[:])
+		}
+		key += "\x00" + string(binary.LittleEndian.AppendUint64(nil, h.Sum64()))
+	}
+	return key
+}
+
+func sortedCopy[T ~string](values []T) []T {
+	if len(values) == 0 {
+		return nil
+	}
+	values = slices.Clone(values)
+	slices.Sort(values)
+	return values
+}
+
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, v := range values {
+		lowered[i] = strings.ToLower(strings.TrimSpace(v))
+	}
+	return lowered
+}
+
+// cachedRecall returns cached results for key, touching each memory and
+// logging the access as a fresh search would
+func (s *Store) cachedRecall(key, query string) ([]models.Memory, uint64, bool) {
+	memories, generation, ok := s.cache.get(key)
+	if !ok {
+		return nil, generation, false
+	}
+	for _, m := range memories {
+		s.recordAccess(m.ID)
+	}
+	s.logAccess(query, memories)
+	return memories, generation, true
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ClearMemories deletes every memory, along with its history, access log
+// entries and embedding, in one transaction and returns how many were
+// removed. A tombstone is kept for each, for incremental exports. With
+// trash set, each memory is first copied to the memory_trash table (as
+// JSON, with its embedding) so it can still be recovered by hand.
+func (s *Store) ClearMemories(trash bool) (int, error) {
+	tx, err := s.begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if trash {
+		if err := trashMemories(tx); err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err := tx.Exec(`INSERT OR REPLACE INTO memory_tombstones (id, deleted_at)
+		SELECT id, ? FROM memories`, time.Now().UTC()); err != nil {
+		return 0, err
+	}
+
+	for _, table := range []string{"memory_history", "access_log", "inferred_links"} {
+		if _, err := tx.Exec("DELETE FROM " + table); err != nil {
+			return 0, err
+		}
+	}
+	result, err := tx.Exec("DELETE FROM memories")
+	if err != nil {
+		return 0, err
+	}
+	n, _ := result.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
+
+// trashMemories copies every memory into the trash table
+func trashMemories(tx *writeTx) error {
+	rows, err := tx.Query("SELECT " + memoryColumns + ", embedding FROM memories")
+	if err != nil {
+		return err
+	}
+
+	type trashed struct {
+		id        string
+		data      []byte
+		embedding []byte
+	}
+	var items []trashed
+	for rows.Next() {
+		var embedding []byte
+		m, err := scanMemory(scanWithEmbedding{rows, &embedding})
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		data, _ := json.Marshal(m)
+		items = append(items, trashed{m.ID, data, embedding})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, item := range items {
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO memory_trash (id, data, embedding, deleted_at)
+			VALUES (?, ?, ?, ?)`, item.id, string(item.data), item.embedding, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanWithEmbedding scans a memoryColumns row followed by the embedding
+// column, so scanMemory can be reused
+type scanWithEmbedding struct {
+	row       rowScanner
+	embedding *[]byte
+}
+
+func (s scanWithEmbedding) Scan(dest ...interface{}) error {
+	return s.row.Scan(append(dest, s.embedding)...)
+}
+
+// DeleteMemory deletes one memory with its history, access log entries and
+// inferred links, keeping a tombstone for incremental exports. It returns
+// false if the memory doesn't exist.
+func (s *Store) DeleteMemory(id string) (bool, error) {
+	tx, err := s.begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM memories WHERE id = ?", id)
+	if err != nil {
+		return false, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return false, nil
+	}
+
+	for _, table := range []string{"memory_history", "access_log", "inferred_links"} {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE memory_id = ?", id); err != nil {
+			return false, err
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM inferred_links WHERE related_id = ?", id); err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec("INSERT OR REPLACE INTO memory_tombstones (id, deleted_at) VALUES (?, ?)",
+		id, time.Now().UTC()); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// DeleteExpired deletes the memories whose expiry has passed, with their
+// history and access log entries, keeping tombstones for incremental
+// exports. It returns how many were removed.
+func (s *Store) DeleteExpired() (int, error) {
+	tx, err := s.begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	n, err := deleteMemories(tx, "SELECT id FROM memories WHERE expires_at IS NOT NULL AND datetime(expires_at) <= datetime('now')")
+	if err != nil {
+		return 0, err
+	}
+	return n, tx.Commit()
+}
+
+// deleteMemories deletes the memories whose IDs the query selects, with
+// their history, access log entries and inferred links, keeping a tombstone
+// for each. The query must select the same memories every time it runs
+// within the transaction. It returns how many were removed.
+func deleteMemories(tx *writeTx, ids string, args ...interface{}) (int, error) {
+	if _, err := tx.Exec(`INSERT OR REPLACE INTO memory_tombstones (id, deleted_at)
+		SELECT id, ? FROM (`+ids+`)`, append([]interface{}{time.Now().UTC()}, args...)...); err != nil {
+		return 0, err
+	}
+	for _, table := range []string{"memory_history", "access_log", "inferred_links"} {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE memory_id IN ("+ids+")", args...); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM inferred_links WHERE related_id IN ("+ids+")", args...); err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec("DELETE FROM memories WHERE id IN ("+ids+")", args...)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
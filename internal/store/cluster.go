@@ -0,0 +1,229 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// Cluster is a group of memories with similar embeddings
+type Cluster struct {
+	Label           string          `json:"label"`
+	Topics          []string        `json:"topics"`
+	Size            int             `json:"size"`
+	Representatives []models.Memory `json:"representatives"`
+}
+
+// ClusterResult is the output of ClusterMemories
+type ClusterResult struct {
+	Clusters []Cluster `json:"clusters"`
+	Excluded int       `json:"excluded"` // Memories skipped for lacking an embedding
+}
+
+// maxClusterIterations bounds k-means refinement
+const maxClusterIterations = 50
+
+// ClusterMemories groups embedded memories into k clusters using k-means
+// over cosine similarity. Each cluster is labelled by its most common
+// topics and lists up to perCluster memories closest to its centroid.
+func (s *Store) ClusterMemories(k, perCluster int) (*ClusterResult, error) {
+	result := &ClusterResult{}
+
+	row := s.db.QueryRow("SELECT COUNT(*) FROM memories WHERE embedding IS NULL")
+	if err := row.Scan(&result.Excluded); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query("SELECT " + memoryColumns + ", embedding FROM memories WHERE embedding IS NOT NULL ORDER BY created_at ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memories []models.Memory
+	var vectors [][]float32
+	for rows.Next() {
+		var blob []byte
+		m, err := scanMemory(rowWithExtra{rows, &blob})
+		if err != nil {
+			return nil, err
+		}
+		if len(blob) == 0 {
+			result.Excluded++
+			continue
+		}
+		memories = append(memories, *m)
+		vectors = append(vectors, decodeEmbedding(blob))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(memories) == 0 {
+		return result, nil
+	}
+	if k > len(memories) {
+		k = len(memories)
+	}
+	if k < 1 {
+		k = 1
+	}
+
+	assignments, centroids := kMeans(vectors, k)
+
+	for c := range centroids {
+		var members []int
+		for i, a := range assignments {
+			if a == c {
+				members = append(members, i)
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+
+		// Closest to the centroid first
+		sort.SliceStable(members, func(a, b int) bool {
+			return cosineSimilarity(vectors[members[a]], centroids[c]) > cosineSimilarity(vectors[members[b]], centroids[c])
+		})
+
+		cluster := Cluster{Size: len(members)}
+		var clusterMemories []models.Memory
+		for _, i := range members {
+			clusterMemories = append(clusterMemories, memories[i])
+		}
+		cluster.Topics = topTopics(clusterMemories, 3)
+		cluster.Label = clusterLabel(cluster.Topics, clusterMemories[0])
+		for i := 0; i < len(clusterMemories) && i < perCluster; i++ {
+			cluster.Representatives = append(cluster.Representatives, clusterMemories[i])
+		}
+
+		result.Clusters = append(result.Clusters, cluster)
+	}
+
+	// Largest clusters first
+	sort.SliceStable(result.Clusters, func(a, b int) bool {
+		return result.Clusters[a].Size > result.Clusters[b].Size
+	})
+
+	return result, nil
+}
+
+// rowWithExtra scans memoryColumns followed by one extra column
+type rowWithExtra struct {
+	row   rowScanner
+	extra interface{}
+}
+
+func (r rowWithExtra) Scan(dest ...interface{}) error {
+	return r.row.Scan(append(dest, r.extra)...)
+}
+
+// kMeans clusters vectors by cosine similarity. Centroids are seeded with
+// farthest-point initialization from the first vector, so results are
+// deterministic for a given input order.
+func kMeans(vectors [][]float32, k int) ([]int, [][]float32) {
+	centroids := [][]float32{vectors[0]}
+	for len(centroids) < k {
+		farthest, lowest := 0, float32(2)
+		for i, v := range vectors {
+			best := float32(-2)
+			for _, c := range centroids {
+				if sim := cosineSimilarity(v, c); sim > best {
+					best = sim
+				}
+			}
+			if best < lowest {
+				farthest, lowest = i, best
+			}
+		}
+		centroids = append(centroids, vectors[farthest])
+	}
+
+	assignments := make([]int, len(vectors))
+	for iter := 0; iter < maxClusterIterations; iter++ {
+		changed := false
+		for i, v := range vectors {
+			best, bestSim := 0, float32(-2)
+			for c, centroid := range centroids {
+				if sim := cosineSimilarity(v, centroid); sim > bestSim {
+					best, bestSim = c, sim
+				}
+			}
+			if assignments[i] != best {
+				assignments[i] = best
+				changed = true
+			}
+		}
+		if !changed && iter > 0 {
+			break
+		}
+
+		// Recompute centroids as the mean of their members
+		for c := range centroids {
+			var sum []float64
+			count := 0
+			for i, a := range assignments {
+				if a != c || len(vectors[i]) != len(centroids[c]) {
+					continue
+				}
+				if sum == nil {
+					sum = make([]float64, len(vectors[i]))
+				}
+				for d, x := range vectors[i] {
+					sum[d] += float64(x)
+				}
+				count++
+			}
+			if count == 0 {
+				continue
+			}
+			centroid := make([]float32, len(sum))
+			for d := range sum {
+				centroid[d] = float32(sum[d] / float64(count))
+			}
+			centroids[c] = centroid
+		}
+	}
+
+	return assignments, centroids
+}
+
+// topTopics returns up to n topics shared by the most memories
+func topTopics(memories []models.Memory, n int) []string {
+	counts := make(map[string]int)
+	for _, m := range memories {
+		for _, t := range m.Topics {
+			counts[t]++
+		}
+	}
+
+	topics := make([]string, 0, len(counts))
+	for t := range counts {
+		topics = append(topics, t)
+	}
+	sort.Slice(topics, func(a, b int) bool {
+		if counts[topics[a]] != counts[topics[b]] {
+			return counts[topics[a]] > counts[topics[b]]
+		}
+		return topics[a] < topics[b]
+	})
+
+	if len(topics) > n {
+		topics = topics[:n]
+	}
+	return topics
+}
+
+// clusterLabel joins the top topics, falling back to the summary of the
+// most central memory when the cluster has no topics
+func clusterLabel(topics []string, central models.Memory) string {
+	if len(topics) == 0 {
+		return central.Summary
+	}
+	label := topics[0]
+	for _, t := range topics[1:] {
+		label += ", " + t
+	}
+	return label
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"database/sql"
+	"sync"
+)
+
+// Concurrency contract
+//
+// A Store is safe for concurrent use by multiple goroutines once it has
+// been configured: the Set* methods must be called before the store is
+// shared. Reads run concurrently on the connection pool (SQLite in WAL
+// mode lets readers proceed alongside a writer). SQLite allows a single
+// writer at a time, so every write statement and write transaction is
+// serialized through the store's write lock with exec and begin. Other
+// processes opening the same database are coordinated by SQLite's own
+// locking and the busy timeout.
+//
+// Every write through exec and begin also invalidates the recall cache,
+// except those that only record access statistics (execStats and
+// beginStats), so recalling doesn't evict its own results.
+
+// writeTx is a write transaction that holds the store's write lock until
+// it is committed or rolled back
+type writeTx struct {
+	*sql.Tx
+	unlock    func()
+	once      sync.Once
+	committed func() // Called after a successful commit, if set
+}
+
+// Commit commits the transaction and releases the write lock
+func (tx *writeTx) Commit() error {
+	defer tx.release()
+	if err := tx.Tx.Commit(); err != nil {
+		return err
+	}
+	if tx.committed != nil {
+		tx.committed()
+	}
+	return nil
+}
+
+// Rollback aborts the transaction, if still open, and releases the write
+// lock. It is safe to defer after Commit.
+func (tx *writeTx) Rollback() error {
+	defer tx.release()
+	return tx.Tx.Rollback()
+}
+
+func (tx *writeTx) release() {
+	tx.once.Do(tx.unlock)
+}
+
+// begin starts a write transaction, waiting for any other write to finish
+func (s *Store) begin() (*writeTx, error) {
+	tx, err := s.beginStats()
+	if err != nil {
+		return nil, err
+	}
+	tx.committed = s.cache.invalidate
+	return tx, nil
+}
+
+// beginStats starts a write transaction that only records access
+// statistics and leaves the recall cache alone
+func (s *Store) beginStats() (*writeTx, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+	s.writeMu.Lock()
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.writeMu.Unlock()
+		return nil, err
+	}
+	return &writeTx{Tx: tx, unlock: s.writeMu.Unlock}, nil
+}
+
+// exec runs a single write statement under the write lock
+func (s *Store) exec(query string, args ...interface{}) (sql.Result, error) {
+	result, err := s.execStats(query, args...)
+	s.cache.invalidate()
+	return result, err
+}
+
+// execStats runs a write statement that only records access statistics and
+// leaves the recall cache alone
+func (s *Store) execStats(query string, args ...interface{}) (sql.Result, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.db.Exec(query, args...)
+}
@@ -0,0 +1,56 @@
+package store
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+func TestConcurrentCreateAndRecall(t *testing.T) {
+	s := newTestStore(t)
+
+	const writers, readers, perWriter = 4, 4, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*perWriter+readers*perWriter)
+
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				m := newTestMemory(fmt.Sprintf("deploy note %d from writer %d", i, w))
+				if err := s.CreateMemory(m); err != nil {
+					errs <- fmt.Errorf("CreateMemory: %w", err)
+				}
+			}
+		}(w)
+	}
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				// Recalls also write access statistics and the access log
+				if _, err := s.Recall(models.RecallRequest{Query: "deploy", Limit: 10}); err != nil {
+					errs <- fmt.Errorf("Recall: %w", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	stats, err := s.GetStats()
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.TotalMemories != writers*perWriter {
+		t.Errorf("store holds %d memories, want %d", stats.TotalMemories, writers*perWriter)
+	}
+}
@@ -0,0 +1,74 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// confidenceSetting is the settings key holding the confidence weight
+const confidenceSetting = "confidence_weight"
+
+// DefaultConfidenceWeight is the confidence weight of stores that haven't
+// set one: a memory captured with 0.7 confidence loses 0.03
+const DefaultConfidenceWeight = 0.1
+
+// loadConfidenceWeight reads the confidence weight stored in the database,
+// so every process opening the store ranks the same way
+func (s *Store) loadConfidenceWeight() error {
+	weight, err := s.storedConfidenceWeight()
+	if err != nil {
+		return err
+	}
+	s.confidenceWeight = weight
+	return nil
+}
+
+// storedConfidenceWeight returns the confidence weight set in the database,
+// or DefaultConfidenceWeight
+func (s *Store) storedConfidenceWeight() (float64, error) {
+	var value string
+	err := s.db.QueryRow("SELECT value FROM settings WHERE key = ?", confidenceSetting).Scan(&value)
+	if err == sql.ErrNoRows {
+		return DefaultConfidenceWeight, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	weight, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid confidence weight %q: %w", value, err)
+	}
+	return weight, nil
+}
+
+// ConfidenceWeight returns how much a memory's confidence weighs in its
+// recall score
+func (s *Store) ConfidenceWeight() float64 {
+	return s.confidenceWeight
+}
+
+// SetConfidenceWeight sets how much a memory's confidence weighs in its
+// recall score, between 0 (ignored) and 1. Recall subtracts (1 - confidence)
+// × weight, so fully confident memories keep their score and less confident
+// ones rank below equally relevant memories. Processes that already have the
+// store open keep their weight until they reopen it.
+func (s *Store) SetConfidenceWeight(weight float64) error {
+	if weight < 0 || weight > 1 {
+		return fmt.Errorf("confidence weight must be between 0 and 1")
+	}
+	if _, err := s.exec(`INSERT INTO settings (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+		confidenceSetting, strconv.FormatFloat(weight, 'g', -1, 64)); err != nil {
+		return err
+	}
+	s.confidenceWeight = weight
+	return nil
+}
+
+// confidenceScore is the recall score adjustment for a memory's confidence
+func (s *Store) confidenceScore(m *models.Memory) float32 {
+	return float32((m.Confidence - 1) * s.confidenceWeight)
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// curatedBoost is added to the recall score of curated memories, so vetted
+// knowledge ranks above raw capture of similar relevance
+const curatedBoost = 0.1
+
+// CuratedImportance is the importance a memory is raised to when curated,
+// unless it is already higher or another importance is given
+const CuratedImportance = 0.9
+
+// curatedScore is the recall score adjustment for a memory's curation
+func curatedScore(m *models.Memory) float32 {
+	if m.CuratedAt == nil {
+		return 0
+	}
+	return curatedBoost
+}
+
+// CurateMemory marks a memory as curated by curator, recording when, and
+// sets its importance: to importance if it is above 0, otherwise raising it
+// to at least CuratedImportance. A non-empty summary replaces the memory's
+// summary, keeping the previous one in its history. Curated memories rank
+// above raw capture and don't decay. Curating a curated memory again
+// records the new curator and time. It returns the updated memory.
+func (s *Store) CurateMemory(id, summary, curator string, importance float64) (*models.Memory, error) {
+	if importance < 0 || importance > 1 {
+		return nil, fmt.Errorf("importance must be between 0 and 1")
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var content string
+	err = tx.QueryRow("SELECT content FROM memories WHERE id = ?", id).Scan(&content)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("memory %s not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if summary != "" {
+		if err := s.updateContent(tx.Tx, id, content, summary, curator); err != nil {
+			return nil, err
+		}
+	}
+
+	newImportance := "MAX(importance, ?)"
+	if importance > 0 {
+		newImportance = "?"
+	} else {
+		importance = CuratedImportance
+	}
+	// Bump updated_at too, so exports and newest-wins imports carry the curation
+	now := time.Now()
+	if _, err := tx.Exec(`UPDATE memories SET importance = `+newImportance+`, curated_at = ?, curated_by = ?,
+		updated_at = ? WHERE id = ?`, importance, now, curator, now, id); err != nil {
+		return nil, err
+	}
+
+	m, err := scanMemory(tx.QueryRow("SELECT "+memoryColumns+" FROM memories WHERE id = ?", id))
+	if err != nil {
+		return nil, err
+	}
+	return m, tx.Commit()
+}
@@ -0,0 +1,120 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// diversityCandidates is how many times the limit in candidates a diverse
+// recall chooses from
+const diversityCandidates = 3
+
+// candidateLimit returns the number of results to fetch for req before
+// diversify narrows them to its limit
+func candidateLimit(req models.RecallRequest) int {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 5
+	}
+	if req.Diversity > 0 {
+		limit *= diversityCandidates
+	}
+	return limit
+}
+
+// diversify picks limit of the ranked memories by Maximal Marginal
+// Relevance: each pick maximizes (1-diversity) × relevance - diversity ×
+// its greatest similarity to the memories already picked, comparing stored
+// embeddings. Relevance is the score rescaled to 0-1 across the candidates.
+// With diversity 0, or fewer than two embedded candidates, the memories
+// are just cut to limit.
+func (s *Store) diversify(memories []models.Memory, limit int, diversity float64) ([]models.Memory, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+	if diversity <= 0 || len(memories) <= 1 {
+		return truncate(memories, limit), nil
+	}
+
+	ids := make([]string, len(memories))
+	for i, m := range memories {
+		ids[i] = m.ID
+	}
+	embeddings, err := s.embeddings(ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) < 2 {
+		return truncate(memories, limit), nil
+	}
+
+	lo, hi := memories[0].Score, memories[0].Score
+	for _, m := range memories {
+		lo, hi = min(lo, m.Score), max(hi, m.Score)
+	}
+	relevance := func(m models.Memory) float64 {
+		if hi == lo {
+			return 1
+		}
+		return float64((m.Score - lo) / (hi - lo))
+	}
+
+	remaining := append([]models.Memory(nil), memories...)
+	var picked []models.Memory
+	for len(picked) < limit && len(remaining) > 0 {
+		best, bestScore := 0, 0.0
+		for i, m := range remaining {
+			var redundancy float64
+			if emb, ok := embeddings[m.ID]; ok {
+				for _, p := range picked {
+					if other, ok := embeddings[p.ID]; ok {
+						redundancy = max(redundancy, float64(cosineSimilarity(emb, other)))
+					}
+				}
+			}
+			score := (1-diversity)*relevance(m) - diversity*redundancy
+			if i == 0 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		picked = append(picked, remaining[best])
+		remaining = append(remaining[:best], remaining[best+1:]...)
+	}
+	return picked, nil
+}
+
+// embeddings loads the stored embeddings of the given memories, leaving out
+// those without one
+func (s *Store) embeddings(ids []string) (map[string][]float32, error) {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := s.db.Query(`SELECT id, embedding FROM memories
+		WHERE embedding IS NOT NULL AND id IN (`+strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	embeddings := make(map[string][]float32, len(ids))
+	for rows.Next() {
+		var id string
+		var blob []byte
+		if err := rows.Scan(&id, &blob); err != nil {
+			return nil, err
+		}
+		if len(blob) > 0 {
+			embeddings[id] = decodeEmbedding(blob)
+		}
+	}
+	return embeddings, rows.Err()
+}
+
+func truncate(memories []models.Memory, limit int) []models.Memory {
+	if len(memories) > limit {
+		return memories[:limit]
+	}
+	return memories
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"database/sql"
+	"sort"
+)
+
+// EmbeddingGroup counts the embeddings produced by one model at one
+// dimension. Model is "" for embeddings stored before models were recorded.
+type EmbeddingGroup struct {
+	Model     string `json:"model"`
+	Dimension int    `json:"dimension"`
+	Count     int    `json:"count"`
+	Stale     bool   `json:"stale"`
+}
+
+// EmbeddingStats describes how many memories have embeddings and which
+// models produced them. The most common model and dimension are current;
+// embeddings from any other are stale and can't be compared with the rest
+// until they are re-embedded with `memorypilot reindex --all`.
+type EmbeddingStats struct {
+	Total            int              `json:"total"`
+	Embedded         int              `json:"embedded"`
+	Unembedded       int              `json:"unembedded"`
+	Stale            int              `json:"stale"`
+	CurrentModel     string           `json:"currentModel"`
+	CurrentDimension int              `json:"currentDimension"`
+	Groups           []EmbeddingGroup `json:"groups"`
+}
+
+// Coverage returns the fraction of memories with an embedding, or 0 if there
+// are no memories
+func (e *EmbeddingStats) Coverage() float64 {
+	if e.Total == 0 {
+		return 0
+	}
+	return float64(e.Embedded) / float64(e.Total)
+}
+
+// NeedsReindex reports whether some memories lack an embedding or have a
+// stale one
+func (e *EmbeddingStats) NeedsReindex() bool {
+	return e.Unembedded > 0 || e.Stale > 0
+}
+
+// GetEmbeddingStats counts embedded and unembedded memories and breaks the
+// embeddings down by model and dimension, largest group first
+func (s *Store) GetEmbeddingStats() (*EmbeddingStats, error) {
+	stats := &EmbeddingStats{Groups: []EmbeddingGroup{}}
+
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM memories").Scan(&stats.Total); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`SELECT embedding_model, length(embedding) / 4, COUNT(*) FROM memories
+		WHERE embedding IS NOT NULL GROUP BY embedding_model, length(embedding)`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var model sql.NullString
+		var g EmbeddingGroup
+		if err := rows.Scan(&model, &g.Dimension, &g.Count); err != nil {
+			return nil, err
+		}
+		g.Model = model.String
+		stats.Groups = append(stats.Groups, g)
+		stats.Embedded += g.Count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	stats.Unembedded = stats.Total - stats.Embedded
+
+	sort.Slice(stats.Groups, func(i, j int) bool {
+		a, b := stats.Groups[i], stats.Groups[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		return a.Dimension < b.Dimension
+	})
+
+	if len(stats.Groups) == 0 {
+		return stats, nil
+	}
+	current := stats.Groups[0]
+	stats.CurrentModel, stats.CurrentDimension = current.Model, current.Dimension
+	for i := range stats.Groups {
+		g := &stats.Groups[i]
+		// An unknown model can't be told apart from the current one, so only
+		// its dimension counts
+		g.Stale = g.Dimension != current.Dimension ||
+			(g.Model != "" && current.Model != "" && g.Model != current.Model)
+		if g.Stale {
+			stats.Stale += g.Count
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,144 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Settings keys holding the memory cap and how memories over it are chosen
+const (
+	maxMemoriesSetting    = "max_memories"
+	evictionPolicySetting = "eviction_policy"
+)
+
+// EvictionPolicy chooses which memories to delete when the store holds more
+// than its maximum. Adding a policy takes an entry in EvictionPolicies.
+type EvictionPolicy struct {
+	Name  string
+	order string // SQL ordering of memories, the first evicted first
+}
+
+// EvictionPolicies lists the eviction policies; the first is the default
+var EvictionPolicies = []EvictionPolicy{
+	// The least important, oldest first among equals
+	{"importance", "importance ASC, created_at ASC, id ASC"},
+	// The least recently recalled; never recalled counts as accessed when created
+	{"lru", "datetime(COALESCE(last_accessed_at, created_at)) ASC, created_at ASC, id ASC"},
+}
+
+// evictionPolicy returns the policy called name
+func evictionPolicy(name string) (EvictionPolicy, error) {
+	for _, p := range EvictionPolicies {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	names := make([]string, len(EvictionPolicies))
+	for i, p := range EvictionPolicies {
+		names[i] = p.Name
+	}
+	return EvictionPolicy{}, fmt.Errorf("unknown eviction policy %q (expected %s)", name, strings.Join(names, " or "))
+}
+
+// Retention is how many memories the store keeps and which go first when
+// there are more
+type Retention struct {
+	MaxMemories int    `json:"maxMemories"` // 0 keeps every memory
+	Eviction    string `json:"eviction"`
+}
+
+// Retention returns the memory cap and eviction policy set in the database.
+// It is read on every call, so running processes pick up changes.
+func (s *Store) Retention() (Retention, error) {
+	r := Retention{Eviction: EvictionPolicies[0].Name}
+
+	var value string
+	err := s.db.QueryRow("SELECT value FROM settings WHERE key = ?", maxMemoriesSetting).Scan(&value)
+	if err != nil && err != sql.ErrNoRows {
+		return r, err
+	}
+	if err == nil {
+		if r.MaxMemories, err = strconv.Atoi(value); err != nil {
+			return r, fmt.Errorf("invalid memory cap %q: %w", value, err)
+		}
+	}
+
+	err = s.db.QueryRow("SELECT value FROM settings WHERE key = ?", evictionPolicySetting).Scan(&value)
+	if err != nil && err != sql.ErrNoRows {
+		return r, err
+	}
+	if err == nil {
+		r.Eviction = value
+	}
+	return r, nil
+}
+
+// SetRetention sets how many memories the store keeps (0 for all of them)
+// and the eviction policy choosing which to delete when there are more.
+// Memories over the cap are deleted by EvictMemories, not here.
+func (s *Store) SetRetention(r Retention) error {
+	if r.MaxMemories < 0 {
+		return fmt.Errorf("memory cap must not be negative")
+	}
+	if _, err := evictionPolicy(r.Eviction); err != nil {
+		return err
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for key, value := range map[string]string{
+		maxMemoriesSetting:    strconv.Itoa(r.MaxMemories),
+		evictionPolicySetting: r.Eviction,
+	} {
+		if _, err := tx.Exec(`INSERT INTO settings (key, value) VALUES (?, ?)
+			ON CONFLICT(key) DO UPDATE SET value = excluded.value`, key, value); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// EvictMemories deletes memories until the store holds no more than its
+// cap, choosing them by the eviction policy, with their history and access
+// log entries, keeping tombstones for incremental exports. Curated
+// memories are never evicted, so a store whose curated memories alone
+// exceed the cap stays over it. It returns how many were removed.
+func (s *Store) EvictMemories() (int, error) {
+	r, err := s.Retention()
+	if err != nil {
+		return 0, err
+	}
+	if r.MaxMemories == 0 {
+		return 0, nil
+	}
+	policy, err := evictionPolicy(r.Eviction)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var total int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM memories").Scan(&total); err != nil {
+		return 0, err
+	}
+	if total <= r.MaxMemories {
+		return 0, nil
+	}
+
+	n, err := deleteMemories(tx, "SELECT id FROM memories WHERE curated_at IS NULL ORDER BY "+policy.order+" LIMIT ?",
+		total-r.MaxMemories)
+	if err != nil {
+		return 0, err
+	}
+	return n, tx.Commit()
+}
@@ -0,0 +1,114 @@
+package store
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+func TestEvictMemories(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	memory := func(id string, importance float64, created, accessed time.Time) *models.Memory {
+		m := newTestMemory("memory " + id)
+		m.ID = id
+		m.Importance = importance
+		m.CreatedAt, m.LastAccessedAt = created, accessed
+		return m
+	}
+
+	tests := []struct {
+		policy string
+		want   []string // IDs of the memories left
+	}{
+		// The least important go, curated ones never do
+		{"importance", []string{"curated", "recent", "important"}},
+		// The least recently accessed go, whatever their importance
+		{"lru", []string{"curated", "accessed", "recent"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			s := newTestStore(t)
+			createMemories(t, s,
+				// Least important and least recently accessed, but curated
+				memory("curated", 0.1, start, start),
+				memory("important", 0.9, start.Add(time.Hour), start.Add(time.Hour)),
+				memory("accessed", 0.2, start.Add(2*time.Hour), start.Add(10*time.Hour)),
+				memory("stale", 0.3, start.Add(3*time.Hour), start.Add(3*time.Hour)),
+				memory("recent", 0.6, start.Add(4*time.Hour), start.Add(4*time.Hour)),
+			)
+			if _, err := s.CurateMemory("curated", "", "reviewer", 0.1); err != nil {
+				t.Fatalf("CurateMemory: %v", err)
+			}
+			if err := s.SetRetention(Retention{MaxMemories: 3, Eviction: tt.policy}); err != nil {
+				t.Fatalf("SetRetention: %v", err)
+			}
+
+			n, err := s.EvictMemories()
+			if err != nil {
+				t.Fatalf("EvictMemories: %v", err)
+			}
+			if n != 2 {
+				t.Errorf("evicted %d memories, want 2", n)
+			}
+			memories, err := s.ListMemories(ListOptions{})
+			if err != nil {
+				t.Fatalf("ListMemories: %v", err)
+			}
+			got := memoryIDs(memories)
+			want := slices.Clone(tt.want)
+			slices.Sort(got)
+			slices.Sort(want)
+			if !slices.Equal(got, want) {
+				t.Errorf("left %v, want %v", got, want)
+			}
+
+			// Evicted memories leave tombstones for incremental exports
+			tombstones, err := s.ListTombstones(time.Time{})
+			if err != nil {
+				t.Fatalf("ListTombstones: %v", err)
+			}
+			if len(tombstones) != 2 {
+				t.Errorf("got %d tombstones, want 2", len(tombstones))
+			}
+
+			// At the cap nothing more goes
+			if n, err := s.EvictMemories(); err != nil || n != 0 {
+				t.Errorf("second EvictMemories = %d, %v; want 0", n, err)
+			}
+		})
+	}
+}
+
+func TestEvictMemoriesKeepsCurated(t *testing.T) {
+	s := newTestStore(t)
+	for i := 0; i < 3; i++ {
+		m := newTestMemory("curated decision")
+		createMemories(t, s, m)
+		if _, err := s.CurateMemory(m.ID, "", "reviewer", 0); err != nil {
+			t.Fatalf("CurateMemory: %v", err)
+		}
+	}
+	if err := s.SetRetention(Retention{MaxMemories: 1, Eviction: "lru"}); err != nil {
+		t.Fatalf("SetRetention: %v", err)
+	}
+	// Curated memories alone over the cap stay
+	if n, err := s.EvictMemories(); err != nil || n != 0 {
+		t.Errorf("EvictMemories = %d, %v; want 0", n, err)
+	}
+}
+
+func TestSetRetentionRejectsUnknownPolicy(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.SetRetention(Retention{MaxMemories: 10, Eviction: "lfu"}); err == nil {
+		t.Errorf("SetRetention with policy lfu succeeded, want an error")
+	}
+	r, err := s.Retention()
+	if err != nil {
+		t.Fatalf("Retention: %v", err)
+	}
+	if r.MaxMemories != 0 || r.Eviction != EvictionPolicies[0].Name {
+		t.Errorf("Retention = %+v, want the defaults", r)
+	}
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"sort"
+	"strings"
+)
+
+// MaxExpansionTerms is the most topics ExpandQuery adds to a query
+const MaxExpansionTerms = 5
+
+// minExpansionPrefix is the shortest query word expanded to the topics it
+// starts
+const minExpansionPrefix = 3
+
+// ExpandQuery suggests topics related to a query: the canonical topic of a
+// word that is an alias, the aliases of a word that is a topic, and topics
+// sharing a stem with a query word or starting with it. The most used
+// topics come first; words already in the query are skipped. Pass the
+// result as RecallRequest.Expand.
+func (s *Store) ExpandQuery(query string) ([]string, error) {
+	counts, err := s.topicCounts()
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := s.topicAliases()
+	if err != nil {
+		return nil, err
+	}
+	tok, err := s.tokenizerFor(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !(r == '-' || r == '_' || r == '.' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r > 127)
+	})
+	inQuery := make(map[string]bool, len(words))
+	stems := make(map[string]bool)
+	for _, w := range words {
+		inQuery[w] = true
+		for _, t := range tok.Tokens(w) {
+			stems[t] = true
+		}
+	}
+
+	related := make(map[string]bool)
+	for _, w := range words {
+		if canonical, ok := aliases[w]; ok {
+			related[canonical] = true
+		}
+		for alias, canonical := range aliases {
+			if canonical == w {
+				related[alias] = true
+			}
+		}
+	}
+	for topic := range counts {
+		if topic == "" || related[topic] {
+			continue
+		}
+		for _, t := range tok.Tokens(topic) {
+			if stems[t] {
+				related[topic] = true
+				break
+			}
+		}
+		for _, w := range words {
+			if len(w) >= minExpansionPrefix && strings.HasPrefix(topic, w) {
+				related[topic] = true
+			}
+		}
+	}
+
+	terms := make([]string, 0, len(related))
+	for term := range related {
+		if !inQuery[term] {
+			terms = append(terms, term)
+		}
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if counts[terms[i]] != counts[terms[j]] {
+			return counts[terms[i]] > counts[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	if len(terms) > MaxExpansionTerms {
+		terms = terms[:MaxExpansionTerms]
+	}
+	return terms, nil
+}
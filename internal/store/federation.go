@@ -0,0 +1,126 @@
+package store
+
+import (
+	"context"
+	"sort"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// FederatedResult is a memory found by a federated search, tagged with the
+// store it came from. Score is normalized across stores; StoreScore is the
+// score the memory had within its own store.
+type FederatedResult struct {
+	models.Memory
+	Store      string  `json:"store"`
+	StoreScore float32 `json:"storeScore"`
+}
+
+// StoreError records a store a federated search had to skip, and why
+type StoreError struct {
+	Store  string `json:"store"`
+	Reason string `json:"error"`
+}
+
+// federatedStore is one store in a Federation
+type federatedStore struct {
+	name  string
+	store *Store
+	owned bool // Opened by the federation, which closes it
+}
+
+// Federation searches several stores as one, so separate databases (one
+// per project, say) can be queried together without merging them
+type Federation struct {
+	stores []federatedStore
+	// Stores that couldn't be opened or searched, in the order found
+	Unavailable []StoreError
+}
+
+// NewFederation creates an empty federation
+func NewFederation() *Federation {
+	return &Federation{}
+}
+
+// Add includes an already open store under name. The caller keeps
+// ownership of it.
+func (f *Federation) Add(name string, s *Store) {
+	f.stores = append(f.stores, federatedStore{name: name, store: s})
+}
+
+// Open opens each database read-only and includes it, named by its path.
+// Databases that can't be opened are recorded in Unavailable and skipped.
+func (f *Federation) Open(dbPaths ...string) {
+	for _, path := range dbPaths {
+		s, err := OpenReadOnly(path)
+		if err != nil {
+			f.skip(path, err)
+			continue
+		}
+		f.stores = append(f.stores, federatedStore{name: path, store: s, owned: true})
+	}
+}
+
+// Len returns how many stores are searched
+func (f *Federation) Len() int {
+	return len(f.stores)
+}
+
+func (f *Federation) skip(name string, err error) {
+	f.Unavailable = append(f.Unavailable, StoreError{Store: name, Reason: err.Error()})
+}
+
+// Search runs req against every store and merges the results, best first,
+// up to req.Limit. With a query embedding each store runs a hybrid search,
+// otherwise a keyword search. Scores are normalized per store by dividing
+// by that store's best score, so a store whose scores run high doesn't
+// crowd out the rest. Stores whose search fails are recorded in
+// Unavailable and skipped.
+func (f *Federation) Search(ctx context.Context, req models.RecallRequest, queryEmbedding []float32) []FederatedResult {
+	var results []FederatedResult
+	for _, fs := range f.stores {
+		var memories []models.Memory
+		var err error
+		if queryEmbedding != nil {
+			memories, err = fs.store.HybridSearchContext(ctx, req, queryEmbedding)
+		} else {
+			memories, err = fs.store.Recall(req)
+		}
+		if err != nil {
+			f.skip(fs.name, err)
+			continue
+		}
+
+		var best float32
+		for _, m := range memories {
+			best = max(best, m.Score)
+		}
+		for _, m := range memories {
+			r := FederatedResult{Memory: m, Store: fs.name, StoreScore: m.Score}
+			if best > 0 {
+				r.Score = m.Score / best
+			}
+			results = append(results, r)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].StoreScore > results[j].StoreScore
+	})
+	if req.Limit > 0 && len(results) > req.Limit {
+		results = results[:req.Limit]
+	}
+	return results
+}
+
+// Close closes the stores the federation opened
+func (f *Federation) Close() {
+	for _, fs := range f.stores {
+		if fs.owned {
+			fs.store.Close()
+		}
+	}
+}
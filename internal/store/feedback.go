@@ -0,0 +1,58 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// feedbackStep is how far one piece of feedback moves a memory's weight
+const feedbackStep = 0.2
+
+// maxFeedback bounds the feedback weight in either direction
+const maxFeedback = 1.0
+
+// feedbackBoost scales the feedback weight into a recall score adjustment,
+// so a fully boosted memory gains 0.15 and a fully demoted one loses 0.15
+const feedbackBoost = 0.15
+
+// feedbackDecay is applied to feedback weights by each daily decay, halving
+// them in about a month so old feedback doesn't dominate forever
+const feedbackDecay = 0.977
+
+// RecordFeedback records whether a recalled memory was useful, moving its
+// feedback weight up or down by one step within [-1, 1]. It returns the new
+// weight.
+func (s *Store) RecordFeedback(id string, useful bool) (float64, error) {
+	step := feedbackStep
+	if !useful {
+		step = -step
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE memories SET feedback = MAX(?, MIN(?, feedback + ?)) WHERE id = ?`,
+		-maxFeedback, maxFeedback, step, id); err != nil {
+		return 0, err
+	}
+
+	var weight float64
+	err = tx.QueryRow("SELECT feedback FROM memories WHERE id = ?", id).Scan(&weight)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("memory %s not found", id)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return weight, tx.Commit()
+}
+
+// feedbackScore is the recall score adjustment for a memory's feedback
+func feedbackScore(m *models.Memory) float32 {
+	return float32(m.Feedback * feedbackBoost)
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// importanceFloorSetting is the settings key holding the recall importance
+// floor
+const importanceFloorSetting = "recall_importance_floor"
+
+// DefaultImportanceFloor is the recall importance floor of stores that
+// haven't set one: auto-captured memories scored as typos or formatting
+// changes, or decayed from unimportant, stay out of recall
+const DefaultImportanceFloor = 0.3
+
+// capturedSources are the sources whose memories the importance floor
+// applies to: those the daemon captured rather than someone chose to keep
+var capturedSources = []models.SourceType{models.SourceTypeGit, models.SourceTypeFile, models.SourceTypeTerminal}
+
+// loadImportanceFloor reads the importance floor stored in the database, so
+// every process opening the store recalls the same memories
+func (s *Store) loadImportanceFloor() error {
+	var value string
+	err := s.db.QueryRow("SELECT value FROM settings WHERE key = ?", importanceFloorSetting).Scan(&value)
+	if err == sql.ErrNoRows {
+		s.importanceFloor = DefaultImportanceFloor
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	floor, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid importance floor %q: %w", value, err)
+	}
+	s.importanceFloor = floor
+	return nil
+}
+
+// ImportanceFloor returns the importance below which auto-captured memories
+// are left out of recall
+func (s *Store) ImportanceFloor() float64 {
+	return s.importanceFloor
+}
+
+// SetImportanceFloor sets the importance below which memories captured by
+// the daemon (from git, files and the terminal) are left out of recall,
+// between 0 (none are) and 1. They stay in the store, and recall includes
+// them when asked for low-importance memories or filtered by type or
+// topic. Memories remembered manually, over MCP or imported, and curated
+// memories, always pass. Processes that already have the store open keep
+// their floor until they reopen it.
+func (s *Store) SetImportanceFloor(floor float64) error {
+	if floor < 0 || floor > 1 {
+		return fmt.Errorf("importance floor must be between 0 and 1")
+	}
+	if _, err := s.exec(`INSERT INTO settings (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+		importanceFloorSetting, strconv.FormatFloat(floor, 'g', -1, 64)); err != nil {
+		return err
+	}
+	s.importanceFloor = floor
+	return nil
+}
+
+// floorClause is the SQL condition leaving auto-captured memories below the
+// importance floor out of a recall, or "" if the floor doesn't apply to req
+func (s *Store) floorClause(req models.RecallRequest) (string, []interface{}) {
+	if s.importanceFloor <= 0 || req.IncludeLow || len(req.Types) > 0 || len(req.Topics) > 0 {
+		return "", nil
+	}
+	placeholders := ""
+	args := make([]interface{}, 0, len(capturedSources)+1)
+	for i, src := range capturedSources {
+		if i > 0 {
+			placeholders += ", "
+		}
+		placeholders += "?"
+		args = append(args, string(src))
+	}
+	args = append(args, s.importanceFloor)
+	return " AND (source_type NOT IN (" + placeholders + ") OR curated_at IS NOT NULL OR importance >= ?)", args
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+func TestImportanceFloor(t *testing.T) {
+	s := newTestStore(t)
+	s.SetRecallCache(0, 0)
+
+	captured := func(content string, importance float64) *models.Memory {
+		m := newTestMemory(content)
+		m.Source = models.Source{Type: models.SourceTypeFile, Reference: "/src/app.go"}
+		m.Importance = importance
+		m.Topics = []string{"deploy"}
+		return m
+	}
+	low := captured("deploy script typo fix", 0.1)
+	high := captured("deploy script now waits for health checks", 0.8)
+	manual := newTestMemory("deploy only from the release branch")
+	manual.Importance = 0.1 // Decayed, but kept on purpose
+	curated := captured("deploy rollback steps", 0.1)
+	createMemories(t, s, low, high, manual, curated)
+	if _, err := s.CurateMemory(curated.ID, "", "reviewer", 0.1); err != nil {
+		t.Fatalf("CurateMemory: %v", err)
+	}
+
+	recall := func(req models.RecallRequest) []string {
+		t.Helper()
+		req.Query = "deploy"
+		req.Limit = 10
+		results, err := s.Recall(req)
+		if err != nil {
+			t.Fatalf("Recall: %v", err)
+		}
+		ids := memoryIDs(results)
+		slices.Sort(ids)
+		return ids
+	}
+	all := []string{low.ID, high.ID, manual.ID, curated.ID}
+	slices.Sort(all)
+	withoutLow := slices.DeleteFunc(slices.Clone(all), func(id string) bool { return id == low.ID })
+
+	tests := []struct {
+		name string
+		req  models.RecallRequest
+		want []string
+	}{
+		// Manual and curated memories pass whatever their importance
+		{"default", models.RecallRequest{}, withoutLow},
+		{"include low", models.RecallRequest{IncludeLow: true}, all},
+		{"type filter", models.RecallRequest{Types: []models.MemoryType{models.MemoryTypeFact}}, all},
+		{"topic filter", models.RecallRequest{Topics: []string{"deploy"}}, []string{low.ID, high.ID, curated.ID}},
+		{"curated only", models.RecallRequest{CuratedOnly: true}, []string{curated.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := slices.Clone(tt.want)
+			slices.Sort(want)
+			if got := recall(tt.req); !slices.Equal(got, want) {
+				t.Errorf("Recall = %v, want %v", got, want)
+			}
+		})
+	}
+
+	// Semantic search applies the same floor
+	for _, m := range []*models.Memory{low, high, manual, curated} {
+		if err := s.UpdateMemoryEmbedding(m.ID, []float32{1, 0}, "test"); err != nil {
+			t.Fatalf("UpdateMemoryEmbedding: %v", err)
+		}
+	}
+	results, err := s.SemanticSearch(models.RecallRequest{Query: "deploy", Limit: 10}, []float32{1, 0})
+	if err != nil {
+		t.Fatalf("SemanticSearch: %v", err)
+	}
+	got := memoryIDs(results)
+	slices.Sort(got)
+	if !slices.Equal(got, withoutLow) {
+		t.Errorf("SemanticSearch = %v, want %v", got, withoutLow)
+	}
+
+	// Without a floor everything is recalled
+	if err := s.SetImportanceFloor(0); err != nil {
+		t.Fatalf("SetImportanceFloor: %v", err)
+	}
+	if got := recall(models.RecallRequest{}); !slices.Equal(got, all) {
+		t.Errorf("Recall without a floor = %v, want %v", got, all)
+	}
+}
+
+func TestImportanceFloorIsStored(t *testing.T) {
+	s := newTestStore(t)
+	if got := s.ImportanceFloor(); got != DefaultImportanceFloor {
+		t.Errorf("ImportanceFloor = %v, want the default %v", got, DefaultImportanceFloor)
+	}
+	if err := s.SetImportanceFloor(1.5); err == nil {
+		t.Errorf("SetImportanceFloor(1.5) succeeded, want an error")
+	}
+	if err := s.SetImportanceFloor(0.6); err != nil {
+		t.Fatalf("SetImportanceFloor: %v", err)
+	}
+	if err := s.loadImportanceFloor(); err != nil {
+		t.Fatalf("loadImportanceFloor: %v", err)
+	}
+	if got := s.ImportanceFloor(); got != 0.6 {
+		t.Errorf("ImportanceFloor after reloading = %v, want 0.6", got)
+	}
+}
@@ -0,0 +1,108 @@
+package store
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+func TestHybridSearchDeduplicates(t *testing.T) {
+	s := newTestStore(t)
+
+	both := newTestMemory("postgres connection pooling uses pgbouncer")
+	both.Embedding = []float32{1, 0, 0}
+	semantic := newTestMemory("database proxy sits in front of the primary")
+	semantic.Embedding = []float32{0.9, 0.1, 0}
+	other := newTestMemory("frontend builds with vite")
+	other.Embedding = []float32{0, 0, 1}
+	createMemories(t, s, both, semantic, other)
+
+	results, err := s.HybridSearch(models.RecallRequest{Query: "pgbouncer", Limit: 10}, []float32{1, 0, 0})
+	if err != nil {
+		t.Fatalf("HybridSearch: %v", err)
+	}
+
+	count := 0
+	for _, m := range results {
+		if m.ID == both.ID {
+			count++
+			if !m.KeywordMatch {
+				t.Errorf("memory matched both ways lost its keyword match")
+			}
+		}
+	}
+	if count != 1 {
+		t.Fatalf("memory matching both searches appears %d times in %v, want once", count, memoryIDs(results))
+	}
+	if results[0].ID != both.ID {
+		t.Errorf("first result is %s, want the memory matching both searches %s", results[0].ID, both.ID)
+	}
+
+	// The duplicate must not take a slot from another result
+	results, err = s.HybridSearch(models.RecallRequest{Query: "pgbouncer", Limit: 2}, []float32{1, 0, 0})
+	if err != nil {
+		t.Fatalf("HybridSearch: %v", err)
+	}
+	if got := memoryIDs(results); len(got) != 2 || got[0] != both.ID || got[1] != semantic.ID {
+		t.Errorf("HybridSearch with limit 2 = %v, want [%s %s]", got, both.ID, semantic.ID)
+	}
+}
+
+func TestHybridSearchZeroEmbedding(t *testing.T) {
+	s := newTestStore(t)
+
+	keyword := newTestMemory("rotate the staging TLS certificate")
+	keyword.Embedding = []float32{0, 1, 0}
+	unrelated := newTestMemory("lunch is at noon")
+	unrelated.Embedding = []float32{1, 0, 0}
+	createMemories(t, s, keyword, unrelated)
+
+	for name, query := range map[string][]float32{
+		"nil":   nil,
+		"empty": {},
+		"zeros": {0, 0, 0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			results, err := s.HybridSearch(models.RecallRequest{Query: "certificate", Limit: 5}, query)
+			if err != nil {
+				t.Fatalf("HybridSearch: %v", err)
+			}
+			// Falls back to keyword search, so only the keyword match comes back
+			if got := memoryIDs(results); len(got) != 1 || got[0] != keyword.ID {
+				t.Fatalf("HybridSearch = %v, want [%s]", got, keyword.ID)
+			}
+			if score := results[0].Score; math.IsNaN(float64(score)) || score <= 0 {
+				t.Errorf("score = %v, want a positive keyword score", score)
+			}
+		})
+	}
+}
+
+func TestUpdateMemoryEmbeddingRejectsInvalid(t *testing.T) {
+	s := newTestStore(t)
+	m := newTestMemory("rotate the staging TLS certificate")
+	m.Embedding = []float32{0, 1, 0}
+	createMemories(t, s, m)
+
+	for name, emb := range map[string][]float32{
+		"empty": {},
+		"zeros": {0, 0, 0},
+		"NaN":   {float32(math.NaN()), 1, 0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := s.UpdateMemoryEmbedding(m.ID, emb, "test"); !errors.Is(err, ErrInvalidEmbedding) {
+				t.Fatalf("UpdateMemoryEmbedding = %v, want ErrInvalidEmbedding", err)
+			}
+			stored, err := s.MemoryEmbedding(m.ID)
+			if err != nil {
+				t.Fatalf("MemoryEmbedding: %v", err)
+			}
+			if !slices.Equal(stored, m.Embedding) {
+				t.Errorf("stored embedding = %v, want the previous %v", stored, m.Embedding)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/contextpilot-dev/memorypilot/internal/tokenize"
+)
+
+// tokenizerSetting is the settings key holding the tokenizer configuration
+const tokenizerSetting = "tokenizer"
+
+// keywords returns the keyword index entry for a memory: its content and
+// summary tokens, space separated with a leading and trailing space so a
+// whole token can be matched with LIKE '% token %'
+func keywords(tok *tokenize.Tokenizer, content, summary string) string {
+	tokens := tok.Tokens(content + "\n" + summary)
+	if len(tokens) == 0 {
+		return " "
+	}
+	return " " + strings.Join(tokens, " ") + " "
+}
+
+// loadTokenizer reads the tokenizer configuration stored in the database.
+// Keeping it in the database, rather than per process, guarantees every
+// writer and reader tokenizes the same way.
+func (s *Store) loadTokenizer() error {
+	_, err := s.tokenizerFor(s.db)
+	return err
+}
+
+// tokenizerFor returns the tokenizer configured in the database, reading
+// the setting through q. It is read on every write and recall, so running
+// processes pick up a configuration changed by another one; the tokenizer
+// is only rebuilt when the stored setting differs from the one it was
+// built from.
+func (s *Store) tokenizerFor(q rowQuerier) (*tokenize.Tokenizer, error) {
+	value, err := storedTokenizerSetting(q)
+	if err != nil {
+		return nil, err
+	}
+
+	s.tokenizerMu.Lock()
+	defer s.tokenizerMu.Unlock()
+	if s.tokenizer != nil && value == s.tokenizerValue {
+		return s.tokenizer, nil
+	}
+	cfg, err := parseTokenizerConfig(value)
+	if err != nil {
+		return nil, err
+	}
+	s.tokenizer = tokenize.New(cfg)
+	s.tokenizerValue = value
+	return s.tokenizer, nil
+}
+
+// TokenizerConfig returns the tokenizer configuration used for keyword
+// search
+func (s *Store) TokenizerConfig() (tokenize.Config, error) {
+	value, err := storedTokenizerSetting(s.db)
+	if err != nil {
+		return tokenize.Config{}, err
+	}
+	return parseTokenizerConfig(value)
+}
+
+// storedTokenizerSetting returns the tokenizer setting as stored, or "" if
+// none is set
+func storedTokenizerSetting(q rowQuerier) (string, error) {
+	var value string
+	err := q.QueryRow("SELECT value FROM settings WHERE key = ?", tokenizerSetting).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return value, err
+}
+
+// parseTokenizerConfig parses a stored tokenizer setting; "" is the
+// default configuration
+func parseTokenizerConfig(value string) (tokenize.Config, error) {
+	var cfg tokenize.Config
+	if value == "" {
+		return cfg, nil
+	}
+	if err := json.Unmarshal([]byte(value), &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// SetTokenizerConfig changes how keyword search tokenizes text and rebuilds
+// the keyword index of every memory with it, in one transaction. It returns
+// the number of memories reindexed.
+func (s *Store) SetTokenizerConfig(cfg tokenize.Config) (int, error) {
+	value, err := json.Marshal(cfg)
+	if err != nil {
+		return 0, err
+	}
+	tok := tokenize.New(cfg)
+
+	tx, err := s.begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`INSERT INTO settings (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`, tokenizerSetting, string(value)); err != nil {
+		return 0, err
+	}
+	if err := indexKeywords(tx.Tx, tok, ""); err != nil {
+		return 0, err
+	}
+
+	var n int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM memories").Scan(&n); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	s.tokenizerMu.Lock()
+	s.tokenizer = tok
+	s.tokenizerValue = string(value)
+	s.tokenizerMu.Unlock()
+	return n, nil
+}
+
+// indexKeywords recomputes the keyword index of every memory, or of those
+// matching where (a WHERE clause) if given
+func indexKeywords(tx *sql.Tx, tok *tokenize.Tokenizer, where string) error {
+	rows, err := tx.Query("SELECT id, content, summary FROM memories " + where)
+	if err != nil {
+		return err
+	}
+
+	updates := make(map[string]string)
+	for rows.Next() {
+		var id, content, summary string
+		if err := rows.Scan(&id, &content, &summary); err != nil {
+			rows.Close()
+			return err
+		}
+		updates[id] = keywords(tok, content, summary)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, kw := range updates {
+		if _, err := tx.Exec("UPDATE memories SET keywords = ? WHERE id = ?", kw, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
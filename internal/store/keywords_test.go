@@ -0,0 +1,51 @@
+package store
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/contextpilot-dev/memorypilot/internal/tokenize"
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+func TestTokenizerChangedByAnotherProcess(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "memories.db")
+	running, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer running.Close()
+	cli, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer cli.Close()
+
+	// Turning stemming off indexes "caching" as itself rather than "cach"
+	stemming := false
+	if _, err := cli.SetTokenizerConfig(tokenize.Config{Stemming: &stemming}); err != nil {
+		t.Fatalf("SetTokenizerConfig: %v", err)
+	}
+
+	m := newTestMemory("caching layer for the API")
+	createMemories(t, running, m)
+
+	for name, s := range map[string]*Store{"running": running, "cli": cli} {
+		results, err := s.Recall(models.RecallRequest{Query: "caching", Limit: 10})
+		if err != nil {
+			t.Fatalf("%s Recall: %v", name, err)
+		}
+		if !slices.Contains(memoryIDs(results), m.ID) {
+			t.Errorf("%s recall of %q = %v, want %s", name, "caching", memoryIDs(results), m.ID)
+		}
+	}
+
+	results, err := running.Recall(models.RecallRequest{Query: "caches", Limit: 10})
+	if err != nil {
+		t.Fatalf("Recall: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("recall of %q without stemming = %v, want none", "caches", memoryIDs(results))
+	}
+}
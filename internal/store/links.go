@@ -0,0 +1,171 @@
+package store
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Link inference defaults
+const (
+	DefaultLinkThreshold    = 3 // Recalls two memories must share to be linked
+	DefaultMaxInferredLinks = 5 // Most inferred links kept per memory
+)
+
+// InferredLink relates two memories that keep being recalled together
+type InferredLink struct {
+	MemoryID      string `json:"memoryId"`
+	RelatedID     string `json:"relatedId"`
+	Cooccurrences int    `json:"cooccurrences"`
+}
+
+// InferLinks rebuilds the inferred links from the access log. Two memories
+// returned by the same recall at least threshold times are linked both
+// ways; each memory keeps its maxPerMemory most frequent partners. Pairs
+// already linked manually are skipped. It returns the number of links
+// stored (each direction counts once). Values of 0 or less use the
+// defaults.
+func (s *Store) InferLinks(threshold, maxPerMemory int) (int, error) {
+	if threshold <= 0 {
+		threshold = DefaultLinkThreshold
+	}
+	if maxPerMemory <= 0 {
+		maxPerMemory = DefaultMaxInferredLinks
+	}
+
+	// Entries of one recall share its query and timestamp
+	rows, err := s.db.Query(`
+		SELECT a.memory_id, b.memory_id, COUNT(*) AS n
+		FROM access_log a
+		JOIN access_log b ON a.query = b.query AND a.accessed_at = b.accessed_at AND a.memory_id < b.memory_id
+		JOIN memories ma ON ma.id = a.memory_id
+		JOIN memories mb ON mb.id = b.memory_id
+		GROUP BY a.memory_id, b.memory_id
+		HAVING n >= ?
+	`, threshold)
+	if err != nil {
+		return 0, err
+	}
+	partners := make(map[string][]InferredLink)
+	for rows.Next() {
+		var a, b string
+		var n int
+		if err := rows.Scan(&a, &b, &n); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		partners[a] = append(partners[a], InferredLink{a, b, n})
+		partners[b] = append(partners[b], InferredLink{b, a, n})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	manual, err := s.manualLinks()
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM inferred_links"); err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	var stored int
+	for _, links := range partners {
+		sort.Slice(links, func(i, j int) bool {
+			if links[i].Cooccurrences != links[j].Cooccurrences {
+				return links[i].Cooccurrences > links[j].Cooccurrences
+			}
+			return links[i].RelatedID < links[j].RelatedID
+		})
+		kept := 0
+		for _, l := range links {
+			if kept == maxPerMemory {
+				break
+			}
+			if manual[l.MemoryID][l.RelatedID] || manual[l.RelatedID][l.MemoryID] {
+				continue
+			}
+			if _, err := tx.Exec(`INSERT INTO inferred_links (memory_id, related_id, cooccurrences, updated_at)
+				VALUES (?, ?, ?, ?)`, l.MemoryID, l.RelatedID, l.Cooccurrences, now); err != nil {
+				return 0, err
+			}
+			kept++
+			stored++
+		}
+	}
+	return stored, tx.Commit()
+}
+
+// manualLinks returns the related memories of each memory that has any
+func (s *Store) manualLinks() (map[string]map[string]bool, error) {
+	rows, err := s.db.Query(`SELECT id, related_memories FROM memories
+		WHERE related_memories IS NOT NULL AND related_memories NOT IN ('', 'null', '[]')`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := make(map[string]map[string]bool)
+	for rows.Next() {
+		var id, relatedJSON string
+		if err := rows.Scan(&id, &relatedJSON); err != nil {
+			return nil, err
+		}
+		var related []string
+		json.Unmarshal([]byte(relatedJSON), &related)
+		links[id] = make(map[string]bool, len(related))
+		for _, r := range related {
+			links[id][r] = true
+		}
+	}
+	return links, rows.Err()
+}
+
+// InferredLinks returns the inferred links of the given memories, most
+// frequent first
+func (s *Store) InferredLinks(ids []string) ([]InferredLink, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := s.db.Query(`SELECT memory_id, related_id, cooccurrences FROM inferred_links
+		WHERE memory_id IN (`+strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")+`)
+		ORDER BY cooccurrences DESC, related_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []InferredLink
+	for rows.Next() {
+		var l InferredLink
+		if err := rows.Scan(&l.MemoryID, &l.RelatedID, &l.Cooccurrences); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
+
+// PruneInferredLinks deletes every inferred link, leaving manual links
+// alone, and returns how many were removed
+func (s *Store) PruneInferredLinks() (int, error) {
+	result, err := s.exec("DELETE FROM inferred_links")
+	if err != nil {
+		return 0, err
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
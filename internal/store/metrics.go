@@ -0,0 +1,35 @@
+package store
+
+import (
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/internal/metrics"
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// RegisterMetrics exposes the memory count and database size as gauges.
+// Call it at most once per process.
+func (s *Store) RegisterMetrics() {
+	metrics.NewGauge("memorypilot_memories", "Memories in the store.", func() (float64, error) {
+		var count int
+		err := s.db.QueryRow("SELECT COUNT(*) FROM memories").Scan(&count)
+		return float64(count), err
+	})
+	metrics.NewGauge("memorypilot_store_size_bytes", "Size of the memory database.", func() (float64, error) {
+		var size int64
+		err := s.db.QueryRow("SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()").Scan(&size)
+		return float64(size), err
+	})
+}
+
+// observeRecall records how long a search took
+func observeRecall(method string, start time.Time, err error) {
+	metrics.RecallDuration.ObserveSince(start, method, metrics.Result(err))
+}
+
+// countCreated records newly stored memories by source
+func countCreated(memories ...*models.Memory) {
+	for _, m := range memories {
+		metrics.MemoriesCreated.Inc(string(m.Source.Type))
+	}
+}
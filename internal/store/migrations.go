@@ -0,0 +1,414 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// migration is one step of schema evolution. Steps run in version order,
+// each in its own transaction, and must be idempotent so databases created
+// before versioning was introduced can be brought up to date safely.
+type migration struct {
+	version int
+	name    string
+	up      func(tx *sql.Tx) error
+}
+
+// MigrationInfo describes a migration and whether it has been applied
+type MigrationInfo struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+}
+
+// MigrationError reports the migration step that failed. The database is
+// left as it was before that step.
+type MigrationError struct {
+	Version int
+	Name    string
+	Err     error
+}
+
+func (e *MigrationError) Error() string {
+	return fmt.Sprintf("migration %d (%s) failed: %v", e.Version, e.Name, e.Err)
+}
+
+func (e *MigrationError) Unwrap() error {
+	return e.Err
+}
+
+// migrations is the ordered list of schema changes. Append new steps to the
+// end; never edit or reorder released ones. Steps only change the schema
+// and must not call code outside this file, which could change what an old
+// step does; filling new columns from existing data belongs in backfill.
+var migrations = []migration{
+	{1, "initial schema", execAll(
+		`CREATE TABLE IF NOT EXISTS projects (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			path TEXT UNIQUE NOT NULL,
+			git_remote TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			last_seen DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS memories (
+			id TEXT PRIMARY KEY,
+			type TEXT NOT NULL CHECK (type IN ('decision','pattern','fact','preference','mistake','learning')),
+			content TEXT NOT NULL,
+			summary TEXT NOT NULL,
+			scope TEXT NOT NULL DEFAULT 'personal' CHECK (scope IN ('personal','project','team','org')),
+			project_id TEXT REFERENCES projects(id),
+			team_id TEXT,
+			
+			source_type TEXT NOT NULL,
+			source_reference TEXT,
+			source_timestamp DATETIME,
+			
+			confidence REAL NOT NULL DEFAULT 0.8,
+			importance REAL NOT NULL DEFAULT 1.0,
+			
+			topics TEXT,
+			related_memories TEXT,
+			embedding BLOB,
+			
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			last_accessed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			access_count INTEGER DEFAULT 0,
+			expires_at DATETIME
+		)`,
+		`CREATE TABLE IF NOT EXISTS events (
+			id TEXT PRIMARY KEY,
+			type TEXT NOT NULL,
+			timestamp DATETIME NOT NULL,
+			data TEXT,
+			project_id TEXT REFERENCES projects(id),
+			processed_at DATETIME
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_memories_project ON memories(project_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_memories_type ON memories(type)`,
+		`CREATE INDEX IF NOT EXISTS idx_memories_scope ON memories(scope)`,
+		`CREATE INDEX IF NOT EXISTS idx_memories_importance ON memories(importance DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_memories_created ON memories(created_at DESC)`,
+	)},
+
+	// Prior versions, recorded on update. Foreign keys aren't enforced, so
+	// deleting a memory deletes its history explicitly (see deleteMemories).
+	{2, "memory history", execAll(
+		`CREATE TABLE IF NOT EXISTS memory_history (
+			memory_id TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			content TEXT NOT NULL,
+			summary TEXT NOT NULL,
+			editor TEXT,
+			edited_at DATETIME NOT NULL,
+			PRIMARY KEY (memory_id, version)
+		)`,
+	)},
+
+	{3, "memory metadata", addColumn("memories", "metadata", "TEXT")},
+
+	{4, "idempotency keys", func(tx *sql.Tx) error {
+		if err := addColumn("memories", "idempotency_key", "TEXT")(tx); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_memories_idempotency ON memories(idempotency_key)`)
+		return err
+	}},
+
+	// Alternative names mapped to a canonical topic
+	{5, "topic aliases", execAll(
+		`CREATE TABLE IF NOT EXISTS topic_aliases (
+			alias TEXT PRIMARY KEY,
+			canonical TEXT NOT NULL
+		)`,
+	)},
+
+	// Which memories each recall returned
+	{6, "access log", execAll(
+		`CREATE TABLE IF NOT EXISTS access_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			memory_id TEXT NOT NULL,
+			query TEXT NOT NULL,
+			score REAL NOT NULL,
+			accessed_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_access_log_memory ON access_log(memory_id)`,
+	)},
+
+	// Memories removed by a clear that asked to keep them
+	{7, "memory trash", execAll(
+		`CREATE TABLE IF NOT EXISTS memory_trash (
+			id TEXT PRIMARY KEY,
+			data TEXT NOT NULL,
+			embedding BLOB,
+			deleted_at DATETIME NOT NULL
+		)`,
+	)},
+
+	// Last write time, for incremental export, and a record of deletions so
+	// an incremental import can converge
+	{8, "change tracking", func(tx *sql.Tx) error {
+		if err := addColumn("memories", "updated_at", "DATETIME")(tx); err != nil {
+			return err
+		}
+		return execAll(
+			`UPDATE memories SET updated_at = created_at WHERE updated_at IS NULL`,
+			`CREATE INDEX IF NOT EXISTS idx_memories_updated ON memories(updated_at)`,
+			`CREATE TABLE IF NOT EXISTS memory_tombstones (
+				id TEXT PRIMARY KEY,
+				deleted_at DATETIME NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_memory_tombstones_deleted ON memory_tombstones(deleted_at)`,
+		)(tx)
+	}},
+
+	// Which model produced each embedding, so incompatible vectors can be
+	// detected on import
+	{9, "embedding model", addColumn("memories", "embedding_model", "TEXT")},
+
+	// Tokenized content and summary for keyword search, and store-wide
+	// settings such as the tokenizer configuration. Existing memories are
+	// indexed by backfill.
+	{10, "keyword index", func(tx *sql.Tx) error {
+		if err := addColumn("memories", "keywords", "TEXT")(tx); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		)`)
+		return err
+	}},
+
+	// Relevance weight learned from recall feedback
+	{11, "relevance feedback", addColumn("memories", "feedback", "REAL NOT NULL DEFAULT 0")},
+
+	// Prose, code, command or config. Existing memories are detected by
+	// backfill.
+	{12, "content type", func(tx *sql.Tx) error {
+		if err := addColumn("memories", "content_type", "TEXT")(tx); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_memories_content_type ON memories(content_type)`)
+		return err
+	}},
+
+	// Links between memories inferred from recall co-occurrence, kept apart
+	// from manual links so they can be rebuilt and pruned on their own
+	{13, "inferred links", execAll(
+		`CREATE TABLE IF NOT EXISTS inferred_links (
+			memory_id TEXT NOT NULL,
+			related_id TEXT NOT NULL,
+			cooccurrences INTEGER NOT NULL,
+			updated_at DATETIME NOT NULL,
+			PRIMARY KEY (memory_id, related_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_inferred_links_related ON inferred_links(related_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_access_log_recall ON access_log(query, accessed_at)`,
+	)},
+
+	// Lookup by source, which keys git memories by commit SHA
+	{14, "source index", execAll(
+		`CREATE INDEX IF NOT EXISTS idx_memories_source ON memories(source_type, source_reference)`,
+	)},
+
+	// Who vetted a memory and when, for curated-only recall
+	{15, "curation", func(tx *sql.Tx) error {
+		if err := addColumn("memories", "curated_at", "DATETIME")(tx); err != nil {
+			return err
+		}
+		if err := addColumn("memories", "curated_by", "TEXT")(tx); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_memories_curated ON memories(curated_at)`)
+		return err
+	}},
+}
+
+// execAll returns a migration step that runs each statement in turn
+func execAll(statements ...string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for _, stmt := range statements {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// addColumn returns a migration step that adds a column to a table unless
+// it already exists
+func addColumn(table, column, definition string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		exists, err := hasColumn(tx, table, column)
+		if err != nil || exists {
+			return err
+		}
+		_, err = tx.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition)
+		return err
+	}
+}
+
+// hasColumn reports whether table has the named column
+func hasColumn(tx *sql.Tx, table, column string) (bool, error) {
+	rows, err := tx.Query("PRAGMA table_info(" + table + ")")
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// migrate applies pending migrations in order and returns the ones it
+// applied
+func (s *Store) migrate() ([]MigrationInfo, error) {
+	if _, err := s.exec(`CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at DATETIME NOT NULL
+	)`); err != nil {
+		return nil, fmt.Errorf("failed to create schema_version table: %w", err)
+	}
+
+	current, err := schemaVersion(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []MigrationInfo
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		now := time.Now()
+		ran, err := s.applyMigration(m, now)
+		if err != nil {
+			return applied, &MigrationError{Version: m.version, Name: m.name, Err: err}
+		}
+		if ran {
+			applied = append(applied, MigrationInfo{Version: m.version, Name: m.name, AppliedAt: &now})
+		}
+	}
+
+	return applied, nil
+}
+
+// applyMigration runs one step and records it, atomically. The write
+// transaction is taken before the schema version is read again, so a step
+// another process applied meanwhile is skipped; it reports whether the
+// step ran.
+func (s *Store) applyMigration(m migration, now time.Time) (bool, error) {
+	tx, err := s.begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	current, err := schemaVersion(tx)
+	if err != nil {
+		return false, err
+	}
+	if m.version <= current {
+		return false, nil
+	}
+
+	if err := m.up(tx.Tx); err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec("INSERT INTO schema_version (version, name, applied_at) VALUES (?, ?, ?)",
+		m.version, m.name, now); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// schemaVersion returns the highest applied migration version, or 0
+func schemaVersion(db rowQuerier) (int, error) {
+	var version sql.NullInt64
+	if err := db.QueryRow("SELECT MAX(version) FROM schema_version").Scan(&version); err != nil {
+		return 0, err
+	}
+	return int(version.Int64), nil
+}
+
+// LatestSchemaVersion is the schema version this build migrates to
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// MigrationStatus lists all known migrations for the database at dbPath
+// and when each was applied, without changing the database
+func MigrationStatus(dbPath string) ([]MigrationInfo, error) {
+	db, err := sql.Open("sqlite3", dbPath+"?mode=ro&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	appliedAt := make(map[int]time.Time)
+	var exists int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_version'").Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists > 0 {
+		rows, err := db.Query("SELECT version, applied_at FROM schema_version")
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var version int
+			var at time.Time
+			if err := rows.Scan(&version, &at); err != nil {
+				return nil, err
+			}
+			appliedAt[version] = at
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	infos := make([]MigrationInfo, len(migrations))
+	for i, m := range migrations {
+		infos[i] = MigrationInfo{Version: m.version, Name: m.name}
+		if at, ok := appliedAt[m.version]; ok {
+			infos[i].AppliedAt = &at
+		}
+	}
+	return infos, nil
+}
+
+// Migrate applies pending migrations and returns the ones it applied. New
+// already does this; Migrate lets callers report what changed.
+func Migrate(dbPath string) ([]MigrationInfo, error) {
+	db, err := sql.Open("sqlite3", dbPath+writableOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	s := &Store{db: db}
+	applied, err := s.migrate()
+	if err != nil {
+		return applied, err
+	}
+	if err := s.loadTokenizer(); err != nil {
+		return applied, err
+	}
+	return applied, s.backfill()
+}
@@ -0,0 +1,35 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// rankBefore reports whether a ranks before b in recall results: by score,
+// then importance, then newest first, then ID, so memories that tie come
+// back in the same order every time
+func rankBefore(a, b *models.Memory) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.Importance != b.Importance {
+		return a.Importance > b.Importance
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// sortByRank sorts memories into recall order
+func sortByRank(memories []models.Memory) {
+	sort.Slice(memories, func(i, j int) bool {
+		return rankBefore(&memories[i], &memories[j])
+	})
+}
+
+// keywordOrder orders keyword search by the score it gives memories, with
+// rankBefore's tie-breaks, so the same memories make the limit every time
+const keywordOrder = ` ORDER BY importance * %g + feedback * %g + (curated_at IS NOT NULL) * %g - (1 - confidence) * %g DESC,
+	importance DESC, created_at DESC, id ASC`
@@ -0,0 +1,114 @@
+package store
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+func TestRankBefore(t *testing.T) {
+	now := time.Now()
+	base := models.Memory{ID: "b", Score: 0.8, Importance: 0.5, CreatedAt: now}
+	tests := []struct {
+		name string
+		a    models.Memory
+		want bool
+	}{
+		{"higher score", models.Memory{ID: "z", Score: 0.9, Importance: 0.1, CreatedAt: now.Add(-time.Hour)}, true},
+		{"lower score", models.Memory{ID: "a", Score: 0.7, Importance: 0.9, CreatedAt: now.Add(time.Hour)}, false},
+		{"more important", models.Memory{ID: "z", Score: 0.8, Importance: 0.6, CreatedAt: now.Add(-time.Hour)}, true},
+		{"newer", models.Memory{ID: "z", Score: 0.8, Importance: 0.5, CreatedAt: now.Add(time.Second)}, true},
+		{"older", models.Memory{ID: "a", Score: 0.8, Importance: 0.5, CreatedAt: now.Add(-time.Second)}, false},
+		{"smaller ID", models.Memory{ID: "a", Score: 0.8, Importance: 0.5, CreatedAt: now}, true},
+		{"larger ID", models.Memory{ID: "c", Score: 0.8, Importance: 0.5, CreatedAt: now}, false},
+		{"itself", base, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rankBefore(&tt.a, &base); got != tt.want {
+				t.Errorf("rankBefore = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newTiedMemories stores memories that score the same for "backup", in an
+// order other than the one recall must return
+func newTiedMemories(t *testing.T, s *Store) (want []string) {
+	t.Helper()
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tied := func(id string, created time.Time) *models.Memory {
+		m := newTestMemory("nightly backup of the " + id + " database")
+		m.ID = id
+		m.Importance = 0.5
+		m.CreatedAt, m.LastAccessedAt = created, created
+		m.Embedding = []float32{1, 0}
+		return m
+	}
+	createMemories(t, s,
+		tied("m2", start),
+		tied("m3", start.Add(2*time.Hour)),
+		tied("m1", start),
+		tied("m0", start.Add(time.Hour)),
+	)
+	// Equal scores: newest first, then by ID
+	return []string{"m3", "m0", "m1", "m2"}
+}
+
+func TestRecallTieOrder(t *testing.T) {
+	s := newTestStore(t)
+	s.SetRecallCache(0, 0) // Every recall runs the query
+	want := newTiedMemories(t, s)
+
+	searches := map[string]func() ([]models.Memory, error){
+		"keyword": func() ([]models.Memory, error) {
+			return s.Recall(models.RecallRequest{Query: "backup", Limit: 10})
+		},
+		"semantic": func() ([]models.Memory, error) {
+			return s.SemanticSearch(models.RecallRequest{Query: "backup", Limit: 10}, []float32{1, 0})
+		},
+		"hybrid": func() ([]models.Memory, error) {
+			return s.HybridSearch(models.RecallRequest{Query: "backup", Limit: 10}, []float32{1, 0})
+		},
+	}
+	for name, search := range searches {
+		t.Run(name, func(t *testing.T) {
+			// Recalling raises importance, equally for all of them, so
+			// repeated recalls still tie
+			for i := 0; i < 3; i++ {
+				results, err := search()
+				if err != nil {
+					t.Fatalf("search: %v", err)
+				}
+				if got := memoryIDs(results); !slices.Equal(got, want) {
+					t.Fatalf("recall %d = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+
+	// A limit cuts the same tail every time
+	results, err := s.Recall(models.RecallRequest{Query: "backup", Limit: 2})
+	if err != nil {
+		t.Fatalf("Recall: %v", err)
+	}
+	if got := memoryIDs(results); !slices.Equal(got, want[:2]) {
+		t.Errorf("Recall with limit 2 = %v, want %v", got, want[:2])
+	}
+}
+
+func TestListMemoriesTieOrder(t *testing.T) {
+	s := newTestStore(t)
+	newTiedMemories(t, s)
+
+	memories, err := s.ListMemories(ListOptions{})
+	if err != nil {
+		t.Fatalf("ListMemories: %v", err)
+	}
+	// Oldest first, ties by ID
+	if got, want := memoryIDs(memories), []string{"m1", "m2", "m0", "m3"}; !slices.Equal(got, want) {
+		t.Errorf("ListMemories = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrReadOnly is returned by writes to a store opened with OpenReadOnly
+var ErrReadOnly = errors.New("store is read-only")
+
+// OpenReadOnly opens an existing database for searching only. It is not
+// migrated, so its schema must already be current; writes, including the
+// access statistics and access log recall normally records, fail with
+// ErrReadOnly.
+func OpenReadOnly(dbPath string) (*Store, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite3", dbPath+"?mode=ro&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	version, err := schemaVersion(db)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to read schema version: %w", classifyCorruption(err))
+	}
+	if latest := LatestSchemaVersion(); version < latest {
+		db.Close()
+		return nil, fmt.Errorf("database schema is at version %d but %d is needed; migrate it first", version, latest)
+	}
+
+	s := &Store{
+		db:                db,
+		readOnly:          true,
+		accessLog:         make(chan AccessLogEntry),
+		accessLogDone:     make(chan struct{}),
+		accessLogDisabled: true,
+		cache:             newRecallCache(DefaultRecallCacheSize, DefaultRecallCacheTTL),
+	}
+	if err := s.loadTokenizer(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to load tokenizer: %w", err)
+	}
+	if err := s.loadConfidenceWeight(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to load confidence weight: %w", err)
+	}
+	if err := s.loadImportanceFloor(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to load importance floor: %w", err)
+	}
+
+	go s.runAccessLog()
+
+	return s, nil
+}
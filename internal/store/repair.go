@@ -0,0 +1,260 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrCorrupt is returned by New when the database fails its integrity
+// check or can't be read as a database. Repair recovers what it can.
+var ErrCorrupt = errors.New("database is corrupt")
+
+// maxIntegrityProblems caps how many problems the integrity check reports
+const maxIntegrityProblems = 10
+
+// RepairResult reports what Repair recovered
+type RepairResult struct {
+	Backup    string         `json:"backup"`          // Where the damaged database was moved
+	Recovered map[string]int `json:"recovered"`       // Rows copied into the new database, per table
+	Partial   []string       `json:"partial"`         // Tables that could only be read in part
+	Fresh     bool           `json:"fresh"`           // Nothing was readable; the new database is empty
+	Error     string         `json:"error,omitempty"` // Why the damaged database couldn't be read, if Fresh
+}
+
+// CheckIntegrity runs SQLite's integrity check on the database at dbPath.
+// It returns an error wrapping ErrCorrupt describing the problems found.
+func CheckIntegrity(dbPath string) error {
+	db, err := sql.Open("sqlite3", dbPath+"?_busy_timeout=5000")
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+	return checkIntegrity(db)
+}
+
+// checkIntegrity runs PRAGMA integrity_check, classifying SQLite's corrupt
+// and not-a-database errors as ErrCorrupt
+func checkIntegrity(db *sql.DB) error {
+	return runCheck(db, "integrity_check")
+}
+
+// quickCheck runs PRAGMA quick_check, which skips the index cross-checks of
+// integrity_check and so stays cheap enough to run on every open
+func quickCheck(db *sql.DB) error {
+	return runCheck(db, "quick_check")
+}
+
+// runCheck runs one of SQLite's check pragmas, reporting its problems as an
+// error wrapping ErrCorrupt
+func runCheck(db *sql.DB, pragma string) error {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA %s(%d)", pragma, maxIntegrityProblems))
+	if err != nil {
+		return classifyCorruption(err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return classifyCorruption(err)
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return classifyCorruption(err)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrCorrupt, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Repair recovers a damaged database. The original file (with its WAL and
+// shared memory files) is moved aside to a timestamped backup, a new
+// database is created at dbPath, and every row that can still be read from
+// the backup is copied into it. If nothing can be read, the new database is
+// left empty. The backup is never deleted.
+func Repair(dbPath string) (*RepairResult, error) {
+	result := &RepairResult{
+		Backup:    fmt.Sprintf("%s.corrupt-%s", dbPath, time.Now().Format("20060102-150405")),
+		Recovered: make(map[string]int),
+	}
+	// Never overwrite an earlier backup
+	base := result.Backup
+	for i := 2; fileExists(result.Backup); i++ {
+		result.Backup = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if err := os.Rename(dbPath+suffix, result.Backup+suffix); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to back up %s: %w", dbPath+suffix, err)
+		}
+	}
+
+	s, err := New(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new database: %w", err)
+	}
+	defer s.Close()
+
+	old, err := sql.Open("sqlite3", result.Backup+"?_busy_timeout=5000")
+	if err != nil {
+		result.Fresh, result.Error = true, err.Error()
+		return result, nil
+	}
+	defer old.Close()
+
+	tables, err := tableNames(old)
+	if err != nil {
+		result.Fresh, result.Error = true, err.Error()
+		return result, nil
+	}
+
+	for _, table := range tables {
+		if table == "schema_version" || strings.HasPrefix(table, "sqlite_") {
+			continue
+		}
+		n, err := s.copyTable(old, table)
+		if n > 0 {
+			result.Recovered[table] = n
+		}
+		if err != nil {
+			result.Partial = append(result.Partial, table)
+		}
+	}
+
+	// The keyword index may be missing from rows copied out of an older
+	// schema, and the tokenizer settings may have been recovered
+	tx, err := s.begin()
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+	tok, err := s.tokenizerFor(tx)
+	if err != nil {
+		return result, err
+	}
+	if err := indexKeywords(tx.Tx, tok, ""); err != nil {
+		return result, err
+	}
+	return result, tx.Commit()
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// tableNames lists the tables of a database
+func tableNames(db *sql.DB) ([]string, error) {
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type = 'table' ORDER BY rootpage")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// columnNames lists the columns of a table, or none if it doesn't exist
+func columnNames(db *sql.DB, table string) ([]string, error) {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// copyTable copies the readable rows of a table in old into the same table
+// of the store, using the columns both have. Reading stops at the first
+// damaged page; the rows copied so far are kept and the read error returned.
+func (s *Store) copyTable(old *sql.DB, table string) (int, error) {
+	oldColumns, err := columnNames(old, table)
+	if err != nil {
+		return 0, err
+	}
+	newColumns, err := columnNames(s.db, table)
+	if err != nil {
+		return 0, err
+	}
+	present := make(map[string]bool)
+	for _, c := range newColumns {
+		present[c] = true
+	}
+	var columns []string
+	for _, c := range oldColumns {
+		if present[c] {
+			columns = append(columns, `"`+c+`"`)
+		}
+	}
+	if len(columns) == 0 {
+		return 0, nil
+	}
+
+	list := strings.Join(columns, ", ")
+	rows, err := old.Query(`SELECT ` + list + ` FROM "` + table + `"`)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	tx, err := s.begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	insert := `INSERT OR IGNORE INTO "` + table + `" (` + list + `) VALUES (` +
+		strings.TrimSuffix(strings.Repeat("?,", len(columns)), ",") + `)`
+	values := make([]interface{}, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+
+	n := 0
+	var readErr error
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			readErr = err
+			break
+		}
+		if _, err := tx.Exec(insert, values...); err != nil {
+			continue
+		}
+		n++
+	}
+	if readErr == nil {
+		readErr = rows.Err()
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, readErr
+}
@@ -0,0 +1,20 @@
+//go:build cgo
+
+package store
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// classifyCorruption wraps SQLite errors that indicate a damaged file in
+// ErrCorrupt and returns others unchanged
+func classifyCorruption(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && (sqliteErr.Code == sqlite3.ErrCorrupt || sqliteErr.Code == sqlite3.ErrNotADB) {
+		return fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	return err
+}
@@ -0,0 +1,9 @@
+//go:build !cgo
+
+package store
+
+// classifyCorruption returns err unchanged: without cgo the SQLite driver
+// is a stub that can't open databases, so no error indicates corruption
+func classifyCorruption(err error) error {
+	return err
+}
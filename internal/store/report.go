@@ -0,0 +1,143 @@
+package store
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// DefaultReportSamples is how many memory IDs each quality check lists
+const DefaultReportSamples = 5
+
+// blankChars are the characters content made only of is empty
+const blankChars = "' ' || char(9) || char(10) || char(13)"
+
+// QualityIssue is what one quality check found: how many memories it
+// flagged, and the IDs of the most important of them
+type QualityIssue struct {
+	Count   int      `json:"count"`
+	Samples []string `json:"samples"`
+}
+
+// QualityReport is the result of checking the store for memories that need
+// cleaning up
+type QualityReport struct {
+	Total        int          `json:"total"`
+	NoTopics     QualityIssue `json:"noTopics"`
+	NoEmbedding  QualityIssue `json:"noEmbedding"`
+	EmptyContent QualityIssue `json:"emptyContent"`
+	Expired      QualityIssue `json:"expired"` // Past their expiry but not swept yet
+	// Duplicates counts the memories whose content repeats an older one's,
+	// ignoring case and surrounding whitespace. DuplicateGroups lists the
+	// largest groups, oldest memory first.
+	Duplicates      QualityIssue `json:"duplicates"`
+	DuplicateGroups [][]string   `json:"duplicateGroups"`
+}
+
+// Clean reports whether no check flagged any memory
+func (r *QualityReport) Clean() bool {
+	return r.NoTopics.Count == 0 && r.NoEmbedding.Count == 0 && r.EmptyContent.Count == 0 &&
+		r.Expired.Count == 0 && r.Duplicates.Count == 0
+}
+
+// QualityReport checks the store for memories without topics, without
+// embeddings, with blank content, past their expiry, or duplicating
+// another, listing up to samples IDs (or duplicate groups) for each check
+func (s *Store) QualityReport(samples int) (*QualityReport, error) {
+	if samples <= 0 {
+		samples = DefaultReportSamples
+	}
+
+	report := &QualityReport{DuplicateGroups: [][]string{}}
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM memories").Scan(&report.Total); err != nil {
+		return nil, err
+	}
+
+	checks := []struct {
+		issue *QualityIssue
+		where string
+	}{
+		{&report.NoTopics, "topics IS NULL OR topics IN ('', '[]', 'null')"},
+		{&report.NoEmbedding, "embedding IS NULL"},
+		{&report.EmptyContent, "trim(content, " + blankChars + ") = ''"},
+		{&report.Expired, "expires_at IS NOT NULL AND datetime(expires_at) <= datetime('now')"},
+	}
+	for _, c := range checks {
+		issue, err := s.qualityIssue(c.where, samples)
+		if err != nil {
+			return nil, err
+		}
+		*c.issue = issue
+	}
+
+	if err := s.findDuplicates(report, samples); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// qualityIssue counts the memories matching where and lists the IDs of the
+// most important, newest first among equals
+func (s *Store) qualityIssue(where string, samples int) (QualityIssue, error) {
+	issue := QualityIssue{Samples: []string{}}
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM memories WHERE " + where).Scan(&issue.Count); err != nil {
+		return issue, err
+	}
+	if issue.Count == 0 {
+		return issue, nil
+	}
+
+	rows, err := s.db.Query("SELECT id FROM memories WHERE "+where+
+		" ORDER BY importance DESC, created_at DESC, id ASC LIMIT ?", samples)
+	if err != nil {
+		return issue, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return issue, err
+		}
+		issue.Samples = append(issue.Samples, id)
+	}
+	return issue, rows.Err()
+}
+
+// findDuplicates fills in the report's duplicates: memories with the same
+// content, ignoring case and surrounding whitespace. Blank content is
+// reported as empty instead.
+func (s *Store) findDuplicates(report *QualityReport, samples int) error {
+	normalized := "lower(trim(content, " + blankChars + "))"
+	rows, err := s.db.Query(`SELECT COUNT(*), group_concat(id, ' ') FROM (
+			SELECT id, ` + normalized + ` AS normalized FROM memories
+			WHERE ` + normalized + ` != ''
+			ORDER BY created_at ASC, id ASC
+		)
+		GROUP BY normalized HAVING COUNT(*) > 1
+		ORDER BY COUNT(*) DESC, MIN(id) ASC`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	report.Duplicates.Samples = []string{}
+	for rows.Next() {
+		var count int
+		var ids sql.NullString
+		if err := rows.Scan(&count, &ids); err != nil {
+			return err
+		}
+		report.Duplicates.Count += count - 1
+		if len(report.DuplicateGroups) < samples {
+			group := strings.Fields(ids.String)
+			report.DuplicateGroups = append(report.DuplicateGroups, group)
+			// The copies, not the original, are what to clean up
+			if len(report.Duplicates.Samples) < samples {
+				report.Duplicates.Samples = append(report.Duplicates.Samples, group[1:]...)
+				if len(report.Duplicates.Samples) > samples {
+					report.Duplicates.Samples = report.Duplicates.Samples[:samples]
+				}
+			}
+		}
+	}
+	return rows.Err()
+}
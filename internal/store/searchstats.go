@@ -0,0 +1,33 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// SearchStats describes how a hybrid search arrived at its results
+type SearchStats struct {
+	Cached   bool          `json:"cached"`   // Served from the recall cache
+	Semantic int           `json:"semantic"` // Candidates from semantic search
+	Keyword  int           `json:"keyword"`  // Candidates from keyword search
+	Merged   int           `json:"merged"`   // Distinct candidates after merging
+	Reranked bool          `json:"reranked"`
+	Duration time.Duration `json:"-"`
+}
+
+type searchStatsKey struct{}
+
+// WithSearchStats returns a context that makes HybridSearchContext fill in
+// stats as it searches
+func WithSearchStats(ctx context.Context, stats *SearchStats) context.Context {
+	return context.WithValue(ctx, searchStatsKey{}, stats)
+}
+
+// searchStatsFrom returns the stats to fill in for ctx, or a throwaway value
+// when the caller didn't ask for them
+func searchStatsFrom(ctx context.Context) *SearchStats {
+	if stats, ok := ctx.Value(searchStatsKey{}).(*SearchStats); ok && stats != nil {
+		return stats
+	}
+	return &SearchStats{}
+}
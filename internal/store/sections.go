@@ -0,0 +1,85 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// SectionMetadataKey is the metadata key holding the heading a memory was
+// imported from. Together with the source reference it identifies the
+// section on re-import.
+const SectionMetadataKey = "heading"
+
+// SectionAction is what UpsertSection did
+type SectionAction string
+
+const (
+	SectionCreated   SectionAction = "created"
+	SectionUpdated   SectionAction = "updated"
+	SectionUnchanged SectionAction = "unchanged"
+)
+
+// UpsertSection stores a memory imported from a document section. If a
+// memory with the same source type, source reference and heading
+// (Metadata[SectionMetadataKey]) exists, its content, summary and topics are
+// updated instead, keeping the previous content in its history.
+func (s *Store) UpsertSection(m *models.Memory) (SectionAction, error) {
+	if err := s.normalizeMemoryTopics(m); err != nil {
+		return "", err
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var id, content, summary string
+	var topicsJSON sql.NullString
+	err = tx.QueryRow(`
+		SELECT id, content, summary, topics FROM memories
+		WHERE source_type = ? AND source_reference = ? AND json_extract(metadata, '$.`+SectionMetadataKey+`') = ?
+		ORDER BY created_at LIMIT 1
+	`, m.Source.Type, m.Source.Reference, m.Metadata[SectionMetadataKey]).Scan(&id, &content, &summary, &topicsJSON)
+
+	if err == sql.ErrNoRows {
+		if err := s.insertMemory(tx, m); err != nil {
+			return "", err
+		}
+		if err := tx.Commit(); err != nil {
+			return "", err
+		}
+		countCreated(m)
+		return SectionCreated, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var topics []string
+	if topicsJSON.Valid {
+		json.Unmarshal([]byte(topicsJSON.String), &topics)
+	}
+
+	m.ID = id
+	action := SectionUnchanged
+	if content != m.Content || summary != m.Summary {
+		if err := s.updateContent(tx.Tx, id, m.Content, m.Summary, "import"); err != nil {
+			return "", err
+		}
+		action = SectionUpdated
+	}
+	if !slices.Equal(topics, m.Topics) {
+		newTopics, _ := json.Marshal(m.Topics)
+		if _, err := tx.Exec(`UPDATE memories SET topics = ?, updated_at = ? WHERE id = ?`,
+			string(newTopics), time.Now(), id); err != nil {
+			return "", err
+		}
+		action = SectionUpdated
+	}
+	return action, tx.Commit()
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// MemoryEmbedding returns the stored embedding of a memory, or nil if it
+// has none
+func (s *Store) MemoryEmbedding(id string) ([]float32, error) {
+	embeddings, err := s.embeddings([]string{id})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[id], nil
+}
+
+// Similar returns the memories whose embeddings are closest to emb, most
+// similar first, leaving out the memory id. Score is the cosine similarity
+// alone, without the importance and feedback weighting of recall.
+func (s *Store) Similar(id string, emb []float32, limit int) ([]models.Memory, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+
+	filters, args := filterClause(models.RecallRequest{})
+	rows, err := s.db.Query("SELECT "+memoryColumns+", embedding FROM memories WHERE embedding IS NOT NULL AND id != ?"+filters,
+		append([]interface{}{id}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memories []models.Memory
+	for rows.Next() {
+		var blob []byte
+		m, err := scanMemory(rowWithExtra{rows, &blob})
+		if err != nil {
+			return nil, err
+		}
+		if len(blob) == 0 {
+			continue
+		}
+		m.Score = cosineSimilarity(emb, decodeEmbedding(blob))
+		memories = append(memories, *m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sortByRank(memories)
+	return truncate(memories, limit), nil
+}
@@ -0,0 +1,196 @@
+package store
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// snapshotPrefix and snapshotExt frame the timestamp in snapshot file names
+const (
+	snapshotPrefix = "memories-"
+	snapshotExt    = ".db"
+)
+
+// snapshotTimeFormat is the timestamp format of snapshot file names
+const snapshotTimeFormat = "20060102-150405"
+
+// Snapshot describes a database snapshot
+type Snapshot struct {
+	Name      string    `json:"name"` // File name, which restore accepts
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"` // In bytes
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CreateSnapshot writes a consistent copy of the database into dir, named
+// after the current time, with SQLite's VACUUM INTO. Writers can keep
+// going while it runs; the snapshot holds every table exactly as it was
+// when the copy started.
+func (s *Store) CreateSnapshot(dir string) (*Snapshot, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	now := time.Now()
+	base := snapshotPrefix + now.Format(snapshotTimeFormat)
+	path := filepath.Join(dir, base+snapshotExt)
+	// Never overwrite an earlier snapshot taken the same second
+	for i := 2; fileExists(path); i++ {
+		path = filepath.Join(dir, fmt.Sprintf("%s-%d%s", base, i, snapshotExt))
+	}
+
+	if _, err := s.db.Exec("VACUUM INTO ?", path); err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{Name: info.Name(), Path: path, Size: info.Size(), CreatedAt: now}, nil
+}
+
+// ListSnapshots lists the snapshots in dir, oldest first. A missing
+// directory holds none.
+func ListSnapshots(dir string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []Snapshot
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, snapshotPrefix) || !strings.HasSuffix(name, snapshotExt) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, Snapshot{
+			Name:      name,
+			Path:      filepath.Join(dir, name),
+			Size:      info.Size(),
+			CreatedAt: snapshotTime(name, info.ModTime()),
+		})
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		if !snapshots[i].CreatedAt.Equal(snapshots[j].CreatedAt) {
+			return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt)
+		}
+		// Snapshots taken the same second are numbered in order
+		if len(snapshots[i].Name) != len(snapshots[j].Name) {
+			return len(snapshots[i].Name) < len(snapshots[j].Name)
+		}
+		return snapshots[i].Name < snapshots[j].Name
+	})
+	return snapshots, nil
+}
+
+// snapshotTime reads when a snapshot was taken from its name, falling back
+// to modTime for files named otherwise
+func snapshotTime(name string, modTime time.Time) time.Time {
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, snapshotPrefix), snapshotExt)
+	if len(stamp) > len(snapshotTimeFormat) {
+		stamp = stamp[:len(snapshotTimeFormat)]
+	}
+	if t, err := time.ParseInLocation(snapshotTimeFormat, stamp, time.Local); err == nil {
+		return t
+	}
+	return modTime
+}
+
+// FindSnapshot resolves a snapshot given by name (as listed) or path
+func FindSnapshot(dir, nameOrPath string) (string, error) {
+	candidates := []string{nameOrPath}
+	if filepath.Base(nameOrPath) == nameOrPath {
+		candidates = []string{filepath.Join(dir, nameOrPath), filepath.Join(dir, nameOrPath+snapshotExt)}
+	}
+	for _, path := range candidates {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("snapshot %s not found (see 'memorypilot snapshot list')", nameOrPath)
+}
+
+// RestoreSnapshot replaces the database at dbPath with a copy of the
+// snapshot at snapshotPath, after checking the snapshot's integrity. The
+// database being replaced (with its WAL and shared memory files) is moved
+// aside to a timestamped file, whose path is returned, so a restore can be
+// undone. The restored database is migrated to the current schema. Nothing
+// may have the database open while it runs.
+func RestoreSnapshot(dbPath, snapshotPath string) (string, error) {
+	if err := CheckIntegrity(snapshotPath); err != nil {
+		return "", fmt.Errorf("snapshot failed the integrity check: %w", err)
+	}
+
+	backup := fmt.Sprintf("%s.pre-restore-%s", dbPath, time.Now().Format(snapshotTimeFormat))
+	base := backup
+	for i := 2; fileExists(backup); i++ {
+		backup = fmt.Sprintf("%s-%d", base, i)
+	}
+	var moved []string
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		err := os.Rename(dbPath+suffix, backup+suffix)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			undoMoves(dbPath, backup, moved)
+			return "", fmt.Errorf("failed to move %s aside: %w", dbPath+suffix, err)
+		}
+		moved = append(moved, suffix)
+	}
+
+	if err := copyFile(snapshotPath, dbPath); err != nil {
+		os.Remove(dbPath)
+		undoMoves(dbPath, backup, moved)
+		return "", fmt.Errorf("failed to copy snapshot: %w", err)
+	}
+
+	s, err := New(dbPath)
+	if err != nil {
+		return backup, fmt.Errorf("restored database failed to open: %w", err)
+	}
+	return backup, s.Close()
+}
+
+// undoMoves moves the files RestoreSnapshot moved aside back in place
+func undoMoves(dbPath, backup string, suffixes []string) {
+	for _, suffix := range suffixes {
+		os.Rename(backup+suffix, dbPath+suffix)
+	}
+}
+
+// copyFile copies src to a new file dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// GetBySourceRef returns the oldest memory with the given source type and
+// reference, or nil if there is none
+func (s *Store) GetBySourceRef(sourceType models.SourceType, ref string) (*models.Memory, error) {
+	row := s.db.QueryRow("SELECT "+memoryColumns+" FROM memories WHERE source_type = ? AND source_reference = ? ORDER BY created_at LIMIT 1",
+		sourceType, ref)
+	m, err := scanMemory(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return m, err
+}
+
+// isCommitRef reports whether a memory's source is a git commit SHA (at
+// least 7 hex digits), optionally followed by "#n" for the nth memory taken
+// from the same commit
+func isCommitRef(src models.Source) bool {
+	if src.Type != models.SourceTypeGit {
+		return false
+	}
+	sha, n, found := strings.Cut(src.Reference, "#")
+	if found {
+		if i, err := strconv.Atoi(n); err != nil || i < 1 {
+			return false
+		}
+	}
+	if len(sha) < 7 || len(sha) > 64 {
+		return false
+	}
+	for _, c := range sha {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// updateBySourceRef updates the memory with m's source type and reference,
+// if there is one, to m's content, summary and topics, keeping the previous
+// content in its history, and gives m its ID. It reports whether there was
+// such a memory.
+func (s *Store) updateBySourceRef(tx *writeTx, m *models.Memory) (bool, error) {
+	var id, content, summary string
+	var topicsJSON sql.NullString
+	err := tx.QueryRow(`
+		SELECT id, content, summary, topics FROM memories
+		WHERE source_type = ? AND source_reference = ?
+		ORDER BY created_at LIMIT 1
+	`, m.Source.Type, m.Source.Reference).Scan(&id, &content, &summary, &topicsJSON)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var topics []string
+	if topicsJSON.Valid {
+		json.Unmarshal([]byte(topicsJSON.String), &topics)
+	}
+
+	m.ID = id
+	if content != m.Content || summary != m.Summary {
+		if err := s.updateContent(tx.Tx, id, m.Content, m.Summary, string(models.SourceTypeGit)); err != nil {
+			return false, err
+		}
+	}
+	if !slices.Equal(topics, m.Topics) {
+		newTopics, _ := json.Marshal(m.Topics)
+		if _, err := tx.Exec(`UPDATE memories SET topics = ?, updated_at = ? WHERE id = ?`,
+			string(newTopics), time.Now(), id); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// newCommitMemory returns a memory captured from the git commit sha
+func newCommitMemory(sha, content string) *models.Memory {
+	m := newTestMemory(content)
+	m.Source = models.Source{Type: models.SourceTypeGit, Reference: sha}
+	m.Importance = 0.6
+	return m
+}
+
+func TestCommitCapturedTwiceIsStoredOnce(t *testing.T) {
+	s := newTestStore(t)
+	const sha = "3f2a9c1d8e7b6a5f4e3d2c1b0a9f8e7d6c5b4a39"
+
+	first := newCommitMemory(sha, "Fix token refresh race in auth client")
+	createMemories(t, s, first)
+
+	// A daemon restart rescans history and captures the commit again
+	again := newCommitMemory(sha, "Fix token refresh race in auth client")
+	createMemories(t, s, again)
+	if again.ID != first.ID {
+		t.Errorf("recaptured commit got ID %s, want the existing %s", again.ID, first.ID)
+	}
+
+	stats, err := s.GetStats()
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.TotalMemories != 1 {
+		t.Fatalf("store holds %d memories after capturing one commit twice, want 1", stats.TotalMemories)
+	}
+
+	m, err := s.GetBySourceRef(models.SourceTypeGit, sha)
+	if err != nil {
+		t.Fatalf("GetBySourceRef: %v", err)
+	}
+	if m == nil || m.ID != first.ID {
+		t.Fatalf("GetBySourceRef = %v, want %s", m, first.ID)
+	}
+
+	// An amended message updates the memory in place, keeping the old one
+	// in its history
+	amended := newCommitMemory(sha, "Fix token refresh race in the auth client")
+	createMemories(t, s, amended)
+	m, err = s.GetMemory(first.ID)
+	if err != nil {
+		t.Fatalf("GetMemory: %v", err)
+	}
+	if m.Content != amended.Content {
+		t.Errorf("content = %q, want the amended %q", m.Content, amended.Content)
+	}
+	history, err := s.GetHistory(first.ID)
+	if err != nil {
+		t.Fatalf("GetHistory: %v", err)
+	}
+	if len(history) != 1 || history[0].Content != first.Content {
+		t.Errorf("history = %+v, want the original content", history)
+	}
+}
+
+func TestDistinctSourceRefsAreKeptApart(t *testing.T) {
+	s := newTestStore(t)
+
+	createMemories(t, s,
+		newCommitMemory("3f2a9c1", "Fix token refresh race"),
+		newCommitMemory("3f2a9c1#2", "Use a mutex around the refresh"),
+		newCommitMemory("9b8c7d6", "Fix token refresh race"),
+	)
+	// Only commit SHAs are keys; other git references may repeat
+	createMemories(t, s,
+		newCommitMemory("main", "Branch note"),
+		newCommitMemory("main", "Branch note"),
+	)
+
+	stats, err := s.GetStats()
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.TotalMemories != 5 {
+		t.Errorf("store holds %d memories, want 5", stats.TotalMemories)
+	}
+}
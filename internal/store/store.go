@@ -1,20 +1,83 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/contextpilot-dev/memorypilot/internal/contenttype"
+	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+	"github.com/contextpilot-dev/memorypilot/internal/ids"
+	"github.com/contextpilot-dev/memorypilot/internal/rerank"
+	"github.com/contextpilot-dev/memorypilot/internal/tokenize"
+	"github.com/contextpilot-dev/memorypilot/internal/tracing"
 	"github.com/contextpilot-dev/memorypilot/pkg/models"
 )
 
-// Store handles all database operations
+// maxHistoryPerMemory caps how many prior versions are kept per memory
+const maxHistoryPerMemory = 20
+
+// writableOptions are the connection options of databases opened for
+// writing. Transactions begin immediately, taking the write lock (or
+// waiting for it) up front, since they are only used to write.
+const writableOptions = "?_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate"
+
+// idempotencyWindow is how long an idempotency key maps to the memory it
+// created; a key reused after this creates a new memory
+const idempotencyWindow = 24 * time.Hour
+
+// matchWeight and importanceWeight weight how well a memory matches the
+// query (cosine similarity, or 1 for a keyword match) and its importance in
+// recall scores, before feedback, curation and confidence adjust them
+const (
+	matchWeight      = 0.7
+	importanceWeight = 0.3
+)
+
+// importanceScore is the recall score contribution of a memory's importance
+func importanceScore(m *models.Memory) float32 {
+	return float32(m.Importance * importanceWeight)
+}
+
+// rerankCandidates is the minimum number of hybrid candidates passed to the
+// reranker, so it has more to choose from than a small limit
+const rerankCandidates = 20
+
+// Store handles all database operations. It is safe for concurrent use
+// once configured; see concurrency.go.
 type Store struct {
-	db *sql.DB
+	db        *sql.DB
+	reranker  rerank.Reranker
+	tokenizer *tokenize.Tokenizer // Keyword search tokenizer, configured in the database
+
+	tokenizerMu    sync.Mutex // Guards tokenizer and tokenizerValue
+	tokenizerValue string     // Stored setting the tokenizer was built from
+
+	confidenceWeight float64 // Weight of confidence in recall scores, configured in the database
+	importanceFloor  float64 // Importance auto-captured memories need to be recalled, configured in the database
+
+	writeMu sync.Mutex   // Serializes writes; see the concurrency contract
+	cache   *recallCache // Recent recall results; nil if disabled
+
+	accessLog         chan AccessLogEntry
+	accessLogDone     chan struct{}
+	accessLogDisabled bool
+
+	closeMu sync.Mutex // Guards closed, so a late recall can't log to the closed queue
+	closed  bool
+
+	readOnly bool // Opened with OpenReadOnly; writes fail with ErrReadOnly
 }
 
 // Stats represents store statistics
@@ -23,94 +86,85 @@ type Stats struct {
 	ByType        map[string]int `json:"byType"`
 	ProjectCount  int            `json:"projectCount"`
 	DaemonRunning bool           `json:"daemonRunning"`
+
+	Embedded        int `json:"embedded"`
+	Unembedded      int `json:"unembedded"`
+	StaleEmbeddings int `json:"staleEmbeddings"`
 }
 
 // New creates a new store instance
 func New(dbPath string) (*Store, error) {
-	db, err := sql.Open("sqlite3", dbPath+"?_journal_mode=WAL&_busy_timeout=5000")
+	db, err := sql.Open("sqlite3", dbPath+writableOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	s := &Store{db: db}
-	if err := s.migrate(); err != nil {
+	// Refuse to write to a damaged file, which could lose more data
+	if err := quickCheck(db); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to migrate database: %w", err)
+		return nil, err
+	}
+
+	s := &Store{
+		db:            db,
+		accessLog:     make(chan AccessLogEntry, accessLogBuffer),
+		accessLogDone: make(chan struct{}),
+		cache:         newRecallCache(DefaultRecallCacheSize, DefaultRecallCacheTTL),
+	}
+	if _, err := s.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", classifyCorruption(err))
+	}
+	if err := s.loadTokenizer(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to load tokenizer: %w", err)
 	}
+	if err := s.loadConfidenceWeight(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to load confidence weight: %w", err)
+	}
+	if err := s.loadImportanceFloor(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to load importance floor: %w", err)
+	}
+	if err := s.backfill(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to backfill memories: %w", err)
+	}
+
+	go s.runAccessLog()
 
 	return s, nil
 }
 
-// Close closes the database connection
-func (s *Store) Close() error {
-	return s.db.Close()
+// SetReranker enables reranking of hybrid search candidates. Pass nil to
+// disable it.
+func (s *Store) SetReranker(r rerank.Reranker) {
+	s.reranker = r
 }
 
-// migrate runs database migrations
-func (s *Store) migrate() error {
-	migrations := []string{
-		// Projects table
-		`CREATE TABLE IF NOT EXISTS projects (
-			id TEXT PRIMARY KEY,
-			name TEXT NOT NULL,
-			path TEXT UNIQUE NOT NULL,
-			git_remote TEXT,
-			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-			last_seen DATETIME DEFAULT CURRENT_TIMESTAMP
-		)`,
-
-		// Memories table
-		`CREATE TABLE IF NOT EXISTS memories (
-			id TEXT PRIMARY KEY,
-			type TEXT NOT NULL CHECK (type IN ('decision','pattern','fact','preference','mistake','learning')),
-			content TEXT NOT NULL,
-			summary TEXT NOT NULL,
-			scope TEXT NOT NULL DEFAULT 'personal' CHECK (scope IN ('personal','project','team','org')),
-			project_id TEXT REFERENCES projects(id),
-			team_id TEXT,
-			
-			source_type TEXT NOT NULL,
-			source_reference TEXT,
-			source_timestamp DATETIME,
-			
-			confidence REAL NOT NULL DEFAULT 0.8,
-			importance REAL NOT NULL DEFAULT 1.0,
-			
-			topics TEXT,
-			related_memories TEXT,
-			embedding BLOB,
-			
-			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-			last_accessed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-			access_count INTEGER DEFAULT 0,
-			expires_at DATETIME
-		)`,
-
-		// Events table
-		`CREATE TABLE IF NOT EXISTS events (
-			id TEXT PRIMARY KEY,
-			type TEXT NOT NULL,
-			timestamp DATETIME NOT NULL,
-			data TEXT,
-			project_id TEXT REFERENCES projects(id),
-			processed_at DATETIME
-		)`,
-
-		// Indexes
-		`CREATE INDEX IF NOT EXISTS idx_memories_project ON memories(project_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_memories_type ON memories(type)`,
-		`CREATE INDEX IF NOT EXISTS idx_memories_scope ON memories(scope)`,
-		`CREATE INDEX IF NOT EXISTS idx_memories_importance ON memories(importance DESC)`,
-		`CREATE INDEX IF NOT EXISTS idx_memories_created ON memories(created_at DESC)`,
-	}
-
-	for _, migration := range migrations {
-		if _, err := s.db.Exec(migration); err != nil {
-			return fmt.Errorf("migration failed: %w", err)
-		}
+// Close flushes the access log and closes the database connection. Calls
+// after the first do nothing.
+func (s *Store) Close() error {
+	s.closeMu.Lock()
+	if s.closed {
+		s.closeMu.Unlock()
+		return nil
 	}
+	s.closed = true
+	close(s.accessLog)
+	s.closeMu.Unlock()
 
-	return nil
+	<-s.accessLogDone
+	return s.db.Close()
+}
+
+// PendingEmbeddings returns how many memories are waiting for an embedding
+func (s *Store) PendingEmbeddings() (int, error) {
+	clause, args := filterClause(models.RecallRequest{})
+	var n int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM memories WHERE embedding IS NULL"+clause, args...).Scan(&n)
+	return n, err
 }
 
 // GetStats returns store statistics
@@ -147,83 +201,836 @@ func (s *Store) GetStats() (*Stats, error) {
 		return nil, err
 	}
 
+	// Embedding coverage
+	embeddings, err := s.GetEmbeddingStats()
+	if err != nil {
+		return nil, err
+	}
+	stats.Embedded = embeddings.Embedded
+	stats.Unembedded = embeddings.Unembedded
+	stats.StaleEmbeddings = embeddings.Stale
+
 	return stats, nil
 }
 
-// CreateMemory stores a new memory
+// ProjectStats counts the memories of one project by type. Memories not
+// attached to a project are grouped under an empty ProjectID.
+type ProjectStats struct {
+	ProjectID string         `json:"projectId"`
+	Name      string         `json:"name"`
+	Path      string         `json:"path,omitempty"`
+	Total     int            `json:"total"`
+	ByType    map[string]int `json:"byType"`
+}
+
+// StatsByProject returns memory counts per project and type, projects with
+// the most memories first. Tracked projects without memories are included
+// with zero counts.
+func (s *Store) StatsByProject() ([]ProjectStats, error) {
+	rows, err := s.db.Query(`
+		SELECT p.id, p.name, p.path, m.type, COUNT(m.id)
+		FROM projects p LEFT JOIN memories m ON m.project_id = p.id
+		GROUP BY p.id, m.type
+		UNION ALL
+		SELECT '', '', '', type, COUNT(*)
+		FROM memories WHERE project_id IS NULL OR project_id NOT IN (SELECT id FROM projects)
+		GROUP BY type
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[string]*ProjectStats)
+	var stats []*ProjectStats
+	for rows.Next() {
+		var id, name, path string
+		var memType sql.NullString
+		var count int
+		if err := rows.Scan(&id, &name, &path, &memType, &count); err != nil {
+			return nil, err
+		}
+		ps, ok := byID[id]
+		if !ok {
+			ps = &ProjectStats{ProjectID: id, Name: name, Path: path, ByType: make(map[string]int)}
+			byID[id] = ps
+			stats = append(stats, ps)
+		}
+		if memType.Valid {
+			ps.ByType[memType.String] += count
+			ps.Total += count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]ProjectStats, len(stats))
+	for i, ps := range stats {
+		result[i] = *ps
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// CreateMemory stores a new memory. Git memories whose source reference is
+// a commit SHA are keyed by it: if one with the same reference exists, its
+// content, summary and topics are updated instead and m takes its ID, so
+// capturing a commit again doesn't duplicate it.
 func (s *Store) CreateMemory(m *models.Memory) error {
+	if err := s.normalizeMemoryTopics(m); err != nil {
+		return err
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if isCommitRef(m.Source) {
+		updated, err := s.updateBySourceRef(tx, m)
+		if err != nil {
+			return err
+		}
+		if updated {
+			return tx.Commit()
+		}
+	}
+
+	if err := s.insertMemory(tx, m); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	countCreated(m)
+	return nil
+}
+
+// CreateMemories stores several memories in one transaction. Each insert
+// runs in its own savepoint, so a failing item doesn't affect the others.
+// The returned slice holds the per-item error (nil on success).
+func (s *Store) CreateMemories(memories []*models.Memory) ([]error, error) {
+	errs := make([]error, len(memories))
+
+	if err := s.normalizeMemoryTopics(memories...); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for i, m := range memories {
+		if _, err := tx.Exec("SAVEPOINT batch_item"); err != nil {
+			return nil, err
+		}
+		if err := s.insertMemory(tx, m); err != nil {
+			errs[i] = err
+			if _, err := tx.Exec("ROLLBACK TO batch_item"); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := tx.Exec("RELEASE batch_item"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	for i, m := range memories {
+		if errs[i] == nil {
+			countCreated(m)
+		}
+	}
+	return errs, nil
+}
+
+func (s *Store) insertMemory(db txQuerier, m *models.Memory) error {
+	tok, err := s.tokenizerFor(db)
+	if err != nil {
+		return err
+	}
 	topicsJSON, _ := json.Marshal(m.Topics)
 	relatedJSON, _ := json.Marshal(m.RelatedMemories)
+	var metadataJSON, idempotencyKey, embeddingBlob, embeddingModel interface{}
+	if len(m.Metadata) > 0 {
+		data, _ := json.Marshal(m.Metadata)
+		metadataJSON = string(data)
+	}
+	if m.IdempotencyKey != "" {
+		idempotencyKey = m.IdempotencyKey
+	}
+	if len(m.Embedding) > 0 {
+		embeddingBlob = encodeEmbedding(m.Embedding)
+		if m.EmbeddingModel != "" {
+			embeddingModel = m.EmbeddingModel
+		}
+	}
+	if m.UpdatedAt.IsZero() {
+		m.UpdatedAt = m.CreatedAt
+	}
+	if m.ContentType == "" {
+		m.ContentType = contenttype.Detect(m.Content)
+	}
+	var curatedBy interface{}
+	if m.CuratedAt != nil {
+		curatedBy = m.CuratedBy
+	}
 
-	_, err := s.db.Exec(`
+	_, err = db.Exec(`
 		INSERT INTO memories (
 			id, type, content, summary, scope, project_id, team_id,
 			source_type, source_reference, source_timestamp,
 			confidence, importance, topics, related_memories, embedding,
-			created_at, last_accessed_at, access_count, expires_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			created_at, last_accessed_at, access_count, expires_at, metadata,
+			idempotency_key, updated_at, embedding_model, keywords, feedback, content_type,
+			curated_at, curated_by
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		m.ID, m.Type, m.Content, m.Summary, m.Scope, m.ProjectID, m.TeamID,
 		m.Source.Type, m.Source.Reference, m.Source.Timestamp,
-		m.Confidence, m.Importance, string(topicsJSON), string(relatedJSON), nil,
-		m.CreatedAt, m.LastAccessedAt, m.AccessCount, m.ExpiresAt,
+		m.Confidence, m.Importance, string(topicsJSON), string(relatedJSON), embeddingBlob,
+		m.CreatedAt, m.LastAccessedAt, m.AccessCount, m.ExpiresAt, metadataJSON,
+		idempotencyKey, m.UpdatedAt, embeddingModel, keywords(tok, m.Content, m.Summary), m.Feedback, m.ContentType,
+		m.CuratedAt, curatedBy,
 	)
 
 	return err
 }
 
-// Recall searches memories based on the request
-func (s *Store) Recall(req models.RecallRequest) ([]models.Memory, error) {
-	// Build query
-	query := `
-		SELECT id, type, content, summary, scope, project_id, team_id,
-			   source_type, source_reference, source_timestamp,
-			   confidence, importance, topics, related_memories,
-			   created_at, last_accessed_at, access_count, expires_at
-		FROM memories
-		WHERE 1=1
-	`
-	args := []interface{}{}
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
-	// Add filters
-	if len(req.Scope) > 0 {
-		placeholders := ""
-		for i, scope := range req.Scope {
-			if i > 0 {
-				placeholders += ","
+// scanMemory scans a row selected with memoryColumns into a memory
+func scanMemory(row rowScanner) (*models.Memory, error) {
+	var m models.Memory
+	var topicsJSON, relatedJSON, metadataJSON sql.NullString
+	var projectID, teamID, idempotencyKey, embeddingModel, contentType sql.NullString
+	var expiresAt, updatedAt, curatedAt sql.NullTime
+	var curatedBy sql.NullString
+
+	err := row.Scan(
+		&m.ID, &m.Type, &m.Content, &m.Summary, &m.Scope, &projectID, &teamID,
+		&m.Source.Type, &m.Source.Reference, &m.Source.Timestamp,
+		&m.Confidence, &m.Importance, &topicsJSON, &relatedJSON,
+		&m.CreatedAt, &m.LastAccessedAt, &m.AccessCount, &expiresAt, &metadataJSON,
+		&idempotencyKey, &updatedAt, &embeddingModel, &m.Feedback, &contentType,
+		&curatedAt, &curatedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if projectID.Valid {
+		m.ProjectID = &projectID.String
+	}
+	if teamID.Valid {
+		m.TeamID = &teamID.String
+	}
+	if expiresAt.Valid {
+		m.ExpiresAt = &expiresAt.Time
+	}
+	if topicsJSON.Valid {
+		json.Unmarshal([]byte(topicsJSON.String), &m.Topics)
+	}
+	if relatedJSON.Valid {
+		json.Unmarshal([]byte(relatedJSON.String), &m.RelatedMemories)
+	}
+	if metadataJSON.Valid {
+		json.Unmarshal([]byte(metadataJSON.String), &m.Metadata)
+	}
+	m.IdempotencyKey = idempotencyKey.String
+	m.EmbeddingModel = embeddingModel.String
+	m.ContentType = models.ContentType(contentType.String)
+	if curatedAt.Valid {
+		m.CuratedAt = &curatedAt.Time
+	}
+	m.CuratedBy = curatedBy.String
+	m.UpdatedAt = m.CreatedAt
+	if updatedAt.Valid {
+		m.UpdatedAt = updatedAt.Time
+	}
+
+	return &m, nil
+}
+
+// memoryColumns is the column list expected by scanMemory
+const memoryColumns = `id, type, content, summary, scope, project_id, team_id,
+	source_type, source_reference, source_timestamp,
+	confidence, importance, topics, related_memories,
+	created_at, last_accessed_at, access_count, expires_at, metadata,
+	idempotency_key, updated_at, embedding_model, feedback, content_type,
+	curated_at, curated_by`
+
+// GetMemory retrieves a memory by ID, returning nil if it doesn't exist
+func (s *Store) GetMemory(id string) (*models.Memory, error) {
+	row := s.db.QueryRow("SELECT "+memoryColumns+" FROM memories WHERE id = ?", id)
+	m, err := scanMemory(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return m, err
+}
+
+// FindByContent returns a memory whose content exactly matches content
+// (ignoring surrounding whitespace), or nil if there is none
+func (s *Store) FindByContent(content string) (*models.Memory, error) {
+	row := s.db.QueryRow("SELECT "+memoryColumns+" FROM memories WHERE trim(content) = ? LIMIT 1", strings.TrimSpace(content))
+	m, err := scanMemory(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return m, err
+}
+
+// CreateLinkedMemories stores memories in one transaction, all or nothing,
+// listing every other memory of the group in each one's RelatedMemories.
+// It is used for the chunks of a long document. If the first memory has an
+// idempotency key already used within the idempotency window, nothing is
+// stored and the memory created with that key is returned.
+func (s *Store) CreateLinkedMemories(memories []*models.Memory) (existing *models.Memory, err error) {
+	if len(memories) == 0 {
+		return nil, nil
+	}
+	if err := s.normalizeMemoryTopics(memories...); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	existing, err = findIdempotent(tx, memories[0].IdempotencyKey)
+	if existing != nil || err != nil {
+		return existing, err
+	}
+
+	if len(memories) > 1 {
+		group := make(map[string]bool, len(memories))
+		for _, m := range memories {
+			group[m.ID] = true
+		}
+		// Rebuild the links rather than appending, so a retried call
+		// doesn't link the same siblings twice
+		for _, m := range memories {
+			var related []string
+			for _, id := range m.RelatedMemories {
+				if !group[id] {
+					related = append(related, id)
+				}
 			}
-			placeholders += "?"
-			args = append(args, scope)
+			for _, sibling := range memories {
+				if sibling.ID != m.ID {
+					related = append(related, sibling.ID)
+				}
+			}
+			m.RelatedMemories = related
+		}
+	}
+
+	for _, m := range memories {
+		if err := s.insertMemory(tx, m); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	countCreated(memories...)
+	return nil, nil
+}
+
+// PreviewLinkedMemories applies the processing CreateLinkedMemories does
+// before storing memories, normalizing their topics and detecting their
+// content type, without writing anything. It returns the memory the first
+// memory's idempotency key already maps to, which CreateLinkedMemories
+// would return instead of storing them.
+func (s *Store) PreviewLinkedMemories(memories []*models.Memory) (*models.Memory, error) {
+	if len(memories) == 0 {
+		return nil, nil
+	}
+	if err := s.normalizeMemoryTopics(memories...); err != nil {
+		return nil, err
+	}
+	for _, m := range memories {
+		if m.ContentType == "" {
+			m.ContentType = contenttype.Detect(m.Content)
+		}
+	}
+	return findIdempotent(s.db, memories[0].IdempotencyKey)
+}
+
+// rowQuerier is satisfied by *sql.DB, *sql.Tx and *writeTx
+type rowQuerier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// txQuerier is a write transaction that can also be read from
+type txQuerier interface {
+	execer
+	rowQuerier
+}
+
+// findIdempotent returns the memory created with an idempotency key within
+// the idempotency window, or nil if there is none or key is empty
+func findIdempotent(db rowQuerier, key string) (*models.Memory, error) {
+	if key == "" {
+		return nil, nil
+	}
+	row := db.QueryRow("SELECT "+memoryColumns+` FROM memories
+		WHERE idempotency_key = ? AND julianday(created_at) >= julianday(?)
+		ORDER BY created_at DESC LIMIT 1`,
+		key, time.Now().Add(-idempotencyWindow).UTC().Format("2006-01-02 15:04:05"))
+	m, err := scanMemory(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return m, err
+}
+
+// GetMemories retrieves the memories with the given IDs, in that order,
+// skipping IDs that don't exist
+func (s *Store) GetMemories(ids []string) ([]models.Memory, error) {
+	var memories []models.Memory
+	for _, id := range ids {
+		m, err := s.GetMemory(id)
+		if err != nil {
+			return nil, err
+		}
+		if m != nil {
+			memories = append(memories, *m)
+		}
+	}
+	return memories, nil
+}
+
+// ListOptions selects the memories returned by ListMemories. Zero values
+// don't filter.
+type ListOptions struct {
+	MissingEmbedding  bool                // Only memories without an embedding
+	Types             []models.MemoryType // Only these types
+	Topics            []string            // Only memories tagged with any of these (aliases resolved)
+	ProjectID         string              // Only memories of this project
+	Since             time.Time           // Only memories created or updated at or after this time
+	Limit             int                 // Only the most recent Limit matches
+	IncludeEmbeddings bool                // Populate Memory.Embedding
+}
+
+// ListMemories returns the memories matching opts, oldest first
+func (s *Store) ListMemories(opts ListOptions) ([]models.Memory, error) {
+	aliases, err := s.topicAliases()
+	if err != nil {
+		return nil, err
+	}
+	clause, args := filterClause(models.RecallRequest{
+		Types:  opts.Types,
+		Topics: canonicalTopics(opts.Topics, aliases),
+	})
+	if opts.MissingEmbedding {
+		clause += " AND embedding IS NULL"
+	}
+	if opts.ProjectID != "" {
+		clause += " AND project_id = ?"
+		args = append(args, opts.ProjectID)
+	}
+	if !opts.Since.IsZero() {
+		clause += " AND datetime(updated_at) >= datetime(?)"
+		args = append(args, opts.Since.UTC().Format("2006-01-02 15:04:05"))
+	}
+
+	columns := memoryColumns
+	if opts.IncludeEmbeddings {
+		columns += ", embedding"
+	}
+	// Take the newest matches when limited, then restore oldest-first order.
+	// Memories created at the same time come out most important first, then
+	// by ID, as in recall.
+	query := "SELECT " + columns + " FROM memories WHERE 1=1" + clause + " ORDER BY created_at DESC, importance ASC, id DESC"
+	if opts.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, opts.Limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memories []models.Memory
+	for rows.Next() {
+		var blob []byte
+		var row rowScanner = rows
+		if opts.IncludeEmbeddings {
+			row = scanWithEmbedding{rows, &blob}
+		}
+		m, err := scanMemory(row)
+		if err != nil {
+			return nil, err
+		}
+		if blob != nil {
+			m.Embedding = decodeEmbedding(blob)
+		}
+		memories = append(memories, *m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(memories)-1; i < j; i, j = i+1, j-1 {
+		memories[i], memories[j] = memories[j], memories[i]
+	}
+	return memories, nil
+}
+
+// UpdateMemoryContent replaces a memory's content and summary, recording the
+// previous version in its history. The content type is detected again, and
+// if the content changed the embedding is cleared so callers can regenerate
+// it for the new content.
+func (s *Store) UpdateMemoryContent(id, content, summary, editor string) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.updateContent(tx.Tx, id, content, summary, editor); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// updateContent is UpdateMemoryContent within tx
+func (s *Store) updateContent(tx *sql.Tx, id, content, summary, editor string) error {
+	var oldContent, oldSummary string
+	err := tx.QueryRow("SELECT content, summary FROM memories WHERE id = ?", id).Scan(&oldContent, &oldSummary)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("memory %s not found", id)
+	}
+	if err != nil {
+		return err
+	}
+
+	var version int
+	if err := tx.QueryRow(
+		"SELECT COALESCE(MAX(version), 0) + 1 FROM memory_history WHERE memory_id = ?", id,
+	).Scan(&version); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO memory_history (memory_id, version, content, summary, editor, edited_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, id, version, oldContent, oldSummary, editor, time.Now()); err != nil {
+		return err
+	}
+
+	tok, err := s.tokenizerFor(tx)
+	if err != nil {
+		return err
+	}
+
+	// The embedding is of the content, so a new summary alone keeps it
+	set := "content = ?, summary = ?, keywords = ?, content_type = ?, embedding = NULL, embedding_model = NULL, updated_at = ?"
+	if content == oldContent {
+		set = "content = ?, summary = ?, keywords = ?, content_type = ?, updated_at = ?"
+	}
+	if _, err := tx.Exec(`UPDATE memories SET `+set+` WHERE id = ?`,
+		content, summary, keywords(tok, content, summary), contenttype.Detect(content), time.Now(), id); err != nil {
+		return err
+	}
+
+	// Drop the oldest versions beyond the retention cap
+	_, err = tx.Exec(`
+		DELETE FROM memory_history WHERE memory_id = ? AND version <= ?
+	`, id, version-maxHistoryPerMemory)
+	return err
+}
+
+// GetHistory returns the recorded prior versions of a memory, newest first
+func (s *Store) GetHistory(id string) ([]models.MemoryVersion, error) {
+	rows, err := s.db.Query(`
+		SELECT memory_id, version, content, summary, editor, edited_at
+		FROM memory_history
+		WHERE memory_id = ?
+		ORDER BY version DESC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []models.MemoryVersion
+	for rows.Next() {
+		var v models.MemoryVersion
+		var editor sql.NullString
+		if err := rows.Scan(&v.MemoryID, &v.Version, &v.Content, &v.Summary, &editor, &v.EditedAt); err != nil {
+			return nil, err
 		}
-		query += " AND scope IN (" + placeholders + ")"
+		v.Editor = editor.String
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
+
+// RevertMemory restores a memory to a prior version. The current content is
+// itself recorded in history, so a revert can be undone.
+func (s *Store) RevertMemory(id string, version int, editor string) (*models.MemoryVersion, error) {
+	tx, err := s.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Read the version in the write transaction, so no edit can land between
+	// reading it and restoring it
+	var v models.MemoryVersion
+	err = tx.QueryRow(`
+		SELECT memory_id, version, content, summary, edited_at
+		FROM memory_history WHERE memory_id = ? AND version = ?
+	`, id, version).Scan(&v.MemoryID, &v.Version, &v.Content, &v.Summary, &v.EditedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("version %d of memory %s not found", version, id)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	if len(req.Types) > 0 {
+	if err := s.updateContent(tx.Tx, id, v.Content, v.Summary, editor); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// filterClause builds the AND conditions shared by keyword and semantic
+// search for the request's scope, type, content type, project, source and
+// metadata filters. Expired memories are always excluded.
+func filterClause(req models.RecallRequest) (string, []interface{}) {
+	var clause string
+	args := []interface{}{}
+
+	in := func(column string, values []interface{}) {
+		if len(values) == 0 {
+			return
+		}
 		placeholders := ""
-		for i, t := range req.Types {
+		for i, v := range values {
 			if i > 0 {
 				placeholders += ","
 			}
 			placeholders += "?"
-			args = append(args, t)
+			args = append(args, v)
+		}
+		clause += " AND " + column + " IN (" + placeholders + ")"
+	}
+
+	var scopes, types, contentTypes, sources []interface{}
+	for _, scope := range req.Scope {
+		scopes = append(scopes, scope)
+	}
+	for _, t := range req.Types {
+		types = append(types, t)
+	}
+	for _, t := range req.ContentTypes {
+		contentTypes = append(contentTypes, t)
+	}
+	for _, src := range req.SourceTypes {
+		sources = append(sources, src)
+	}
+	in("scope", scopes)
+	in("type", types)
+	in("content_type", contentTypes)
+	in("source_type", sources)
+
+	// A memory tagged with both an included and an excluded topic is
+	// excluded
+	tagged := func(not string, topics []string) {
+		if len(topics) == 0 {
+			return
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(topics)), ",")
+		clause += " AND " + not + "EXISTS (SELECT 1 FROM json_each(memories.topics) WHERE lower(value) IN (" + placeholders + "))"
+		for _, topic := range topics {
+			args = append(args, topic)
+		}
+	}
+	tagged("", req.Topics)
+	tagged("NOT ", req.ExcludeTopics)
+
+	if req.CuratedOnly {
+		clause += " AND curated_at IS NOT NULL"
+	}
+	if req.CreatedAfter != nil {
+		clause += " AND datetime(created_at) >= datetime(?)"
+		args = append(args, req.CreatedAfter.UTC().Format("2006-01-02 15:04:05"))
+	}
+	if req.CreatedBefore != nil {
+		clause += " AND datetime(created_at) < datetime(?)"
+		args = append(args, req.CreatedBefore.UTC().Format("2006-01-02 15:04:05"))
+	}
+	for _, c := range req.Importance {
+		// Operators are checked so they can't inject SQL
+		if c.Valid() {
+			clause += " AND importance " + c.Op + " ?"
+			args = append(args, c.Value)
 		}
-		query += " AND type IN (" + placeholders + ")"
 	}
 
+	// Expired memories stay hidden until the daemon sweeps them
+	clause += " AND (expires_at IS NULL OR datetime(expires_at) > datetime('now'))"
+
 	if req.ProjectID != nil {
-		query += " AND (project_id = ? OR project_id IS NULL)"
+		clause += " AND (project_id = ? OR project_id IS NULL)"
 		args = append(args, *req.ProjectID)
 	}
 
+	// Sort keys so the generated SQL is stable
+	keys := make([]string, 0, len(req.Metadata))
+	for key := range req.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		clause += " AND json_extract(metadata, ?) = ?"
+		args = append(args, metadataPath(key), req.Metadata[key])
+	}
+
+	return clause, args
+}
+
+// metadataPath returns the JSON path for a metadata key, quoted so keys
+// containing dots or brackets are matched literally
+func metadataPath(key string) string {
+	return `$."` + key + `"`
+}
+
+// ValidateMetadata checks that metadata keys can be stored and filtered on
+func ValidateMetadata(metadata map[string]string) error {
+	for key := range metadata {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("metadata keys must not be empty")
+		}
+		if strings.Contains(key, `"`) {
+			return fmt.Errorf("metadata key %q must not contain double quotes", key)
+		}
+	}
+	return nil
+}
+
+// Recall searches memories based on the request
+func (s *Store) Recall(req models.RecallRequest) ([]models.Memory, error) {
+	key := recallCacheKey("keyword", req, nil)
+	cached, generation, ok := s.cachedRecall(key, req.Query)
+	if ok {
+		return cached, nil
+	}
+
+	start := time.Now()
+	candidates := req
+	candidates.Limit = candidateLimit(req)
+	memories, err := s.recall(candidates)
+	if err == nil {
+		memories, err = s.diversify(memories, req.Limit, req.Diversity)
+	}
+	observeRecall("keyword", start, err)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.put(key, generation, memories)
+	s.logAccess(req.Query, memories)
+	return memories, nil
+}
+
+func (s *Store) recall(req models.RecallRequest) ([]models.Memory, error) {
+	aliases, err := s.topicAliases()
+	if err != nil {
+		return nil, err
+	}
+	tok, err := s.tokenizerFor(s.db)
+	if err != nil {
+		return nil, err
+	}
+	req.Topics = canonicalTopics(req.Topics, aliases)
+	req.ExcludeTopics = canonicalTopics(req.ExcludeTopics, aliases)
+
+	// Build query
+	query := "SELECT " + memoryColumns + " FROM memories WHERE 1=1"
+
+	// Add filters
+	filters, args := filterClause(req)
+	query += filters
+	floor, floorArgs := s.floorClause(req)
+	query += floor
+	args = append(args, floorArgs...)
+
 	// Text search (basic for now, will add vector search later)
-	if req.Query != "" {
-		query += " AND (content LIKE ? OR summary LIKE ? OR topics LIKE ?)"
-		searchTerm := "%" + req.Query + "%"
-		args = append(args, searchTerm, searchTerm, searchTerm)
+	if req.Exact && req.Query != "" {
+		// instr is case-sensitive and treats % and _ literally, unlike LIKE
+		query += " AND (instr(content, ?) > 0 OR instr(summary, ?) > 0)"
+		args = append(args, req.Query, req.Query)
+	} else if req.Query != "" {
+		var match string
+		if tokens := tok.Tokens(req.Query); len(tokens) > 0 {
+			// Every query term must appear in the keyword index or topics
+			terms := make([]string, len(tokens))
+			for i, t := range tokens {
+				terms[i] = "(keywords LIKE ? OR topics LIKE ?)"
+				args = append(args, "% "+t+" %", "%"+t+"%")
+			}
+			match = strings.Join(terms, " AND ")
+		} else {
+			// Nothing but stopwords: match the query as a substring
+			searchTerm := "%" + req.Query + "%"
+			match = "content LIKE ? OR summary LIKE ? OR topics LIKE ?"
+			args = append(args, searchTerm, searchTerm, searchTerm)
+		}
+		// A query naming a topic alias also matches the canonical topic
+		if canonical, ok := aliases[normalizeTopic(req.Query)]; ok {
+			match = "(" + match + ") OR topics LIKE ?"
+			args = append(args, `%"`+canonical+`"%`)
+		}
+		// So does any expansion term, as a topic or by all of its words
+		for _, term := range req.Expand {
+			alt := []string{"topics LIKE ?"}
+			args = append(args, `%"`+normalizeTopic(term)+`"%`)
+			if tokens := tok.Tokens(term); len(tokens) > 0 {
+				words := make([]string, len(tokens))
+				for i, t := range tokens {
+					words[i] = "keywords LIKE ?"
+					args = append(args, "% "+t+" %")
+				}
+				alt = append(alt, "("+strings.Join(words, " AND ")+")")
+			}
+			match = "(" + match + ") OR " + strings.Join(alt, " OR ")
+		}
+		query += " AND (" + match + ")"
 	}
 
-	// Order by importance and recency
-	query += " ORDER BY importance DESC, last_accessed_at DESC"
+	// Order by importance adjusted for feedback, curation and confidence
+	query += fmt.Sprintf(keywordOrder, importanceWeight, feedbackBoost, curatedBoost, s.confidenceWeight)
 
 	// Limit
 	limit := req.Limit
@@ -242,49 +1049,39 @@ func (s *Store) Recall(req models.RecallRequest) ([]models.Memory, error) {
 
 	var memories []models.Memory
 	for rows.Next() {
-		var m models.Memory
-		var topicsJSON, relatedJSON sql.NullString
-		var projectID, teamID sql.NullString
-		var expiresAt sql.NullTime
-
-		err := rows.Scan(
-			&m.ID, &m.Type, &m.Content, &m.Summary, &m.Scope, &projectID, &teamID,
-			&m.Source.Type, &m.Source.Reference, &m.Source.Timestamp,
-			&m.Confidence, &m.Importance, &topicsJSON, &relatedJSON,
-			&m.CreatedAt, &m.LastAccessedAt, &m.AccessCount, &expiresAt,
-		)
+		m, err := scanMemory(rows)
 		if err != nil {
 			return nil, err
 		}
 
-		if projectID.Valid {
-			m.ProjectID = &projectID.String
-		}
-		if teamID.Valid {
-			m.TeamID = &teamID.String
-		}
-		if expiresAt.Valid {
-			m.ExpiresAt = &expiresAt.Time
-		}
-		if topicsJSON.Valid {
-			json.Unmarshal([]byte(topicsJSON.String), &m.Topics)
-		}
-		if relatedJSON.Valid {
-			json.Unmarshal([]byte(relatedJSON.String), &m.RelatedMemories)
+		// A keyword hit contains every query term, so treat it as a full
+		// text match and weight importance the same way semantic search does
+		if req.Query != "" {
+			m.Score = matchWeight + importanceScore(m) + feedbackScore(m) + curatedScore(m) + s.confidenceScore(m)
+			m.KeywordMatch = true
+			if req.Explain {
+				keyword := float32(matchWeight)
+				m.Explanation = s.explain(m)
+				m.Explanation.Keyword = &keyword
+			}
 		}
 
-		memories = append(memories, m)
+		memories = append(memories, *m)
 
 		// Record access
 		s.recordAccess(m.ID)
 	}
 
+	// Scores are computed in float32, which can tie memories SQL told apart
+	if req.Query != "" {
+		sortByRank(memories)
+	}
 	return memories, nil
 }
 
 // recordAccess updates access statistics for a memory
 func (s *Store) recordAccess(memoryID string) {
-	s.db.Exec(`
+	s.execStats(`
 		UPDATE memories
 		SET last_accessed_at = ?,
 			access_count = access_count + 1,
@@ -293,33 +1090,84 @@ func (s *Store) recordAccess(memoryID string) {
 	`, time.Now(), memoryID)
 }
 
-// DecayImportance reduces importance of old memories
-func (s *Store) DecayImportance() error {
-	_, err := s.db.Exec(`
+// DecayImportance reduces importance of old memories and fades relevance
+// feedback. Memories decay by the daily factor for their type in rates, or
+// 0.99 for types not listed.
+func (s *Store) DecayImportance(rates map[models.MemoryType]float64) error {
+	factor := "0.99"
+	var args []interface{}
+	if len(rates) > 0 {
+		types := make([]string, 0, len(rates))
+		for t := range rates {
+			types = append(types, string(t))
+		}
+		sort.Strings(types)
+		factor = "CASE type"
+		for _, t := range types {
+			factor += " WHEN ? THEN ?"
+			args = append(args, t, rates[models.MemoryType(t)])
+		}
+		factor += " ELSE 0.99 END"
+	}
+	if _, err := s.exec(`
 		UPDATE memories
-		SET importance = importance * 0.99
+		SET importance = MAX(0.1, importance * `+factor+`)
 		WHERE importance > 0.1
 		  AND last_accessed_at < datetime('now', '-1 day')
-	`)
+		  AND curated_at IS NULL
+	`, args...); err != nil {
+		return err
+	}
+	_, err := s.exec(`UPDATE memories SET feedback = feedback * ? WHERE feedback != 0`, feedbackDecay)
 	return err
 }
 
 // CreateProject stores a new project
 func (s *Store) CreateProject(p *models.Project) error {
-	_, err := s.db.Exec(`
+	_, err := s.exec(`
 		INSERT OR REPLACE INTO projects (id, name, path, git_remote, created_at, last_seen)
 		VALUES (?, ?, ?, ?, ?, ?)
 	`, p.ID, p.Name, p.Path, p.GitRemote, p.CreatedAt, p.LastSeen)
 	return err
 }
 
+// EnsureProject returns the project rooted at path, tracking it, named
+// after its directory, if it isn't yet, and records it as seen now
+func (s *Store) EnsureProject(path string) (*models.Project, error) {
+	now := time.Now()
+	if _, err := s.execStats(`
+		INSERT INTO projects (id, name, path, created_at, last_seen)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(path) DO UPDATE SET last_seen = excluded.last_seen
+	`, ids.New(), filepath.Base(path), path, now, now); err != nil {
+		return nil, err
+	}
+	return s.GetProjectByPath(path)
+}
+
+// FindProject looks up a project by ID, path or name, returning nil if none
+// matches
+func (s *Store) FindProject(ref string) (*models.Project, error) {
+	row := s.db.QueryRow(`
+		SELECT id, name, path, git_remote, created_at, last_seen
+		FROM projects WHERE id = ? OR path = ? OR name = ?
+		ORDER BY id = ? DESC, path = ? DESC
+		LIMIT 1
+	`, ref, ref, ref, ref, ref)
+	return scanProject(row)
+}
+
 // GetProjectByPath retrieves a project by its filesystem path
 func (s *Store) GetProjectByPath(path string) (*models.Project, error) {
 	row := s.db.QueryRow(`
 		SELECT id, name, path, git_remote, created_at, last_seen
 		FROM projects WHERE path = ?
 	`, path)
+	return scanProject(row)
+}
 
+// scanProject scans a projects row, returning nil if there is none
+func scanProject(row rowScanner) (*models.Project, error) {
 	var p models.Project
 	var gitRemote sql.NullString
 	err := row.Scan(&p.ID, &p.Name, &p.Path, &gitRemote, &p.CreatedAt, &p.LastSeen)
@@ -338,7 +1186,7 @@ func (s *Store) GetProjectByPath(path string) (*models.Project, error) {
 // CreateEvent stores a new event
 func (s *Store) CreateEvent(e *models.Event) error {
 	dataJSON, _ := json.Marshal(e.Data)
-	_, err := s.db.Exec(`
+	_, err := s.exec(`
 		INSERT INTO events (id, type, timestamp, data, project_id)
 		VALUES (?, ?, ?, ?, ?)
 	`, e.ID, e.Type, e.Timestamp, string(dataJSON), e.ProjectID)
@@ -385,56 +1233,113 @@ func (s *Store) GetUnprocessedEvents(limit int) ([]models.Event, error) {
 
 // MarkEventProcessed marks an event as processed
 func (s *Store) MarkEventProcessed(eventID string) error {
-	_, err := s.db.Exec(`
+	_, err := s.exec(`
 		UPDATE events SET processed_at = ? WHERE id = ?
 	`, time.Now(), eventID)
 	return err
 }
 
-// UpdateMemoryEmbedding stores the embedding for a memory
-func (s *Store) UpdateMemoryEmbedding(memoryID string, embedding []float32) error {
-	blob := encodeEmbedding(embedding)
-	_, err := s.db.Exec(`
-		UPDATE memories SET embedding = ? WHERE id = ?
-	`, blob, memoryID)
+// EmbeddingDimension returns the dimension of the stored embeddings, or 0
+// if no memory has an embedding yet
+func (s *Store) EmbeddingDimension() (int, error) {
+	var size sql.NullInt64
+	err := s.db.QueryRow("SELECT length(embedding) FROM memories WHERE embedding IS NOT NULL LIMIT 1").Scan(&size)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return int(size.Int64) / 4, nil
+}
+
+// ErrInvalidEmbedding is returned when storing an empty, all-zero or
+// non-finite embedding, which would make similarity scores meaningless
+var ErrInvalidEmbedding = errors.New("embedding is empty, all zeros or not finite")
+
+// EmbeddingModel returns the model that produced most stored embeddings, or
+// "" if there are none or their model is unknown
+func (s *Store) EmbeddingModel() (string, error) {
+	var model sql.NullString
+	err := s.db.QueryRow(`SELECT embedding_model FROM memories
+		WHERE embedding IS NOT NULL AND embedding_model IS NOT NULL
+		GROUP BY embedding_model ORDER BY COUNT(*) DESC LIMIT 1`).Scan(&model)
+	if err != nil && err != sql.ErrNoRows {
+		return "", err
+	}
+	return model.String, nil
+}
+
+// UpdateMemoryEmbedding stores the embedding for a memory, along with the
+// model that produced it ("" if unknown)
+func (s *Store) UpdateMemoryEmbedding(memoryID string, emb []float32, model string) error {
+	if !embedding.Valid(emb) {
+		return ErrInvalidEmbedding
+	}
+	var modelName interface{}
+	if model != "" {
+		modelName = model
+	}
+	blob := encodeEmbedding(emb)
+	_, err := s.exec(`
+		UPDATE memories SET embedding = ?, embedding_model = ?, updated_at = ? WHERE id = ?
+	`, blob, modelName, time.Now(), memoryID)
 	return err
 }
 
-// SemanticSearch searches memories using vector similarity
-func (s *Store) SemanticSearch(queryEmbedding []float32, limit int) ([]models.Memory, error) {
-	// Get all memories with embeddings
-	rows, err := s.db.Query(`
-		SELECT id, type, content, summary, scope, project_id, team_id,
-			   source_type, source_reference, source_timestamp,
-			   confidence, importance, topics, related_memories, embedding,
-			   created_at, last_accessed_at, access_count, expires_at
-		FROM memories
-		WHERE embedding IS NOT NULL
-	`)
+// SemanticSearch searches memories using vector similarity, applying the
+// request's filters and returning up to req.Limit results
+func (s *Store) SemanticSearch(req models.RecallRequest, queryEmbedding []float32) ([]models.Memory, error) {
+	key := recallCacheKey("semantic", req, queryEmbedding)
+	cached, generation, ok := s.cachedRecall(key, req.Query)
+	if ok {
+		return cached, nil
+	}
+
+	start := time.Now()
+	candidates := req
+	candidates.Limit = candidateLimit(req)
+	memories, err := s.semanticSearch(candidates, queryEmbedding)
+	if err == nil {
+		memories, err = s.diversify(memories, req.Limit, req.Diversity)
+	}
+	observeRecall("semantic", start, err)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	s.cache.put(key, generation, memories)
+	s.logAccess(req.Query, memories)
+	return memories, nil
+}
+
+func (s *Store) semanticSearch(req models.RecallRequest, queryEmbedding []float32) ([]models.Memory, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 5
+	}
 
-	type scoredMemory struct {
-		memory models.Memory
-		score  float32
+	aliases, err := s.topicAliases()
+	if err != nil {
+		return nil, err
 	}
+	req.Topics = canonicalTopics(req.Topics, aliases)
+	req.ExcludeTopics = canonicalTopics(req.ExcludeTopics, aliases)
+
+	// Get all matching memories with embeddings
+	filters, args := filterClause(req)
+	floor, floorArgs := s.floorClause(req)
+	filters += floor
+	args = append(args, floorArgs...)
+	rows, err := s.db.Query("SELECT "+memoryColumns+", embedding FROM memories WHERE embedding IS NOT NULL"+filters, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	var scored []scoredMemory
+	var scored []models.Memory
 	for rows.Next() {
-		var m models.Memory
-		var topicsJSON, relatedJSON sql.NullString
-		var projectID, teamID sql.NullString
-		var expiresAt sql.NullTime
 		var embeddingBlob []byte
-
-		err := rows.Scan(
-			&m.ID, &m.Type, &m.Content, &m.Summary, &m.Scope, &projectID, &teamID,
-			&m.Source.Type, &m.Source.Reference, &m.Source.Timestamp,
-			&m.Confidence, &m.Importance, &topicsJSON, &relatedJSON, &embeddingBlob,
-			&m.CreatedAt, &m.LastAccessedAt, &m.AccessCount, &expiresAt,
-		)
+		m, err := scanMemory(rowWithExtra{rows, &embeddingBlob})
 		if err != nil {
 			continue
 		}
@@ -446,93 +1351,205 @@ func (s *Store) SemanticSearch(queryEmbedding []float32, limit int) ([]models.Me
 		embedding := decodeEmbedding(embeddingBlob)
 		similarity := cosineSimilarity(queryEmbedding, embedding)
 
-		if projectID.Valid {
-			m.ProjectID = &projectID.String
-		}
-		if teamID.Valid {
-			m.TeamID = &teamID.String
-		}
-		if expiresAt.Valid {
-			m.ExpiresAt = &expiresAt.Time
-		}
-		if topicsJSON.Valid {
-			json.Unmarshal([]byte(topicsJSON.String), &m.Topics)
-		}
-		if relatedJSON.Valid {
-			json.Unmarshal([]byte(relatedJSON.String), &m.RelatedMemories)
+		// Combine similarity with importance, feedback, curation and confidence
+		semantic := similarity * matchWeight
+		m.Score = semantic + importanceScore(m) + feedbackScore(m) + curatedScore(m) + s.confidenceScore(m)
+		if req.Explain {
+			m.Explanation = s.explain(m)
+			m.Explanation.Semantic = &semantic
+			m.Explanation.Similarity = &similarity
 		}
+		scored = append(scored, *m)
+	}
 
-		// Combine similarity with importance
-		score := similarity*0.7 + float32(m.Importance)*0.3
-		scored = append(scored, scoredMemory{memory: m, score: score})
+	// Take the top N
+	sortByRank(scored)
+	results := truncate(scored, limit)
+	for _, m := range results {
+		s.recordAccess(m.ID)
 	}
 
-	// Sort by score (simple bubble sort for now, can optimize later)
-	for i := 0; i < len(scored); i++ {
-		for j := i + 1; j < len(scored); j++ {
-			if scored[j].score > scored[i].score {
-				scored[i], scored[j] = scored[j], scored[i]
-			}
-		}
+	return results, nil
+}
+
+// HybridSearch combines semantic and keyword search for the request.
+// Results scoring below req.MinScore are dropped before req.Limit is applied.
+// An invalid query embedding (empty or all zeros) degrades to keyword search.
+func (s *Store) HybridSearch(req models.RecallRequest, queryEmbedding []float32) ([]models.Memory, error) {
+	return s.HybridSearchContext(context.Background(), req, queryEmbedding)
+}
+
+// HybridSearchContext is HybridSearch recorded as a tracing span, with
+// child spans for each search and the rerank, under the span in ctx. If ctx
+// carries SearchStats (see WithSearchStats), they are filled in.
+func (s *Store) HybridSearchContext(ctx context.Context, req models.RecallRequest, queryEmbedding []float32) (merged []models.Memory, err error) {
+	if !embedding.Valid(queryEmbedding) {
+		return s.Recall(req)
 	}
 
-	// Take top N
-	var results []models.Memory
-	for i := 0; i < len(scored) && i < limit; i++ {
-		results = append(results, scored[i].memory)
-		s.recordAccess(scored[i].memory.ID)
+	stats := searchStatsFrom(ctx)
+	start := time.Now()
+	defer func() { stats.Duration = time.Since(start) }()
+
+	key := recallCacheKey("hybrid", req, queryEmbedding)
+	cached, generation, ok := s.cachedRecall(key, req.Query)
+	if ok {
+		stats.Cached = true
+		return cached, nil
 	}
 
-	return results, nil
-}
+	ctx, span := tracing.Start(ctx, "store.HybridSearch")
+	span.SetAttr("query.length", len(req.Query))
+	defer func() {
+		observeRecall("hybrid", start, err)
+		span.SetError(err)
+		span.SetAttr("result.count", len(merged))
+		span.End()
+	}()
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 5
+	}
+
+	// Fetch extra candidates from each side to merge, and more still when
+	// a reranker will narrow them down
+	candidates := req
+	candidates.Limit = candidateLimit(req) * 2
+	if s.reranker != nil && candidates.Limit < rerankCandidates {
+		candidates.Limit = rerankCandidates
+	}
 
-// HybridSearch combines semantic and keyword search
-func (s *Store) HybridSearch(query string, queryEmbedding []float32, limit int) ([]models.Memory, error) {
 	// Get semantic results
 	var semanticResults []models.Memory
 	if queryEmbedding != nil && len(queryEmbedding) > 0 {
-		var err error
-		semanticResults, err = s.SemanticSearch(queryEmbedding, limit*2)
+		_, semanticSpan := tracing.Start(ctx, "store.semanticSearch")
+		semanticResults, err = s.semanticSearch(candidates, queryEmbedding)
+		semanticSpan.SetError(err)
+		semanticSpan.SetAttr("result.count", len(semanticResults))
+		semanticSpan.End()
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	// Get keyword results
-	keywordResults, err := s.Recall(models.RecallRequest{
-		Query: query,
-		Limit: limit * 2,
-	})
+	_, keywordSpan := tracing.Start(ctx, "store.keywordSearch")
+	keywordResults, err := s.recall(candidates)
+	keywordSpan.SetError(err)
+	keywordSpan.SetAttr("result.count", len(keywordResults))
+	keywordSpan.End()
 	if err != nil {
 		return nil, err
 	}
 
-	// Merge results (semantic results first, then keyword results not in semantic)
-	seen := make(map[string]bool)
-	var merged []models.Memory
-
-	for _, m := range semanticResults {
-		if !seen[m.ID] {
-			seen[m.ID] = true
+	// Merge results, ranked by score. A memory matched by both appears once,
+	// keeping its higher score.
+	seen := make(map[string]int) // memory ID -> index in merged
+
+	for _, results := range [][]models.Memory{semanticResults, keywordResults} {
+		for _, m := range results {
+			if i, ok := seen[m.ID]; ok {
+				merged[i].KeywordMatch = merged[i].KeywordMatch || m.KeywordMatch
+				// The explanation goes with the score kept
+				if m.Score > merged[i].Score {
+					merged[i].Score = m.Score
+					merged[i].Explanation = m.Explanation
+				}
+				continue
+			}
+			seen[m.ID] = len(merged)
 			merged = append(merged, m)
 		}
 	}
 
-	for _, m := range keywordResults {
-		if !seen[m.ID] {
-			seen[m.ID] = true
-			merged = append(merged, m)
-		}
+	sortByRank(merged)
+
+	stats.Semantic = len(semanticResults)
+	stats.Keyword = len(keywordResults)
+	stats.Merged = len(merged)
+
+	if s.reranker != nil && req.Query != "" {
+		stats.Reranked = true
+		_, rerankSpan := tracing.Start(ctx, "store.rerank")
+		rerankSpan.SetAttr("candidate.count", len(merged))
+		merged = s.rerank(req.Query, merged)
+		rerankSpan.End()
 	}
 
-	// Limit results
-	if len(merged) > limit {
-		merged = merged[:limit]
+	merged = FilterByScore(merged, req.MinScore)
+
+	// Limit results, picking a varied set if asked to
+	merged, err = s.diversify(merged, limit, req.Diversity)
+	if err != nil {
+		return nil, err
 	}
 
+	s.cache.put(key, generation, merged)
+	s.logAccess(req.Query, merged)
+
 	return merged, nil
 }
 
+// rerank rescores memories with the reranker and sorts them by the new
+// score. On failure the original order and scores are kept.
+func (s *Store) rerank(query string, memories []models.Memory) []models.Memory {
+	if len(memories) == 0 {
+		return memories
+	}
+
+	docs := make([]string, len(memories))
+	for i, m := range memories {
+		docs[i] = m.Content
+	}
+
+	start := time.Now()
+	scores, err := s.reranker.Rerank(query, docs)
+	if err != nil {
+		log.Printf("Reranking failed after %s, keeping hybrid order: %v", time.Since(start), err)
+		return memories
+	}
+	log.Printf("Reranked %d candidates in %s", len(memories), time.Since(start))
+
+	for i := range memories {
+		memories[i].Score = scores[i]
+		if e := memories[i].Explanation; e != nil {
+			e.Rerank = &scores[i]
+			e.Final = scores[i]
+		}
+	}
+	sortByRank(memories)
+	return memories
+}
+
+// explain breaks down the score of a memory, apart from the query match
+// contribution the search sets
+func (s *Store) explain(m *models.Memory) *models.ScoreExplanation {
+	return &models.ScoreExplanation{
+		Importance: importanceScore(m),
+		Feedback:   feedbackScore(m),
+		Curated:    curatedScore(m),
+		Confidence: s.confidenceScore(m),
+		Final:      m.Score,
+	}
+}
+
+// FilterByScore returns the memories whose score is at least minScore,
+// preserving order. A minScore of 0 keeps everything.
+func FilterByScore(memories []models.Memory, minScore float32) []models.Memory {
+	if minScore <= 0 {
+		return memories
+	}
+
+	var filtered []models.Memory
+	for _, m := range memories {
+		if m.Score >= minScore {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
+}
+
 // Helper functions for embedding storage
 
 func encodeEmbedding(embedding []float32) []byte {
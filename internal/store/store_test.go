@@ -0,0 +1,58 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/internal/ids"
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// newTestStore opens a store in a fresh database that is closed when the
+// test ends
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := New(filepath.Join(t.TempDir(), "memories.db"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// newTestMemory returns a manually remembered fact with content
+func newTestMemory(content string) *models.Memory {
+	now := time.Now()
+	return &models.Memory{
+		ID:             ids.New(),
+		Type:           models.MemoryTypeFact,
+		Content:        content,
+		Summary:        content,
+		Scope:          models.MemoryScopePersonal,
+		Source:         models.Source{Type: models.SourceTypeManual, Reference: "test", Timestamp: now},
+		Confidence:     1.0,
+		Importance:     1.0,
+		CreatedAt:      now,
+		LastAccessedAt: now,
+	}
+}
+
+// createMemories stores memories, failing the test on error
+func createMemories(t *testing.T, s *Store, memories ...*models.Memory) {
+	t.Helper()
+	for _, m := range memories {
+		if err := s.CreateMemory(m); err != nil {
+			t.Fatalf("CreateMemory(%q): %v", m.Content, err)
+		}
+	}
+}
+
+// memoryIDs returns the IDs of memories in order
+func memoryIDs(memories []models.Memory) []string {
+	result := make([]string, len(memories))
+	for i, m := range memories {
+		result[i] = m.ID
+	}
+	return result
+}
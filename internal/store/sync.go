@@ -0,0 +1,253 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// ConflictStrategy decides what ImportAll does with an imported memory
+// whose ID already exists locally
+type ConflictStrategy string
+
+const (
+	// ConflictSkip keeps the local memory
+	ConflictSkip ConflictStrategy = "skip"
+	// ConflictOverwrite replaces the local memory with the imported one
+	ConflictOverwrite ConflictStrategy = "overwrite"
+	// ConflictNewestWins keeps whichever copy has the later UpdatedAt
+	ConflictNewestWins ConflictStrategy = "newest-wins"
+	// ConflictMerge takes the content of the newer copy, the union of both
+	// copies' topics, metadata and links, and the higher importance
+	ConflictMerge ConflictStrategy = "merge"
+)
+
+// ParseConflictStrategy validates a strategy name
+func ParseConflictStrategy(name string) (ConflictStrategy, error) {
+	switch strategy := ConflictStrategy(name); strategy {
+	case ConflictSkip, ConflictOverwrite, ConflictNewestWins, ConflictMerge:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("invalid conflict strategy %q (expected skip, overwrite, newest-wins or merge)", name)
+}
+
+// ImportResult counts what ImportAll changed
+type ImportResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Merged  int `json:"merged"`
+	Skipped int `json:"skipped"` // Kept the local copy, or already deleted locally
+	Deleted int `json:"deleted"`
+}
+
+// ListTombstones returns memories deleted at or after since, oldest first
+func (s *Store) ListTombstones(since time.Time) ([]models.Tombstone, error) {
+	rows, err := s.db.Query(`SELECT id, deleted_at FROM memory_tombstones
+		WHERE datetime(deleted_at) >= datetime(?) ORDER BY deleted_at ASC`,
+		since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tombstones []models.Tombstone
+	for rows.Next() {
+		var t models.Tombstone
+		if err := rows.Scan(&t.ID, &t.DeletedAt); err != nil {
+			return nil, err
+		}
+		tombstones = append(tombstones, t)
+	}
+	return tombstones, rows.Err()
+}
+
+// ImportAll upserts memories and applies tombstones in one transaction.
+// Memories that exist locally are resolved with strategy. A deleted memory
+// is only recreated if it was updated after the deletion, and a tombstone
+// only deletes a memory not updated since, so repeated incremental imports
+// converge with the exporting store. Local access statistics and feedback
+// are kept.
+func (s *Store) ImportAll(memories []models.Memory, tombstones []models.Tombstone, strategy ConflictStrategy) (*ImportResult, error) {
+	if _, err := ParseConflictStrategy(string(strategy)); err != nil {
+		return nil, err
+	}
+
+	incoming := make([]*models.Memory, len(memories))
+	for i := range memories {
+		incoming[i] = &memories[i]
+	}
+	if err := s.normalizeMemoryTopics(incoming...); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &ImportResult{}
+	for _, m := range incoming {
+		if m.UpdatedAt.IsZero() {
+			m.UpdatedAt = m.CreatedAt
+		}
+
+		var localEmbedding []byte
+		row := tx.QueryRow("SELECT "+memoryColumns+", embedding FROM memories WHERE id = ?", m.ID)
+		local, err := scanMemory(scanWithEmbedding{row, &localEmbedding})
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		if local == nil {
+			var deletedAt time.Time
+			err := tx.QueryRow("SELECT deleted_at FROM memory_tombstones WHERE id = ?", m.ID).Scan(&deletedAt)
+			if err != nil && err != sql.ErrNoRows {
+				return nil, err
+			}
+			if err == nil && !m.UpdatedAt.After(deletedAt) {
+				result.Skipped++
+				continue
+			}
+			if err := s.insertMemory(tx, m); err != nil {
+				return nil, err
+			}
+			if _, err := tx.Exec("DELETE FROM memory_tombstones WHERE id = ?", m.ID); err != nil {
+				return nil, err
+			}
+			result.Created++
+			continue
+		}
+
+		switch strategy {
+		case ConflictSkip:
+			result.Skipped++
+			continue
+		case ConflictNewestWins:
+			if !m.UpdatedAt.After(local.UpdatedAt) {
+				result.Skipped++
+				continue
+			}
+			result.Updated++
+		case ConflictOverwrite:
+			// Keep the overwrite from being undone by a newer-wins sync
+			if !m.UpdatedAt.After(local.UpdatedAt) {
+				m.UpdatedAt = time.Now()
+			}
+			result.Updated++
+		case ConflictMerge:
+			merged := mergeMemories(local, m)
+			if merged == nil {
+				result.Skipped++
+				continue
+			}
+			m = merged
+			result.Merged++
+		}
+
+		// An export without embeddings shouldn't drop a still valid one
+		if len(m.Embedding) == 0 && m.Content == local.Content && localEmbedding != nil {
+			m.Embedding = decodeEmbedding(localEmbedding)
+		}
+		m.AccessCount = local.AccessCount
+		m.LastAccessedAt = local.LastAccessedAt
+		m.Feedback = local.Feedback
+		if _, err := tx.Exec("DELETE FROM memories WHERE id = ?", m.ID); err != nil {
+			return nil, err
+		}
+		if err := s.insertMemory(tx, m); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, t := range tombstones {
+		n, err := deleteMemories(tx, "SELECT id FROM memories WHERE id = ? AND datetime(updated_at) <= datetime(?)",
+			t.ID, t.DeletedAt.UTC().Format("2006-01-02 15:04:05"))
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			result.Deleted++
+			// deleteMemories stamps the tombstone now; keep the original deletion time
+			if _, err := tx.Exec("UPDATE memory_tombstones SET deleted_at = ? WHERE id = ?", t.DeletedAt.UTC(), t.ID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// Keep a local copy updated after the deletion
+		var exists bool
+		if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM memories WHERE id = ?)", t.ID).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if exists {
+			continue
+		}
+		// Record the deletion so an older copy arriving later isn't recreated
+		if _, err := tx.Exec(`INSERT INTO memory_tombstones (id, deleted_at) VALUES (?, ?)
+			ON CONFLICT(id) DO UPDATE SET deleted_at = MAX(deleted_at, excluded.deleted_at)`, t.ID, t.DeletedAt.UTC()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// mergeMemories combines a local memory with an imported copy: the newer
+// copy's content, the union of topics, metadata and links, the higher
+// importance and either copy's curation. It returns nil if the result
+// wouldn't change the local memory.
+func mergeMemories(local, imported *models.Memory) *models.Memory {
+	newer, older := imported, local
+	if !imported.UpdatedAt.After(local.UpdatedAt) {
+		newer, older = local, imported
+	}
+
+	merged := *newer
+	merged.Topics = union(older.Topics, newer.Topics)
+	merged.RelatedMemories = union(older.RelatedMemories, newer.RelatedMemories)
+	merged.Importance = max(local.Importance, imported.Importance)
+	if merged.CuratedAt == nil {
+		merged.CuratedAt, merged.CuratedBy = older.CuratedAt, older.CuratedBy
+	}
+	if len(older.Metadata)+len(newer.Metadata) > 0 {
+		merged.Metadata = make(map[string]string)
+		for k, v := range older.Metadata {
+			merged.Metadata[k] = v
+		}
+		for k, v := range newer.Metadata {
+			merged.Metadata[k] = v
+		}
+	}
+	if merged.Content == local.Content && merged.Summary == local.Summary &&
+		merged.Importance == local.Importance &&
+		(merged.CuratedAt == nil) == (local.CuratedAt == nil) &&
+		slices.Equal(merged.Topics, local.Topics) &&
+		slices.Equal(merged.RelatedMemories, local.RelatedMemories) &&
+		maps.Equal(merged.Metadata, local.Metadata) {
+		return nil
+	}
+
+	merged.UpdatedAt = time.Now()
+	return &merged
+}
+
+// union returns a followed by the elements of b not in a
+func union(a, b []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, v := range append(append([]string{}, a...), b...) {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,360 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// TopicMergeSuggestion proposes folding one topic into another because
+// their names are textually similar
+type TopicMergeSuggestion struct {
+	Alias          string `json:"alias"`
+	Canonical      string `json:"canonical"`
+	AliasCount     int    `json:"aliasCount"`
+	CanonicalCount int    `json:"canonicalCount"`
+}
+
+// normalizeTopic lowercases and trims a topic name
+func normalizeTopic(topic string) string {
+	return strings.ToLower(strings.TrimSpace(topic))
+}
+
+// topicAliases loads the alias -> canonical topic map
+func (s *Store) topicAliases() (map[string]string, error) {
+	rows, err := s.db.Query("SELECT alias, canonical FROM topic_aliases")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := make(map[string]string)
+	for rows.Next() {
+		var alias, canonical string
+		if err := rows.Scan(&alias, &canonical); err != nil {
+			return nil, err
+		}
+		aliases[alias] = canonical
+	}
+	return aliases, rows.Err()
+}
+
+// canonicalTopics normalizes topics and maps aliases to their canonical
+// topic, dropping empty and duplicate entries
+func canonicalTopics(topics []string, aliases map[string]string) []string {
+	if topics == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	result := []string{}
+	for _, topic := range topics {
+		topic = normalizeTopic(topic)
+		if canonical, ok := aliases[topic]; ok {
+			topic = canonical
+		}
+		if topic == "" || seen[topic] {
+			continue
+		}
+		seen[topic] = true
+		result = append(result, topic)
+	}
+	return result
+}
+
+// normalizeMemoryTopics rewrites the topics of memories about to be stored
+func (s *Store) normalizeMemoryTopics(memories ...*models.Memory) error {
+	aliases, err := s.topicAliases()
+	if err != nil {
+		return err
+	}
+	for _, m := range memories {
+		m.Topics = canonicalTopics(m.Topics, aliases)
+	}
+	return nil
+}
+
+// UpdateMemoryTopics replaces a memory's topics, normalizing them and
+// mapping aliases to their canonical topic
+func (s *Store) UpdateMemoryTopics(id string, topics []string) error {
+	aliases, err := s.topicAliases()
+	if err != nil {
+		return err
+	}
+	topicsJSON, _ := json.Marshal(canonicalTopics(topics, aliases))
+	result, err := s.exec(`UPDATE memories SET topics = ?, updated_at = ? WHERE id = ?`,
+		string(topicsJSON), time.Now(), id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("memory %s not found", id)
+	}
+	return nil
+}
+
+// AddTopicAlias makes alias an alternative name for canonical. Existing
+// memories tagged with alias are retagged, and aliases that pointed at alias
+// now point at canonical.
+func (s *Store) AddTopicAlias(alias, canonical string) error {
+	alias = normalizeTopic(alias)
+	canonical = normalizeTopic(canonical)
+	if alias == "" || canonical == "" {
+		return fmt.Errorf("alias and canonical topic must not be empty")
+	}
+
+	aliases, err := s.topicAliases()
+	if err != nil {
+		return err
+	}
+	// Resolve chains so every alias points directly at a canonical topic
+	if c, ok := aliases[canonical]; ok {
+		canonical = c
+	}
+	if alias == canonical {
+		return fmt.Errorf("%q cannot be an alias of itself", alias)
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`INSERT INTO topic_aliases (alias, canonical) VALUES (?, ?)
+		ON CONFLICT(alias) DO UPDATE SET canonical = excluded.canonical`, alias, canonical); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE topic_aliases SET canonical = ? WHERE canonical = ?", canonical, alias); err != nil {
+		return err
+	}
+	// A topic that becomes an alias can no longer be canonical itself
+	if _, err := tx.Exec("DELETE FROM topic_aliases WHERE alias = ?", canonical); err != nil {
+		return err
+	}
+
+	if _, err := retagTopic(tx.Tx, alias, canonical); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RenameTopic renames topic from to to on every memory tagged with it and
+// in the alias table, in one transaction, and returns how many memories
+// changed. If to is already in use the topics merge, and memories tagged
+// with both keep it once. Aliases of from become aliases of to; to stops
+// being an alias if it was one.
+func (s *Store) RenameTopic(from, to string) (int, error) {
+	from = normalizeTopic(from)
+	to = normalizeTopic(to)
+	if from == "" || to == "" {
+		return 0, fmt.Errorf("topic names must not be empty")
+	}
+	if from == to {
+		return 0, fmt.Errorf("%q is already called that", from)
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// to becomes a canonical topic, and from keeps its role under the new
+	// name
+	if _, err := tx.Exec("DELETE FROM topic_aliases WHERE alias = ?", to); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec("UPDATE topic_aliases SET alias = ? WHERE alias = ?", to, from); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec("UPDATE topic_aliases SET canonical = ? WHERE canonical = ?", to, from); err != nil {
+		return 0, err
+	}
+	// Renaming an alias to its canonical topic leaves it pointing at itself
+	if _, err := tx.Exec("DELETE FROM topic_aliases WHERE alias = canonical"); err != nil {
+		return 0, err
+	}
+
+	changed, err := retagTopic(tx.Tx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	return changed, tx.Commit()
+}
+
+// retagTopic replaces topic from with to on every memory tagged with it,
+// dropping duplicates, and returns how many memories it changed
+func retagTopic(tx *sql.Tx, from, to string) (int, error) {
+	rows, err := tx.Query(`SELECT id, topics FROM memories
+		WHERE EXISTS (SELECT 1 FROM json_each(memories.topics) WHERE lower(value) = ?)`, from)
+	if err != nil {
+		return 0, err
+	}
+
+	updates := make(map[string]string)
+	for rows.Next() {
+		var id string
+		var topicsJSON sql.NullString
+		if err := rows.Scan(&id, &topicsJSON); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		var topics []string
+		json.Unmarshal([]byte(topicsJSON.String), &topics)
+		retagged, _ := json.Marshal(canonicalTopics(topics, map[string]string{from: to}))
+		updates[id] = string(retagged)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for id, topicsJSON := range updates {
+		if _, err := tx.Exec("UPDATE memories SET topics = ?, updated_at = ? WHERE id = ?", topicsJSON, now, id); err != nil {
+			return 0, err
+		}
+	}
+	return len(updates), nil
+}
+
+// topicCounts returns how many memories are tagged with each topic
+func (s *Store) topicCounts() (map[string]int, error) {
+	rows, err := s.db.Query(`SELECT lower(trim(value)), COUNT(*) FROM memories, json_each(memories.topics)
+		WHERE json_valid(memories.topics) AND json_each.type = 'text' GROUP BY lower(trim(value))`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var topic string
+		var count int
+		if err := rows.Scan(&topic, &count); err != nil {
+			return nil, err
+		}
+		counts[topic] = count
+	}
+	return counts, rows.Err()
+}
+
+// ListTopics returns the topics in use, most used first
+func (s *Store) ListTopics() ([]string, error) {
+	counts, err := s.topicCounts()
+	if err != nil {
+		return nil, err
+	}
+
+	topics := make([]string, 0, len(counts))
+	for topic := range counts {
+		if topic != "" {
+			topics = append(topics, topic)
+		}
+	}
+	sort.Slice(topics, func(i, j int) bool {
+		if counts[topics[i]] != counts[topics[j]] {
+			return counts[topics[i]] > counts[topics[j]]
+		}
+		return topics[i] < topics[j]
+	})
+	return topics, nil
+}
+
+// SuggestTopicMerges finds pairs of topics whose names are textually similar
+// (differing only in punctuation, plurals or a small typo). The more widely
+// used topic is proposed as canonical.
+func (s *Store) SuggestTopicMerges() ([]TopicMergeSuggestion, error) {
+	counts, err := s.topicCounts()
+	if err != nil {
+		return nil, err
+	}
+
+	topics := make([]string, 0, len(counts))
+	for topic := range counts {
+		if topic != "" {
+			topics = append(topics, topic)
+		}
+	}
+	sort.Strings(topics)
+
+	var suggestions []TopicMergeSuggestion
+	for i := 0; i < len(topics); i++ {
+		for j := i + 1; j < len(topics); j++ {
+			a, b := topics[i], topics[j]
+			if !similarTopics(a, b) {
+				continue
+			}
+			// Prefer the more used topic, then the shorter name
+			if counts[b] > counts[a] || (counts[b] == counts[a] && len(b) < len(a)) {
+				a, b = b, a
+			}
+			suggestions = append(suggestions, TopicMergeSuggestion{
+				Alias:          b,
+				Canonical:      a,
+				AliasCount:     counts[b],
+				CanonicalCount: counts[a],
+			})
+		}
+	}
+
+	return suggestions, nil
+}
+
+// similarTopics reports whether two topic names likely mean the same thing
+func similarTopics(a, b string) bool {
+	a, b = topicKey(a), topicKey(b)
+	if a == b {
+		return true
+	}
+	if strings.TrimSuffix(a, "s") == strings.TrimSuffix(b, "s") {
+		return true
+	}
+	// Allow one edit for longer names, where a single typo is unlikely to
+	// turn one real word into another
+	if len([]rune(a)) >= 5 && len([]rune(b)) >= 5 {
+		return editDistance(a, b) <= 1
+	}
+	return false
+}
+
+// topicKey strips everything but letters and digits, so "react-js",
+// "react_js" and "reactjs" compare equal
+func topicKey(topic string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, topic)
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
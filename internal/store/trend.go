@@ -0,0 +1,84 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// TrendBucket is the number of memories created in one time period
+type TrendBucket struct {
+	Period string `json:"period"` // Day (2006-01-02), week start (Monday) or month (2006-01)
+	Count  int    `json:"count"`
+}
+
+// GetCreationTrend returns memory creation counts for the last periods
+// buckets of the given size (day, week or month), oldest first. Periods
+// without memories are included with a zero count. Times are in UTC.
+func (s *Store) GetCreationTrend(bucket string, periods int) ([]TrendBucket, error) {
+	if periods <= 0 {
+		periods = 7
+	}
+
+	now := time.Now().UTC()
+	var expr string
+	var keys []string
+	var start time.Time
+
+	switch bucket {
+	case "", "day":
+		expr = "strftime('%Y-%m-%d', created_at)"
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		start = today.AddDate(0, 0, -(periods - 1))
+		for i := 0; i < periods; i++ {
+			keys = append(keys, start.AddDate(0, 0, i).Format("2006-01-02"))
+		}
+	case "week":
+		// Weeks start on Monday
+		expr = "date(created_at, 'weekday 0', '-6 days')"
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		start = monday.AddDate(0, 0, -7*(periods-1))
+		for i := 0; i < periods; i++ {
+			keys = append(keys, start.AddDate(0, 0, 7*i).Format("2006-01-02"))
+		}
+	case "month":
+		expr = "strftime('%Y-%m', created_at)"
+		month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		start = month.AddDate(0, -(periods - 1), 0)
+		for i := 0; i < periods; i++ {
+			keys = append(keys, start.AddDate(0, i, 0).Format("2006-01"))
+		}
+	default:
+		return nil, fmt.Errorf("invalid bucket %q (expected day, week or month)", bucket)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT `+expr+` AS period, COUNT(*)
+		FROM memories
+		WHERE datetime(created_at) >= ?
+		GROUP BY period
+	`, start.Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var period string
+		var count int
+		if err := rows.Scan(&period, &count); err != nil {
+			return nil, err
+		}
+		counts[period] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	trend := make([]TrendBucket, len(keys))
+	for i, key := range keys {
+		trend[i] = TrendBucket{Period: key, Count: counts[key]}
+	}
+	return trend, nil
+}
@@ -0,0 +1,177 @@
+package summary
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// MinLength and MaxLength bound configured summary lengths, in characters
+const (
+	MinLength = 20
+	MaxLength = 500
+)
+
+// defaultTypeLengths are the built-in summary lengths of the types whose
+// summaries need more room than DefaultMaxLen: decisions carry their
+// rationale, mistakes and learnings what to do differently
+var defaultTypeLengths = map[models.MemoryType]int{
+	models.MemoryTypeDecision: 160,
+	models.MemoryTypeMistake:  140,
+	models.MemoryTypeLearning: 140,
+	models.MemoryTypePattern:  120,
+}
+
+// Lengths sets how long summaries of each memory type may be, in
+// characters. Types that aren't listed use Default.
+type Lengths struct {
+	Default int                       `json:"default,omitempty"`
+	Types   map[models.MemoryType]int `json:"types,omitempty"`
+}
+
+// DefaultLengths returns the built-in lengths: 160 characters for
+// decisions, 140 for mistakes and learnings, 120 for patterns and
+// DefaultMaxLen for facts and preferences
+func DefaultLengths() Lengths {
+	l := Lengths{Default: DefaultMaxLen, Types: make(map[models.MemoryType]int, len(defaultTypeLengths))}
+	for t, n := range defaultTypeLengths {
+		l.Types[t] = n
+	}
+	return l
+}
+
+// LoadLengths reads lengths from a JSON file over the built-in ones
+func LoadLengths(path string) (Lengths, error) {
+	l := DefaultLengths()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return l, err
+	}
+	var file Lengths
+	if err := json.Unmarshal(data, &file); err != nil {
+		return l, fmt.Errorf("invalid summary lengths %s: %w", path, err)
+	}
+	if file.Default != 0 {
+		l.Default = file.Default
+	}
+	for t, n := range file.Types {
+		l.Types[t] = n
+	}
+	if err := l.Validate(); err != nil {
+		return l, fmt.Errorf("invalid summary lengths %s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Validate checks that every type is known and every length is between
+// MinLength and MaxLength
+func (l Lengths) Validate() error {
+	if err := validLength(l.fallback()); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+	for t, n := range l.Types {
+		if !t.Valid() {
+			return fmt.Errorf("unknown memory type %q", t)
+		}
+		if err := validLength(n); err != nil {
+			return fmt.Errorf("%s: %w", t, err)
+		}
+	}
+	return nil
+}
+
+func validLength(n int) error {
+	if n < MinLength || n > MaxLength {
+		return fmt.Errorf("length %d must be between %d and %d", n, MinLength, MaxLength)
+	}
+	return nil
+}
+
+// For returns the summary length of memory type t
+func (l Lengths) For(t models.MemoryType) int {
+	if n, ok := l.Types[t]; ok {
+		return n
+	}
+	return l.fallback()
+}
+
+// fallback returns the length of types that aren't listed
+func (l Lengths) fallback() int {
+	if l.Default > 0 {
+		return l.Default
+	}
+	return DefaultMaxLen
+}
+
+// Describe lists the lengths that differ from the default, e.g.
+// "decision 160, mistake 140, other types 100"
+func (l Lengths) Describe() string {
+	var parts []string
+	for _, t := range models.MemoryTypes {
+		if n, ok := l.Types[t]; ok && n != l.fallback() {
+			parts = append(parts, fmt.Sprintf("%s %d", t, n))
+		}
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("%d", l.fallback())
+	}
+	return strings.Join(parts, ", ") + fmt.Sprintf(", other types %d", l.fallback())
+}
+
+// TypeSummarizer summarizes content to the length configured for a memory
+// type
+type TypeSummarizer interface {
+	Summarizer
+	SummarizeType(text string, t models.MemoryType) (string, error)
+}
+
+// ForType summarizes text for a memory of type t: at that type's length if
+// sum is a TypeSummarizer, otherwise as sum always does
+func ForType(sum Summarizer, text string, t models.MemoryType) (string, error) {
+	if ts, ok := sum.(TypeSummarizer); ok {
+		return ts.SummarizeType(text, t)
+	}
+	return sum.Summarize(text)
+}
+
+// typedSummarizer runs one backend summarizer per configured length
+type typedSummarizer struct {
+	lengths  Lengths
+	byLength map[int]Summarizer
+}
+
+// NewTyped returns a summarizer for the named backend (see New) that
+// summarizes each memory type to its length in lengths. Summarize uses the
+// default length.
+func NewTyped(backend string, lengths Lengths) (TypeSummarizer, error) {
+	if err := lengths.Validate(); err != nil {
+		return nil, err
+	}
+	t := &typedSummarizer{lengths: lengths, byLength: make(map[int]Summarizer)}
+	for _, n := range append([]int{lengths.fallback()}, slices.Collect(maps.Values(lengths.Types))...) {
+		if _, ok := t.byLength[n]; ok {
+			continue
+		}
+		sum, err := New(backend, n)
+		if err != nil {
+			return nil, err
+		}
+		t.byLength[n] = sum
+	}
+	return t, nil
+}
+
+// Summarize summarizes text to the default length
+func (t *typedSummarizer) Summarize(text string) (string, error) {
+	return t.byLength[t.lengths.fallback()].Summarize(text)
+}
+
+// SummarizeType summarizes text to the length of memory type typ
+func (t *typedSummarizer) SummarizeType(text string, typ models.MemoryType) (string, error) {
+	return t.byLength[t.lengths.For(typ)].Summarize(text)
+}
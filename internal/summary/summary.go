@@ -0,0 +1,177 @@
+package summary
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultMaxLen is the default maximum summary length in characters
+const DefaultMaxLen = 100
+
+// Summarizer produces a short summary of memory content
+type Summarizer interface {
+	Summarize(text string) (string, error)
+}
+
+// New returns a summarizer for the named backend ("truncate" or "ollama").
+// An empty backend selects truncation.
+func New(backend string, maxLen int) (Summarizer, error) {
+	switch backend {
+	case "", "truncate":
+		return NewTruncatingSummarizer(maxLen), nil
+	case "ollama":
+		return NewOllamaSummarizer("", "", maxLen), nil
+	default:
+		return nil, fmt.Errorf("unknown summarizer %q (expected truncate or ollama)", backend)
+	}
+}
+
+// TruncatingSummarizer shortens text at a sentence or word boundary
+type TruncatingSummarizer struct {
+	maxLen int
+}
+
+// NewTruncatingSummarizer creates a summarizer that never exceeds maxLen
+func NewTruncatingSummarizer(maxLen int) *TruncatingSummarizer {
+	if maxLen <= 0 {
+		maxLen = DefaultMaxLen
+	}
+	return &TruncatingSummarizer{maxLen: maxLen}
+}
+
+// Summarize returns text unchanged if it fits, otherwise the longest run of
+// whole sentences that fits, falling back to whole words followed by "..."
+func (t *TruncatingSummarizer) Summarize(text string) (string, error) {
+	return Truncate(text, t.maxLen), nil
+}
+
+// Truncate shortens text to at most maxLen characters, preferring to end at
+// a sentence boundary, then a word boundary (with "..."), then a hard cut
+func Truncate(text string, maxLen int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= maxLen {
+		return text
+	}
+
+	// Prefer whole sentences if at least half the budget is used
+	window := string(runes[:maxLen])
+	if i := lastSentenceEnd(window); i >= 0 && len([]rune(window[:i+1])) >= maxLen/2 {
+		return window[:i+1]
+	}
+
+	const ellipsis = "..."
+	if maxLen <= len(ellipsis) {
+		return string(runes[:maxLen])
+	}
+
+	cut := string(runes[:maxLen-len(ellipsis)])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	cut = strings.TrimRight(cut, " ,;:-")
+	return cut + ellipsis
+}
+
+// lastSentenceEnd returns the byte index of the last sentence terminator in
+// s that is followed by a space, or -1
+func lastSentenceEnd(s string) int {
+	for i := len(s) - 2; i >= 0; i-- {
+		switch s[i] {
+		case '.', '!', '?':
+			if s[i+1] == ' ' {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// OllamaSummarizer asks a local LLM for a summary, falling back to truncation
+type OllamaSummarizer struct {
+	endpoint string
+	model    string
+	maxLen   int
+	client   *http.Client
+}
+
+// NewOllamaSummarizer creates a new Ollama-backed summarizer
+func NewOllamaSummarizer(endpoint, model string, maxLen int) *OllamaSummarizer {
+	if endpoint == "" {
+		endpoint = "http://localhost:11434"
+	}
+	if model == "" {
+		model = "llama3.2"
+	}
+	if maxLen <= 0 {
+		maxLen = DefaultMaxLen
+	}
+	return &OllamaSummarizer{
+		endpoint: endpoint,
+		model:    model,
+		maxLen:   maxLen,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+const summaryPrompt = `Summarize the following note in one short sentence of at most %d characters.
+Respond with the summary only, no quotes or explanation.
+
+%s`
+
+type ollamaGenerateRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Stream bool   `json:"stream"`
+}
+
+type ollamaGenerateResponse struct {
+	Response string `json:"response"`
+}
+
+// Summarize generates a summary with the LLM. Short text is returned as is,
+// and the result is truncated if the model overshoots the length. If the
+// LLM is unavailable, a truncated summary is returned along with the error.
+func (o *OllamaSummarizer) Summarize(text string) (string, error) {
+	if len([]rune(text)) <= o.maxLen {
+		return Truncate(text, o.maxLen), nil
+	}
+
+	body, err := json.Marshal(ollamaGenerateRequest{
+		Model:  o.model,
+		Prompt: fmt.Sprintf(summaryPrompt, o.maxLen, text),
+		Stream: false,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := o.client.Post(o.endpoint+"/api/generate", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return Truncate(text, o.maxLen), fmt.Errorf("ollama request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return Truncate(text, o.maxLen), fmt.Errorf("ollama error: %s", string(body))
+	}
+
+	var result ollamaGenerateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return Truncate(text, o.maxLen), fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	summary := strings.Trim(strings.TrimSpace(result.Response), `"`)
+	if summary == "" {
+		return Truncate(text, o.maxLen), nil
+	}
+	return Truncate(summary, o.maxLen), nil
+}
@@ -0,0 +1,127 @@
+package tokenize
+
+import (
+	"strings"
+	"unicode"
+)
+
+// English is the built-in stopword list: words too common to help a
+// keyword search
+var English = []string{
+	"a", "about", "after", "all", "also", "am", "an", "and", "any", "are",
+	"as", "at", "be", "been", "before", "being", "but", "by", "can", "could",
+	"did", "do", "does", "doing", "done", "for", "from", "had", "has", "have",
+	"having", "he", "her", "here", "him", "his", "how", "i", "if", "in",
+	"into", "is", "it", "its", "just", "me", "my", "no", "not", "of", "on",
+	"or", "our", "ours", "out", "over", "she", "should", "so", "some", "than",
+	"that", "the", "their", "them", "then", "there", "these", "they", "this",
+	"those", "to", "too", "up", "us", "use", "used", "using", "very", "was",
+	"we", "were", "what", "when", "where", "which", "while", "who", "why",
+	"will", "with", "would", "you", "your",
+}
+
+// Config customizes a Tokenizer. The zero value is the default: the
+// English stopwords and stemming.
+type Config struct {
+	// Stopwords replaces the built-in list when set
+	Stopwords []string `json:"stopwords,omitempty"`
+	// ExtraStopwords are added to the list
+	ExtraStopwords []string `json:"extraStopwords,omitempty"`
+	// Stemming reduces words to a common stem ("caching" and "caches"
+	// both become "cach"). Defaults to true.
+	Stemming *bool `json:"stemming,omitempty"`
+}
+
+// Tokenizer splits text into the normalized terms used by keyword search
+type Tokenizer struct {
+	stopwords map[string]bool
+	stem      bool
+}
+
+// New creates a tokenizer from cfg
+func New(cfg Config) *Tokenizer {
+	words := cfg.Stopwords
+	if words == nil {
+		words = English
+	}
+	t := &Tokenizer{
+		stopwords: make(map[string]bool),
+		stem:      cfg.Stemming == nil || *cfg.Stemming,
+	}
+	for _, w := range append(append([]string{}, words...), cfg.ExtraStopwords...) {
+		t.stopwords[strings.ToLower(strings.TrimSpace(w))] = true
+	}
+	return t
+}
+
+// Default returns a tokenizer with the default configuration
+func Default() *Tokenizer {
+	return New(Config{})
+}
+
+// Tokens lowercases text, splits it into words of letters and digits, drops
+// stopwords and single characters, and stems what remains. Each term is
+// returned once, in order of first appearance.
+func (t *Tokenizer) Tokens(text string) []string {
+	seen := make(map[string]bool)
+	var tokens []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) < 2 || t.stopwords[word] {
+			continue
+		}
+		if t.stem {
+			// Check the stem too, so "retries" is dropped with "retry"
+			if word = Stem(word); t.stopwords[word] {
+				continue
+			}
+		}
+		if !seen[word] {
+			seen[word] = true
+			tokens = append(tokens, word)
+		}
+	}
+	return tokens
+}
+
+// Stem strips common English inflections from a lowercase word: plurals,
+// -ing, -ed and a trailing e, so that for example "handle", "handled",
+// "handles" and "handling" share the stem "handl". It is deliberately
+// simple; words it doesn't recognize are returned unchanged.
+func Stem(word string) string {
+	r := []rune(word)
+	if len(r) < 4 {
+		return word
+	}
+	has := func(suffix string) bool { return strings.HasSuffix(string(r), suffix) }
+
+	switch {
+	case has("ies") && len(r) > 4:
+		r = append(r[:len(r)-3], 'y')
+	case has("sses"):
+		r = r[:len(r)-2]
+	case has("s") && !has("ss") && !has("us") && !has("is"):
+		r = r[:len(r)-1]
+	}
+
+	for _, suffix := range []string{"ing", "ed"} {
+		if has(suffix) && len(r)-len(suffix) >= 3 {
+			r = r[:len(r)-len(suffix)]
+			// "running" -> "runn" -> "run"
+			if n := len(r); n >= 2 && r[n-1] == r[n-2] && !strings.ContainsRune("lsz", r[n-1]) && !isVowel(r[n-1]) {
+				r = r[:n-1]
+			}
+			break
+		}
+	}
+
+	if has("e") && len(r) > 3 {
+		r = r[:len(r)-1]
+	}
+	return string(r)
+}
+
+func isVowel(r rune) bool {
+	return strings.ContainsRune("aeiou", r)
+}
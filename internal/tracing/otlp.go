@@ -0,0 +1,231 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// exportInterval is how often queued spans are sent
+	exportInterval = 5 * time.Second
+	// exportBatch is how many queued spans trigger an early send
+	exportBatch = 512
+	// queueSize bounds spans waiting for export; more are dropped
+	queueSize = 4096
+)
+
+// Init enables tracing if an OTLP endpoint is configured in the standard
+// OpenTelemetry environment variables, and returns a function that flushes
+// queued spans and stops the exporter. It does nothing if tracing isn't
+// configured.
+func Init(defaultServiceName string) (shutdown func()) {
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if endpoint == "" {
+		base := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+		if base == "" {
+			return func() {}
+		}
+		endpoint = strings.TrimSuffix(base, "/") + "/v1/traces"
+	}
+
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+
+	e := &otlpExporter{
+		endpoint:    endpoint,
+		headers:     parseHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS")),
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		queue:       make(chan *Span, queueSize),
+		done:        make(chan struct{}),
+	}
+	go e.run()
+	exporter.Store(e)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			exporter.Store(nil)
+			e.close()
+		})
+	}
+}
+
+// parseHeaders parses OTEL_EXPORTER_OTLP_HEADERS ("key=value,key2=value2")
+func parseHeaders(value string) map[string]string {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	return headers
+}
+
+// otlpExporter batches finished spans and posts them to an OTLP/HTTP
+// endpoint
+type otlpExporter struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	client      *http.Client
+	queue       chan *Span
+	done        chan struct{}
+
+	mu     sync.Mutex // Guards closed and closing the queue
+	closed bool
+}
+
+// enqueue hands a span to the exporter without blocking; spans are dropped
+// while the queue is full or after the exporter is closed
+func (e *otlpExporter) enqueue(s *Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return
+	}
+	select {
+	case e.queue <- s:
+	default:
+	}
+}
+
+// close stops accepting spans and waits for the queued ones to be sent
+func (e *otlpExporter) close() {
+	e.mu.Lock()
+	if !e.closed {
+		e.closed = true
+		close(e.queue)
+	}
+	e.mu.Unlock()
+	<-e.done
+}
+
+func (e *otlpExporter) run() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+
+	var batch []*Span
+	for {
+		select {
+		case s, ok := <-e.queue:
+			if !ok {
+				e.export(batch)
+				return
+			}
+			batch = append(batch, s)
+			if len(batch) >= exportBatch {
+				e.export(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			e.export(batch)
+			batch = nil
+		}
+	}
+}
+
+func (e *otlpExporter) export(spans []*Span) {
+	if len(spans) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(e.encode(spans))
+	if err != nil {
+		log.Printf("Failed to encode spans: %v", err)
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("Failed to export spans: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, val := range e.headers {
+		req.Header.Set(key, val)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		log.Printf("Failed to export spans: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		log.Printf("Failed to export spans: %s: %s", resp.Status, msg)
+	}
+}
+
+// encode builds an OTLP ExportTraceServiceRequest in its JSON mapping
+func (e *otlpExporter) encode(spans []*Span) map[string]interface{} {
+	encoded := make([]map[string]interface{}, len(spans))
+	for i, s := range spans {
+		span := map[string]interface{}{
+			"traceId":           hex.EncodeToString(s.traceID[:]),
+			"spanId":            hex.EncodeToString(s.spanID[:]),
+			"name":              s.name,
+			"kind":              1, // SPAN_KIND_INTERNAL
+			"startTimeUnixNano": strconv.FormatInt(s.start.UnixNano(), 10),
+			"endTimeUnixNano":   strconv.FormatInt(s.end.UnixNano(), 10),
+			"attributes":        encodeAttributes(s.attrs),
+		}
+		if s.parentID != [8]byte{} {
+			span["parentSpanId"] = hex.EncodeToString(s.parentID[:])
+		}
+		if s.failed {
+			span["status"] = map[string]interface{}{"code": 2, "message": s.errMsg} // STATUS_CODE_ERROR
+		}
+		encoded[i] = span
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []map[string]interface{}{{
+			"resource": map[string]interface{}{
+				"attributes": encodeAttributes([]attribute{{"service.name", e.serviceName}}),
+			},
+			"scopeSpans": []map[string]interface{}{{
+				"scope": map[string]string{"name": "github.com/contextpilot-dev/memorypilot"},
+				"spans": encoded,
+			}},
+		}},
+	}
+}
+
+func encodeAttributes(attrs []attribute) []map[string]interface{} {
+	encoded := make([]map[string]interface{}, len(attrs))
+	for i, a := range attrs {
+		var value map[string]interface{}
+		switch v := a.value.(type) {
+		case string:
+			value = map[string]interface{}{"stringValue": v}
+		case int64:
+			value = map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+		case float64:
+			value = map[string]interface{}{"doubleValue": v}
+		case bool:
+			value = map[string]interface{}{"boolValue": v}
+		default:
+			value = map[string]interface{}{"stringValue": fmt.Sprint(v)}
+		}
+		encoded[i] = map[string]interface{}{"key": a.key, "value": value}
+	}
+	return encoded
+}
@@ -0,0 +1,134 @@
+// Package tracing records OpenTelemetry spans and exports them with OTLP
+// over HTTP (JSON encoding). Tracing is enabled by Init when
+// OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is set;
+// otherwise Start returns a nil span and every span method is a no-op.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// exporter receives finished spans; nil while tracing is disabled
+var exporter atomic.Pointer[otlpExporter]
+
+// Enabled reports whether spans are being recorded
+func Enabled() bool {
+	return exporter.Load() != nil
+}
+
+// Span is one timed operation in a trace. A nil *Span is valid and records
+// nothing.
+type Span struct {
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	name     string
+	start    time.Time
+	end      time.Time
+	attrs    []attribute
+	errMsg   string
+	failed   bool
+}
+
+type attribute struct {
+	key   string
+	value interface{} // string, int64, float64 or bool
+}
+
+type spanKey struct{}
+
+// remoteParent is a parent span from another process, taken from a W3C
+// traceparent header
+type remoteParent struct {
+	traceID [16]byte
+	spanID  [8]byte
+}
+
+type remoteKey struct{}
+
+// Start begins a span named name, as a child of the span in ctx if there is
+// one, and returns a context carrying the new span. Call End on the span
+// when the operation finishes.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	if !Enabled() {
+		return ctx, nil
+	}
+
+	s := &Span{name: name, start: time.Now()}
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok && parent != nil {
+		s.traceID = parent.traceID
+		s.parentID = parent.spanID
+	} else if remote, ok := ctx.Value(remoteKey{}).(remoteParent); ok {
+		s.traceID = remote.traceID
+		s.parentID = remote.spanID
+	} else {
+		rand.Read(s.traceID[:])
+	}
+	rand.Read(s.spanID[:])
+
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// WithTraceparent returns a context whose next root span continues the
+// trace in a W3C traceparent header value. Invalid values are ignored.
+func WithTraceparent(ctx context.Context, traceparent string) context.Context {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return ctx
+	}
+	var remote remoteParent
+	if _, err := hex.Decode(remote.traceID[:], []byte(parts[1])); err != nil {
+		return ctx
+	}
+	if _, err := hex.Decode(remote.spanID[:], []byte(parts[2])); err != nil {
+		return ctx
+	}
+	if remote.traceID == [16]byte{} || remote.spanID == [8]byte{} {
+		return ctx
+	}
+	return context.WithValue(ctx, remoteKey{}, remote)
+}
+
+// SetAttr records an attribute on the span. Values other than strings,
+// integers, floats and booleans are formatted as strings.
+func (s *Span) SetAttr(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	switch v := value.(type) {
+	case string, int64, float64, bool:
+	case int:
+		value = int64(v)
+	case float32:
+		value = float64(v)
+	default:
+		value = fmt.Sprint(v)
+	}
+	s.attrs = append(s.attrs, attribute{key, value})
+}
+
+// SetError marks the span as failed if err is not nil
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.failed = true
+	s.errMsg = err.Error()
+}
+
+// End finishes the span and queues it for export
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.end = time.Now()
+	if e := exporter.Load(); e != nil {
+		e.enqueue(s)
+	}
+}
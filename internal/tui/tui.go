@@ -0,0 +1,567 @@
+// Package tui is an interactive terminal browser for the memory store:
+// live search, paging through results, memory detail, editing, deleting and
+// filtering by type and topic.
+package tui
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/contextpilot-dev/memorypilot/internal/embedding"
+	"github.com/contextpilot-dev/memorypilot/internal/store"
+	"github.com/contextpilot-dev/memorypilot/internal/summary"
+	"github.com/contextpilot-dev/memorypilot/pkg/models"
+)
+
+// pageSize is how many more results are loaded when scrolling past the end
+const pageSize = 100
+
+// memoryTypes are cycled through by the type filter; "" shows all types
+var memoryTypes = append([]models.MemoryType{""}, models.MemoryTypes...)
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	dimStyle      = lipgloss.NewStyle().Faint(true)
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+// mode is what the keyboard currently drives
+type mode int
+
+const (
+	modeList    mode = iota // Typing searches, arrows move through results
+	modeDetail              // Full view of the selected memory
+	modeTopic               // Typing edits the topic filter
+	modeConfirm             // Waiting for y/n to delete the selected memory
+)
+
+// Options configures the browser
+type Options struct {
+	Embedder   embedding.Embedder // Re-embeds edited memories; nil to skip
+	Summarizer summary.Summarizer // Summarizes edited memories
+	Editor     string             // Command used to edit memories
+}
+
+// model is the bubbletea model of the browser
+type model struct {
+	store *store.Store
+	opts  Options
+
+	query      string
+	typeIndex  int // Into memoryTypes
+	topic      string
+	topicInput string
+
+	results []models.Memory
+	limit   int
+	more    bool // The last search filled limit, so more may match
+	seq     int  // Latest search; older results are dropped
+
+	cursor int // Selected result
+	top    int // First result on screen
+	scroll int // First line of the detail view
+
+	mode   mode
+	back   mode // Where to return after confirming a delete
+	status string
+	err    error
+
+	width, height int
+}
+
+// searchMsg carries the results of search number seq
+type searchMsg struct {
+	seq     int
+	results []models.Memory
+	err     error
+}
+
+// editedMsg reports that the editor exited for memory id
+type editedMsg struct {
+	id   string
+	path string
+	err  error
+}
+
+// savedMsg reports the outcome of writing or deleting a memory
+type savedMsg struct {
+	status string
+	err    error
+}
+
+// Run opens the browser on s and blocks until the user quits
+func Run(s *store.Store, opts Options) error {
+	if opts.Summarizer == nil {
+		opts.Summarizer = summary.NewTruncatingSummarizer(summary.DefaultMaxLen)
+	}
+	if opts.Editor == "" {
+		opts.Editor = "vi"
+	}
+	m := &model{store: s, opts: opts, limit: pageSize}
+	_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m *model) Init() tea.Cmd {
+	return m.search()
+}
+
+// search starts a search for the current query and filters. An empty query
+// lists the newest memories.
+func (m *model) search() tea.Cmd {
+	m.seq++
+	seq, query, limit := m.seq, strings.TrimSpace(m.query), m.limit
+	var types []models.MemoryType
+	if t := memoryTypes[m.typeIndex]; t != "" {
+		types = []models.MemoryType{t}
+	}
+	var topics []string
+	if m.topic != "" {
+		topics = []string{m.topic}
+	}
+
+	return func() tea.Msg {
+		if query == "" {
+			results, err := m.store.ListMemories(store.ListOptions{Types: types, Topics: topics, Limit: limit})
+			slices.Reverse(results)
+			return searchMsg{seq, results, err}
+		}
+		results, err := m.store.Recall(models.RecallRequest{Query: query, Types: types, Topics: topics, Limit: limit})
+		return searchMsg{seq, results, err}
+	}
+}
+
+// restart searches again from the first page
+func (m *model) restart() tea.Cmd {
+	m.limit = pageSize
+	m.cursor, m.top = 0, 0
+	return m.search()
+}
+
+// selected returns the selected memory, or nil if there are no results
+func (m *model) selected() *models.Memory {
+	if m.cursor < 0 || m.cursor >= len(m.results) {
+		return nil
+	}
+	return &m.results[m.cursor]
+}
+
+// listHeight is how many results fit on screen
+func (m *model) listHeight() int {
+	return max(m.height-4, 1)
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.clampCursor()
+		return m, nil
+
+	case searchMsg:
+		if msg.seq != m.seq {
+			return m, nil
+		}
+		m.err = msg.err
+		m.results = msg.results
+		m.more = len(msg.results) == m.limit
+		m.clampCursor()
+		return m, nil
+
+	case editedMsg:
+		return m, m.saveEdit(msg)
+
+	case savedMsg:
+		m.status, m.err = msg.status, msg.err
+		return m, m.search()
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		switch m.mode {
+		case modeDetail:
+			return m.updateDetail(msg)
+		case modeTopic:
+			return m.updateTopic(msg)
+		case modeConfirm:
+			return m.updateConfirm(msg)
+		default:
+			return m.updateList(msg)
+		}
+	}
+	return m, nil
+}
+
+// updateList handles keys while browsing results. Printable keys edit the
+// query, so commands use control keys.
+func (m *model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status = ""
+	switch msg.Type {
+	case tea.KeyEsc:
+		if m.query == "" {
+			return m, tea.Quit
+		}
+		m.query = ""
+		return m, m.restart()
+	case tea.KeyUp, tea.KeyCtrlP:
+		return m, m.move(-1)
+	case tea.KeyDown, tea.KeyCtrlN:
+		return m, m.move(1)
+	case tea.KeyPgUp:
+		return m, m.move(-m.listHeight())
+	case tea.KeyPgDown:
+		return m, m.move(m.listHeight())
+	case tea.KeyHome:
+		return m, m.move(-len(m.results))
+	case tea.KeyEnd:
+		return m, m.move(len(m.results))
+	case tea.KeyEnter:
+		if m.selected() != nil {
+			m.mode, m.scroll = modeDetail, 0
+		}
+	case tea.KeyCtrlT:
+		m.typeIndex = (m.typeIndex + 1) % len(memoryTypes)
+		return m, m.restart()
+	case tea.KeyCtrlF:
+		m.mode, m.topicInput = modeTopic, m.topic
+	case tea.KeyCtrlE:
+		return m, m.edit()
+	case tea.KeyCtrlD:
+		if m.selected() != nil {
+			m.mode, m.back = modeConfirm, modeList
+		}
+	case tea.KeyBackspace:
+		if m.query != "" {
+			runes := []rune(m.query)
+			m.query = string(runes[:len(runes)-1])
+			return m, m.restart()
+		}
+	case tea.KeyCtrlU:
+		m.query = ""
+		return m, m.restart()
+	case tea.KeyRunes, tea.KeySpace:
+		m.query += string(msg.Runes)
+		return m, m.restart()
+	}
+	return m, nil
+}
+
+// updateDetail handles keys in the detail view
+func (m *model) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status = ""
+	switch msg.String() {
+	case "esc", "q", "enter", "backspace":
+		m.mode = modeList
+	case "up", "k":
+		m.scroll = max(m.scroll-1, 0)
+	case "down", "j":
+		m.scroll++
+	case "pgup":
+		m.scroll = max(m.scroll-m.listHeight(), 0)
+	case "pgdown", " ":
+		m.scroll += m.listHeight()
+	case "e", "ctrl+e":
+		return m, m.edit()
+	case "d", "ctrl+d":
+		m.mode, m.back = modeConfirm, modeDetail
+	}
+	return m, nil
+}
+
+// updateTopic handles keys while editing the topic filter
+func (m *model) updateTopic(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = modeList
+	case tea.KeyEnter:
+		m.mode = modeList
+		m.topic = strings.TrimSpace(m.topicInput)
+		return m, m.restart()
+	case tea.KeyBackspace:
+		if m.topicInput != "" {
+			runes := []rune(m.topicInput)
+			m.topicInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		m.topicInput = ""
+	case tea.KeyRunes, tea.KeySpace:
+		m.topicInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// updateConfirm waits for the user to confirm deleting the selected memory
+func (m *model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.mode = m.back
+	mem := m.selected()
+	if mem == nil || (msg.String() != "y" && msg.String() != "Y") {
+		m.status = "Delete cancelled"
+		return m, nil
+	}
+	m.mode = modeList
+	id := mem.ID
+	return m, func() tea.Msg {
+		deleted, err := m.store.DeleteMemory(id)
+		if err == nil && !deleted {
+			err = fmt.Errorf("memory %s not found", id)
+		}
+		return savedMsg{status: "Deleted " + id, err: err}
+	}
+}
+
+// move moves the selection by delta, loading the next page when it passes
+// the last loaded result
+func (m *model) move(delta int) tea.Cmd {
+	m.cursor += delta
+	loadMore := m.cursor >= len(m.results) && m.more
+	m.clampCursor()
+	if loadMore {
+		m.limit += pageSize
+		m.status = "Loading more..."
+		return m.search()
+	}
+	return nil
+}
+
+// clampCursor keeps the selection within the results and on screen
+func (m *model) clampCursor() {
+	m.cursor = min(max(m.cursor, 0), max(len(m.results)-1, 0))
+	if m.cursor < m.top {
+		m.top = m.cursor
+	}
+	if m.cursor >= m.top+m.listHeight() {
+		m.top = m.cursor - m.listHeight() + 1
+	}
+}
+
+// edit opens the selected memory's content in the editor
+func (m *model) edit() tea.Cmd {
+	mem := m.selected()
+	if mem == nil {
+		return nil
+	}
+	f, err := os.CreateTemp("", "memorypilot-*.md")
+	if err != nil {
+		m.err = err
+		return nil
+	}
+	_, err = f.WriteString(mem.Content)
+	f.Close()
+	if err != nil {
+		m.err = err
+		return nil
+	}
+
+	id, path := mem.ID, f.Name()
+	args := append(strings.Fields(m.opts.Editor), path)
+	return tea.ExecProcess(exec.Command(args[0], args[1:]...), func(err error) tea.Msg {
+		return editedMsg{id: id, path: path, err: err}
+	})
+}
+
+// saveEdit stores the content written by the editor, if it changed
+func (m *model) saveEdit(msg editedMsg) tea.Cmd {
+	return func() tea.Msg {
+		defer os.Remove(msg.path)
+		if msg.err != nil {
+			return savedMsg{err: fmt.Errorf("editor failed: %w", msg.err)}
+		}
+		data, err := os.ReadFile(msg.path)
+		if err != nil {
+			return savedMsg{err: err}
+		}
+		content := strings.TrimSpace(string(data))
+
+		current, err := m.store.GetMemory(msg.id)
+		if err != nil {
+			return savedMsg{err: err}
+		}
+		if current == nil {
+			return savedMsg{err: fmt.Errorf("memory %s not found", msg.id)}
+		}
+		if content == "" || content == current.Content {
+			return savedMsg{status: "No changes"}
+		}
+
+		summaryText, err := summary.ForType(m.opts.Summarizer, content, current.Type)
+		if err != nil {
+			summaryText = summary.Truncate(content, summary.DefaultMaxLen)
+		}
+		if err := m.store.UpdateMemoryContent(msg.id, content, summaryText, "tui"); err != nil {
+			return savedMsg{err: err}
+		}
+
+		// Best effort: the daemon or 'reindex' fills in missing embeddings
+		if m.opts.Embedder != nil {
+			if emb, err := m.opts.Embedder.Embed(content); err == nil && embedding.Valid(emb) {
+				m.store.UpdateMemoryEmbedding(msg.id, emb, embedding.ModelName(m.opts.Embedder))
+			}
+		}
+		return savedMsg{status: "Saved " + msg.id}
+	}
+}
+
+func (m *model) View() string {
+	if m.width == 0 {
+		return ""
+	}
+	if m.mode == modeDetail || (m.mode == modeConfirm && m.back == modeDetail) {
+		return m.viewDetail()
+	}
+
+	var b strings.Builder
+	b.WriteString(m.viewHeader())
+	b.WriteString("\n")
+
+	if len(m.results) == 0 {
+		b.WriteString(dimStyle.Render("  No memories found"))
+		b.WriteString("\n")
+	}
+	end := min(m.top+m.listHeight(), len(m.results))
+	for i := m.top; i < end; i++ {
+		r := m.results[i]
+		line := truncate(fmt.Sprintf(" %s  %-10s %s", r.CreatedAt.Format("2006-01-02"), r.Type, oneLine(r.Summary)), m.width)
+		if i == m.cursor {
+			line = selectedStyle.Render(line)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	for i := end - m.top; i < m.listHeight(); i++ {
+		b.WriteString("\n")
+	}
+
+	b.WriteString(m.viewFooter())
+	return b.String()
+}
+
+// viewHeader renders the search line and active filters
+func (m *model) viewHeader() string {
+	typeName := "all"
+	if t := memoryTypes[m.typeIndex]; t != "" {
+		typeName = string(t)
+	}
+	topic := m.topic
+	if topic == "" {
+		topic = "any"
+	}
+	count := fmt.Sprintf("%d results", len(m.results))
+	if m.more {
+		count = fmt.Sprintf("%d+ results", len(m.results))
+	}
+
+	search := "Search: " + m.query
+	if m.mode != modeTopic {
+		search += "▏"
+	}
+	return titleStyle.Render(truncate(fmt.Sprintf("%s   [type: %s · topic: %s · %s]", search, typeName, topic, count), m.width))
+}
+
+// viewFooter renders the status line and key help
+func (m *model) viewFooter() string {
+	switch {
+	case m.mode == modeTopic:
+		return titleStyle.Render("Topic filter: ") + m.topicInput + "▏" + dimStyle.Render("  (enter apply · empty clears · esc cancel)")
+	case m.mode == modeConfirm:
+		return errorStyle.Render(fmt.Sprintf("Delete %s? (y/n)", m.selected().ID))
+	case m.err != nil:
+		return errorStyle.Render(truncate("Error: "+m.err.Error(), m.width))
+	case m.status != "":
+		return truncate(m.status, m.width)
+	}
+	return dimStyle.Render(truncate("type to search · ↑↓ pgup/pgdn move · enter view · ^E edit · ^D delete · ^T type · ^F topic · esc clear/quit", m.width))
+}
+
+// viewDetail renders the selected memory in full
+func (m *model) viewDetail() string {
+	mem := m.selected()
+	if mem == nil {
+		return ""
+	}
+
+	lines := []string{
+		titleStyle.Render(fmt.Sprintf("[%s] %s", mem.Type, oneLine(mem.Summary))),
+		"",
+	}
+	for _, line := range strings.Split(mem.Content, "\n") {
+		lines = append(lines, wrap(line, m.width)...)
+	}
+	lines = append(lines, "",
+		dimStyle.Render("ID:         ")+mem.ID,
+		dimStyle.Render("Created:    ")+mem.CreatedAt.Format("2006-01-02 15:04"),
+		dimStyle.Render("Source:     ")+fmt.Sprintf("%s (%s)", mem.Source.Type, mem.Source.Reference),
+		dimStyle.Render("Importance: ")+fmt.Sprintf("%.2f", mem.Importance),
+	)
+	if mem.ContentType != "" {
+		lines = append(lines, dimStyle.Render("Content:    ")+string(mem.ContentType))
+	}
+	if len(mem.Topics) > 0 {
+		lines = append(lines, dimStyle.Render("Topics:     ")+strings.Join(mem.Topics, ", "))
+	}
+	keys := slices.Sorted(maps.Keys(mem.Metadata))
+	for _, key := range keys {
+		lines = append(lines, dimStyle.Render("Metadata:   ")+key+"="+mem.Metadata[key])
+	}
+
+	height := max(m.height-1, 1)
+	m.scroll = min(m.scroll, max(len(lines)-height, 0))
+	end := min(m.scroll+height, len(lines))
+
+	var b strings.Builder
+	for _, line := range lines[m.scroll:end] {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	for i := end - m.scroll; i < height; i++ {
+		b.WriteString("\n")
+	}
+
+	switch {
+	case m.mode == modeConfirm:
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Delete %s? (y/n)", mem.ID)))
+	case m.err != nil:
+		b.WriteString(errorStyle.Render(truncate("Error: "+m.err.Error(), m.width)))
+	default:
+		b.WriteString(dimStyle.Render(truncate("↑↓ scroll · e edit · d delete · esc back", m.width)))
+	}
+	return b.String()
+}
+
+// oneLine collapses whitespace so text fits on one line
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncate cuts s to width display cells
+func truncate(s string, width int) string {
+	if width <= 0 || lipgloss.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// wrap splits a line into pieces of at most width runes
+func wrap(line string, width int) []string {
+	runes := []rune(line)
+	if width <= 0 || len(runes) <= width {
+		return []string{line}
+	}
+	var lines []string
+	for len(runes) > width {
+		lines = append(lines, string(runes[:width]))
+		runes = runes[width:]
+	}
+	return append(lines, string(runes))
+}
@@ -0,0 +1,134 @@
+package watcher
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultMinDiffLines is the fewest added plus removed lines a file change
+// needs before it is captured
+const DefaultMinDiffLines = 3
+
+// maxDiffBytes caps the diff kept for one file change
+const maxDiffBytes = 4000
+
+// FileDiff is a file's uncommitted change, from git
+type FileDiff struct {
+	Repo      string   // Repository root
+	Path      string   // File path relative to Repo
+	Diff      string   // Unified diff against HEAD, truncated to maxDiffBytes
+	Added     int      // Lines added
+	Removed   int      // Lines removed
+	Functions []string // Functions touched, from hunk headers and changed declarations
+}
+
+// Lines returns the number of changed lines
+func (d FileDiff) Lines() int {
+	return d.Added + d.Removed
+}
+
+// gitDiff diffs path against HEAD in its git repository. ok is false if the
+// file isn't in a repository or git ignores it. Files git doesn't track yet
+// are diffed as entirely new.
+func gitDiff(path string) (diff FileDiff, ok bool) {
+	dir := filepath.Dir(path)
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return diff, false
+	}
+	repo := strings.TrimSpace(string(out))
+	rel, err := filepath.Rel(repo, path)
+	if err != nil {
+		// Symlinked directories can make the paths disagree; fall back to
+		// the name within its directory
+		rel = filepath.Base(path)
+	}
+	if exec.Command("git", "-C", dir, "check-ignore", "-q", filepath.Base(path)).Run() == nil {
+		return diff, false
+	}
+
+	var raw []byte
+	if exec.Command("git", "-C", dir, "ls-files", "--error-unmatch", filepath.Base(path)).Run() == nil {
+		raw, err = exec.Command("git", "-C", dir, "diff", "--no-color", "--no-ext-diff", "HEAD", "--", filepath.Base(path)).Output()
+	} else {
+		// git diff --no-index exits with 1 when the files differ, and
+		// understands /dev/null on every platform
+		raw, err = exec.Command("git", "-C", dir, "diff", "--no-color", "--no-ext-diff", "--no-index", "--", "/dev/null", filepath.Base(path)).Output()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			err = nil
+		}
+	}
+	if err != nil {
+		return diff, false
+	}
+
+	diff = parseDiff(raw)
+	diff.Repo = repo
+	diff.Path = filepath.ToSlash(rel)
+	return diff, true
+}
+
+var (
+	hunkHeader  = regexp.MustCompile(`^@@ [^@]+ @@ ?(.*)$`)
+	declaration = regexp.MustCompile(`^\s*(?:export\s+)?(?:async\s+)?(?:func|def|function|fn|class|interface|type)\s+(?:\([^)]*\)\s*)?([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+// parseDiff counts the changed lines of a unified diff and picks out the
+// functions it touches
+func parseDiff(raw []byte) FileDiff {
+	var diff FileDiff
+	seen := make(map[string]bool)
+	var current string // Innermost declaration seen so far in the hunk
+	touch := func() {
+		if current != "" && !seen[current] {
+			seen[current] = true
+			diff.Functions = append(diff.Functions, current)
+		}
+	}
+	declare := func(line string) {
+		if m := declaration.FindStringSubmatch(line); m != nil {
+			current = m[1]
+		}
+	}
+
+	for _, line := range strings.Split(string(raw), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			diff.Added++
+			declare(line[1:])
+			touch()
+		case strings.HasPrefix(line, "-"):
+			diff.Removed++
+			declare(line[1:])
+			touch()
+		case strings.HasPrefix(line, "@@"):
+			current = ""
+			if m := hunkHeader.FindStringSubmatch(line); m != nil {
+				declare(m[1])
+			}
+		case strings.HasPrefix(line, " "):
+			declare(line[1:])
+		}
+	}
+
+	// Keep from the first hunk on; the header lines only repeat the path
+	if i := bytes.Index(raw, []byte("\n@@")); i >= 0 {
+		raw = raw[i+1:]
+	}
+	text := string(raw)
+	if len(text) > maxDiffBytes {
+		cut := strings.LastIndexByte(text[:maxDiffBytes], '\n')
+		if cut < 0 {
+			cut = maxDiffBytes
+		}
+		text = text[:cut] + "\n... (diff truncated)"
+	}
+	diff.Diff = strings.TrimRight(text, "\n")
+	return diff
+}
@@ -1,11 +1,14 @@
 package watcher
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -21,6 +24,15 @@ type FileWatcher struct {
 	stopChan   chan struct{}
 	pending    map[string]time.Time
 	pendingMux sync.Mutex
+
+	minDiffLines int
+	lastDiff     map[string]string // path -> last captured diff
+
+	roots     []string // Code directories watched recursively
+	healthMux sync.Mutex
+	err       error // Why the watcher died
+	skipped   int   // Directories not watched because of a system limit
+	limit     string
 }
 
 // NewFileWatcher creates a new file watcher
@@ -30,13 +42,27 @@ func NewFileWatcher(debounce time.Duration, sink EventSink) *FileWatcher {
 		eventSink: sink,
 		stopChan:  make(chan struct{}),
 		pending:   make(map[string]time.Time),
+
+		minDiffLines: DefaultMinDiffLines,
+		lastDiff:     make(map[string]string),
+	}
+}
+
+// SetMinDiffLines sets the fewest added plus removed lines a change to a
+// file in a git repository needs to be captured. 0 or less restores the
+// default.
+func (w *FileWatcher) SetMinDiffLines(n int) {
+	if n <= 0 {
+		n = DefaultMinDiffLines
 	}
+	w.minDiffLines = n
 }
 
 // Start begins watching for file events
 func (w *FileWatcher) Start() error {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
+		w.fail(err)
 		return err
 	}
 	w.watcher = watcher
@@ -45,16 +71,19 @@ func (w *FileWatcher) Start() error {
 	go w.debounceLoop()
 
 	// Add common code directories
-	home, _ := os.UserHomeDir()
-	codeDirs := []string{
-		filepath.Join(home, "Documents", "source-code"),
-		filepath.Join(home, "Projects"),
-	}
+	w.roots = CodeRoots()
 
-	for _, dir := range codeDirs {
+	for _, dir := range w.roots {
 		w.addDirRecursive(dir)
 	}
 
+	w.healthMux.Lock()
+	if w.skipped > 0 {
+		log.Printf("⚠️ File watcher: %s; %d directories aren't watched and their changes won't be captured",
+			w.limit, w.skipped)
+	}
+	w.healthMux.Unlock()
+
 	return nil
 }
 
@@ -85,7 +114,14 @@ func (w *FileWatcher) addDirRecursive(root string) {
 				return filepath.SkipDir
 			}
 
-			w.watcher.Add(path)
+			if err := w.watcher.Add(path); err != nil {
+				if limit := watchLimit(err); limit != "" {
+					w.healthMux.Lock()
+					w.skipped++
+					w.limit = limit
+					w.healthMux.Unlock()
+				}
+			}
 		}
 
 		return nil
@@ -125,6 +161,7 @@ func (w *FileWatcher) watch() {
 
 		case event, ok := <-w.watcher.Events:
 			if !ok {
+				w.fail(errors.New("file events stopped arriving"))
 				return
 			}
 
@@ -140,6 +177,7 @@ func (w *FileWatcher) watch() {
 
 		case err, ok := <-w.watcher.Errors:
 			if !ok {
+				w.fail(errors.New("file events stopped arriving"))
 				return
 			}
 			log.Printf("File watcher error: %v", err)
@@ -147,6 +185,63 @@ func (w *FileWatcher) watch() {
 	}
 }
 
+// watchLimit explains an error adding a watch caused by a system limit, or
+// returns "" for any other error
+func watchLimit(err error) string {
+	switch {
+	case errors.Is(err, syscall.ENOSPC):
+		return "inotify watch limit reached (raise it with: sudo sysctl fs.inotify.max_user_watches=524288)"
+	case errors.Is(err, syscall.EMFILE):
+		return "open file limit reached (raise it with: ulimit -n)"
+	}
+	return ""
+}
+
+// fail marks the watcher dead
+func (w *FileWatcher) fail(err error) {
+	w.healthMux.Lock()
+	defer w.healthMux.Unlock()
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+// Health reports the file watcher dead once its event stream stopped, or a
+// code directory that exists isn't watched anymore, say because it was
+// removed or unmounted and came back. Directories left out because of a
+// watch limit are a warning: restarting wouldn't get them watched.
+func (w *FileWatcher) Health() Health {
+	h := Health{Name: "file", Alive: true}
+	watched := make(map[string]bool)
+	if w.watcher != nil {
+		for _, path := range w.watcher.WatchList() {
+			watched[path] = true
+		}
+	}
+	h.Watches = len(watched)
+
+	w.healthMux.Lock()
+	defer w.healthMux.Unlock()
+	if w.err != nil {
+		h.Alive = false
+		h.Error = w.err.Error()
+		return h
+	}
+	if w.skipped > 0 {
+		h.Warning = fmt.Sprintf("%s; %d directories aren't watched", w.limit, w.skipped)
+		return h
+	}
+
+	for _, root := range w.roots {
+		if info, err := os.Stat(root); err == nil && info.IsDir() && !watched[root] {
+			h.Alive = false
+			h.Error = fmt.Sprintf("%s isn't watched anymore (removed or unmounted)", root)
+			return h
+		}
+	}
+	return h
+}
+
 func (w *FileWatcher) debounceLoop() {
 	ticker := time.NewTicker(w.debounce)
 	defer ticker.Stop()
@@ -208,12 +303,34 @@ func (w *FileWatcher) emitEvent(path string) {
 		return
 	}
 
-	// Read content for small files
-	var content string
-	if info.Size() < 10000 {
-		data, err := os.ReadFile(path)
+	data := map[string]interface{}{
+		"path":     path,
+		"filename": filepath.Base(path),
+		"ext":      filepath.Ext(path),
+		"size":     info.Size(),
+	}
+
+	// In a git repository, capture what changed since HEAD rather than the
+	// whole file
+	if diff, ok := gitDiff(path); ok {
+		if diff.Lines() < w.minDiffLines {
+			return
+		}
+		if w.lastDiff[path] == diff.Diff {
+			return
+		}
+		w.lastDiff[path] = diff.Diff
+		data["repo"] = diff.Repo
+		data["relpath"] = diff.Path
+		data["diff"] = diff.Diff
+		data["added"] = diff.Added
+		data["removed"] = diff.Removed
+		data["functions"] = diff.Functions
+	} else if info.Size() < 10000 {
+		// Read content for small files
+		content, err := os.ReadFile(path)
 		if err == nil {
-			content = string(data)
+			data["content"] = string(content)
 		}
 	}
 
@@ -221,13 +338,7 @@ func (w *FileWatcher) emitEvent(path string) {
 		ID:        ulid.Make().String(),
 		Type:      "file_change",
 		Timestamp: time.Now(),
-		Data: map[string]interface{}{
-			"path":     path,
-			"filename": filepath.Base(path),
-			"ext":      filepath.Ext(path),
-			"size":     info.Size(),
-			"content":  content,
-		},
+		Data:      data,
 	}
 
 	log.Printf("File event: %s", filepath.Base(path))
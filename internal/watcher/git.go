@@ -60,21 +60,7 @@ func (w *GitWatcher) watch() {
 }
 
 func (w *GitWatcher) scanGitRepos() {
-	// Get home directory
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return
-	}
-
-	// Common code directories
-	codeDirs := []string{
-		filepath.Join(home, "Documents", "source-code"),
-		filepath.Join(home, "Projects"),
-		filepath.Join(home, "code"),
-		filepath.Join(home, "dev"),
-	}
-
-	for _, codeDir := range codeDirs {
+	for _, codeDir := range CodeRoots() {
 		if _, err := os.Stat(codeDir); os.IsNotExist(err) {
 			continue
 		}
@@ -172,3 +158,8 @@ func (w *GitWatcher) checkRepo(repoPath string) {
 		log.Printf("Event queue full, dropping git event")
 	}
 }
+
+// Health reports the git watcher alive: it polls and has nothing to lose
+func (w *GitWatcher) Health() Health {
+	return Health{Name: "git", Alive: true}
+}
@@ -18,8 +18,13 @@ type TerminalWatcher struct {
 	stopChan      chan struct{}
 	historyFiles  []string
 	lastPositions map[string]int64
+	readHistory   bool
 }
 
+// ignoredExitCodes are failures not worth learning from: command not found
+// (usually a typo) and interrupted with Ctrl+C
+var ignoredExitCodes = map[int]bool{127: true, 130: true}
+
 // NewTerminalWatcher creates a new terminal watcher
 func NewTerminalWatcher(sink EventSink) *TerminalWatcher {
 	home, _ := os.UserHomeDir()
@@ -31,11 +36,22 @@ func NewTerminalWatcher(sink EventSink) *TerminalWatcher {
 			filepath.Join(home, ".bash_history"),
 		},
 		lastPositions: make(map[string]int64),
+		readHistory:   true,
 	}
 }
 
+// SetReadHistory sets whether commands are picked up from shell history
+// files. Shell hooks reporting through Report make them redundant.
+func (w *TerminalWatcher) SetReadHistory(read bool) {
+	w.readHistory = read
+}
+
 // Start begins watching for terminal events
 func (w *TerminalWatcher) Start() error {
+	if !w.readHistory {
+		return nil
+	}
+
 	// Initialize positions
 	for _, path := range w.historyFiles {
 		if info, err := os.Stat(path); err == nil {
@@ -113,11 +129,55 @@ func (w *TerminalWatcher) parseHistoryLine(line, historyFile string) string {
 	return strings.TrimSpace(line)
 }
 
+// Report emits an event for a command reported by a shell hook, with its
+// exit code and working directory. Failed commands are kept unless they
+// are trivial, sensitive or failed in a way not worth learning from;
+// successful ones only if they would be picked up from history.
+func (w *TerminalWatcher) Report(cmd string, exitCode int, dir string) {
+	cmd = strings.TrimSpace(cmd)
+	if exitCode == 0 && !w.isInteresting(cmd) {
+		return
+	}
+	if exitCode != 0 && (ignoredExitCodes[exitCode] || isTrivial(cmd)) {
+		return
+	}
+
+	w.emit(cmd, map[string]interface{}{
+		"command":   cmd,
+		"exit_code": exitCode,
+		"dir":       dir,
+	})
+}
+
 func (w *TerminalWatcher) isInteresting(cmd string) bool {
-	if len(cmd) < 3 {
+	if isTrivial(cmd) {
 		return false
 	}
 
+	// Interesting commands
+	interestingStarts := []string{
+		"git ", "npm ", "yarn ", "pnpm ",
+		"go ", "cargo ", "python ", "pip ",
+		"docker ", "kubectl ", "terraform ",
+		"make ", "brew ",
+	}
+
+	for _, prefix := range interestingStarts {
+		if strings.HasPrefix(cmd, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isTrivial reports whether a command is too short, may carry secrets, or
+// is everyday navigation not worth remembering
+func isTrivial(cmd string) bool {
+	if len(cmd) < 3 {
+		return true
+	}
+
 	// Skip sensitive commands
 	sensitiveStarts := []string{
 		"export ", "set ", "unset ",
@@ -129,7 +189,7 @@ func (w *TerminalWatcher) isInteresting(cmd string) bool {
 
 	for _, prefix := range sensitiveStarts {
 		if strings.HasPrefix(cmd, prefix) {
-			return false
+			return true
 		}
 	}
 
@@ -144,36 +204,26 @@ func (w *TerminalWatcher) isInteresting(cmd string) bool {
 		base := parts[0]
 		for _, noise := range noiseCommands {
 			if base == noise {
-				return false
+				return true
 			}
 		}
 	}
 
-	// Interesting commands
-	interestingStarts := []string{
-		"git ", "npm ", "yarn ", "pnpm ",
-		"go ", "cargo ", "python ", "pip ",
-		"docker ", "kubectl ", "terraform ",
-		"make ", "brew ",
-	}
-
-	for _, prefix := range interestingStarts {
-		if strings.HasPrefix(cmd, prefix) {
-			return true
-		}
-	}
-
 	return false
 }
 
 func (w *TerminalWatcher) emitEvent(cmd string) {
+	w.emit(cmd, map[string]interface{}{
+		"command": cmd,
+	})
+}
+
+func (w *TerminalWatcher) emit(cmd string, data map[string]interface{}) {
 	event := models.Event{
 		ID:        ulid.Make().String(),
 		Type:      "terminal_cmd",
 		Timestamp: time.Now(),
-		Data: map[string]interface{}{
-			"command": cmd,
-		},
+		Data:      data,
 	}
 
 	log.Printf("Terminal event: %s", truncate(cmd, 50))
@@ -185,9 +235,20 @@ func (w *TerminalWatcher) emitEvent(cmd string) {
 	}
 }
 
+// truncate shortens s to at most maxLen runes, never splitting a character
 func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
 		return s
 	}
-	return s[:maxLen-3] + "..."
+	if maxLen <= 3 {
+		return string(runes[:maxLen])
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
+
+// Health reports the terminal watcher alive: it polls history files, and
+// reported commands don't depend on it running
+func (w *TerminalWatcher) Health() Health {
+	return Health{Name: "terminal", Alive: true}
 }
@@ -0,0 +1,41 @@
+package watcher
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateMultiByte(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		maxLen int
+		want   string
+	}{
+		{"fits", "git push 🚀", 10, "git push 🚀"},
+		{"emoji cut", "echo 🚀🚀🚀🚀🚀", 8, "echo ..."},
+		{"CJK cut", "echo 数据库迁移已完成", 10, "echo 数据..."},
+		{"shorter than ellipsis", "数据库", 2, "数据"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncate(tt.s, tt.maxLen)
+			if got != tt.want {
+				t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.maxLen, got, tt.want)
+			}
+			if n := utf8.RuneCountInString(got); n > tt.maxLen {
+				t.Errorf("truncate(%q, %d) is %d characters long", tt.s, tt.maxLen, n)
+			}
+		})
+	}
+}
+
+func TestTruncateNeverSplitsRunes(t *testing.T) {
+	s := strings.Repeat("é🚀字", 20)
+	for maxLen := 1; maxLen < 70; maxLen++ {
+		if got := truncate(s, maxLen); !utf8.ValidString(got) {
+			t.Fatalf("truncate to %d characters gave invalid UTF-8 %q", maxLen, got)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package watcher
 
 import (
+	"os"
+	"path/filepath"
+	"time"
+
 	"github.com/contextpilot-dev/memorypilot/pkg/models"
 )
 
@@ -8,6 +12,37 @@ import (
 type Watcher interface {
 	Start() error
 	Stop()
+	// Health reports whether the watcher is still capturing
+	Health() Health
+}
+
+// Health describes whether a watcher is still capturing. A dead watcher
+// has stopped for good and has to be replaced; a warning means it is
+// capturing, but not everything.
+type Health struct {
+	Name    string `json:"name"`
+	Alive   bool   `json:"alive"`
+	Error   string `json:"error,omitempty"`   // Why it died
+	Warning string `json:"warning,omitempty"` // What it is missing while alive
+	Watches int    `json:"watches,omitempty"` // Directories watched, for the file watcher
+
+	// Set by the agent, which restarts dead watchers
+	Restarts    int        `json:"restarts"`
+	LastRestart *time.Time `json:"lastRestart,omitempty"`
+}
+
+// CodeDirs are the directories, relative to the home directory, that the
+// git watcher searches for repositories and the file watcher watches
+var CodeDirs = []string{"Documents/source-code", "Projects", "code", "dev"}
+
+// CodeRoots returns CodeDirs as absolute paths
+func CodeRoots() []string {
+	home, _ := os.UserHomeDir()
+	roots := make([]string, len(CodeDirs))
+	for i, dir := range CodeDirs {
+		roots[i] = filepath.Join(home, filepath.FromSlash(dir))
+	}
+	return roots
 }
 
 // EventSink is a channel that receives events
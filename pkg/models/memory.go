@@ -1,6 +1,9 @@
 package models
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -16,6 +19,22 @@ const (
 	MemoryTypeLearning   MemoryType = "learning"
 )
 
+// MemoryTypes lists the known memory types
+var MemoryTypes = []MemoryType{
+	MemoryTypeDecision, MemoryTypePattern, MemoryTypeFact,
+	MemoryTypePreference, MemoryTypeMistake, MemoryTypeLearning,
+}
+
+// Valid reports whether t is one of the known memory types
+func (t MemoryType) Valid() bool {
+	switch t {
+	case MemoryTypeDecision, MemoryTypePattern, MemoryTypeFact,
+		MemoryTypePreference, MemoryTypeMistake, MemoryTypeLearning:
+		return true
+	}
+	return false
+}
+
 // MemoryScope represents the visibility of a memory
 type MemoryScope string
 
@@ -26,6 +45,25 @@ const (
 	MemoryScopeOrg      MemoryScope = "org"
 )
 
+// ContentType describes the form of a memory's content
+type ContentType string
+
+const (
+	ContentTypeProse   ContentType = "prose"
+	ContentTypeCode    ContentType = "code"
+	ContentTypeCommand ContentType = "command"
+	ContentTypeConfig  ContentType = "config"
+)
+
+// Valid reports whether t is one of the known content types
+func (t ContentType) Valid() bool {
+	switch t {
+	case ContentTypeProse, ContentTypeCode, ContentTypeCommand, ContentTypeConfig:
+		return true
+	}
+	return false
+}
+
 // SourceType represents where a memory came from
 type SourceType string
 
@@ -45,12 +83,37 @@ type Source struct {
 	Timestamp time.Time  `json:"timestamp"`
 }
 
+// String describes where a memory came from, e.g. "git (a1b2c3d)"
+func (s Source) String() string {
+	if s.Reference == "" {
+		return string(s.Type)
+	}
+	return fmt.Sprintf("%s (%s)", s.Type, s.Reference)
+}
+
+// FormatMetadata renders metadata as "key=value" pairs sorted by key
+func FormatMetadata(metadata map[string]string) string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + metadata[key]
+	}
+	return strings.Join(pairs, ", ")
+}
+
 // Memory represents a single piece of remembered information
 type Memory struct {
 	ID      string     `json:"id"`
 	Type    MemoryType `json:"type"`
 	Content string     `json:"content"`
 	Summary string     `json:"summary"`
+	// Form of Content; detected when stored unless set explicitly
+	ContentType ContentType `json:"contentType,omitempty"`
 
 	// Scope
 	Scope     MemoryScope `json:"scope"`
@@ -61,21 +124,99 @@ type Memory struct {
 	Source Source `json:"source"`
 
 	// Intelligence
-	Confidence float64   `json:"confidence"` // 0.0-1.0
-	Importance float64   `json:"importance"` // 0.0-1.0, decays over time
-	Embedding  []float32 `json:"embedding"`  // 384-dim vector
+	Confidence float64   `json:"confidence"`          // 0.0-1.0
+	Importance float64   `json:"importance"`          // 0.0-1.0, decays over time
+	Embedding  []float32 `json:"embedding,omitempty"` // 384-dim vector
+	// Model that produced Embedding, as "provider/model" ("" if unknown)
+	EmbeddingModel string  `json:"embeddingModel,omitempty"`
+	Score          float32 `json:"score,omitempty"` // Search relevance, set by recall only
+	// Whether the memory contains the query as keyword text, set by recall
+	// only; semantic matches may share no words with the query. Never
+	// serialized, since it describes one query rather than the memory.
+	KeywordMatch bool `json:"-"`
+	// Learned from recall feedback (-1.0 to 1.0), fading over time
+	Feedback float64 `json:"feedback,omitempty"`
+	// When and by whom the memory was vetted; curated memories rank above
+	// raw capture and don't decay
+	CuratedAt *time.Time `json:"curatedAt,omitempty"`
+	CuratedBy string     `json:"curatedBy,omitempty"`
+
+	// Breakdown of Score, set by recall when explain is requested
+	Explanation *ScoreExplanation `json:"explanation,omitempty"`
 
 	// Relationships
 	Topics          []string `json:"topics"`
 	RelatedMemories []string `json:"relatedMemories"`
+	// Memories often recalled together with this one, set by recall when
+	// related memories are requested; not stored with the memory
+	InferredRelated []string `json:"inferredRelated,omitempty"`
+
+	// Free-form key/value annotations (ticket IDs, authors, ...)
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Client-supplied key that makes retried writes idempotent
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
 
 	// Lifecycle
 	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"` // Last change to content, summary, topics or embedding
 	LastAccessedAt time.Time  `json:"lastAccessedAt"`
 	AccessCount    int        `json:"accessCount"`
 	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
 }
 
+// ScoreExplanation breaks a recall score into the contributions that add
+// up to it, unless a reranker replaced it
+type ScoreExplanation struct {
+	Semantic   *float32 `json:"semantic,omitempty"`   // Cosine similarity to the query × 0.7, if matched semantically
+	Similarity *float32 `json:"similarity,omitempty"` // The cosine similarity itself, if matched semantically
+	Keyword    *float32 `json:"keyword,omitempty"`    // 0.7 for containing the query, if matched as keyword text
+	Importance float32  `json:"importance"`           // Importance at recall time × 0.3
+	Feedback   float32  `json:"feedback"`             // Relevance feedback weight (-1 to 1) × 0.15
+	Curated    float32  `json:"curated,omitempty"`    // 0.1 if the memory is curated
+	Confidence float32  `json:"confidence"`           // -(1 - confidence) × the store's confidence weight
+	Rerank     *float32 `json:"rerank,omitempty"`     // Reranker score, if reranked, which replaces the sum as Final
+	Final      float32  `json:"final"`                // The score results are ranked by
+}
+
+// String renders the breakdown as a sum, e.g. "final 0.912 = semantic
+// 0.560 (similarity 0.800) + importance 0.300 + feedback 0.000 +
+// confidence 0.000"
+func (e *ScoreExplanation) String() string {
+	var parts []string
+	if e.Semantic != nil {
+		part := fmt.Sprintf("semantic %.3f", *e.Semantic)
+		if e.Similarity != nil {
+			part += fmt.Sprintf(" (similarity %.3f)", *e.Similarity)
+		}
+		parts = append(parts, part)
+	}
+	if e.Keyword != nil {
+		parts = append(parts, fmt.Sprintf("keyword %.3f", *e.Keyword))
+	}
+	parts = append(parts, fmt.Sprintf("importance %.3f", e.Importance), fmt.Sprintf("feedback %.3f", e.Feedback))
+	if e.Curated != 0 {
+		parts = append(parts, fmt.Sprintf("curated %.3f", e.Curated))
+	}
+	parts = append(parts, fmt.Sprintf("confidence %.3f", e.Confidence))
+
+	sum := strings.Join(parts, " + ")
+	if e.Rerank != nil {
+		return fmt.Sprintf("final %.3f (reranked; fused score was %s)", e.Final, sum)
+	}
+	return fmt.Sprintf("final %.3f = %s", e.Final, sum)
+}
+
+// MemoryVersion is a prior revision of a memory, recorded on update
+type MemoryVersion struct {
+	MemoryID string    `json:"memoryId"`
+	Version  int       `json:"version"`
+	Content  string    `json:"content"`
+	Summary  string    `json:"summary"`
+	Editor   string    `json:"editor"`
+	EditedAt time.Time `json:"editedAt"`
+}
+
 // Project represents a tracked project/repository
 type Project struct {
 	ID        string    `json:"id"`
@@ -95,13 +236,62 @@ type Event struct {
 	ProjectID *string                `json:"projectId,omitempty"`
 }
 
+// Tombstone records that a memory was deleted, so incremental exports can
+// propagate the deletion
+type Tombstone struct {
+	ID        string    `json:"id"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
 // RecallRequest represents a search query
 type RecallRequest struct {
-	Query     string        `json:"query"`
-	Scope     []MemoryScope `json:"scope,omitempty"`
-	ProjectID *string       `json:"projectId,omitempty"`
-	Types     []MemoryType  `json:"types,omitempty"`
-	Limit     int           `json:"limit,omitempty"`
+	Query       string        `json:"query"`
+	Scope       []MemoryScope `json:"scope,omitempty"`
+	ProjectID   *string       `json:"projectId,omitempty"`
+	Types       []MemoryType  `json:"types,omitempty"`
+	Limit       int           `json:"limit,omitempty"`
+	Exact       bool          `json:"exact,omitempty"` // Literal, case-sensitive phrase match
+	SourceTypes []SourceType  `json:"sourceTypes,omitempty"`
+	// Only memories whose content is of these types
+	ContentTypes []ContentType     `json:"contentTypes,omitempty"`
+	MinScore     float32           `json:"minScore,omitempty"` // Drop results scoring below this (0-1)
+	Metadata     map[string]string `json:"metadata,omitempty"` // Only memories with all of these key/value pairs
+	Topics       []string          `json:"topics,omitempty"`   // Only memories tagged with any of these (aliases resolved)
+	// Drop memories tagged with any of these, even if they match Topics
+	ExcludeTopics []string `json:"excludeTopics,omitempty"`
+	Explain       bool     `json:"explain,omitempty"` // Attach a ScoreExplanation to each result
+	// Only curated memories
+	CuratedOnly bool `json:"curatedOnly,omitempty"`
+	// Include auto-captured memories below the store's importance floor,
+	// which recall otherwise leaves out unless filtering by type or topic
+	IncludeLow bool `json:"includeLow,omitempty"`
+	// Only memories created at or after CreatedAfter and before
+	// CreatedBefore
+	CreatedAfter  *time.Time `json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time `json:"createdBefore,omitempty"`
+	// Only memories whose importance satisfies all of these comparisons
+	Importance []Comparison `json:"importance,omitempty"`
+	// Related terms (see Store.ExpandQuery); keyword search also matches
+	// memories containing or tagged with any of them
+	Expand []string `json:"expand,omitempty"`
+	// Trade-off between relevance (0) and variety (1) when picking results
+	// by Maximal Marginal Relevance; 0 disables it
+	Diversity float64 `json:"diversity,omitempty"`
+}
+
+// Comparison compares a value against a bound, e.g. {">", 0.5}
+type Comparison struct {
+	Op    string  `json:"op"` // One of <, <=, =, >=, >
+	Value float64 `json:"value"`
+}
+
+// Valid reports whether c uses a known operator
+func (c Comparison) Valid() bool {
+	switch c.Op {
+	case "<", "<=", "=", ">=", ">":
+		return true
+	}
+	return false
 }
 
 // RecallResponse represents search results